@@ -65,6 +65,10 @@ type ConnectionArgs struct {
 	// Caching support for image servers
 	CachePath   string
 	CacheExpiry time.Duration
+
+	// ASCII armored OpenPGP keys trusted to sign the index files of simplestreams servers.
+	// When set, only signed index files are accepted.
+	TrustedKeys string
 }
 
 // ConnectIncus lets you connect to a remote Incus daemon over HTTPs.
@@ -289,6 +293,16 @@ func ConnectSimpleStreams(uri string, args *ConnectionArgs) (ImageServer, error)
 	ssClient := simplestreams.NewClient(uri, *httpClient, args.UserAgent)
 	server.ssClient = ssClient
 
+	// Setup signature verification
+	if args.TrustedKeys != "" {
+		err = ssClient.SetTrustedKeys(args.TrustedKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		server.httpTrustedKeys = args.TrustedKeys
+	}
+
 	// Setup the cache
 	if args.CachePath != "" {
 		if !util.PathExists(args.CachePath) {
@@ -554,8 +554,13 @@ func (r *ProtocolIncus) getSourceImageConnectionInfo(source ImageServer, image a
 		return nil, err
 	}
 
+	if info.TrustedKeys != "" && !r.HasExtension("simplestreams_signing") {
+		return nil, fmt.Errorf("The server is missing the required \"simplestreams_signing\" API extension")
+	}
+
 	instSrc.Protocol = info.Protocol
 	instSrc.Certificate = info.Certificate
+	instSrc.TrustedKeys = info.TrustedKeys
 
 	// Generate secret token if needed
 	if !image.Public {
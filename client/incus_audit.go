@@ -0,0 +1,69 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Audit log handling functions
+
+// GetAuditEntries returns the most recent entries of the audit log, up to the server's default limit.
+func (r *ProtocolIncus) GetAuditEntries() ([]api.AuditEntry, error) {
+	return r.GetAuditEntriesWithFilter(nil, time.Time{}, time.Time{}, 0)
+}
+
+// GetAuditEntriesWithFilter returns the most recent entries of the audit log matching the filters and recorded between since and until, oldest first.
+// A zero since or until leaves the corresponding end of the range open and a zero limit uses the server's default limit.
+func (r *ProtocolIncus) GetAuditEntriesWithFilter(filters []string, since time.Time, until time.Time, limit int) ([]api.AuditEntry, error) {
+	if !r.HasExtension("audit_log") {
+		return nil, fmt.Errorf("The server is missing the required \"audit_log\" API extension")
+	}
+
+	entries := []api.AuditEntry{}
+
+	v := url.Values{}
+	v.Set("recursion", "1")
+
+	if len(filters) > 0 {
+		v.Set("filter", parseFilters(filters))
+	}
+
+	if !since.IsZero() {
+		v.Set("since", since.Format(time.RFC3339))
+	}
+
+	if !until.IsZero() {
+		v.Set("until", until.Format(time.RFC3339))
+	}
+
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/audit?%s", v.Encode()), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetAuditEntry returns the audit log entry with the given ID.
+func (r *ProtocolIncus) GetAuditEntry(id int64) (*api.AuditEntry, error) {
+	if !r.HasExtension("audit_log") {
+		return nil, fmt.Errorf("The server is missing the required \"audit_log\" API extension")
+	}
+
+	entry := api.AuditEntry{}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/audit/%d", id), nil, "", &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
@@ -0,0 +1,187 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthGroupNames returns the names of the authorization groups.
+func (r *ProtocolIncus) GetAuthGroupNames() ([]string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	urls := []string{}
+
+	_, err := r.queryStruct("GET", "/auth/groups", nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames("/1.0/auth/groups", urls...)
+}
+
+// GetAuthGroups returns the authorization groups.
+func (r *ProtocolIncus) GetAuthGroups() ([]api.AuthGroup, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	groups := []api.AuthGroup{}
+
+	_, err := r.queryStruct("GET", "/auth/groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns information about the given authorization group.
+func (r *ProtocolIncus) GetAuthGroup(name string) (*api.AuthGroup, string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, "", fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	group := api.AuthGroup{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (r *ProtocolIncus) CreateAuthGroup(group api.AuthGroupsPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("POST", "/auth/groups", group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthGroup updates the given authorization group.
+func (r *ProtocolIncus) UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthGroup changes the name of an existing authorization group.
+func (r *ProtocolIncus) RenameAuthGroup(name string, group api.AuthGroupPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("POST", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthGroup deletes an existing authorization group.
+func (r *ProtocolIncus) DeleteAuthGroup(name string) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetIdentities returns the identities known to the built-in authorization driver.
+func (r *ProtocolIncus) GetIdentities() ([]api.Identity, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	identities := []api.Identity{}
+
+	_, err := r.queryStruct("GET", "/auth/identities?recursion=1", nil, "", &identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetIdentity returns information about the given identity.
+func (r *ProtocolIncus) GetIdentity(authenticationMethod string, identifier string) (*api.Identity, string, error) {
+	if !r.HasExtension("auth_builtin") {
+		return nil, "", fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	identity := api.Identity{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), nil, "", &identity)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &identity, etag, nil
+}
+
+// CreateIdentity adds a new identity to the built-in authorization driver.
+func (r *ProtocolIncus) CreateIdentity(identity api.IdentitiesPost) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("POST", "/auth/identities", identity, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateIdentity updates the given identity.
+func (r *ProtocolIncus) UpdateIdentity(authenticationMethod string, identifier string, identity api.IdentityPut, ETag string) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), identity, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIdentity removes the given identity from the built-in authorization driver.
+func (r *ProtocolIncus) DeleteIdentity(authenticationMethod string, identifier string) error {
+	if !r.HasExtension("auth_builtin") {
+		return fmt.Errorf("The server is missing the required \"auth_builtin\" API extension")
+	}
+
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authenticationMethod), url.PathEscape(identifier)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -941,12 +941,17 @@ func (r *ProtocolIncus) CopyImage(source ImageServer, image api.Image, args *Ima
 		return &rop, nil
 	}
 
+	if info.TrustedKeys != "" && !r.HasExtension("simplestreams_signing") {
+		return nil, fmt.Errorf("The server is missing the required \"simplestreams_signing\" API extension")
+	}
+
 	// Prepare the copy request
 	req := api.ImagesPost{
 		Source: &api.ImagesPostSource{
 			ImageSource: api.ImageSource{
 				Certificate: info.Certificate,
 				Protocol:    info.Protocol,
+				TrustedKeys: info.TrustedKeys,
 			},
 			Fingerprint: image.Fingerprint,
 			Mode:        "pull",
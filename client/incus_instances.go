@@ -603,6 +603,10 @@ func (r *ProtocolIncus) CreateInstance(instance api.InstancesPost) (Operation, e
 		}
 	}
 
+	if instance.Source.Type == "backup" && !r.HasExtension("backup_s3") {
+		return nil, fmt.Errorf(`The server is missing the required "backup_s3" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", path, instance, "")
 	if err != nil {
@@ -2866,6 +2870,14 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, fmt.Errorf("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return nil, fmt.Errorf(`The server is missing the required "backup_incremental" API extension`)
+	}
+
+	if backup.Target != "" && !r.HasExtension("backup_s3") {
+		return nil, fmt.Errorf(`The server is missing the required "backup_s3" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
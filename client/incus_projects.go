@@ -3,6 +3,7 @@ package incus
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -78,6 +79,34 @@ func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error)
 	return &projectState, nil
 }
 
+// GetProjectUsage returns the resources consumed by the instances of a project between since and until.
+// Zero values let the server pick the default period.
+func (r *ProtocolIncus) GetProjectUsage(name string, since time.Time, until time.Time) (*api.ProjectUsage, error) {
+	if !r.HasExtension("project_usage_accounting") {
+		return nil, fmt.Errorf("The server is missing the required \"project_usage_accounting\" API extension")
+	}
+
+	v := url.Values{}
+
+	if !since.IsZero() {
+		v.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	if !until.IsZero() {
+		v.Set("until", until.UTC().Format(time.RFC3339))
+	}
+
+	projectUsage := api.ProjectUsage{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/projects/%s/usage?%s", url.PathEscape(name), v.Encode()), nil, "", &projectUsage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectUsage, nil
+}
+
 // GetProjectAccess returns an Access entry for the specified project.
 func (r *ProtocolIncus) GetProjectAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -210,6 +210,10 @@ func (r *ProtocolIncus) CreateStoragePoolVolume(pool string, volume api.StorageV
 		return fmt.Errorf("The server is missing the required \"storage\" API extension")
 	}
 
+	if volume.Source.Type == "backup" && !r.HasExtension("backup_s3_volumes") {
+		return fmt.Errorf(`The server is missing the required "backup_s3_volumes" API extension`)
+	}
+
 	// Send the request
 	path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(pool), url.PathEscape(volume.Type))
 	_, _, err := r.query("POST", path, volume, "")
@@ -300,6 +304,38 @@ func (r *ProtocolIncus) GetStoragePoolVolumeSnapshot(pool string, volumeType str
 	return &snapshot, etag, nil
 }
 
+// GetStoragePoolVolumeSnapshotDiff returns the paths that changed between two snapshots of the storage volume.
+// If fromSnapshotName is empty, the changes made to the volume since the snapshot are returned instead.
+func (r *ProtocolIncus) GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string) ([]api.StorageVolumeSnapshotDiff, error) {
+	if !r.HasExtension("storage_volume_snapshot_diff") {
+		return nil, fmt.Errorf("The server is missing the required \"storage_volume_snapshot_diff\" API extension")
+	}
+
+	diff := []api.StorageVolumeSnapshotDiff{}
+
+	v := url.Values{}
+	if fromSnapshotName != "" {
+		v.Set("from", fromSnapshotName)
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/snapshots/%s/diff",
+		url.PathEscape(pool),
+		url.PathEscape(volumeType),
+		url.PathEscape(volumeName),
+		url.PathEscape(snapshotName))
+
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	_, err := r.queryStruct("GET", path, nil, "", &diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
 // RenameStoragePoolVolumeSnapshot renames a storage volume snapshot.
 func (r *ProtocolIncus) RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (Operation, error) {
 	if !r.HasExtension("storage_api_volume_snapshots") {
@@ -885,6 +921,10 @@ func (r *ProtocolIncus) CreateStorageVolumeBackup(pool string, volName string, b
 		return nil, fmt.Errorf("The server is missing the required \"custom_volume_backup\" API extension")
 	}
 
+	if backup.Target != "" && !r.HasExtension("backup_s3_volumes") {
+		return nil, fmt.Errorf(`The server is missing the required "backup_s3_volumes" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups", url.PathEscape(pool), url.PathEscape(volName)), backup, "")
 	if err != nil {
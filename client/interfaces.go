@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -86,6 +87,25 @@ type InstanceServer interface {
 	UseTarget(name string) (client InstanceServer)
 	UseProject(name string) (client InstanceServer)
 
+	// Audit log functions
+	GetAuditEntries() (entries []api.AuditEntry, err error)
+	GetAuditEntriesWithFilter(filters []string, since time.Time, until time.Time, limit int) (entries []api.AuditEntry, err error)
+	GetAuditEntry(id int64) (entry *api.AuditEntry, err error)
+
+	// Authorization functions
+	GetAuthGroupNames() (names []string, err error)
+	GetAuthGroups() (groups []api.AuthGroup, err error)
+	GetAuthGroup(name string) (group *api.AuthGroup, ETag string, err error)
+	CreateAuthGroup(group api.AuthGroupsPost) (err error)
+	UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) (err error)
+	RenameAuthGroup(name string, group api.AuthGroupPost) (err error)
+	DeleteAuthGroup(name string) (err error)
+	GetIdentities() (identities []api.Identity, err error)
+	GetIdentity(authenticationMethod string, identifier string) (identity *api.Identity, ETag string, err error)
+	CreateIdentity(identity api.IdentitiesPost) (err error)
+	UpdateIdentity(authenticationMethod string, identifier string, identity api.IdentityPut, ETag string) (err error)
+	DeleteIdentity(authenticationMethod string, identifier string) (err error)
+
 	// Certificate functions
 	GetCertificateFingerprints() (fingerprints []string, err error)
 	GetCertificates() (certificates []api.Certificate, err error)
@@ -307,6 +327,7 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	GetProjectUsage(name string, since time.Time, until time.Time) (usage *api.ProjectUsage, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
@@ -367,6 +388,7 @@ type InstanceServer interface {
 	GetStoragePoolVolumeSnapshotNames(pool string, volumeType string, volumeName string) (names []string, err error)
 	GetStoragePoolVolumeSnapshots(pool string, volumeType string, volumeName string) (snapshots []api.StorageVolumeSnapshot, err error)
 	GetStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string) (snapshot *api.StorageVolumeSnapshot, ETag string, err error)
+	GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string) (diff []api.StorageVolumeSnapshotDiff, err error)
 	RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (op Operation, err error)
 	UpdateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, volume api.StorageVolumeSnapshotPut, ETag string) (err error)
 
@@ -426,6 +448,7 @@ type ConnectionInfo struct {
 	SocketPath  string
 	Project     string
 	Target      string
+	TrustedKeys string
 }
 
 // The BackupFileRequest struct is used for a backup download request.
@@ -484,6 +507,10 @@ type ImageFileRequest struct {
 	// Path retriever for image delta downloads
 	// If set, it must return the path to the image file or an empty string if not available
 	DeltaSourceRetriever func(fingerprint string, file string) string
+
+	// Resume the download from the data already present in the target files (only supported by simplestreams)
+	// The target files must then also implement io.Reader and should be truncated to the returned sizes
+	Resume bool
 }
 
 // The ImageFileResponse struct is used as the response for image downloads.
@@ -15,6 +15,7 @@ type ProtocolSimpleStreams struct {
 	httpHost        string
 	httpUserAgent   string
 	httpCertificate string
+	httpTrustedKeys string
 }
 
 // Disconnect is a no-op for simplestreams.
@@ -28,6 +29,7 @@ func (r *ProtocolSimpleStreams) GetConnectionInfo() (*ConnectionInfo, error) {
 	info.Certificate = r.httpCertificate
 	info.Protocol = "simplestreams"
 	info.URL = r.httpHost
+	info.TrustedKeys = r.httpTrustedKeys
 
 	return &info, nil
 }
@@ -108,13 +108,23 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 
 	// Download function
 	download := func(path string, filename string, hash string, target io.WriteSeeker) (int64, error) {
+		downloadFile := func(uri string) (int64, error) {
+			// Resume partial downloads if requested.
+			resumeTarget, ok := target.(io.ReadWriteSeeker)
+			if req.Resume && ok {
+				return util.DownloadFileHashResume(context.TODO(), &httpClient, r.httpUserAgent, req.ProgressHandler, req.Canceler, filename, uri, hash, sha256.New(), resumeTarget)
+			}
+
+			return util.DownloadFileHash(context.TODO(), &httpClient, r.httpUserAgent, req.ProgressHandler, req.Canceler, filename, uri, hash, sha256.New(), target)
+		}
+
 		// Try over http
 		uri, err := url.JoinPath(fmt.Sprintf("http://%s", strings.TrimPrefix(r.httpHost, "https://")), path)
 		if err != nil {
 			return -1, err
 		}
 
-		size, err := util.DownloadFileHash(context.TODO(), &httpClient, r.httpUserAgent, req.ProgressHandler, req.Canceler, filename, uri, hash, sha256.New(), target)
+		size, err := downloadFile(uri)
 		if err != nil {
 			// Handle cancellation
 			if err.Error() == "net/http: request canceled" {
@@ -127,7 +137,7 @@ func (r *ProtocolSimpleStreams) GetImageFile(fingerprint string, req ImageFileRe
 				return -1, err
 			}
 
-			size, err = util.DownloadFileHash(context.TODO(), &httpClient, r.httpUserAgent, req.ProgressHandler, req.Canceler, filename, uri, hash, sha256.New(), target)
+			size, err = downloadFile(uri)
 			if err != nil {
 				if errors.Is(err, util.ErrNotFound) {
 					logger.Info("Unable to download file by hash, invalidate potentially outdated cache", logger.Ctx{"filename": filename, "uri": uri, "hash": hash})
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Filesystem freeze ioctls (FIFREEZE and FITHAW from linux/fs.h), not provided by golang.org/x/sys/unix.
+// They are defined as _IOWR('X', 119, int) and _IOWR('X', 120, int) which have the same value on all architectures.
+const (
+	ioctlFIFREEZE = 0xC0045877
+	ioctlFITHAW   = 0xC0045878
+)
+
+// freezeHooksPath is the directory holding executables run before freezing and after thawing the filesystems.
+const freezeHooksPath = "/etc/incus-agent/freeze-hook.d"
+
+// freezeTimeoutDefault is the number of seconds after which frozen filesystems are thawed unless configured otherwise.
+const freezeTimeoutDefault = 60
+
+var freezeCmd = APIEndpoint{
+	Name: "freeze",
+	Path: "freeze",
+
+	Post:   APIEndpointAction{Handler: freezePost},
+	Delete: APIEndpointAction{Handler: freezeDelete},
+}
+
+// freezeState tracks the currently frozen filesystems.
+var freezeState struct {
+	mu     sync.Mutex
+	frozen []string
+	hooks  []string
+	timer  *time.Timer
+
+	// Whether the last frozen filesystems were thawed after the timeout.
+	expired bool
+}
+
+// freezeIoctl runs a freeze ioctl against the filesystem mounted on the given path.
+var freezeIoctl = freezeIoctlMountPoint
+
+// freezeListMountPoints returns the filesystems to freeze.
+var freezeListMountPoints = freezeMountPoints
+
+func freezePost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.FreezePost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Timeout <= 0 {
+		req.Timeout = freezeTimeoutDefault
+	}
+
+	freezeState.mu.Lock()
+	defer freezeState.mu.Unlock()
+
+	if freezeState.timer != nil {
+		return response.Conflict(errors.New("Filesystems are already frozen"))
+	}
+
+	freezeState.expired = false
+
+	// Let the applications flush their data.
+	hooks, err := freezeHooks()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	for i, hook := range hooks {
+		err = freezeRunHook(hook, "freeze")
+		if err != nil {
+			freezeRunHooks(hooks[:i], "thaw")
+			return response.InternalError(err)
+		}
+	}
+
+	// Freeze the filesystems.
+	mountPoints, err := freezeListMountPoints()
+	if err != nil {
+		freezeRunHooks(hooks, "thaw")
+		return response.InternalError(err)
+	}
+
+	frozen := []string{}
+	for _, mountPoint := range mountPoints {
+		err = freezeIoctl(mountPoint, ioctlFIFREEZE)
+		if err != nil {
+			freezeThawAll(frozen)
+			freezeRunHooks(hooks, "thaw")
+			return response.InternalError(fmt.Errorf("Failed freezing %q: %w", mountPoint, err))
+		}
+
+		frozen = append(frozen, mountPoint)
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(req.Timeout)*time.Second, func() {
+		freezeState.mu.Lock()
+		defer freezeState.mu.Unlock()
+
+		// Skip if the filesystems were thawed in the meantime.
+		if freezeState.timer != timer {
+			return
+		}
+
+		logger.Warn("Thawing filesystems after freeze timeout")
+		freezeThaw()
+		freezeState.expired = true
+	})
+
+	freezeState.frozen = frozen
+	freezeState.hooks = hooks
+	freezeState.timer = timer
+
+	logger.Info("Froze filesystems", logger.Ctx{"mountpoints": frozen})
+
+	return response.EmptySyncResponse
+}
+
+func freezeDelete(d *Daemon, r *http.Request) response.Response {
+	freezeState.mu.Lock()
+	defer freezeState.mu.Unlock()
+
+	// Report that the filesystems didn't remain frozen until now.
+	if freezeState.expired {
+		freezeState.expired = false
+		return response.Conflict(errors.New("Filesystems were thawed after the freeze timeout"))
+	}
+
+	freezeThaw()
+
+	return response.EmptySyncResponse
+}
+
+// freezeThaw thaws the frozen filesystems and runs the hooks.
+// The caller must hold the freezeState lock.
+func freezeThaw() {
+	if freezeState.timer == nil {
+		return
+	}
+
+	freezeState.timer.Stop()
+	freezeThawAll(freezeState.frozen)
+	freezeRunHooks(freezeState.hooks, "thaw")
+
+	logger.Info("Thawed filesystems", logger.Ctx{"mountpoints": freezeState.frozen})
+
+	freezeState.frozen = nil
+	freezeState.hooks = nil
+	freezeState.timer = nil
+}
+
+// freezeThawAll thaws the given filesystems in reverse order.
+func freezeThawAll(mountPoints []string) {
+	for i := len(mountPoints) - 1; i >= 0; i-- {
+		err := freezeIoctl(mountPoints[i], ioctlFITHAW)
+		if err != nil {
+			logger.Error("Failed thawing filesystem", logger.Ctx{"mountpoint": mountPoints[i], "err": err})
+		}
+	}
+}
+
+// freezeIoctlMountPoint runs a freeze ioctl against the filesystem mounted on the given path.
+func freezeIoctlMountPoint(mountPoint string, request uint) error {
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return unix.IoctlSetInt(int(f.Fd()), request, 0)
+}
+
+// freezeMountPoints returns the writable block-backed filesystems, nested mounts first.
+// Filesystems mounted more than once are only listed once.
+func freezeMountPoints() ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return freezeParseMountInfo(f)
+}
+
+// freezeParseMountInfo returns the writable block-backed filesystems found in mountinfo, nested mounts first.
+func freezeParseMountInfo(r io.Reader) ([]string, error) {
+	devices := map[string]bool{}
+	mountPoints := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Format: ID PARENT MAJOR:MINOR ROOT MOUNTPOINT OPTIONS [OPTIONAL...] - FSTYPE SOURCE SUPEROPTIONS
+		fields := strings.Fields(scanner.Text())
+		separator := slices.Index(fields, "-")
+		if separator < 6 || len(fields) < separator+3 {
+			continue
+		}
+
+		source := fields[separator+2]
+		if !strings.HasPrefix(source, "/dev/") {
+			continue
+		}
+
+		if !slices.Contains(strings.Split(fields[5], ","), "rw") {
+			continue
+		}
+
+		if devices[fields[2]] {
+			continue
+		}
+
+		devices[fields[2]] = true
+		mountPoints = append(mountPoints, freezeUnescape(fields[4]))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(mountPoints)
+
+	return mountPoints, nil
+}
+
+// freezeUnescape decodes the octal escapes used for special characters in mountinfo paths.
+func freezeUnescape(path string) string {
+	replacer := strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+	return replacer.Replace(path)
+}
+
+// freezeHooks returns the executables found in the hooks directory, sorted by name.
+func freezeHooks() ([]string, error) {
+	entries, err := os.ReadDir(freezeHooksPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	hooks := []string{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		hooks = append(hooks, filepath.Join(freezeHooksPath, entry.Name()))
+	}
+
+	return hooks, nil
+}
+
+// freezeRunHook runs a single hook with the given action ("freeze" or "thaw").
+func freezeRunHook(hook string, action string) error {
+	output, err := exec.Command(hook, action).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed running %q hook %q: %w (%s)", action, hook, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// freezeRunHooks runs the given hooks in reverse order, logging failures.
+func freezeRunHooks(hooks []string, action string) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		err := freezeRunHook(hooks[i], action)
+		if err != nil {
+			logger.Error("Failed running freeze hook", logger.Ctx{"err": err})
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFreeze replaces the filesystem freeze ioctls and records the calls made.
+type fakeFreeze struct {
+	mu    sync.Mutex
+	calls []string
+	fail  string
+}
+
+func (f *fakeFreeze) ioctl(mountPoint string, request uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	action := "freeze"
+	if request == ioctlFITHAW {
+		action = "thaw"
+	}
+
+	if action == "freeze" && mountPoint == f.fail {
+		return errors.New("Device busy")
+	}
+
+	f.calls = append(f.calls, action+" "+mountPoint)
+
+	return nil
+}
+
+func (f *fakeFreeze) getCalls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls
+}
+
+func setupFakeFreeze(t *testing.T, mountPoints ...string) *fakeFreeze {
+	f := &fakeFreeze{}
+
+	freezeIoctl = f.ioctl
+	freezeListMountPoints = func() ([]string, error) { return mountPoints, nil }
+
+	t.Cleanup(func() {
+		freezeState.mu.Lock()
+		freezeThaw()
+		freezeState.expired = false
+		freezeState.mu.Unlock()
+
+		freezeIoctl = freezeIoctlMountPoint
+		freezeListMountPoints = freezeMountPoints
+	})
+
+	return f
+}
+
+func freezeRequest(method string, body string) int {
+	req := httptest.NewRequest(method, "/1.0/freeze", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler := freezeDelete
+	if method == http.MethodPost {
+		handler = freezePost
+	}
+
+	_ = handler(nil, req).Render(rec)
+
+	return rec.Code
+}
+
+func TestFreezeThaw(t *testing.T) {
+	f := setupFakeFreeze(t, "/srv", "/")
+
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodPost, `{"timeout": 60}`))
+	assert.Equal(t, []string{"freeze /srv", "freeze /"}, f.getCalls())
+
+	// Only a single freeze is allowed at a time.
+	assert.Equal(t, http.StatusConflict, freezeRequest(http.MethodPost, `{"timeout": 60}`))
+
+	// Filesystems are thawed in reverse order.
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodDelete, ""))
+	assert.Equal(t, []string{"freeze /srv", "freeze /", "thaw /", "thaw /srv"}, f.getCalls())
+
+	// Thawing again is a no-op.
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodDelete, ""))
+	assert.Len(t, f.getCalls(), 4)
+}
+
+func TestFreezeFailure(t *testing.T) {
+	f := setupFakeFreeze(t, "/srv", "/home", "/")
+	f.fail = "/home"
+
+	// The filesystems frozen before the failure are thawed again.
+	assert.Equal(t, http.StatusInternalServerError, freezeRequest(http.MethodPost, `{"timeout": 60}`))
+	assert.Equal(t, []string{"freeze /srv", "thaw /srv"}, f.getCalls())
+
+	// Nothing remains frozen.
+	f.fail = ""
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodPost, `{"timeout": 60}`))
+}
+
+func TestFreezeTimeout(t *testing.T) {
+	f := setupFakeFreeze(t, "/")
+
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodPost, `{"timeout": 1}`))
+
+	require.Eventually(t, func() bool { return len(f.getCalls()) == 2 }, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, []string{"freeze /", "thaw /"}, f.getCalls())
+
+	// The thaw request reports that the filesystems didn't remain frozen.
+	assert.Equal(t, http.StatusConflict, freezeRequest(http.MethodDelete, ""))
+
+	// Only once.
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodDelete, ""))
+
+	// A new freeze clears the timeout state.
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodPost, `{"timeout": 1}`))
+	assert.Equal(t, http.StatusOK, freezeRequest(http.MethodDelete, ""))
+}
+
+func TestFreezeParseMountInfo(t *testing.T) {
+	mountInfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+23 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs udev rw
+24 22 8:2 / /mnt/my\040data rw,relatime shared:3 - xfs /dev/sda2 rw
+25 22 8:2 /sub /srv rw,relatime shared:4 - xfs /dev/sda2 rw
+26 22 8:3 / /boot ro,relatime shared:5 - ext4 /dev/sda3 ro
+27 22 0:6 / /run rw,nosuid shared:6 - tmpfs tmpfs rw
+`
+
+	mountPoints, err := freezeParseMountInfo(strings.NewReader(mountInfo))
+	require.NoError(t, err)
+
+	// Nested mounts come first, read-only, virtual and already listed filesystems are skipped.
+	assert.Equal(t, []string{"/mnt/my data", "/"}, mountPoints)
+}
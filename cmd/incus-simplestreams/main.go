@@ -10,8 +10,9 @@ import (
 )
 
 type cmdGlobal struct {
-	flagHelp    bool
-	flagVersion bool
+	flagHelp       bool
+	flagVersion    bool
+	flagSigningKey string
 }
 
 func main() {
@@ -31,6 +32,7 @@ func main() {
 	globalCmd := cmdGlobal{}
 	app.PersistentFlags().BoolVar(&globalCmd.flagVersion, "version", false, "Print version number")
 	app.PersistentFlags().BoolVarP(&globalCmd.flagHelp, "help", "h", false, "Print help")
+	app.PersistentFlags().StringVar(&globalCmd.flagSigningKey, "signing-key", "", "Path to an ASCII armored OpenPGP private key used to sign the index files"+"``")
 
 	// Help handling.
 	app.SetHelpCommand(&cobra.Command{
@@ -54,6 +56,10 @@ func main() {
 	listCmd := cmdList{global: &globalCmd}
 	app.AddCommand(listCmd.Command())
 
+	// mirror sub-command.
+	mirrorCmd := cmdMirror{global: &globalCmd}
+	app.AddCommand(mirrorCmd.Command())
+
 	// remove sub-command.
 	removeCmd := cmdRemove{global: &globalCmd}
 	app.AddCommand(removeCmd.Command())
@@ -439,7 +439,7 @@ func (c *cmdAdd) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Re-generate the index.
-	err = writeIndex(&products)
+	err = writeIndex(&products, c.global.flagSigningKey)
 	if err != nil {
 		return err
 	}
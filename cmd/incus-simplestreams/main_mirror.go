@@ -0,0 +1,467 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/osarch"
+	"github.com/lxc/incus/v6/shared/simplestreams"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// mirrorFileTypes lists the data file types for each image type.
+var mirrorFileTypes = map[string][]string{
+	"container":       {"root.tar.xz", "squashfs", "squashfs.vcdiff"},
+	"virtual-machine": {"disk-kvm.img", "disk-kvm.img.vcdiff"},
+}
+
+// mirrorMetadataFileTypes lists the metadata file types, mirrored for all image types.
+var mirrorMetadataFileTypes = []string{"incus.tar.xz", "incus_combined.tar.gz", "lxd.tar.xz"}
+
+type cmdMirror struct {
+	global *cmdGlobal
+
+	flagArchitectures []string
+	flagKeep          int
+	flagOS            []string
+	flagReleases      []string
+	flagTrustedKeys   string
+	flagTypes         []string
+	flagVariants      []string
+}
+
+// Command generates the command definition.
+func (c *cmdMirror) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "mirror <url>"
+	cmd.Short = "Mirror images from an upstream server"
+	cmd.Long = cli.FormatSection("Description",
+		`Mirror images from an upstream simplestreams server
+
+This command downloads the images of an upstream simplestreams server
+matching the provided filters and adds them to the local index.
+Each filter can be repeated to match multiple values.
+
+All files are checked against the hashes listed in the upstream index.
+Interrupted downloads are resumed on the next run and files already present are skipped.
+
+Only the most recent versions of each product are kept (see "--keep"),
+older ones being removed from the local index along with their files.
+
+The upstream index signature can be verified with "--trusted-keys".
+`)
+	cmd.Example = cli.FormatSection("", `incus-simplestreams mirror https://images.linuxcontainers.org --os=debian --release=12 --architecture=amd64 --variant=cloud
+    Mirror the Debian 12 cloud images for x86_64 (container and virtual-machine).
+
+incus-simplestreams mirror https://images.linuxcontainers.org --os=alpine --type=container --keep=1
+    Mirror the latest version of all Alpine container images.`)
+
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringArrayVar(&c.flagOS, "os", nil, "Only mirror images of this operating system"+"``")
+	cmd.Flags().StringArrayVar(&c.flagReleases, "release", nil, "Only mirror images of this release"+"``")
+	cmd.Flags().StringArrayVar(&c.flagArchitectures, "architecture", nil, "Only mirror images of this architecture"+"``")
+	cmd.Flags().StringArrayVar(&c.flagVariants, "variant", nil, "Only mirror images of this variant"+"``")
+	cmd.Flags().StringArrayVar(&c.flagTypes, "type", nil, "Only mirror images of this type (container or virtual-machine)"+"``")
+	cmd.Flags().IntVar(&c.flagKeep, "keep", 3, "Number of versions to keep for each product (0 to keep all)"+"``")
+	cmd.Flags().StringVar(&c.flagTrustedKeys, "trusted-keys", "", "File with the OpenPGP keys trusted to sign the upstream index"+"``")
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdMirror) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	for _, imageType := range c.flagTypes {
+		_, ok := mirrorFileTypes[imageType]
+		if !ok {
+			return fmt.Errorf("Invalid image type %q", imageType)
+		}
+	}
+
+	if c.flagKeep < 0 {
+		return errors.New("The number of versions to keep can't be negative")
+	}
+
+	// Connect to the upstream server.
+	upstreamURL := strings.TrimSuffix(args[0], "/")
+	httpClient := &http.Client{}
+	upstream := simplestreams.NewClient(upstreamURL, *httpClient, version.UserAgent)
+
+	if c.flagTrustedKeys != "" {
+		content, err := os.ReadFile(c.flagTrustedKeys)
+		if err != nil {
+			return err
+		}
+
+		err = upstream.SetTrustedKeys(string(content))
+		if err != nil {
+			return err
+		}
+	}
+
+	upstreamProducts, err := upstream.GetProducts()
+	if err != nil {
+		return err
+	}
+
+	// Create the paths if missing.
+	err = os.MkdirAll("images", 0o755)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	err = os.MkdirAll("streams/v1", 0o755)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	// Load the images file.
+	products := simplestreams.Products{}
+
+	body, err := os.ReadFile("streams/v1/images.json")
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		// Create a blank images file.
+		products = simplestreams.Products{
+			ContentID: "images",
+			DataType:  "image-downloads",
+			Format:    "products:1.0",
+			Products:  map[string]simplestreams.Product{},
+		}
+	} else {
+		// Parse the existing images file.
+		err = json.Unmarshal(body, &products)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Mirror the matching products.
+	productNames := make([]string, 0, len(upstreamProducts.Products))
+	for name := range upstreamProducts.Products {
+		productNames = append(productNames, name)
+	}
+
+	sort.Strings(productNames)
+
+	removedPaths := []string{}
+	for _, productName := range productNames {
+		upstreamProduct := upstreamProducts.Products[productName]
+		if !c.matchProduct(upstreamProduct) {
+			continue
+		}
+
+		product, ok := products.Products[productName]
+		if !ok {
+			product.Versions = map[string]simplestreams.ProductVersion{}
+		}
+
+		// Refresh the product details from upstream.
+		versions := product.Versions
+		product = upstreamProduct
+		product.Versions = versions
+
+		// Select the versions to mirror, most recent first.
+		upstreamVersionNames := make([]string, 0, len(upstreamProduct.Versions))
+		for versionName, upstreamVersion := range upstreamProduct.Versions {
+			if len(c.filterItems(upstreamVersion, nil).Items) == 0 {
+				continue
+			}
+
+			upstreamVersionNames = append(upstreamVersionNames, versionName)
+		}
+
+		sort.Sort(sort.Reverse(sort.StringSlice(upstreamVersionNames)))
+
+		if c.flagKeep > 0 && len(upstreamVersionNames) > c.flagKeep {
+			upstreamVersionNames = upstreamVersionNames[:c.flagKeep]
+		}
+
+		keptVersionNames := c.keptVersions(product.Versions, upstreamVersionNames)
+
+		for _, versionName := range upstreamVersionNames {
+			if !slices.Contains(keptVersionNames, versionName) {
+				continue
+			}
+
+			upstreamVersion := c.filterItems(upstreamProduct.Versions[versionName], keptVersionNames)
+			if len(upstreamVersion.Items) == 0 {
+				continue
+			}
+
+			localVersion, ok := product.Versions[versionName]
+			if !ok {
+				localVersion = upstreamVersion
+				localVersion.Items = map[string]simplestreams.ProductVersionItem{}
+			}
+
+			// Files already present are skipped, missing ones are fetched again.
+			for itemName, item := range upstreamVersion.Items {
+				targetPath := fmt.Sprintf("images/%s.%s", item.HashSha256, item.FileType)
+				err = c.download(httpClient, upstreamURL, item, targetPath, fmt.Sprintf("%s:%s:%s", productName, versionName, itemName))
+				if err != nil {
+					return err
+				}
+
+				item.Path = targetPath
+				localVersion.Items[itemName] = item
+			}
+
+			product.Versions[versionName] = localVersion
+		}
+
+		// Remove the versions that are no longer kept.
+		for versionName, localVersion := range product.Versions {
+			if slices.Contains(keptVersionNames, versionName) {
+				continue
+			}
+
+			for _, item := range localVersion.Items {
+				removedPaths = append(removedPaths, item.Path)
+			}
+
+			delete(product.Versions, versionName)
+			fmt.Printf("Removed %s:%s\n", productName, versionName)
+		}
+
+		// Remove the deltas against versions that are no longer kept.
+		for _, localVersion := range product.Versions {
+			for itemName, item := range localVersion.Items {
+				_, ok := product.Versions[item.DeltaBase]
+				if item.DeltaBase == "" || ok {
+					continue
+				}
+
+				removedPaths = append(removedPaths, item.Path)
+				delete(localVersion.Items, itemName)
+			}
+		}
+
+		if len(product.Versions) == 0 {
+			delete(products.Products, productName)
+			continue
+		}
+
+		products.Products[productName] = product
+	}
+
+	// Write back the images file.
+	body, err = json.Marshal(&products)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile("streams/v1/images.json", body, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// Re-generate the index.
+	err = writeIndex(&products, c.global.flagSigningKey)
+	if err != nil {
+		return err
+	}
+
+	// Remove the files that are no longer referenced.
+	referencedPaths := map[string]bool{}
+	for _, product := range products.Products {
+		for _, version := range product.Versions {
+			for _, item := range version.Items {
+				referencedPaths[item.Path] = true
+			}
+		}
+	}
+
+	for _, path := range removedPaths {
+		if referencedPaths[path] {
+			continue
+		}
+
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchProduct returns whether the product matches the filters.
+func (c *cmdMirror) matchProduct(product simplestreams.Product) bool {
+	match := func(filter []string, value string) bool {
+		return len(filter) == 0 || slices.ContainsFunc(filter, func(entry string) bool { return strings.EqualFold(entry, value) })
+	}
+
+	if !match(c.flagOS, product.OperatingSystem) || !match(c.flagReleases, product.Release) || !match(c.flagVariants, product.Variant) {
+		return false
+	}
+
+	if len(c.flagArchitectures) == 0 {
+		return true
+	}
+
+	// Compare the architectures by ID so that aliases (like "amd64" and "x86_64") match.
+	productArchID, err := osarch.ArchitectureID(product.Architecture)
+	for _, architecture := range c.flagArchitectures {
+		archID, archErr := osarch.ArchitectureID(architecture)
+		if (err == nil && archErr == nil && archID == productArchID) || strings.EqualFold(architecture, product.Architecture) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keptVersions returns the names of the versions kept in the local index, combining the existing and mirrored versions.
+func (c *cmdMirror) keptVersions(localVersions map[string]simplestreams.ProductVersion, upstreamVersionNames []string) []string {
+	versionNames := slices.Clone(upstreamVersionNames)
+	for versionName := range localVersions {
+		if !slices.Contains(versionNames, versionName) {
+			versionNames = append(versionNames, versionName)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(versionNames)))
+
+	if c.flagKeep > 0 && len(versionNames) > c.flagKeep {
+		versionNames = versionNames[:c.flagKeep]
+	}
+
+	return versionNames
+}
+
+// filterItems returns the version with only the items to mirror.
+// No item is returned if the version doesn't have any data file of the requested types.
+func (c *cmdMirror) filterItems(version simplestreams.ProductVersion, keptVersionNames []string) simplestreams.ProductVersion {
+	imageTypes := c.flagTypes
+	if len(imageTypes) == 0 {
+		imageTypes = []string{"container", "virtual-machine"}
+	}
+
+	fileTypes := []string{}
+	for _, imageType := range imageTypes {
+		fileTypes = append(fileTypes, mirrorFileTypes[imageType]...)
+	}
+
+	filtered := version
+	filtered.Items = map[string]simplestreams.ProductVersionItem{}
+
+	hasData := false
+	for itemName, item := range version.Items {
+		// Only mirror files that can be verified and safely stored.
+		_, err := hex.DecodeString(item.HashSha256)
+		if err != nil || len(item.HashSha256) != 64 || strings.ContainsAny(item.FileType, `/\`) {
+			continue
+		}
+
+		if slices.Contains(mirrorMetadataFileTypes, item.FileType) {
+			// Unified images hold both the metadata and the data.
+			if item.FileType == "incus_combined.tar.gz" {
+				hasData = true
+			}
+
+			filtered.Items[itemName] = item
+			continue
+		}
+
+		if !slices.Contains(fileTypes, item.FileType) {
+			continue
+		}
+
+		// Deltas are only useful if their base version is mirrored too.
+		if strings.HasSuffix(item.FileType, ".vcdiff") {
+			if !slices.Contains(keptVersionNames, item.DeltaBase) {
+				continue
+			}
+		} else {
+			hasData = true
+		}
+
+		filtered.Items[itemName] = item
+	}
+
+	if !hasData {
+		filtered.Items = map[string]simplestreams.ProductVersionItem{}
+	}
+
+	return filtered
+}
+
+// download retrieves a file from the upstream server unless already present, checking its hash.
+// Partially downloaded files are resumed.
+func (c *cmdMirror) download(httpClient *http.Client, upstreamURL string, item simplestreams.ProductVersionItem, targetPath string, name string) error {
+	// Files are only moved in place once their hash was verified.
+	info, err := os.Stat(targetPath)
+	if err == nil && info.Size() == item.Size {
+		return nil
+	}
+
+	uri, err := url.JoinPath(upstreamURL, item.Path)
+	if err != nil {
+		return err
+	}
+
+	partialPath := targetPath + ".partial"
+	target, err := os.OpenFile(partialPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = target.Close() }()
+
+	progress := cli.ProgressRenderer{Format: "Downloading " + name + ": %s"}
+	size, err := util.DownloadFileHashResume(context.TODO(), httpClient, version.UserAgent, progress.UpdateProgress, nil, "", uri, item.HashSha256, sha256.New(), target)
+	if err != nil {
+		progress.Done("")
+
+		// Start over next time if the data is corrupted.
+		if errors.Is(err, util.ErrHashMismatch) {
+			_ = os.Remove(partialPath)
+		}
+
+		return fmt.Errorf("Failed downloading %q: %w", uri, err)
+	}
+
+	err = target.Truncate(size)
+	if err != nil {
+		return err
+	}
+
+	err = target.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(partialPath, targetPath)
+	if err != nil {
+		return err
+	}
+
+	progress.Done("Downloaded " + name)
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/simplestreams"
+)
+
+// testUpstream is a simplestreams server serving in-memory products and files.
+type testUpstream struct {
+	mu       sync.Mutex
+	products simplestreams.Products
+	files    map[string][]byte
+	requests []string
+}
+
+func newTestUpstream(t *testing.T) (*testUpstream, string) {
+	upstream := &testUpstream{
+		products: simplestreams.Products{
+			ContentID: "images",
+			DataType:  "image-downloads",
+			Format:    "products:1.0",
+			Products:  map[string]simplestreams.Product{},
+		},
+		files: map[string][]byte{},
+	}
+
+	server := httptest.NewServer(upstream)
+	t.Cleanup(server.Close)
+
+	return upstream, server.URL
+}
+
+func (u *testUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	u.requests = append(u.requests, path+" "+r.Header.Get("Range"))
+
+	var body []byte
+	switch path {
+	case "streams/v1/index.json":
+		productNames := []string{}
+		for name := range u.products.Products {
+			productNames = append(productNames, name)
+		}
+
+		body, _ = json.Marshal(simplestreams.Stream{
+			Format: "index:1.0",
+			Index: map[string]simplestreams.StreamIndex{
+				"images": {DataType: "image-downloads", Path: "streams/v1/images.json", Format: "products:1.0", Products: productNames},
+			},
+		})
+	case "streams/v1/images.json":
+		body, _ = json.Marshal(u.products)
+	default:
+		data, ok := u.files[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		body = data
+	}
+
+	http.ServeContent(w, r, path, time.Time{}, strings.NewReader(string(body)))
+}
+
+// addVersion adds a version of a product with an item for each of the file types.
+// Items with a ".vcdiff" file type are deltas against the given base version.
+func (u *testUpstream) addVersion(productName string, versionName string, deltaBase string, fileTypes ...string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	fields := strings.Split(productName, ":")
+	product, ok := u.products.Products[productName]
+	if !ok {
+		product = simplestreams.Product{
+			OperatingSystem: fields[0],
+			Release:         fields[1],
+			Architecture:    fields[2],
+			Variant:         fields[3],
+			Versions:        map[string]simplestreams.ProductVersion{},
+		}
+	}
+
+	version := simplestreams.ProductVersion{Items: map[string]simplestreams.ProductVersionItem{}}
+	for _, fileType := range fileTypes {
+		data := []byte(fmt.Sprintf("%s %s %s", productName, versionName, fileType))
+		path := fmt.Sprintf("images/%s/%s/%s", strings.ReplaceAll(productName, ":", "/"), versionName, fileType)
+		u.files[path] = data
+
+		item := simplestreams.ProductVersionItem{
+			FileType:   fileType,
+			Path:       path,
+			HashSha256: fmt.Sprintf("%x", sha256.Sum256(data)),
+			Size:       int64(len(data)),
+		}
+
+		if strings.HasSuffix(fileType, ".vcdiff") {
+			item.DeltaBase = deltaBase
+		}
+
+		version.Items[fileType] = item
+	}
+
+	product.Versions[versionName] = version
+	u.products.Products[productName] = product
+}
+
+// mirror runs the mirror command in the current directory.
+func mirror(upstreamURL string, args ...string) error {
+	c := &cmdMirror{global: &cmdGlobal{}}
+	cmd := c.Command()
+	cmd.SetArgs(append(args, upstreamURL))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	return cmd.Execute()
+}
+
+// localVersions returns the item file types of each version of the local products.
+func localVersions(t *testing.T) map[string]map[string][]string {
+	body, err := os.ReadFile("streams/v1/images.json")
+	require.NoError(t, err)
+
+	products := simplestreams.Products{}
+	require.NoError(t, json.Unmarshal(body, &products))
+
+	result := map[string]map[string][]string{}
+	for productName, product := range products.Products {
+		result[productName] = map[string][]string{}
+		for versionName, version := range product.Versions {
+			fileTypes := []string{}
+			for _, item := range version.Items {
+				// Mirrored files are stored locally under their hash.
+				assert.Equal(t, fmt.Sprintf("images/%s.%s", item.HashSha256, item.FileType), item.Path)
+				assert.FileExists(t, item.Path)
+				fileTypes = append(fileTypes, item.FileType)
+			}
+
+			sort.Strings(fileTypes)
+			result[productName][versionName] = fileTypes
+		}
+	}
+
+	return result
+}
+
+// chdirTemp moves to a new temporary directory for the duration of the test.
+func chdirTemp(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+}
+
+func TestMirror(t *testing.T) {
+	chdirTemp(t)
+	upstream, upstreamURL := newTestUpstream(t)
+
+	upstream.addVersion("debian:12:amd64:cloud", "20240101_00:00", "", "incus.tar.xz", "squashfs", "disk-kvm.img")
+	upstream.addVersion("debian:12:amd64:cloud", "20240102_00:00", "20240101_00:00", "incus.tar.xz", "squashfs", "squashfs.vcdiff", "disk-kvm.img")
+	upstream.addVersion("debian:12:amd64:cloud", "20240103_00:00", "20240102_00:00", "incus.tar.xz", "squashfs", "squashfs.vcdiff", "disk-kvm.img")
+	upstream.addVersion("debian:12:arm64:cloud", "20240103_00:00", "", "incus.tar.xz", "root.tar.xz")
+	upstream.addVersion("alpine:3.19:amd64:default", "20240103_00:00", "", "incus.tar.xz", "root.tar.xz")
+
+	// Only the matching products, types and most recent versions are mirrored,
+	// with the deltas against the mirrored versions.
+	err := mirror(upstreamURL, "--os=debian", "--architecture=x86_64", "--type=container", "--keep=2")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[string][]string{
+		"debian:12:amd64:cloud": {
+			"20240102_00:00": {"incus.tar.xz", "squashfs"},
+			"20240103_00:00": {"incus.tar.xz", "squashfs", "squashfs.vcdiff"},
+		},
+	}, localVersions(t))
+
+	assert.FileExists(t, "streams/v1/index.json")
+
+	entries, err := os.ReadDir("images")
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	// Files already present aren't downloaded again.
+	upstream.requests = nil
+
+	err = mirror(upstreamURL, "--os=debian", "--architecture=x86_64", "--type=container", "--keep=2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"streams/v1/index.json ", "streams/v1/images.json "}, upstream.requests)
+
+	// Older versions are removed along with their files and the deltas against them.
+	upstream.addVersion("debian:12:amd64:cloud", "20240104_00:00", "20240103_00:00", "incus.tar.xz", "squashfs", "squashfs.vcdiff", "disk-kvm.img")
+
+	err = mirror(upstreamURL, "--os=debian", "--architecture=x86_64", "--type=container", "--keep=2")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[string][]string{
+		"debian:12:amd64:cloud": {
+			"20240103_00:00": {"incus.tar.xz", "squashfs"},
+			"20240104_00:00": {"incus.tar.xz", "squashfs", "squashfs.vcdiff"},
+		},
+	}, localVersions(t))
+
+	entries, err = os.ReadDir("images")
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+func TestMirrorResume(t *testing.T) {
+	chdirTemp(t)
+	upstream, upstreamURL := newTestUpstream(t)
+
+	upstream.addVersion("alpine:3.19:amd64:default", "20240101_00:00", "", "incus.tar.xz", "root.tar.xz")
+	item := upstream.products.Products["alpine:3.19:amd64:default"].Versions["20240101_00:00"].Items["root.tar.xz"]
+
+	// Partially downloaded files are resumed.
+	targetPath := fmt.Sprintf("images/%s.%s", item.HashSha256, item.FileType)
+	require.NoError(t, os.MkdirAll("images", 0o755))
+	require.NoError(t, os.WriteFile(targetPath+".partial", upstream.files[item.Path][:10], 0o644))
+
+	err := mirror(upstreamURL)
+	require.NoError(t, err)
+	assert.Contains(t, upstream.requests, item.Path+" bytes=10-")
+	assert.NoFileExists(t, targetPath+".partial")
+
+	data, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+	assert.Equal(t, upstream.files[item.Path], data)
+}
+
+func TestMirrorHashMismatch(t *testing.T) {
+	chdirTemp(t)
+	upstream, upstreamURL := newTestUpstream(t)
+
+	upstream.addVersion("alpine:3.19:amd64:default", "20240101_00:00", "", "incus.tar.xz", "root.tar.xz")
+	item := upstream.products.Products["alpine:3.19:amd64:default"].Versions["20240101_00:00"].Items["root.tar.xz"]
+	upstream.files[item.Path] = []byte("corrupted")
+
+	// Corrupted files are rejected and downloaded from scratch next time.
+	err := mirror(upstreamURL)
+	assert.ErrorContains(t, err, "Hash mismatch")
+
+	targetPath := filepath.Join("images", fmt.Sprintf("%s.%s", item.HashSha256, item.FileType))
+	assert.NoFileExists(t, targetPath)
+	assert.NoFileExists(t, targetPath+".partial")
+}
+
+func TestMirrorInvalidFlags(t *testing.T) {
+	chdirTemp(t)
+
+	assert.ErrorContains(t, mirror("https://images.example.net", "--type=vm"), `Invalid image type "vm"`)
+	assert.ErrorContains(t, mirror("https://images.example.net", "--keep=-1"), "can't be negative")
+}
+
+func TestMirrorMatchProduct(t *testing.T) {
+	product := simplestreams.Product{OperatingSystem: "Debian", Release: "12", Architecture: "amd64", Variant: "cloud"}
+
+	tests := []struct {
+		name  string
+		c     cmdMirror
+		match bool
+	}{
+		{name: "No filter", c: cmdMirror{}, match: true},
+		{name: "Matching OS", c: cmdMirror{flagOS: []string{"alpine", "debian"}}, match: true},
+		{name: "Other OS", c: cmdMirror{flagOS: []string{"alpine"}}, match: false},
+		{name: "Matching release and variant", c: cmdMirror{flagReleases: []string{"12"}, flagVariants: []string{"cloud"}}, match: true},
+		{name: "Other variant", c: cmdMirror{flagVariants: []string{"default"}}, match: false},
+		{name: "Matching architecture", c: cmdMirror{flagArchitectures: []string{"amd64"}}, match: true},
+		{name: "Architecture alias", c: cmdMirror{flagArchitectures: []string{"x86_64"}}, match: true},
+		{name: "Other architecture", c: cmdMirror{flagArchitectures: []string{"arm64"}}, match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.c.matchProduct(product))
+		})
+	}
+}
+
+func TestMirrorFilterItems(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+
+	version := simplestreams.ProductVersion{Items: map[string]simplestreams.ProductVersionItem{
+		"incus.tar.xz":    {FileType: "incus.tar.xz", HashSha256: hash},
+		"root.tar.xz":     {FileType: "root.tar.xz", HashSha256: hash},
+		"squashfs.vcdiff": {FileType: "squashfs.vcdiff", HashSha256: hash, DeltaBase: "20240101_00:00"},
+		"disk-kvm.img":    {FileType: "disk-kvm.img", HashSha256: hash},
+		"unknown":         {FileType: "unknown", HashSha256: hash},
+		"no-hash":         {FileType: "squashfs"},
+		"bad-type":        {FileType: "../squashfs", HashSha256: hash},
+	}}
+
+	itemNames := func(version simplestreams.ProductVersion) []string {
+		names := []string{}
+		for name := range version.Items {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		return names
+	}
+
+	c := cmdMirror{}
+	assert.Equal(t, []string{"disk-kvm.img", "incus.tar.xz", "root.tar.xz"}, itemNames(c.filterItems(version, nil)))
+	assert.Equal(t, []string{"disk-kvm.img", "incus.tar.xz", "root.tar.xz", "squashfs.vcdiff"}, itemNames(c.filterItems(version, []string{"20240101_00:00"})))
+
+	c = cmdMirror{flagTypes: []string{"virtual-machine"}}
+	assert.Equal(t, []string{"disk-kvm.img", "incus.tar.xz"}, itemNames(c.filterItems(version, nil)))
+
+	// Versions without data files of the requested types are skipped.
+	delete(version.Items, "disk-kvm.img")
+	assert.Empty(t, c.filterItems(version, nil).Items)
+
+	// Unified images hold their own data.
+	unified := simplestreams.ProductVersion{Items: map[string]simplestreams.ProductVersionItem{
+		"incus_combined.tar.gz": {FileType: "incus_combined.tar.gz", HashSha256: hash},
+	}}
+
+	assert.Equal(t, []string{"incus_combined.tar.gz"}, itemNames(c.filterItems(unified, nil)))
+}
+
+func TestMirrorKeptVersions(t *testing.T) {
+	local := map[string]simplestreams.ProductVersion{
+		"20240101_00:00": {},
+		"20240103_00:00": {},
+	}
+
+	// Local versions count towards the versions to keep.
+	c := cmdMirror{flagKeep: 2}
+	assert.Equal(t, []string{"20240104_00:00", "20240103_00:00"}, c.keptVersions(local, []string{"20240104_00:00", "20240102_00:00"}))
+
+	c = cmdMirror{flagKeep: 0}
+	assert.Equal(t, []string{"20240104_00:00", "20240103_00:00", "20240102_00:00", "20240101_00:00"}, c.keptVersions(local, []string{"20240104_00:00", "20240102_00:00"}))
+}
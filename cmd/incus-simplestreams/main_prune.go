@@ -202,7 +202,7 @@ func (c *cmdPrune) prune() error {
 		}
 
 		// Re-generate the index.
-		err = writeIndex(&products)
+		err = writeIndex(&products, c.global.flagSigningKey)
 		if err != nil {
 			return err
 		}
@@ -161,7 +161,7 @@ func (c *cmdRemove) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Re-generate the index.
-	err = writeIndex(&products)
+	err = writeIndex(&products, c.global.flagSigningKey)
 	if err != nil {
 		return err
 	}
@@ -2,12 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/lxc/incus/v6/shared/simplestreams"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
-func writeIndex(products *simplestreams.Products) error {
+func writeIndex(products *simplestreams.Products, signingKey string) error {
 	// Update the product list.
 	productNames := make([]string, 0, len(products.Products))
 	for name := range products.Products {
@@ -37,5 +40,60 @@ func writeIndex(products *simplestreams.Products) error {
 		return err
 	}
 
+	return signIndex(signingKey, "streams/v1/index.json", "streams/v1/images.json")
+}
+
+// signIndex writes the signed variant of the index files using the given private key file.
+// Without a key, existing signed files are removed as they would otherwise become outdated.
+func signIndex(signingKey string, paths ...string) error {
+	if signingKey == "" {
+		for _, path := range paths {
+			signedPath := simplestreams.SignedPath(path)
+			if !util.PathExists(signedPath) {
+				continue
+			}
+
+			err := os.Remove(signedPath)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Removed outdated signed index %q, use --signing-key to sign the index\n", signedPath)
+		}
+
+		return nil
+	}
+
+	content, err := os.ReadFile(signingKey)
+	if err != nil {
+		return err
+	}
+
+	keyring, err := simplestreams.ParseKeyring(string(content))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return err
+		}
+
+		signed, err := simplestreams.Sign(body, keyring)
+		if err != nil {
+			return fmt.Errorf("Failed signing %q: %w", path, err)
+		}
+
+		err = os.WriteFile(simplestreams.SignedPath(path), signed, 0o644)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -146,6 +146,8 @@ type cmdAction struct {
 	flagStateful  bool
 	flagStateless bool
 	flagTimeout   int
+
+	flagWithDependencies bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -160,6 +162,7 @@ func (c *cmdAction) Command(action string) *cobra.Command {
 		cmd.Flags().BoolVar(&c.flagStateful, "stateful", false, i18n.G("Store the instance state"))
 	case "start":
 		cmd.Flags().BoolVar(&c.flagStateless, "stateless", false, i18n.G("Ignore the instance state"))
+		cmd.Flags().BoolVar(&c.flagWithDependencies, "with-dependencies", false, i18n.G("Start the instances listed in boot.depends_on first"))
 	}
 
 	if slices.Contains([]string{"start", "restart", "stop"}, action) {
@@ -274,6 +277,10 @@ func (c *cmdAction) doAction(action string, conf *config.Config, nameArg string)
 		return fmt.Errorf(i18n.G("Must supply instance name for: ")+"\"%s\"", nameArg)
 	}
 
+	if c.flagWithDependencies && !d.HasExtension("instance_boot_dependencies") {
+		return errors.New(i18n.G("The server doesn't support starting instance dependencies"))
+	}
+
 	if action == "start" {
 		current, _, err := d.GetInstance(name)
 		if err != nil {
@@ -292,10 +299,11 @@ func (c *cmdAction) doAction(action string, conf *config.Config, nameArg string)
 	}
 
 	req := api.InstanceStatePut{
-		Action:   action,
-		Timeout:  c.flagTimeout,
-		Force:    c.flagForce,
-		Stateful: state,
+		Action:           action,
+		Timeout:          c.flagTimeout,
+		Force:            c.flagForce,
+		Stateful:         state,
+		WithDependencies: action == "start" && c.flagWithDependencies,
 	}
 
 	op, err := d.UpdateInstanceState(name, req, "")
@@ -366,6 +374,10 @@ func (c *cmdAction) doAction(action string, conf *config.Config, nameArg string)
 func (c *cmdAction) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
 
+	if c.flagWithDependencies && c.flagAll {
+		return errors.New(i18n.G("--with-dependencies can't be used with --all"))
+	}
+
 	var names []string
 	if c.flagAll {
 		// If no server passed, use current default.
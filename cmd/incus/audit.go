@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type auditColumn struct {
+	Name string
+	Data func(api.AuditEntry) string
+}
+
+type cmdAudit struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAudit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("audit")
+	cmd.Short = i18n.G("Query the audit log")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Query the audit log`))
+
+	// List
+	auditListCmd := cmdAuditList{global: c.global, audit: c}
+	cmd.AddCommand(auditListCmd.Command())
+
+	// Show
+	auditShowCmd := cmdAuditShow{global: c.global, audit: c}
+	cmd.AddCommand(auditShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parseAuditTime parses either an absolute RFC3339 time or a duration relative to now.
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return time.Now().Add(-duration), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(i18n.G("Invalid time %q, expected a RFC3339 time or a duration"), value)
+	}
+
+	return t, nil
+}
+
+// List.
+type cmdAuditList struct {
+	global *cmdGlobal
+	audit  *cmdAudit
+
+	flagColumns string
+	flagFormat  string
+	flagSince   string
+	flagUntil   string
+	flagLimit   int
+}
+
+const defaultAuditColumns = "idLumUse"
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuditList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:] [<filter>...]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List audit log entries")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List audit log entries
+
+Filters may be of the <key>=<value> form for any property of the entries,
+for example username=alice or method=DELETE.
+
+The --since and --until options take either a RFC3339 time or a duration
+relative to now, for example 24h.
+
+Only the most recent entries are shown, up to --limit (1000 by default).
+
+The -c option takes a (optionally comma-separated) list of arguments
+that control which entry attributes to output when displaying in table
+or csv format.
+
+Default column layout is: idLumUse
+
+Column shorthand chars:
+
+    a - Source address
+    d - Date
+    e - Error
+    E - Entity
+    i - ID
+    L - Location
+    m - Method
+    o - Operation ID
+    O - Operation status
+    p - Protocol
+    s - Status code
+    u - Username
+    U - URL`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus audit list --since 24h
+    List the changes made over the last day
+
+incus audit list username=alice method=DELETE
+    List the deletions made by alice`))
+
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultAuditColumns, i18n.G("Columns")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVar(&c.flagSince, "since", "", i18n.G("Only show entries recorded at or after this time")+"``")
+	cmd.Flags().StringVar(&c.flagUntil, "until", "", i18n.G("Only show entries recorded before this time")+"``")
+	cmd.Flags().IntVar(&c.flagLimit, "limit", 0, i18n.G("Maximum number of entries to show")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuditList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote and filters.
+	remote := ""
+	filters := []string{}
+
+	if len(args) != 0 {
+		filters = args
+		if strings.Contains(args[0], ":") && !strings.Contains(args[0], "=") {
+			remote = args[0]
+			filters = args[1:]
+		}
+	}
+
+	for _, filter := range filters {
+		if !strings.Contains(filter, "=") {
+			return fmt.Errorf(i18n.G("Invalid filter %q, expected <key>=<value>"), filter)
+		}
+	}
+
+	since, err := parseAuditTime(c.flagSince)
+	if err != nil {
+		return err
+	}
+
+	until, err := parseAuditTime(c.flagUntil)
+	if err != nil {
+		return err
+	}
+
+	if c.flagLimit < 0 {
+		return errors.New(i18n.G("The limit can't be negative"))
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	entries, err := resource.server.GetAuditEntriesWithFilter(filters, since, until, c.flagLimit)
+	if err != nil {
+		return err
+	}
+
+	// Process the columns
+	columns, err := c.parseColumns(resource.server.IsClustered())
+	if err != nil {
+		return err
+	}
+
+	// Render the table, keeping the entries in the order they were recorded.
+	data := [][]string{}
+	for _, entry := range entries {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, column.Data(entry))
+		}
+
+		data = append(data, row)
+	}
+
+	rawData := make([]*api.AuditEntry, len(entries))
+	for i := range entries {
+		rawData[i] = &entries[i]
+	}
+
+	headers := []string{}
+	for _, column := range columns {
+		headers = append(headers, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, headers, data, rawData)
+}
+
+func (c *cmdAuditList) parseColumns(clustered bool) ([]auditColumn, error) {
+	columnsShorthandMap := map[rune]auditColumn{
+		'a': {i18n.G("ADDRESS"), func(entry api.AuditEntry) string { return entry.Address }},
+		'd': {i18n.G("DATE"), func(entry api.AuditEntry) string { return entry.Date.Local().Format(dateLayout) }},
+		'e': {i18n.G("ERROR"), func(entry api.AuditEntry) string { return entry.Error }},
+		'E': {i18n.G("ENTITY"), func(entry api.AuditEntry) string { return entry.Entity }},
+		'i': {i18n.G("ID"), func(entry api.AuditEntry) string { return strconv.FormatInt(entry.ID, 10) }},
+		'm': {i18n.G("METHOD"), func(entry api.AuditEntry) string { return entry.Method }},
+		'o': {i18n.G("OPERATION"), func(entry api.AuditEntry) string { return entry.OperationID }},
+		'O': {i18n.G("OPERATION STATUS"), func(entry api.AuditEntry) string { return entry.OperationStatus }},
+		'p': {i18n.G("PROTOCOL"), func(entry api.AuditEntry) string { return entry.Protocol }},
+		's': {i18n.G("STATUS"), func(entry api.AuditEntry) string { return strconv.Itoa(entry.StatusCode) }},
+		'u': {i18n.G("USERNAME"), func(entry api.AuditEntry) string { return entry.Username }},
+		'U': {i18n.G("URL"), func(entry api.AuditEntry) string { return entry.URL }},
+	}
+
+	if clustered {
+		columnsShorthandMap['L'] = auditColumn{i18n.G("LOCATION"), func(entry api.AuditEntry) string { return entry.Location }}
+	} else {
+		if c.flagColumns != defaultAuditColumns {
+			if strings.ContainsAny(c.flagColumns, "L") {
+				return nil, errors.New(i18n.G("Can't specify column L when not clustered"))
+			}
+		}
+
+		c.flagColumns = strings.ReplaceAll(c.flagColumns, "L", "")
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+
+	columns := []auditColumn{}
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// Show.
+type cmdAuditShow struct {
+	global *cmdGlobal
+	audit  *cmdAudit
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuditShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<id>"))
+	cmd.Short = i18n.G("Show audit log entries")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show audit log entries`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuditShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, idStr, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid audit log entry ID %q"), idStr)
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	entry, err := remoteServer.GetAuditEntry(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
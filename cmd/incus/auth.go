@@ -0,0 +1,1291 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdAuth struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuth) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("auth")
+	cmd.Short = i18n.G("Manage built-in authorization")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the groups and identities of the built-in authorization driver`))
+
+	// Group
+	authGroupCmd := cmdAuthGroup{global: c.global}
+	cmd.AddCommand(authGroupCmd.Command())
+
+	// Identity
+	authIdentityCmd := cmdAuthIdentity{global: c.global}
+	cmd.AddCommand(authIdentityCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group.
+type cmdAuthGroup struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("group")
+	cmd.Short = i18n.G("Manage authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authorization groups`))
+
+	// Create
+	authGroupCreateCmd := cmdAuthGroupCreate{global: c.global}
+	cmd.AddCommand(authGroupCreateCmd.Command())
+
+	// Delete
+	authGroupDeleteCmd := cmdAuthGroupDelete{global: c.global}
+	cmd.AddCommand(authGroupDeleteCmd.Command())
+
+	// Edit
+	authGroupEditCmd := cmdAuthGroupEdit{global: c.global}
+	cmd.AddCommand(authGroupEditCmd.Command())
+
+	// List
+	authGroupListCmd := cmdAuthGroupList{global: c.global}
+	cmd.AddCommand(authGroupListCmd.Command())
+
+	// Permission
+	authGroupPermissionCmd := cmdAuthGroupPermission{global: c.global}
+	cmd.AddCommand(authGroupPermissionCmd.Command())
+
+	// Rename
+	authGroupRenameCmd := cmdAuthGroupRename{global: c.global}
+	cmd.AddCommand(authGroupRenameCmd.Command())
+
+	// Show
+	authGroupShowCmd := cmdAuthGroupShow{global: c.global}
+	cmd.AddCommand(authGroupShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdAuthGroupCreate struct {
+	global *cmdGlobal
+
+	flagDescription string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Create an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create an authorization group`))
+
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group create operators
+
+incus auth group create operators < group.yaml
+    Create an authorization group with permissions from group.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Group description")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.AuthGroupPut
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	// Create the group
+	group := api.AuthGroupsPost{
+		Name:         resource.name,
+		AuthGroupPut: stdinData,
+	}
+
+	if c.flagDescription != "" {
+		group.Description = c.flagDescription
+	}
+
+	err = resource.server.CreateAuthGroup(group)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthGroupDelete struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<group>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete an authorization group`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	// Delete the group
+	err = resource.server.DeleteAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthGroupEdit struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Edit an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit an authorization group`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthGroupPut{}
+
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthGroup(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(group.Writable())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthGroupPut{}
+
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthGroup(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Returns a string explaining the expected YAML structure for an authorization group.
+func (c *cmdAuthGroupEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the authorization group.
+### Any line starting with a '# will be ignored.
+###
+### A sample group looks like:
+### description: Operators
+### permissions:
+### - entitlement: can_exec
+###   object: project:default
+### oidc_groups:
+### - incus-operators`)
+}
+
+// List.
+type cmdAuthGroupList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the authorization groups`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	groups, err := resource.server.GetAuthGroups()
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := [][]string{}
+	for _, group := range groups {
+		data = append(data, []string{group.Name, group.Description, fmt.Sprintf("%d", len(group.Permissions)), fmt.Sprintf("%d", len(group.Identities))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PERMISSIONS"),
+		i18n.G("IDENTITIES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, groups)
+}
+
+// Permission.
+type cmdAuthGroupPermission struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupPermission) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("permission")
+	cmd.Short = i18n.G("Manage authorization group permissions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authorization group permissions
+
+Objects are written as <type>:<name>, for example "server:incus", "project:default" or "instance:default/c1".`))
+
+	// Add
+	authGroupPermissionAddCmd := cmdAuthGroupPermissionAdd{global: c.global}
+	cmd.AddCommand(authGroupPermissionAddCmd.Command())
+
+	// Remove
+	authGroupPermissionRemoveCmd := cmdAuthGroupPermissionRemove{global: c.global}
+	cmd.AddCommand(authGroupPermissionRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Permission add.
+type cmdAuthGroupPermissionAdd struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupPermissionAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<group> <entitlement> <object>"))
+	cmd.Short = i18n.G("Grant a permission to an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Grant a permission to an authorization group`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth group permission add operators can_exec project:default
+    Allow members of "operators" to run commands in the instances of the default project.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupPermissionAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	permission := api.AuthPermission{Entitlement: args[1], Object: args[2]}
+	if slices.Contains(group.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Authorization group %s already has the permission"), resource.name)
+	}
+
+	group.Permissions = append(group.Permissions, permission)
+
+	return resource.server.UpdateAuthGroup(resource.name, group.Writable(), etag)
+}
+
+// Permission remove.
+type cmdAuthGroupPermissionRemove struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupPermissionRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<group> <entitlement> <object>"))
+	cmd.Short = i18n.G("Revoke a permission from an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Revoke a permission from an authorization group`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupPermissionRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	permission := api.AuthPermission{Entitlement: args[1], Object: args[2]}
+	if !slices.Contains(group.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Authorization group %s doesn't have the permission"), resource.name)
+	}
+
+	group.Permissions = slices.DeleteFunc(group.Permissions, func(p api.AuthPermission) bool { return p == permission })
+
+	return resource.server.UpdateAuthGroup(resource.name, group.Writable(), etag)
+}
+
+// Rename.
+type cmdAuthGroupRename struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<group> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename an authorization group`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	// Perform the rename
+	err = resource.server.RenameAuthGroup(resource.name, api.AuthGroupPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdAuthGroupShow struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthGroupShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Show authorization group details")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show authorization group details`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthGroupShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	// Show the group
+	group, _, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&group)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Identity.
+type cmdAuthIdentity struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentity) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("identity")
+	cmd.Short = i18n.G("Manage identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage identities
+
+Identities are written as <authentication method>/<identifier>, for example
+"oidc/jane@example.com" or "tls/<certificate fingerprint>".`))
+
+	// Create
+	authIdentityCreateCmd := cmdAuthIdentityCreate{global: c.global}
+	cmd.AddCommand(authIdentityCreateCmd.Command())
+
+	// Delete
+	authIdentityDeleteCmd := cmdAuthIdentityDelete{global: c.global}
+	cmd.AddCommand(authIdentityDeleteCmd.Command())
+
+	// Edit
+	authIdentityEditCmd := cmdAuthIdentityEdit{global: c.global}
+	cmd.AddCommand(authIdentityEditCmd.Command())
+
+	// Group
+	authIdentityGroupCmd := cmdAuthIdentityGroup{global: c.global}
+	cmd.AddCommand(authIdentityGroupCmd.Command())
+
+	// List
+	authIdentityListCmd := cmdAuthIdentityList{global: c.global}
+	cmd.AddCommand(authIdentityListCmd.Command())
+
+	// Show
+	authIdentityShowCmd := cmdAuthIdentityShow{global: c.global}
+	cmd.AddCommand(authIdentityShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parseIdentity splits an identity name into its authentication method and identifier.
+func parseIdentity(name string) (string, string, error) {
+	authenticationMethod, identifier, ok := strings.Cut(name, "/")
+	if !ok || authenticationMethod == "" || identifier == "" {
+		return "", "", fmt.Errorf(i18n.G("Invalid identity %q, expected <authentication method>/<identifier>"), name)
+	}
+
+	return authenticationMethod, identifier, nil
+}
+
+// Create.
+type cmdAuthIdentityCreate struct {
+	global *cmdGlobal
+
+	flagName   string
+	flagGroups []string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<authentication method>/<identifier>"))
+	cmd.Short = i18n.G("Add an identity")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Add an identity`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth identity create oidc/jane@example.com --group operators
+    Add the OIDC user jane@example.com to the "operators" group.`))
+
+	cmd.Flags().StringVar(&c.flagName, "name", "", i18n.G("Identity name")+"``")
+	cmd.Flags().StringSliceVarP(&c.flagGroups, "group", "g", nil, i18n.G("Authorization group of the identity")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	identity := api.IdentitiesPost{
+		AuthenticationMethod: authenticationMethod,
+		Identifier:           identifier,
+		IdentityPut: api.IdentityPut{
+			Name:   c.flagName,
+			Groups: c.flagGroups,
+		},
+	}
+
+	err = resource.server.CreateIdentity(identity)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s added")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthIdentityDelete struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<authentication method>/<identifier>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete an identity")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete an identity`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	err = resource.server.DeleteIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthIdentityEdit struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<authentication method>/<identifier>"))
+	cmd.Short = i18n.G("Edit an identity")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit an identity`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.IdentityPut{}
+
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateIdentity(authenticationMethod, identifier, newdata, "")
+	}
+
+	// Extract the current value
+	identity, etag, err := resource.server.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(identity.Writable())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.IdentityPut{}
+
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateIdentity(authenticationMethod, identifier, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Returns a string explaining the expected YAML structure for an identity.
+func (c *cmdAuthIdentityEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the identity.
+### Any line starting with a '# will be ignored.`)
+}
+
+// Group.
+type cmdAuthIdentityGroup struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("group")
+	cmd.Short = i18n.G("Manage the groups of an identity")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the groups of an identity`))
+
+	// Add
+	authIdentityGroupAddCmd := cmdAuthIdentityGroupAdd{global: c.global}
+	cmd.AddCommand(authIdentityGroupAddCmd.Command())
+
+	// Remove
+	authIdentityGroupRemoveCmd := cmdAuthIdentityGroupRemove{global: c.global}
+	cmd.AddCommand(authIdentityGroupRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group add.
+type cmdAuthIdentityGroupAdd struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityGroupAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<authentication method>/<identifier> <group>"))
+	cmd.Short = i18n.G("Add an identity to an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Add an identity to an authorization group`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityGroupAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	identity, etag, err := resource.server.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(identity.Groups, args[1]) {
+		return fmt.Errorf(i18n.G("Identity %s is already a member of %s"), resource.name, args[1])
+	}
+
+	identity.Groups = append(identity.Groups, args[1])
+
+	return resource.server.UpdateIdentity(authenticationMethod, identifier, identity.Writable(), etag)
+}
+
+// Group remove.
+type cmdAuthIdentityGroupRemove struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityGroupRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<authentication method>/<identifier> <group>"))
+	cmd.Short = i18n.G("Remove an identity from an authorization group")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Remove an identity from an authorization group`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityGroupRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	identity, etag, err := resource.server.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(identity.Groups, args[1]) {
+		return fmt.Errorf(i18n.G("Identity %s isn't a member of %s"), resource.name, args[1])
+	}
+
+	identity.Groups = slices.DeleteFunc(identity.Groups, func(group string) bool { return group == args[1] })
+
+	return resource.server.UpdateIdentity(authenticationMethod, identifier, identity.Writable(), etag)
+}
+
+// List.
+type cmdAuthIdentityList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the identities`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	identities, err := resource.server.GetIdentities()
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := [][]string{}
+	for _, identity := range identities {
+		data = append(data, []string{identity.AuthenticationMethod, identity.Identifier, identity.Name, strings.Join(identity.Groups, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("AUTHENTICATION METHOD"),
+		i18n.G("IDENTIFIER"),
+		i18n.G("NAME"),
+		i18n.G("GROUPS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, identities)
+}
+
+// Show.
+type cmdAuthIdentityShow struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAuthIdentityShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<authentication method>/<identifier>"))
+	cmd.Short = i18n.G("Show identity details")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show identity details`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAuthIdentityShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authenticationMethod, identifier, err := parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	identity, _, err := resource.server.GetIdentity(authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&identity)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -23,18 +24,22 @@ type cmdExport struct {
 	flagInstanceOnly         bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagIncrementalFrom      string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
 func (c *cmdExport) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("export", i18n.G("[<remote>:]<instance> [target] [--instance-only] [--optimized-storage]"))
+	cmd.Use = usage("export", i18n.G("[<remote>:]<instance> [target] [--instance-only] [--optimized-storage] [--incremental-from <snapshot>]"))
 	cmd.Short = i18n.G("Export instance backups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Export instances as backup tarballs.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`))
+    Download a backup tarball of the u1 instance.
+
+incus export u1 backup1.tar.gz --incremental-from snap0
+    Download a backup tarball of the u1 instance only containing the changes since its snap0 snapshot.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -42,6 +47,7 @@ func (c *cmdExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
+	cmd.Flags().StringVar(&c.flagIncrementalFrom, "incremental-from", "", i18n.G("Only export the changes since the given snapshot (implies --optimized-storage)")+"``")
 
 	return cmd
 }
@@ -69,12 +75,17 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 
 	instanceOnly := c.flagInstanceOnly
 
+	if c.flagIncrementalFrom != "" && instanceOnly {
+		return errors.New(i18n.G("--incremental-from can't be used with --instance-only"))
+	}
+
 	req := api.InstanceBackupsPost{
 		Name:                 "",
 		ExpiresAt:            time.Now().Add(24 * time.Hour),
 		InstanceOnly:         instanceOnly,
-		OptimizedStorage:     c.flagOptimizedStorage,
+		OptimizedStorage:     c.flagOptimizedStorage || c.flagIncrementalFrom != "",
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		IncrementalFrom:      c.flagIncrementalFrom,
 	}
 
 	op, err := d.CreateInstanceBackup(name, req)
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -19,9 +21,11 @@ import (
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	internalFilter "github.com/lxc/incus/v6/internal/filter"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/oci"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/archive"
+	config "github.com/lxc/incus/v6/shared/cliconfig"
 	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/termios"
 	"github.com/lxc/incus/v6/shared/util"
@@ -90,6 +94,10 @@ hash or alias name (if one is set).`))
 	imageListCmd := cmdImageList{global: c.global, image: c}
 	cmd.AddCommand(imageListCmd.Command())
 
+	// Push
+	imagePushCmd := cmdImagePush{global: c.global, image: c}
+	cmd.AddCommand(imagePushCmd.Command())
+
 	// Refresh
 	imageRefreshCmd := cmdImageRefresh{global: c.global, image: c}
 	cmd.AddCommand(imageRefreshCmd.Command())
@@ -498,7 +506,8 @@ type cmdImageExport struct {
 	global *cmdGlobal
 	image  *cmdImage
 
-	flagVM bool
+	flagFormat string
+	flagVM     bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -509,9 +518,13 @@ func (c *cmdImageExport) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Export and download images
 
-The output target is optional and defaults to the working directory.`))
+The output target is optional and defaults to the working directory.
+
+With --format=oci, container images are converted to an OCI image tagged "latest"
+in the OCI image layout at the target (defaults to a directory named after the image fingerprint).`))
 
 	cmd.Flags().BoolVar(&c.flagVM, "vm", false, i18n.G("Query virtual machine images"))
+	cmd.Flags().StringVar(&c.flagFormat, "format", "incus", i18n.G("Format of the exported image (incus or oci)")+"``")
 	cmd.RunE = c.Run
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -552,6 +565,31 @@ func (c *cmdImageExport) Run(cmd *cobra.Command, args []string) error {
 
 	fingerprint := c.image.dereferenceAlias(remoteServer, imageType, name)
 
+	switch c.flagFormat {
+	case "incus":
+	case "oci":
+		target := fingerprint
+		if len(args) > 1 {
+			target = args[1]
+		}
+
+		progress := cli.ProgressRenderer{
+			Format: i18n.G("Exporting the image: %s"),
+			Quiet:  c.global.flagQuiet,
+		}
+
+		err = exportOCIImage(remoteServer, fingerprint, target, "latest", &progress)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+
+		progress.Done(i18n.G("Image exported successfully!"))
+		return nil
+	default:
+		return fmt.Errorf(i18n.G("Invalid image format %q"), c.flagFormat)
+	}
+
 	// Default target is current directory
 	target := "."
 	targetMeta := fingerprint
@@ -662,6 +700,205 @@ func (c *cmdImageExport) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// exportOCIImage downloads a container image and converts it to an OCI image tagged with the given name in the image layout at path.
+func exportOCIImage(d incus.ImageServer, fingerprint string, path string, tag string, progress *cli.ProgressRenderer) error {
+	image, _, err := d.GetImage(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	if image.Type == string(api.InstanceTypeVM) {
+		return errors.New(i18n.G("Only container images can be converted to OCI images"))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "incus-oci-")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	metaFile, err := os.Create(filepath.Join(tmpDir, "meta"))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = metaFile.Close() }()
+
+	rootfsFile, err := os.Create(filepath.Join(tmpDir, "rootfs"))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rootfsFile.Close() }()
+
+	// Download the image.
+	req := incus.ImageFileRequest{
+		MetaFile:        io.WriteSeeker(metaFile),
+		RootfsFile:      io.WriteSeeker(rootfsFile),
+		ProgressHandler: progress.UpdateProgress,
+	}
+
+	resp, err := d.GetImageFile(image.Fingerprint, req)
+	if err != nil {
+		return err
+	}
+
+	rootfsPath := rootfsFile.Name()
+	if resp.RootfsSize == 0 {
+		rootfsPath = ""
+	}
+
+	// Convert it.
+	progress.Update(i18n.G("Converting to an OCI image"))
+
+	_, err = oci.WriteLayout(path, tag, metaFile.Name(), rootfsPath)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed converting to an OCI image: %w"), err)
+	}
+
+	return nil
+}
+
+// ociReference returns the registry reference of a push destination.
+// The destination is either "oci://<registry>/<repository>[:<tag>]" or "<remote>:<repository>[:<tag>]" for OCI remotes.
+func ociReference(conf *config.Config, destination string) (string, error) {
+	if strings.HasPrefix(destination, "oci://") {
+		return destination, nil
+	}
+
+	remoteName, name, err := conf.ParseRemote(destination)
+	if err != nil {
+		return "", err
+	}
+
+	remote := conf.Remotes[remoteName]
+	if remote.Protocol != "oci" {
+		return "", fmt.Errorf(i18n.G("The remote %q isn't an OCI registry"), remoteName)
+	}
+
+	if name == "" {
+		return "", errors.New(i18n.G("A repository name is required"))
+	}
+
+	u, err := url.Parse(remote.Addr)
+	if err != nil {
+		return "", err
+	}
+
+	return "oci://" + u.Host + strings.TrimSuffix(u.Path, "/") + "/" + name, nil
+}
+
+// Push.
+type cmdImagePush struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagInsecure bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdImagePush) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("push", i18n.G("[<remote>:]<image> oci://<registry>/<repository>[:<tag>]|<remote>:<repository>[:<tag>]"))
+	cmd.Short = i18n.G("Push images to OCI registries")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Push images to OCI registries
+
+The container image is converted to an OCI image and uploaded to the registry,
+either given by URL or as an OCI remote. The tag defaults to "latest".
+
+The registry credentials are read from the container tools authentication file (see "skopeo login").`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus image push ubuntu-custom oci://harbor.example.net/system/ubuntu:24.04
+    Push the "ubuntu-custom" image to the system/ubuntu repository of harbor.example.net with the "24.04" tag.
+
+incus image push local:ubuntu-custom harbor:system/ubuntu
+    Push the "ubuntu-custom" image to the system/ubuntu repository of the "harbor" OCI remote with the "latest" tag.`))
+
+	cmd.Flags().BoolVar(&c.flagInsecure, "insecure", false, i18n.G("Skip the TLS verification and allow plain HTTP registries"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpImages(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdImagePush) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	reference, err := ociReference(c.global.conf, args[1])
+	if err != nil {
+		return err
+	}
+
+	_, _, err = oci.ParseReference(reference)
+	if err != nil {
+		return err
+	}
+
+	// Parse remote
+	remoteName, name, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetImageServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	fingerprint := c.image.dereferenceAlias(remoteServer, string(api.InstanceTypeContainer), name)
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Pushing the image: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	err = pushOCIImage(remoteServer, fingerprint, reference, oci.PushArgs{Insecure: c.flagInsecure}, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done(fmt.Sprintf(i18n.G("Image pushed to %s"), reference))
+	return nil
+}
+
+// pushOCIImage converts a container image to an OCI image and uploads it to the registry.
+func pushOCIImage(d incus.ImageServer, fingerprint string, reference string, args oci.PushArgs, progress *cli.ProgressRenderer) error {
+	layoutPath, err := os.MkdirTemp("", "incus-oci-")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(layoutPath) }()
+
+	err = exportOCIImage(d, fingerprint, layoutPath, "latest", progress)
+	if err != nil {
+		return err
+	}
+
+	progress.Update(i18n.G("Uploading to the registry"))
+
+	return oci.Push(context.TODO(), layoutPath, "latest", reference, args)
+}
+
 // Import.
 type cmdImageImport struct {
 	global *cmdGlobal
@@ -26,10 +26,16 @@ func (c *cmdImport) Command() *cobra.Command {
 	cmd.Use = usage("import", i18n.G("[<remote>:] <backup file> [<instance name>]"))
 	cmd.Short = i18n.G("Import instance backups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Import backups of instances including their snapshots.`))
+		`Import backups of instances including their snapshots.
+
+Incremental backups are applied onto the existing instance they were taken from,
+so a chain of backups is restored by importing each of them in order.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+incus import backup0.tar.gz && incus import backup1.tar.gz
+    Create a new instance using backup0.tar.gz and apply the incremental backup1.tar.gz on top of it.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
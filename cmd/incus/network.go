@@ -1087,6 +1087,15 @@ func (c *cmdNetworkInfo) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// BGP information.
+	if state.BGP != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("BGP routes:"))
+		for _, route := range state.BGP.Routes {
+			fmt.Printf("  %s "+i18n.G("via %s (peer %s)")+"\n", route.Prefix, route.Nexthop, route.Peer)
+		}
+	}
+
 	return nil
 }
 
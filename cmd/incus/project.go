@@ -8,7 +8,9 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -78,6 +80,10 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectGetInfo := cmdProjectInfo{global: c.global, project: c}
 	cmd.AddCommand(projectGetInfo.Command())
 
+	// Usage
+	projectUsageCmd := cmdProjectUsage{global: c.global, project: c}
+	cmd.AddCommand(projectUsageCmd.Command())
+
 	// Set default
 	projectSwitchCmd := cmdProjectSwitch{global: c.global, project: c}
 	cmd.AddCommand(projectSwitchCmd.Command())
@@ -1188,6 +1194,154 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectState)
 }
 
+// Usage.
+type cmdProjectUsage struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagSince  string
+	flagUntil  string
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdProjectUsage) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("usage", i18n.G("[<remote>:]<project>"))
+	cmd.Short = i18n.G("Show the resources consumed by a project")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the resources consumed by a project
+
+Resource usage of the project's instances is recorded hourly.
+The period defaults to the last 30 days. Dates are interpreted as UTC.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project usage p1
+    Show the resources consumed by the instances of project p1 over the last 30 days
+
+incus project usage p1 --since 2024-01-01 --until 2024-02-01 --format csv
+    Export the resources consumed by the instances of project p1 in January 2024 as CSV`))
+
+	cmd.Flags().StringVar(&c.flagSince, "since", "", i18n.G("Start of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVar(&c.flagUntil, "until", "", i18n.G("End of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseTime parses a date or RFC3339 timestamp.
+func (c *cmdProjectUsage) parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(i18n.G("Invalid date %q, expected YYYY-MM-DD or RFC3339"), value)
+	}
+
+	return t, nil
+}
+
+// Run runs the actual command logic.
+func (c *cmdProjectUsage) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	since, err := c.parseTime(c.flagSince)
+	if err != nil {
+		return err
+	}
+
+	until, err := c.parseTime(c.flagUntil)
+	if err != nil {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	projectUsage, err := resource.server.GetProjectUsage(resource.name, since, until)
+	if err != nil {
+		return err
+	}
+
+	// Keep raw values for machine readable output.
+	humanReadable := !strings.HasPrefix(c.flagFormat, cli.TableFormatCSV)
+
+	formatRow := func(name string, instanceType string, usage api.ProjectResourceUsage) []string {
+		formatBytes := func(value int64) string {
+			if humanReadable {
+				return units.GetByteSizeStringIEC(value, 2)
+			}
+
+			return strconv.FormatInt(value, 10)
+		}
+
+		return []string{
+			name,
+			instanceType,
+			strconv.FormatFloat(usage.CPUSeconds, 'f', 2, 64),
+			strconv.FormatFloat(usage.MemoryGiBHours, 'f', 2, 64),
+			formatBytes(usage.DiskBytesRead),
+			formatBytes(usage.DiskBytesWritten),
+			formatBytes(usage.NetworkBytesReceived),
+			formatBytes(usage.NetworkBytesSent),
+		}
+	}
+
+	data := [][]string{}
+	for _, instUsage := range projectUsage.Instances {
+		data = append(data, formatRow(instUsage.Name, instUsage.Type, instUsage.ProjectResourceUsage))
+	}
+
+	if humanReadable {
+		data = append(data, formatRow(i18n.G("TOTAL"), "", projectUsage.Total))
+	}
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("TYPE"),
+		i18n.G("CPU (SECONDS)"),
+		i18n.G("MEMORY (GIB-HOURS)"),
+		i18n.G("DISK READ"),
+		i18n.G("DISK WRITTEN"),
+		i18n.G("NETWORK RECEIVED"),
+		i18n.G("NETWORK SENT"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectUsage)
+}
+
 // Get current project.
 type cmdProjectGetCurrent struct {
 	global  *cmdGlobal
@@ -12,6 +12,7 @@ import (
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/oci"
 	"github.com/lxc/incus/v6/shared/api"
 )
 
@@ -32,7 +33,10 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Use = usage("publish", i18n.G("[<remote>:]<instance>[/<snapshot>] [<remote>:] [flags] [key=value...]"))
 	cmd.Short = i18n.G("Publish instances as images")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Publish instances as images`))
+		`Publish instances as images
+
+When the target remote is an OCI registry, the container is pushed to the registry
+as an OCI image, using the only alias as "<repository>[:<tag>]", and isn't kept as an image on the server.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagMakePublic, "public", false, i18n.G("Make the image public"))
@@ -98,6 +102,26 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("There is no \"image name\".  Did you want an alias?"))
 	}
 
+	// Images published to OCI registries are pushed from the source server.
+	ociDestination := ""
+	if conf.Remotes[iRemote].Protocol == "oci" {
+		if len(c.flagAliases) != 1 {
+			return errors.New(i18n.G("Publishing to an OCI registry requires exactly one alias (the repository and tag)"))
+		}
+
+		ociDestination, err = ociReference(conf, iRemote+":"+c.flagAliases[0])
+		if err != nil {
+			return err
+		}
+
+		_, _, err = oci.ParseReference(ociDestination)
+		if err != nil {
+			return err
+		}
+
+		iRemote = cRemote
+	}
+
 	d, err := conf.GetInstanceServer(iRemote)
 	if err != nil {
 		return err
@@ -208,6 +232,10 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 	// Reformat aliases
 	aliases := []api.ImageAlias{}
 	for _, entry := range c.flagAliases {
+		if ociDestination != "" {
+			break
+		}
+
 		alias := api.ImageAlias{}
 		alias.Name = entry
 		aliases = append(aliases, alias)
@@ -291,6 +319,26 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return errors.New("Bad fingerprint")
 	}
 
+	// For OCI registries, push the image and remove it from the server.
+	if ociDestination != "" {
+		defer func() { _, _ = s.DeleteImage(fingerprint) }()
+
+		progress := cli.ProgressRenderer{
+			Format: i18n.G("Pushing the image: %s"),
+			Quiet:  c.global.flagQuiet,
+		}
+
+		err = pushOCIImage(s, fingerprint, ociDestination, oci.PushArgs{}, &progress)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+
+		progress.Done("")
+		fmt.Printf(i18n.G("Instance published to %s")+"\n", ociDestination)
+		return nil
+	}
+
 	// For remote publish, copy to target now
 	if cRemote != iRemote {
 		defer func() { _, _ = s.DeleteImage(fingerprint) }()
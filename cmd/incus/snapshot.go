@@ -41,6 +41,10 @@ func (c *cmdSnapshot) Command() *cobra.Command {
 	snapshotDeleteCmd := cmdSnapshotDelete{global: c.global, snapshot: c}
 	cmd.AddCommand(snapshotDeleteCmd.Command())
 
+	// Diff.
+	snapshotDiffCmd := cmdSnapshotDiff{global: c.global, snapshot: c}
+	cmd.AddCommand(snapshotDiffCmd.Command())
+
 	// List.
 	snapshotListCmd := cmdSnapshotList{global: c.global, snapshot: c}
 	cmd.AddCommand(snapshotListCmd.Command())
@@ -279,6 +283,103 @@ func (c *cmdSnapshotDelete) doDelete(d incus.InstanceServer, instName string, na
 	return op.Wait()
 }
 
+// Diff.
+type cmdSnapshotDiff struct {
+	global   *cmdGlobal
+	snapshot *cmdSnapshot
+
+	flagFormat string
+	flagFrom   string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdSnapshotDiff) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("diff", i18n.G("[<remote>:]<instance> <snapshot>"))
+	cmd.Short = i18n.G("List files changed in instance snapshots")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List files changed in instance snapshots
+
+Without --from, the files changed in the instance since the snapshot are listed.
+With --from, the files changed between the two snapshots are listed.
+
+This is only supported for containers.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus snapshot diff u1 snap0
+    List the files changed in instance u1 since snapshot snap0.
+
+incus snapshot diff u1 snap1 --from snap0
+    List the files changed between snapshots snap0 and snap1 of instance u1.`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVar(&c.flagFrom, "from", "", i18n.G("Snapshot to compare against")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpInstanceSnapshots(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdSnapshotDiff) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	inst, _, err := resource.server.GetInstance(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Find the storage pool holding the instance volume.
+	_, rootDisk, err := instance.GetRootDiskDevice(inst.ExpandedDevices)
+	if err != nil {
+		return err
+	}
+
+	diff, err := resource.server.GetStoragePoolVolumeSnapshotDiff(rootDisk["pool"], inst.Type, resource.name, args[1], c.flagFrom)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, entry := range diff {
+		data = append(data, []string{strings.ToUpper(entry.Type), entry.Path})
+	}
+
+	header := []string{
+		i18n.G("TYPE"),
+		i18n.G("PATH"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, diff)
+}
+
 // List.
 type cmdSnapshotList struct {
 	global   *cmdGlobal
@@ -18,6 +18,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -41,6 +42,12 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	auditCmd,
+	auditEntryCmd,
+	authGroupCmd,
+	authGroupsCmd,
+	authIdentitiesCmd,
+	authIdentityCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -112,6 +119,7 @@ var api10 = []APIEndpoint{
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
+	projectUsageCmd,
 	projectAccessCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
@@ -126,6 +134,7 @@ var api10 = []APIEndpoint{
 	storagePoolVolumesCmd,
 	storagePoolVolumeSnapshotsTypeCmd,
 	storagePoolVolumeSnapshotTypeCmd,
+	storagePoolVolumeSnapshotTypeDiffCmd,
 	storagePoolVolumesTypeCmd,
 	storagePoolVolumeTypeCmd,
 	storagePoolVolumeTypeCustomBackupsCmd,
@@ -827,14 +836,25 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 				d.taskPruneImages.Reset()
 			}
 
+		case "images.download.bandwidth_limit", "images.download.concurrency":
+			imageDownloads.configure(clusterConfig.ImagesDownloadConcurrency(), clusterConfig.ImagesDownloadBandwidthLimit())
+
+		case "migration.bandwidth_limit":
+			localMigration.SetServerBandwidthLimit(clusterConfig.MigrationBandwidthLimit())
+
 		case "loki.api.url", "loki.auth.username", "loki.auth.password", "loki.api.ca_cert", "loki.instance", "loki.labels", "loki.loglevel", "loki.types":
 			// Notify the logging mechanism about changes to the deprecated keys for backward compatibility.
 			loggingChanges["loki"] = struct{}{}
 
+		case "metrics.push.type", "metrics.push.address", "metrics.push.interval":
+			if !s.OS.MockMode {
+				d.taskPushMetrics.Reset()
+			}
+
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
-		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
+		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim", "oidc.groups.claim":
 			oidcChanged = true
 
 		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
@@ -973,7 +993,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			d.oidcVerifier = nil
 		} else {
 			var err error
-			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, clusterConfig.OIDCGroupsClaim())
 			if err != nil {
 				return fmt.Errorf("Failed creating verifier: %w", err)
 			}
@@ -1034,5 +1054,15 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Setup the built-in authorization, restoring it after another authorizer got removed.
+	_, builtinChanged := clusterChanged["authorization.builtin"]
+	scriptlet, scriptletChanged := clusterChanged["authorization.scriptlet"]
+	if builtinChanged || openFGAChanged || (scriptletChanged && scriptlet == "") {
+		err := d.setupBuiltinAuthorization(clusterConfig.AuthorizationBuiltin())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,998 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var authGroupsCmd = APIEndpoint{
+	Path: "auth/groups",
+
+	Get:  APIEndpointAction{Handler: authGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authGroupCmd = APIEndpoint{
+	Path: "auth/groups/{name}",
+
+	Get:    APIEndpointAction{Handler: authGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post:   APIEndpointAction{Handler: authGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentitiesCmd = APIEndpoint{
+	Path: "auth/identities",
+
+	Get:  APIEndpointAction{Handler: authIdentitiesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authIdentitiesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentityCmd = APIEndpoint{
+	Path: "auth/identities/{authenticationMethod}/{identifier}",
+
+	Get:    APIEndpointAction{Handler: authIdentityGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authIdentityDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/auth/groups auth auth_groups_get
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/groups/operators",
+//	              "/1.0/auth/groups/viewers"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/groups?recursion=1 auth auth_groups_get_recursion1
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization groups
+//	          items:
+//	            $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var groups []api.AuthGroup
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groups, err = tx.GetAuthGroups(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, groups)
+	}
+
+	urls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "groups", group.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/groups auth auth_groups_post
+//
+//	Create an authorization group
+//
+//	Creates a new authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Authorization group to create
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthGroupsPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidate(req.AuthGroupPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthGroup(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.AuthGroupCreated.Event(req.Name, requestor, nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/groups/{name} auth auth_group_get
+//
+//	Get the authorization group
+//
+//	Gets a specific authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authorization group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetAuthGroup(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, group, group.Writable())
+}
+
+// swagger:operation POST /1.0/auth/groups/{name} auth auth_group_post
+//
+//	Rename the authorization group
+//
+//	Renames an existing authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Authorization group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameAuthGroup(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.AuthGroupRenamed.Event(req.Name, requestor, logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation PUT /1.0/auth/groups/{name} auth auth_group_put
+//
+//	Update the authorization group
+//
+//	Updates the entire authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Authorization group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/groups/{name} auth auth_group_patch
+//
+//	Partially update the authorization group
+//
+//	Updates a subset of the authorization group fields.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Authorization group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetAuthGroup(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, group.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	// For PATCH, unspecified fields keep their current value.
+	req := api.AuthGroupPut{}
+	if r.Method == http.MethodPatch {
+		req = group.Writable()
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateAuthGroup(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupUpdated.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/groups/{name} auth auth_group_delete
+//
+//	Delete the authorization group
+//
+//	Removes the authorization group, its members are kept.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthGroup(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupDeleted.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/auth/identities auth auth_identities_get
+//
+//	Get the identities
+//
+//	Returns a list of identities (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/identities/oidc/jane@example.com",
+//	              "/1.0/auth/identities/tls/2bd5a7b6e0d2b24ec7e2ab5ad7ef9ea9c06e1e5dde8d6f9d0ffa4fe3eb5b8bc7"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/identities?recursion=1 auth auth_identities_get_recursion1
+//
+//	Get the identities
+//
+//	Returns a list of identities (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of identities
+//	          items:
+//	            $ref: "#/definitions/Identity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var identities []api.Identity
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		identities, err = tx.GetIdentities(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, identities)
+	}
+
+	urls := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "identities", identity.AuthenticationMethod, identity.Identifier).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/identities auth auth_identities_post
+//
+//	Add an identity
+//
+//	Adds a new identity to the built-in authorization driver.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity to add
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentitiesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.IdentitiesPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authIdentityValidate(req.AuthenticationMethod, req.Identifier)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateIdentity(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.IdentityCreated.Event(req.AuthenticationMethod, req.Identifier, requestor, nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_get
+//
+//	Get the identity
+//
+//	Gets a specific identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Identity
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Identity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.Identity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = tx.GetIdentity(ctx, authenticationMethod, identifier)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, identity, identity.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_put
+//
+//	Update the identity
+//
+//	Updates the entire identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_patch
+//
+//	Partially update the identity
+//
+//	Updates a subset of the identity fields.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.Identity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = tx.GetIdentity(ctx, authenticationMethod, identifier)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, identity.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	// For PATCH, unspecified fields keep their current value.
+	req := api.IdentityPut{}
+	if r.Method == http.MethodPatch {
+		req = identity.Writable()
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateIdentity(ctx, authenticationMethod, identifier, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityUpdated.Event(authenticationMethod, identifier, requestor, logger.Ctx{"groups": req.Groups}))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_delete
+//
+//	Delete the identity
+//
+//	Removes the identity from the built-in authorization driver.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteIdentity(ctx, authenticationMethod, identifier)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = authResetCache(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityDeleted.Event(authenticationMethod, identifier, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// authIdentityFromRequest returns the authentication method and identifier from the request path.
+func authIdentityFromRequest(r *http.Request) (string, string, error) {
+	authenticationMethod, err := url.PathUnescape(mux.Vars(r)["authenticationMethod"])
+	if err != nil {
+		return "", "", err
+	}
+
+	identifier, err := url.PathUnescape(mux.Vars(r)["identifier"])
+	if err != nil {
+		return "", "", err
+	}
+
+	return authenticationMethod, identifier, nil
+}
+
+func authGroupValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.Contains(name, "/") {
+		return errors.New("Authorization group names may not contain slashes")
+	}
+
+	if slices.Contains([]string{".", ".."}, name) {
+		return fmt.Errorf("Invalid authorization group name %q", name)
+	}
+
+	return nil
+}
+
+func authGroupValidate(group api.AuthGroupPut) error {
+	for _, permission := range group.Permissions {
+		err := auth.ValidatePermission(permission)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, oidcGroup := range group.OIDCGroups {
+		if oidcGroup == "" {
+			return errors.New("OIDC group names can't be empty")
+		}
+	}
+
+	return nil
+}
+
+func authIdentityValidate(authenticationMethod string, identifier string) error {
+	if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodOIDC}, authenticationMethod) {
+		return fmt.Errorf("Invalid authentication method %q", authenticationMethod)
+	}
+
+	if identifier == "" {
+		return errors.New("No identifier provided")
+	}
+
+	if strings.Contains(identifier, "/") {
+		return errors.New("Identifiers may not contain slashes")
+	}
+
+	return nil
+}
+
+// authResetCache discards the cached authorization groups and permissions on all cluster members.
+func authResetCache(s *state.State) error {
+	builtin, ok := s.Authorizer.(*auth.Builtin)
+	if ok {
+		builtin.ResetCache()
+	}
+
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	return notifier(func(client incus.InstanceServer) error {
+		_, _, err := client.RawQuery(http.MethodPost, "/internal/auth/reset-cache", nil, "")
+		return err
+	})
+}
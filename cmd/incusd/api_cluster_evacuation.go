@@ -11,6 +11,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -51,6 +52,10 @@ type evacuateOpts struct {
 	stopInstance    evacuateStopFunc
 	migrateInstance evacuateMigrateFunc
 	op              *operations.Operation
+
+	// Placements of the instances being moved concurrently.
+	pendingPlacements *instance.PlacementPending
+	placementLock     *sync.Mutex
 }
 
 func evacuateClusterSetState(s *state.State, name string, newState int) error {
@@ -170,6 +175,9 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 	group, groupCtx := errgroup.WithContext(ctx)
 	group.SetLimit(numParallelEvacs)
 
+	opts.pendingPlacements = instance.NewPlacementPending()
+	opts.placementLock = &sync.Mutex{}
+
 	for _, inst := range opts.instances {
 		group.Go(func() error {
 			return evacuateInstancesFunc(groupCtx, inst, opts)
@@ -232,8 +240,17 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 		action = "migrate"
 	}
 
-	// Find a new location for the instance.
-	sourceMemberInfo, targetMemberInfo, err := evacuateClusterSelectTarget(ctx, opts.s, inst)
+	// Find a new location for the instance. Placements are made one at a time so that the placement group
+	// policies take the instances which are still being moved into account.
+	opts.placementLock.Lock()
+	sourceMemberInfo, targetMemberInfo, err := evacuateClusterSelectTarget(ctx, opts.s, inst, opts.pendingPlacements)
+	if err == nil {
+		release := opts.pendingPlacements.Add(instProject.Name, inst.ExpandedConfig(), targetMemberInfo.Name)
+		defer release()
+	}
+
+	opts.placementLock.Unlock()
+
 	if err != nil {
 		if api.StatusErrorCheck(err, http.StatusNotFound) {
 			// Skip migration if no target is available.
@@ -241,7 +258,15 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 			return nil
 		}
 
-		return err
+		if api.StatusErrorCheck(err, http.StatusConflict) && isRunning && !inst.IsRunning() {
+			// The placement policy doesn't allow moving the instance, restart it where it was.
+			startErr := inst.Start(false)
+			if startErr != nil {
+				l.Warn("Failed restarting instance", logger.Ctx{"err": startErr})
+			}
+		}
+
+		return fmt.Errorf("Failed to find a new location for instance %q in project %q: %w", inst.Name(), instProject.Name, err)
 	}
 
 	// Start migrating the instance.
@@ -525,7 +550,7 @@ func restoreClusterMemberFunc(inst instance.Instance, op *operations.Operation,
 	return nil
 }
 
-func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst instance.Instance) (*db.NodeInfo, *db.NodeInfo, error) {
+func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst instance.Instance, pendingPlacements *instance.PlacementPending) (*db.NodeInfo, *db.NodeInfo, error) {
 	var sourceMemberInfo *db.NodeInfo
 	var targetMemberInfo *db.NodeInfo
 
@@ -566,6 +591,12 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			return err
 		}
 
+		// Apply the placement group policy.
+		candidateMembers, err = instance.PlacementCandidates(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), candidateMembers, pendingPlacements)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -238,6 +238,21 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			continue
 		}
 
+		// Check that the placement group policy allows the move (taking the previous moves into account).
+		var allowed bool
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allowed, err = instance.PlacementAllowsMove(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), srcServer.NodeInfo.Name, dstServer.NodeInfo.Name)
+
+			return err
+		})
+		if err != nil {
+			return -1, fmt.Errorf("Failed to check placement policy: %w", err)
+		}
+
+		if !allowed {
+			continue
+		}
+
 		// Prepare for live migration.
 		req := api.InstancePost{
 			Migration: true,
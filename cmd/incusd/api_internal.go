@@ -16,6 +16,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"golang.org/x/sys/unix"
@@ -48,6 +49,7 @@ import (
 )
 
 var apiInternal = []APIEndpoint{
+	internalAuthResetCacheCmd,
 	internalBGPStateCmd,
 	internalClusterAcceptCmd,
 	internalClusterAssignCmd,
@@ -61,6 +63,7 @@ var apiInternal = []APIEndpoint{
 	internalGarbageCollectorCmd,
 	internalImageOptimizeCmd,
 	internalImageRefreshCmd,
+	internalInstanceWaitDependencyCmd,
 	internalRAFTSnapshotCmd,
 	internalRebalanceLoadCmd,
 	internalReadyCmd,
@@ -102,6 +105,12 @@ var internalSQLCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: internalSQLPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalAuthResetCacheCmd = APIEndpoint{
+	Path: "auth/reset-cache",
+
+	Post: APIEndpointAction{Handler: internalAuthResetCache, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 // Internal cluster traffic.
 var internalClusterAcceptCmd = APIEndpoint{
 	Path: "cluster/accept",
@@ -159,6 +168,13 @@ var internalVirtualMachineOnResizeCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: internalVirtualMachineOnResize, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+// Instance start-up dependencies.
+var internalInstanceWaitDependencyCmd = APIEndpoint{
+	Path: "instances/{name}/wait-dependency",
+
+	Get: APIEndpointAction{Handler: internalInstanceWaitDependency, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 // Debugging.
 var internalBGPStateCmd = APIEndpoint{
 	Path: "debug/bgp",
@@ -289,6 +305,17 @@ func internalWaitReady(d *Daemon, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// internalAuthResetCache discards the cached authorization groups and permissions after they were changed on
+// another cluster member.
+func internalAuthResetCache(d *Daemon, r *http.Request) response.Response {
+	builtin, ok := d.authorizer.(*auth.Builtin)
+	if ok {
+		builtin.ResetCache()
+	}
+
+	return response.EmptySyncResponse
+}
+
 func internalShutdown(d *Daemon, r *http.Request) response.Response {
 	force := request.QueryParam(r, "force")
 	logger.Info("Asked to shutdown by API", logger.Ctx{"force": force})
@@ -500,6 +527,34 @@ func internalVirtualMachineOnResize(d *Daemon, r *http.Request) response.Respons
 	return response.EmptySyncResponse
 }
 
+// internalInstanceWaitDependency waits for a local instance to be up, so it can be used as a dependency of
+// instances located on other cluster members.
+func internalInstanceWaitDependency(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	timeout, err := strconv.Atoi(request.QueryParam(r, "timeout"))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid timeout: %w", err))
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, request.ProjectParam(r), name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = instanceWaitDependency(r.Context(), inst, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
 // Perform a database dump.
 func internalSQLGet(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
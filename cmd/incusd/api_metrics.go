@@ -22,6 +22,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 )
@@ -97,12 +98,23 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	// Wait until daemon is fully started.
 	<-d.waitReady.Done()
 
+	metricSet, err := getMetrics(r.Context(), s, projectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return getFilteredMetrics(s, r, compress, metricSet)
+}
+
+// getMetrics returns the internal metrics along with the metrics of the local instances of the given project
+// (or of all projects if empty). Instance metrics are cached for a few seconds.
+func getMetrics(ctx context.Context, s *state.State, projectName string) (*metrics.MetricSet, error) {
 	// Prepare response.
 	metricSet := metrics.NewMetricSet(nil)
 
 	var projectNames []string
 
-	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Figure out the projects to retrieve.
 		if projectName != "" {
 			projectNames = []string{projectName}
@@ -125,7 +137,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		return nil
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
@@ -160,18 +172,18 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	cacheDuration := time.Duration(8) * time.Second
 
 	// Acquire update lock.
-	lockCtx, lockCtxCancel := context.WithTimeout(r.Context(), cacheDuration)
+	lockCtx, lockCtxCancel := context.WithTimeout(ctx, cacheDuration)
 	defer lockCtxCancel()
 
 	unlock, err := locking.Lock(lockCtx, "metricsGet")
 	if err != nil {
-		return response.SmartError(api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err))
+		return nil, api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err)
 	}
 
 	defer unlock()
@@ -185,14 +197,14 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	// Gather information about host interfaces once.
 	hostInterfaces, _ := net.Interfaces()
 
 	var instances []instance.Instance
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
 			inst, err := instance.Load(s, dbInst, p)
 			if err != nil {
@@ -205,7 +217,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		}, projectsToFetch...)
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
 	// Prepare temporary metrics storage.
@@ -290,7 +302,67 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	metricsCacheLock.Unlock()
 
-	return getFilteredMetrics(s, r, compress, metricSet)
+	return metricSet, nil
+}
+
+// pushMetricsTask returns a task pushing the local metrics to the configured remote endpoint.
+// The task is idle while metrics.push.type isn't set and must be reset when the push configuration changes.
+func pushMetricsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		pushType, address, username, password, token, caCert := s.GlobalConfig.MetricsPushTarget()
+		if pushType == "" {
+			return
+		}
+
+		// Identify the pushing server the same way a scraper would.
+		labels := map[string]string{}
+		switch pushType {
+		case metrics.PushTypePrometheus:
+			labels["job"] = "incus"
+			labels["instance"] = s.ServerName
+		case metrics.PushTypeOTLP:
+			labels["service.name"] = "incus"
+			labels["service.instance.id"] = s.ServerName
+		}
+
+		for k, v := range s.GlobalConfig.MetricsPushLabels() {
+			labels[k] = v
+		}
+
+		pusher, err := metrics.NewPusher(pushType, address, username, password, token, caCert, labels)
+		if err != nil {
+			logger.Warn("Failed setting up metrics push", logger.Ctx{"err": err})
+			return
+		}
+
+		metricSet, err := getMetrics(ctx, s, "")
+		if err != nil {
+			logger.Warn("Failed gathering metrics to push", logger.Ctx{"err": err})
+			return
+		}
+
+		err = pusher.Push(ctx, metricSet, time.Now())
+		if err != nil {
+			logger.Warn("Failed pushing metrics", logger.Ctx{"address": address, "err": err})
+			return
+		}
+	}
+
+	schedule := func() (time.Duration, error) {
+		s := d.State()
+
+		// Don't schedule anything until pushing is configured.
+		pushType, _, _, _, _, _ := s.GlobalConfig.MetricsPushTarget()
+		if pushType == "" {
+			return 0, nil
+		}
+
+		return s.GlobalConfig.MetricsPushInterval(), nil
+	}
+
+	return f, schedule
 }
 
 func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSet *metrics.MetricSet) response.Response {
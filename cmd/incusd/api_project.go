@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -59,6 +60,12 @@ var projectStateCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: projectStateGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
 }
 
+var projectUsageCmd = APIEndpoint{
+	Path: "projects/{name}/usage",
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
 var projectAccessCmd = APIEndpoint{
 	Path: "projects/{name}/access",
 
@@ -1307,6 +1314,134 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, &state)
 }
 
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project resource usage
+//
+//	Gets the resources consumed by the instances of the project over a period of time.
+//	Usage is recorded hourly, so only the hours starting within the period are accounted for.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: since
+//	    description: Start of the period (RFC3339), defaults to 30 days before the end of the period
+//	    type: string
+//	    example: 2024-01-01T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: End of the period (RFC3339), defaults to now
+//	    type: string
+//	    example: 2024-02-01T00:00:00Z
+//	responses:
+//	  "200":
+//	    description: Project usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	until := time.Now().UTC()
+	if r.FormValue("until") != "" {
+		until, err = time.Parse(time.RFC3339, r.FormValue("until"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid until value: %w", err))
+		}
+	}
+
+	since := until.Add(-30 * 24 * time.Hour)
+	if r.FormValue("since") != "" {
+		since, err = time.Parse(time.RFC3339, r.FormValue("since"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid since value: %w", err))
+		}
+	}
+
+	if !since.Before(until) {
+		return response.BadRequest(fmt.Errorf("The start of the period must be before its end"))
+	}
+
+	var usages []db.InstanceUsage
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the project exists.
+		_, err := cluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		usages, err = tx.GetProjectInstancesUsage(ctx, name, since, until)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	usage := api.ProjectUsage{
+		Since:     since,
+		Until:     until,
+		Instances: make([]api.ProjectInstanceUsage, 0, len(usages)),
+	}
+
+	for _, instUsage := range usages {
+		resourceUsage := api.ProjectResourceUsage{
+			CPUSeconds:           instUsage.CPUSeconds,
+			MemoryGiBHours:       instUsage.MemoryByteSeconds / (1024 * 1024 * 1024) / 3600,
+			DiskBytesRead:        instUsage.DiskReadBytes,
+			DiskBytesWritten:     instUsage.DiskWrittenBytes,
+			NetworkBytesReceived: instUsage.NetworkReceivedBytes,
+			NetworkBytesSent:     instUsage.NetworkSentBytes,
+		}
+
+		usage.Instances = append(usage.Instances, api.ProjectInstanceUsage{
+			ProjectResourceUsage: resourceUsage,
+			Name:                 instUsage.Instance,
+			Type:                 instUsage.Type.String(),
+		})
+
+		usage.Total.CPUSeconds += resourceUsage.CPUSeconds
+		usage.Total.MemoryGiBHours += resourceUsage.MemoryGiBHours
+		usage.Total.DiskBytesRead += resourceUsage.DiskBytesRead
+		usage.Total.DiskBytesWritten += resourceUsage.DiskBytesWritten
+		usage.Total.NetworkBytesReceived += resourceUsage.NetworkBytesReceived
+		usage.Total.NetworkBytesSent += resourceUsage.NetworkBytesSent
+	}
+
+	return response.SyncResponse(true, &usage)
+}
+
 // Check if a project is empty.
 func projectIsEmpty(ctx context.Context, project *cluster.Project, tx *db.ClusterTx) (bool, error) {
 	usedBy, err := projectUsedBy(ctx, tx, project)
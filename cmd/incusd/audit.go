@@ -0,0 +1,713 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// auditMaxErrorBody is the amount of an error response body kept to extract the error message from.
+const auditMaxErrorBody = 64 * 1024
+
+// auditDefaultLimit is the number of entries returned when the request doesn't set a limit.
+const auditDefaultLimit = 1000
+
+// auditPageSize is the number of entries loaded at once when filtering entries which can't be filtered by the database.
+const auditPageSize = 1000
+
+// auditRecordAttempts is the number of times writing an entry is attempted before giving up.
+const auditRecordAttempts = 3
+
+// auditFilterColumns maps the fields of audit log entries which can be filtered in the database to their column.
+var auditFilterColumns = map[string]string{
+	"location":         "location",
+	"username":         "username",
+	"protocol":         "protocol",
+	"address":          "source_address",
+	"method":           "method",
+	"url":              "url",
+	"entity":           "entity",
+	"status_code":      "status_code",
+	"error":            "error",
+	"operation_id":     "operation_id",
+	"operation_status": "operation_status",
+}
+
+var auditCmd = APIEndpoint{
+	Path: "audit",
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+}
+
+var auditEntryCmd = APIEndpoint{
+	Path: "audit/{id}",
+
+	Get: APIEndpointAction{Handler: auditEntryGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+}
+
+// auditResponseWriter wraps a http.ResponseWriter to keep track of the status code and error of a response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	errorBody  bytes.Buffer
+}
+
+// WriteHeader records the status code before passing it on.
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write keeps the beginning of error responses before passing the data on.
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	if w.statusCode >= http.StatusBadRequest && w.errorBody.Len() < auditMaxErrorBody {
+		w.errorBody.Write(b[:min(len(b), auditMaxErrorBody-w.errorBody.Len())])
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush passes flushes on to the underlying writer.
+func (w *auditResponseWriter) Flush() {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack passes connection hijacking on to the underlying writer.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("Response writer doesn't support hijacking")
+	}
+
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying writer.
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// auditIsMutation returns whether the request is a mutation of the API covered by the audit log.
+func auditIsMutation(r *http.Request, apiVersion string) bool {
+	if apiVersion != "1.0" {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// auditShouldRecord returns whether the request is a mutation which should be recorded in the audit log.
+// Requests forwarded by other cluster members are recorded by the member which received them.
+func auditShouldRecord(r *http.Request, apiVersion string, protocol string) bool {
+	return protocol != "cluster" && auditIsMutation(r, apiVersion)
+}
+
+// auditEntryFromResponse builds the audit log entry for a request from the response which was sent for it.
+func auditEntryFromResponse(r *http.Request, w *auditResponseWriter, username string, protocol string) api.AuditEntry {
+	entry := api.AuditEntry{
+		Date:       time.Now().UTC(),
+		Username:   username,
+		Protocol:   protocol,
+		Address:    r.RemoteAddr,
+		Method:     r.Method,
+		URL:        r.URL.RequestURI(),
+		StatusCode: w.statusCode,
+	}
+
+	if entry.StatusCode == 0 {
+		entry.StatusCode = http.StatusOK
+	}
+
+	// Background operations point at the operation, other requests may point at the created entity.
+	location := w.Header().Get("Location")
+	operationPrefix := fmt.Sprintf("/%s/operations/", version.APIVersion)
+	if strings.HasPrefix(location, operationPrefix) {
+		entry.OperationID = strings.TrimPrefix(location, operationPrefix)
+	} else if location != "" {
+		entry.Entity = location
+	}
+
+	if entry.Entity == "" {
+		entry.Entity = r.URL.Path
+
+		projectName := request.QueryParam(r, "project")
+		if projectName != "" {
+			entry.Entity += "?project=" + projectName
+		}
+	}
+
+	if entry.StatusCode >= http.StatusBadRequest {
+		errorResp := api.ResponseRaw{}
+
+		err := json.Unmarshal(w.errorBody.Bytes(), &errorResp)
+		if err == nil && errorResp.Error != "" {
+			entry.Error = errorResp.Error
+		} else {
+			entry.Error = http.StatusText(entry.StatusCode)
+		}
+	}
+
+	return entry
+}
+
+// auditRecord appends an entry to the audit log, retrying on failure.
+// Entries which can't be recorded raise a warning.
+func (d *Daemon) auditRecord(entry api.AuditEntry) {
+	s := d.State()
+	entry.Location = s.ServerName
+
+	var err error
+	for attempt := 1; attempt <= auditRecordAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(d.shutdownCtx, 10*time.Second)
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := tx.CreateAuditEntry(ctx, entry)
+			return err
+		})
+		cancel()
+
+		if err == nil || d.shutdownCtx.Err() != nil {
+			break
+		}
+
+		if attempt < auditRecordAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+
+	if err != nil {
+		logger.Error("Failed recording audit log entry", logger.Ctx{"method": entry.Method, "url": entry.URL, "username": entry.Username, "err": err})
+
+		warnErr := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", -1, -1, warningtype.AuditLogFailure, fmt.Sprintf("Failed recording %s %s by %q: %v", entry.Method, entry.URL, entry.Username, err))
+		})
+		if warnErr != nil {
+			logger.Warn("Failed to create warning", logger.Ctx{"err": warnErr})
+		}
+	}
+}
+
+// auditTrackOperation records the final status of a local operation in the audit log entry of the request which started it.
+// The entry of a forwarded request is recorded by the member which received it once the response is back,
+// so the update is retried until the entry shows up.
+func (d *Daemon) auditTrackOperation(operationID string) {
+	op, err := operations.OperationGetInternal(operationID)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		opErr := op.Wait(d.shutdownCtx)
+		if d.shutdownCtx.Err() != nil {
+			return
+		}
+
+		status := op.Status().String()
+		errMsg := ""
+		if opErr != nil {
+			errMsg = opErr.Error()
+		}
+
+		s := d.State()
+
+		for attempt := 1; attempt <= 5; attempt++ {
+			var updated int64
+
+			err := s.DB.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+				var err error
+				updated, err = tx.UpdateAuditEntryOperation(ctx, operationID, status, errMsg)
+				return err
+			})
+			if err == nil && updated > 0 {
+				return
+			}
+
+			if err != nil {
+				logger.Warn("Failed recording operation status in audit log", logger.Ctx{"operation": operationID, "err": err})
+			}
+
+			select {
+			case <-d.shutdownCtx.Done():
+				return
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}()
+}
+
+// auditEnabled returns whether the audit log is enabled.
+func (d *Daemon) auditEnabled() bool {
+	d.globalConfigMu.Lock()
+	defer d.globalConfigMu.Unlock()
+
+	return d.globalConfig != nil && d.globalConfig.AuditEnabled()
+}
+
+// auditFilterSQL converts the filter into a SQL condition on the audit_log table.
+// It returns false when the filter uses fields, operators or patterns which can't be evaluated by the database.
+func auditFilterSQL(clauses *filter.ClauseSet) (string, []any, bool) {
+	where := ""
+	args := []any{}
+
+	for _, clause := range clauses.Clauses {
+		column, ok := auditFilterColumns[clause.Field]
+		if !ok {
+			return "", nil, false
+		}
+
+		var operator string
+		switch clause.Operator {
+		case clauses.Ops.Equals:
+			operator = "="
+		case clauses.Ops.NotEquals:
+			operator = "!="
+		default:
+			return "", nil, false
+		}
+
+		var condition string
+		if column == "status_code" {
+			value, err := strconv.Atoi(clause.Value)
+			if err != nil {
+				return "", nil, false
+			}
+
+			condition = fmt.Sprintf("%s %s ?", column, operator)
+			args = append(args, value)
+		} else {
+			// Values are otherwise matched as case insensitive patterns or lists.
+			// Only patterns made of ASCII characters and "." or ".*" wildcards match the same way in SQL.
+			pattern, ok := auditLikePattern(clause.Value)
+			if !ok {
+				return "", nil, false
+			}
+
+			if operator == "=" {
+				operator = "LIKE"
+			} else {
+				operator = "NOT LIKE"
+			}
+
+			condition = fmt.Sprintf(`%s %s ? ESCAPE '\'`, column, operator)
+			args = append(args, pattern)
+		}
+
+		if clause.Not {
+			condition = "NOT " + condition
+		}
+
+		// Clauses are combined from left to right.
+		switch {
+		case where == "":
+			where = condition
+		case clause.PrevLogical == clauses.Ops.Or:
+			where = fmt.Sprintf("(%s) OR %s", where, condition)
+		default:
+			where = fmt.Sprintf("(%s) AND %s", where, condition)
+		}
+	}
+
+	return where, args, true
+}
+
+// auditLikePattern converts a filter value into the equivalent case insensitive SQL LIKE pattern.
+func auditLikePattern(value string) (string, bool) {
+	if strings.Contains(value, ",") {
+		return "", false
+	}
+
+	var pattern strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c >= utf8.RuneSelf:
+			return "", false
+		case c == '.' && i+1 < len(value) && value[i+1] == '*':
+			pattern.WriteByte('%')
+			i++
+		case c == '.':
+			pattern.WriteByte('_')
+		case c == '%' || c == '_':
+			pattern.WriteByte('\\')
+			pattern.WriteByte(c)
+		case regexp.QuoteMeta(string(c)) != string(c):
+			return "", false
+		default:
+			pattern.WriteByte(c)
+		}
+	}
+
+	return pattern.String(), true
+}
+
+// getAuditEntries returns the most recent entries matching the filter, up to limit, most recent first.
+// Filters which the database can't evaluate are applied while paging through the entries.
+func getAuditEntries(ctx context.Context, tx *db.ClusterTx, dbFilter db.AuditEntryFilter, clauses *filter.ClauseSet, limit int) ([]api.AuditEntry, error) {
+	where, args, ok := auditFilterSQL(clauses)
+	if ok {
+		dbFilter.Where = where
+		dbFilter.Args = args
+		dbFilter.Limit = limit
+
+		return tx.GetAuditEntries(ctx, dbFilter)
+	}
+
+	entries := []api.AuditEntry{}
+	dbFilter.Limit = auditPageSize
+
+	for {
+		page, err := tx.GetAuditEntries(ctx, dbFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range page {
+			match, err := filter.Match(entry, *clauses)
+			if err != nil {
+				return nil, err
+			}
+
+			if !match {
+				continue
+			}
+
+			entries = append(entries, entry)
+			if len(entries) == limit {
+				return entries, nil
+			}
+		}
+
+		if len(page) < auditPageSize {
+			return entries, nil
+		}
+
+		dbFilter.BeforeID = page[len(page)-1].ID
+	}
+}
+
+// swagger:operation GET /1.0/audit audit audit_get
+//
+//	List the audit log entries
+//
+//	Returns a list of audit log entries (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: since
+//	    description: Only return entries recorded at or after this time (RFC3339)
+//	    type: string
+//	    example: 2024-01-01T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: Only return entries recorded before this time (RFC3339)
+//	    type: string
+//	    example: 2024-02-01T00:00:00Z
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return, the most recent ones being kept (defaults to 1000)
+//	    type: integer
+//	    example: 100
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/audit/1",
+//	              "/1.0/audit/2"
+//	            ]
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/audit?recursion=1 audit audit_get_recursion1
+//
+//	Get the audit log entries
+//
+//	Returns a list of audit log entries (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: since
+//	    description: Only return entries recorded at or after this time (RFC3339)
+//	    type: string
+//	    example: 2024-01-01T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: Only return entries recorded before this time (RFC3339)
+//	    type: string
+//	    example: 2024-02-01T00:00:00Z
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return, the most recent ones being kept (defaults to 1000)
+//	    type: integer
+//	    example: 100
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit log entries
+//	          items:
+//	            $ref: "#/definitions/AuditEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	recursion := localUtil.IsRecursionRequest(r)
+
+	clauses, err := filter.Parse(r.FormValue("filter"), filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	var since, until time.Time
+
+	if r.FormValue("since") != "" {
+		since, err = time.Parse(time.RFC3339, r.FormValue("since"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid since value: %w", err))
+		}
+	}
+
+	if r.FormValue("until") != "" {
+		until, err = time.Parse(time.RFC3339, r.FormValue("until"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid until value: %w", err))
+		}
+	}
+
+	limit := auditDefaultLimit
+	if r.FormValue("limit") != "" {
+		limit, err = strconv.Atoi(r.FormValue("limit"))
+		if err != nil || limit <= 0 {
+			return response.BadRequest(fmt.Errorf("Invalid limit value %q", r.FormValue("limit")))
+		}
+	}
+
+	var entries []api.AuditEntry
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		entries, err = getAuditEntries(ctx, tx, db.AuditEntryFilter{Since: since, Until: until}, clauses, limit)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Return the entries oldest first.
+	slices.Reverse(entries)
+
+	if recursion {
+		return response.SyncResponse(true, entries)
+	}
+
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "audit", strconv.FormatInt(entry.ID, 10)).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation GET /1.0/audit/{id} audit audit_entry_get
+//
+//	Get the audit log entry
+//
+//	Gets a specific audit log entry.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Audit log entry
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuditEntry"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditEntryGet(d *Daemon, r *http.Request) response.Response {
+	idStr, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid audit log entry ID %q", idStr))
+	}
+
+	var entry *api.AuditEntry
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		entry, err = tx.GetAuditEntry(ctx, id)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entry)
+}
+
+func pruneExpiredAuditEntriesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Only prune on the leader as the audit log is shared by the whole cluster.
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			return
+		}
+
+		expiry := s.GlobalConfig.AuditExpiryDays()
+		if expiry <= 0 {
+			return
+		}
+
+		var deleted int64
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			deleted, err = tx.DeleteAuditEntriesBefore(ctx, time.Now().AddDate(0, 0, -int(expiry)))
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed pruning expired audit log entries", logger.Ctx{"err": err})
+			return
+		}
+
+		if deleted > 0 {
+			logger.Info("Pruned expired audit log entries", logger.Ctx{"count": deleted})
+		}
+	}
+
+	return f, task.Daily()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/filter"
+)
+
+// Test that only mutations received directly by the server are recorded.
+func TestAuditShouldRecord(t *testing.T) {
+	cases := []struct {
+		method     string
+		apiVersion string
+		protocol   string
+		want       bool
+	}{
+		{http.MethodGet, "1.0", "tls", false},
+		{http.MethodPost, "1.0", "tls", true},
+		{http.MethodDelete, "1.0", "oidc", true},
+		{http.MethodPatch, "1.0", "unix", true},
+		{http.MethodPut, "1.0", "cluster", false},
+		{http.MethodPost, "internal", "unix", false},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, "/1.0/instances", nil)
+		assert.Equal(t, c.want, auditShouldRecord(r, c.apiVersion, c.protocol), "%s %s %s", c.method, c.apiVersion, c.protocol)
+	}
+}
+
+// Test that the entity, operation and error are extracted from the response.
+func TestAuditEntryFromResponse(t *testing.T) {
+	// Background operation.
+	r := httptest.NewRequest(http.MethodPost, "/1.0/instances?project=p1", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	w := &auditResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	w.Header().Set("Location", "/1.0/operations/b0a2c1e4")
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(`{"type":"async"}`))
+
+	entry := auditEntryFromResponse(r, w, "alice", "tls")
+	assert.Equal(t, "alice", entry.Username)
+	assert.Equal(t, "tls", entry.Protocol)
+	assert.Equal(t, "10.0.0.1:1234", entry.Address)
+	assert.Equal(t, "/1.0/instances?project=p1", entry.URL)
+	assert.Equal(t, "/1.0/instances?project=p1", entry.Entity)
+	assert.Equal(t, "b0a2c1e4", entry.OperationID)
+	assert.Equal(t, http.StatusAccepted, entry.StatusCode)
+	assert.Empty(t, entry.Error)
+
+	// Created entity.
+	r = httptest.NewRequest(http.MethodPost, "/1.0/networks", nil)
+	w = &auditResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	w.Header().Set("Location", "/1.0/networks/n1")
+	w.WriteHeader(http.StatusCreated)
+
+	entry = auditEntryFromResponse(r, w, "alice", "tls")
+	assert.Equal(t, "/1.0/networks/n1", entry.Entity)
+	assert.Empty(t, entry.OperationID)
+
+	// Failure.
+	r = httptest.NewRequest(http.MethodDelete, "/1.0/instances/c1", nil)
+	w = &auditResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"type":"error","error":"Instance not found","error_code":404}`))
+
+	entry = auditEntryFromResponse(r, w, "bob", "oidc")
+	assert.Equal(t, "/1.0/instances/c1", entry.Entity)
+	assert.Equal(t, http.StatusNotFound, entry.StatusCode)
+	assert.Equal(t, "Instance not found", entry.Error)
+}
+
+// Test that filters are converted into SQL conditions only when the database evaluates them the same way.
+func TestAuditFilterSQL(t *testing.T) {
+	cases := []struct {
+		filter string
+		where  string
+		args   []any
+		ok     bool
+	}{
+		{"", "", []any{}, true},
+		{"username eq alice", `username LIKE ? ESCAPE '\'`, []any{"alice"}, true},
+		{"address ne 10.0.0.1:1234", `source_address NOT LIKE ? ESCAPE '\'`, []any{"10_0_0_1:1234"}, true},
+		{"url eq /1.0/instances.*", `url LIKE ? ESCAPE '\'`, []any{"/1_0/instances%"}, true},
+		{"error eq 100%_done", `error LIKE ? ESCAPE '\'`, []any{`100\%\_done`}, true},
+		{"status_code eq 404", "status_code = ?", []any{404}, true},
+		{"method eq DELETE or not operation_status eq Success and location eq server01", `((method LIKE ? ESCAPE '\') OR NOT operation_status LIKE ? ESCAPE '\') AND location LIKE ? ESCAPE '\'`, []any{"DELETE", "Success", "server01"}, true},
+
+		// Other patterns, lists and fields which aren't columns are left to the server.
+		{"username eq ali(ce)?", "", nil, false},
+		{"username eq ^alice", "", nil, false},
+		{"method eq PUT,POST", "", nil, false},
+		{"username eq élodie", "", nil, false},
+		{"status_code eq abc", "", nil, false},
+		{"date eq 2024-01-01", "", nil, false},
+	}
+
+	for _, c := range cases {
+		clauses, err := filter.Parse(c.filter, filter.QueryOperatorSet())
+		require.NoError(t, err)
+
+		where, args, ok := auditFilterSQL(clauses)
+		assert.Equal(t, c.ok, ok, c.filter)
+		assert.Equal(t, c.where, where, c.filter)
+		assert.Equal(t, c.args, args, c.filter)
+	}
+}
@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/instancewriter"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -23,8 +28,10 @@ import (
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/archive"
 	"github.com/lxc/incus/v6/shared/idmap"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -34,8 +41,9 @@ import (
 )
 
 // Create a new backup.
-func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, op *operations.Operation) error {
-	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": args.Name})
+// If incrementalFrom is set, the backup only contains the changes made since that snapshot.
+func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, incrementalFrom string, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": args.Name, "incrementalFrom": incrementalFrom})
 	l.Debug("Instance backup started")
 	defer l.Debug("Instance backup finished")
 
@@ -48,6 +56,11 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Failed loading instance storage pool: %w", err)
 	}
 
+	// Incremental backups rely on the pool driver's optimized format.
+	if incrementalFrom != "" && (!args.OptimizedStorage || !pool.Driver().Info().OptimizedBackups) {
+		return fmt.Errorf("Incremental backups require optimized storage on a storage pool driver that supports it")
+	}
+
 	// Ignore requests for optimized backups when pool driver doesn't support it.
 	if args.OptimizedStorage && !pool.Driver().Info().OptimizedBackups {
 		args.OptimizedStorage = false
@@ -105,28 +118,16 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		}
 	}
 
-	// Create the target path if needed.
-	backupsPath := internalUtil.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, sourceInst.Name()))
-	if !util.PathExists(backupsPath) {
-		err := os.MkdirAll(backupsPath, 0o700)
-		if err != nil {
-			return err
-		}
-
-		reverter.Add(func() { _ = os.Remove(backupsPath) })
-	}
-
-	target := internalUtil.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, b.Name()))
-
 	// Setup the tarball writer.
-	l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-	tarFileWriter, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+	target := b.Target()
+	l.Debug("Opening backup tarball for writing", logger.Ctx{"target": target.Name(), "path": b.Path()})
+	tarFileWriter, err := target.Create(b.Path())
 	if err != nil {
-		return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		return fmt.Errorf("Error opening backup tarball for writing %q: %w", b.Path(), err)
 	}
 
 	defer func() { _ = tarFileWriter.Close() }()
-	reverter.Add(func() { _ = os.Remove(target) })
+	reverter.Add(func() { _ = target.Delete(b.Path()) })
 
 	// Get IDMap to unshift container as the tarball is created.
 	var idmapSet *idmap.Set
@@ -183,7 +184,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), tarWriter)
+	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), incrementalFrom, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -195,7 +196,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), nil)
+	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), incrementalFrom, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -229,7 +230,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 }
 
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, incrementalFrom string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -264,6 +265,7 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		IncrementalFrom:  incrementalFrom,
 	}
 
 	if snapshots {
@@ -271,6 +273,16 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		for _, s := range config.Snapshots {
 			indexInfo.Snapshots = append(indexInfo.Snapshots, s.Name)
 		}
+
+		// Incremental backups only contain the snapshots taken after their base snapshot.
+		if incrementalFrom != "" {
+			idx := slices.Index(indexInfo.Snapshots, incrementalFrom)
+			if idx < 0 {
+				return fmt.Errorf("Incremental base snapshot %q not found", incrementalFrom)
+			}
+
+			indexInfo.Snapshots = indexInfo.Snapshots[idx+1:]
+		}
 	}
 
 	// Convert to YAML.
@@ -359,6 +371,473 @@ func pruneExpiredBackupsTask(d *Daemon) (task.Func, task.Schedule) {
 	return f, schedule
 }
 
+// backupNextName returns the next free "backup%d" name for a backup of the given parent, taking into account
+// the existing backup names (in "<parent>/<backup>" form).
+func backupNextName(parentName string, backupNames []string) string {
+	base := parentName + internalInstance.SnapshotDelimiter + "backup"
+	length := len(base)
+	max := 0
+
+	for _, backupName := range backupNames {
+		// Ignore backups not containing base.
+		if !strings.HasPrefix(backupName, base) {
+			continue
+		}
+
+		substr := backupName[length:]
+		var num int
+		count, err := fmt.Sscanf(substr, "%d", &num)
+		if err != nil || count != 1 {
+			continue
+		}
+
+		if num >= max {
+			max = num + 1
+		}
+	}
+
+	return fmt.Sprintf("backup%d", max)
+}
+
+func autoCreateAndRotateBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	// `f` creates new scheduled instance and custom volume backups and then rotates them based on retention.
+	f := func(ctx context.Context) {
+		s := d.State()
+		var instances []instance.Instance
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		// Get list of instances on the local member that are due to have backups creating.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// Check if instance has backup schedule enabled.
+				schedule, ok := inst.ExpandedConfig()["backups.schedule"]
+				if !ok || schedule == "" {
+					return nil
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+					return nil
+				}
+
+				// If backup should only be taken if instance is running, check if running.
+				if util.IsFalseOrEmpty(inst.ExpandedConfig()["backups.schedule.stopped"]) && !inst.IsRunning() {
+					return nil
+				}
+
+				err = project.AllowBackupCreation(tx, dbInst.Project)
+				if err != nil {
+					return nil
+				}
+
+				logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		// Get list of custom volumes that are due to have backups creating.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule, ok := v.Config["backups.schedule"]
+				if !ok || schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = project.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the backup later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(remoteVolumes) > 0 {
+			// Skip backing up remote custom volumes if there are no online members, as we can't be
+			// sure that the cluster isn't partitioned and we may end up attempting the backup on
+			// multiple members.
+			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+				logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+			} else {
+				localMemberID := s.DB.Cluster.GetNodeID()
+
+				for _, v := range remoteVolumes {
+					// If there are multiple cluster members, a stable random member is chosen
+					// to perform the backup from. This avoids taking the backup on every member.
+					if memberCount > 1 {
+						selectedNodeID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
+						if err != nil {
+							logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+							continue
+						}
+
+						// Don't back up, if we're not the chosen one.
+						if localMemberID != selectedNodeID {
+							continue
+						}
+					}
+
+					logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v)
+				}
+			}
+		}
+
+		// Handle instance backup auto creation.
+		if len(instances) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateInstanceBackups(ctx, s, instances, op)
+			}
+
+			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupCreate, nil, nil, opRun, nil, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled instance backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled instance backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled instance backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled instance backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled instance backups")
+					}
+				}
+			}
+		}
+
+		// Handle custom volume backup auto creation.
+		if len(volumes) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateCustomVolumeBackups(ctx, s, volumes)
+			}
+
+			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, nil, nil, opRun, nil, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled volume backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled volume backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled volume backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled custom volume backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled volume backups")
+					}
+				}
+			}
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+func autoCreateInstanceBackups(ctx context.Context, s *state.State, instances []instance.Instance, op *operations.Operation) error {
+	// Make the backups sequentially.
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		backups, err := inst.Backups()
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		backupNames := make([]string, 0, len(backups))
+		for _, b := range backups {
+			backupNames = append(backupNames, b.Name())
+		}
+
+		expiry, err := internalInstance.GetExpiry(time.Now(), inst.ExpandedConfig()["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Failed getting backup expiry for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		args := db.InstanceBackup{
+			Name:         inst.Name() + internalInstance.SnapshotDelimiter + backupNextName(inst.Name(), backupNames),
+			InstanceID:   inst.ID(),
+			CreationDate: time.Now(),
+			ExpiryDate:   expiry,
+			Target:       inst.ExpandedConfig()["backups.target"],
+			Scheduled:    true,
+		}
+
+		err = backupCreate(s, args, inst, "", op)
+		if err != nil {
+			return fmt.Errorf("Failed creating backup %q for instance %q (project %q): %w", args.Name, inst.Name(), inst.Project().Name, err)
+		}
+
+		err = rotateInstanceBackups(ctx, s, inst)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rotateInstanceBackups deletes the oldest scheduled backups of the instance until no more than backups.retention
+// remain. Backups created manually are left alone.
+func rotateInstanceBackups(ctx context.Context, s *state.State, inst instance.Instance) error {
+	retention := inst.ExpandedConfig()["backups.retention"]
+	if retention == "" {
+		return nil
+	}
+
+	keep, err := strconv.Atoi(retention)
+	if err != nil {
+		return fmt.Errorf("Invalid backups.retention value %q: %w", retention, err)
+	}
+
+	if keep == 0 {
+		return nil
+	}
+
+	allBackups, err := inst.Backups()
+	if err != nil {
+		return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	backups := make([]backup.InstanceBackup, 0, len(allBackups))
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, b := range allBackups {
+			dbBackup, err := tx.GetInstanceBackup(ctx, inst.Project().Name, b.Name())
+			if err != nil {
+				return err
+			}
+
+			if dbBackup.Scheduled {
+				backups = append(backups, b)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	if len(backups) <= keep {
+		return nil
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreationDate().Before(backups[j].CreationDate())
+	})
+
+	for _, b := range backups[:len(backups)-keep] {
+		err = b.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting instance backup %q: %w", b.Name(), err)
+		}
+
+		logger.Debug("Rotated instance backup", logger.Ctx{"project": inst.Project().Name, "backup": b.Name()})
+	}
+
+	return nil
+}
+
+func autoCreateCustomVolumeBackups(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs) error {
+	// Make the backups sequentially.
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		var backupNames []string
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			poolID, err := tx.GetStoragePoolID(ctx, v.PoolName)
+			if err != nil {
+				return err
+			}
+
+			backupNames, err = tx.GetStoragePoolVolumeBackupsNames(ctx, v.ProjectName, v.Name, poolID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		expiry, err := internalInstance.GetExpiry(time.Now(), v.Config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Failed getting backup expiry for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		args := db.StoragePoolVolumeBackup{
+			Name:         v.Name + internalInstance.SnapshotDelimiter + backupNextName(v.Name, backupNames),
+			VolumeID:     v.ID,
+			CreationDate: time.Now(),
+			ExpiryDate:   expiry,
+			Scheduled:    true,
+			Target:       v.Config["backups.target"],
+		}
+
+		err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name)
+		if err != nil {
+			return fmt.Errorf("Failed creating backup %q for volume %q (project %q, pool %q): %w", args.Name, v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, nil, logger.Ctx{"type": db.StoragePoolVolumeTypeNameCustom}))
+
+		err = rotateCustomVolumeBackups(ctx, s, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rotateCustomVolumeBackups deletes the oldest scheduled backups of the volume until no more than backups.retention
+// remain. Backups created manually are left alone.
+func rotateCustomVolumeBackups(ctx context.Context, s *state.State, v db.StorageVolumeArgs) error {
+	retention := v.Config["backups.retention"]
+	if retention == "" {
+		return nil
+	}
+
+	keep, err := strconv.Atoi(retention)
+	if err != nil {
+		return fmt.Errorf("Invalid backups.retention value %q: %w", retention, err)
+	}
+
+	if keep == 0 {
+		return nil
+	}
+
+	var backups []db.StoragePoolVolumeBackup
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, v.PoolName)
+		if err != nil {
+			return err
+		}
+
+		allBackups, err := tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, poolID)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range allBackups {
+			if b.Scheduled {
+				backups = append(backups, b)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading backups of volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+	}
+
+	if len(backups) <= keep {
+		return nil
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreationDate.Before(backups[j].CreationDate)
+	})
+
+	// The deletion is done outside of the transaction to avoid any unnecessary IO while inside of
+	// the transaction.
+	for _, b := range backups[:len(backups)-keep] {
+		target, err := instance.BackupTargetLoad(s, b.Target)
+		if err != nil {
+			return fmt.Errorf("Failed loading target of storage volume backup %q: %w", b.Name, err)
+		}
+
+		volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, target)
+		err = volBackup.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting storage volume backup %q: %w", b.Name, err)
+		}
+
+		logger.Debug("Rotated storage volume backup", logger.Ctx{"project": v.ProjectName, "pool": v.PoolName, "backup": b.Name})
+	}
+
+	return nil
+}
+
 func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	var backups []db.InstanceBackup
 
@@ -378,7 +857,11 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 			return fmt.Errorf("Error loading instance for deleting backup %q: %w", b.Name, err)
 		}
 
-		instBackup := backup.NewInstanceBackup(s, inst, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.InstanceOnly, b.OptimizedStorage)
+		instBackup, err := instance.BackupLoadByName(s, inst.Project().Name, b.Name)
+		if err != nil {
+			return fmt.Errorf("Error loading instance backup %q: %w", b.Name, err)
+		}
+
 		err = instBackup.Delete()
 		if err != nil {
 			return fmt.Errorf("Error deleting instance backup %q: %w", b.Name, err)
@@ -446,28 +929,23 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		compress = s.GlobalConfig.BackupsCompressionAlgorithm()
 	}
 
-	// Create the target path if needed.
-	backupsPath := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, volumeName))
-	if !util.PathExists(backupsPath) {
-		err := os.MkdirAll(backupsPath, 0o700)
-		if err != nil {
-			return err
-		}
-
-		reverter.Add(func() { _ = os.Remove(backupsPath) })
+	// Load the target the backup is stored on.
+	target, err := instance.BackupTargetLoad(s, backupRow.Target)
+	if err != nil {
+		return err
 	}
 
-	target := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
+	b := backup.NewVolumeBackup(s, projectName, pool.Name(), volumeName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage, target)
 
 	// Setup the tarball writer.
-	l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-	tarFileWriter, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
+	l.Debug("Opening backup tarball for writing", logger.Ctx{"target": target.Name(), "path": b.Path()})
+	tarFileWriter, err := target.Create(b.Path())
 	if err != nil {
-		return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		return fmt.Errorf("Error opening backup tarball for writing %q: %w", b.Path(), err)
 	}
 
 	defer func() { _ = tarFileWriter.Close() }()
-	reverter.Add(func() { _ = os.Remove(target) })
+	reverter.Add(func() { _ = target.Delete(b.Path()) })
 
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
@@ -618,7 +1096,13 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 				continue
 			}
 
-			volBackup := backup.NewVolumeBackup(s, vol.ProjectName, vol.PoolName, vol.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+			target, err := instance.BackupTargetLoad(s, b.Target)
+			if err != nil {
+				logger.Warn("Failed loading target of backup", logger.Ctx{"backup": b.Name, "err": err})
+				continue
+			}
+
+			volBackup := backup.NewVolumeBackup(s, vol.ProjectName, vol.PoolName, vol.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, target)
 
 			volumeBackups = append(volumeBackups, volBackup)
 		}
@@ -864,3 +1348,94 @@ func pruneExpiredStorageBucketBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+// backupOpenStored opens a backup tarball stored on a backup target to restore it.
+//
+// The tarball is read straight from the target, which supports the seeking done while restoring, so that no
+// local space is needed. Squashfs backups are the exception as unsquashfs can only read a local file. Those are
+// downloaded into the backups directory and decompressed there, which needs free space for both the backup and
+// its decompressed tarball.
+//
+// The returned function closes the tarball and removes any temporary files.
+func backupOpenStored(target backup.Target, path string) (io.ReadSeeker, func(), error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	tarball, _, err := target.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reverter.Add(func() { _ = tarball.Close() })
+
+	// Detect squashfs compression, reading the whole header even from slow targets.
+	header := make([]byte, 263)
+	_, err = io.ReadFull(tarball, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+
+	_, algo, decomArgs, err := archive.DetectCompressionFile(bytes.NewReader(header))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if algo != ".squashfs" {
+		_, err = tarball.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cleanup := reverter.Clone().Fail
+		reverter.Success()
+
+		return tarball, cleanup, nil
+	}
+
+	// Download the backup into a temporary file to pass it to the decompression command.
+	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		_ = backupFile.Close()
+		_ = os.Remove(backupFile.Name())
+	}()
+
+	_, err = tarball.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = io.Copy(backupFile, tarball)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create temporary file to store the decompressed tarball in.
+	tarFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_decompress_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reverter.Add(func() {
+		_ = tarFile.Close()
+		_ = os.Remove(tarFile.Name())
+	})
+
+	err = archive.ExtractWithFds(decomArgs[0], append(decomArgs[1:], backupFile.Name()), nil, nil, tarFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tarFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return tarFile, cleanup, nil
+}
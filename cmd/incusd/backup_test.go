@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the next backup name takes into account existing numbered backups of the same parent only.
+func TestBackupNextName(t *testing.T) {
+	assert.Equal(t, "backup0", backupNextName("c1", nil))
+	assert.Equal(t, "backup0", backupNextName("c1", []string{"c1/custom", "c2/backup4"}))
+	assert.Equal(t, "backup3", backupNextName("c1", []string{"c1/backup0", "c1/backup2", "c1/custom"}))
+	assert.Equal(t, "backup1", backupNextName("c1", []string{"c10/backup5", "c1/backup0"}))
+}
@@ -563,7 +563,7 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 
 	// Access check.
 	// Check if the user is already trusted.
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -40,6 +40,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/daemon"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/db/query"
 	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/dns"
@@ -51,10 +52,12 @@ import (
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/logging"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/network/ovn"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
 	networkZone "github.com/lxc/incus/v6/internal/server/network/zone"
 	"github.com/lxc/incus/v6/internal/server/node"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -106,6 +109,7 @@ type Daemon struct {
 	// Indexes of tasks that need to be reset when their execution interval changes
 	taskPruneImages      *task.Task
 	taskClusterHeartbeat *task.Task
+	taskPushMetrics      *task.Task
 
 	// Stores startup time of daemon
 	startTime time.Time
@@ -416,7 +420,7 @@ func allowPermission(objectType auth.ObjectType, entitlement auth.Entitlement, m
 
 // Convenience function around Authenticate.
 func (d *Daemon) checkTrustedClient(r *http.Request) error {
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if !trusted || err != nil {
 		if err != nil {
 			return err
@@ -473,12 +477,12 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // will validate the TLS certificate.
 //
 // This does not perform authorization, only validates authentication.
-// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
-// client that has been authenticated (cluster, unix, or tls).
-func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, the type of
+// client that has been authenticated (cluster, unix, or tls) and the OIDC groups of the client (if any).
+func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, []string, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
-		return false, "", "", err
+		return false, "", "", nil, err
 	}
 
 	// Allow internal cluster traffic by checking against the trusted certfificates.
@@ -486,7 +490,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, fingerprint := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeServer], d.endpoints.NetworkCert(), false)
 			if trusted {
-				return true, fingerprint, "cluster", nil
+				return true, fingerprint, "cluster", nil, nil
 			}
 		}
 	}
@@ -496,38 +500,38 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		if w != nil {
 			cred, err := ucred.GetCredFromContext(r.Context())
 			if err != nil {
-				return false, "", "", err
+				return false, "", "", nil, err
 			}
 
 			u, err := user.LookupId(fmt.Sprintf("%d", cred.Uid))
 			if err != nil {
-				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil
+				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil, nil
 			}
 
-			return true, u.Username, "unix", nil
+			return true, u.Username, "unix", nil, nil
 		}
 
-		return true, "", "unix", nil
+		return true, "", "unix", nil, nil
 	}
 
 	// DevIncus unix socket credentials on main API.
 	if r.RemoteAddr == "@dev_incus" {
-		return false, "", "", fmt.Errorf("Main API query can't come from /dev/incus socket")
+		return false, "", "", nil, fmt.Errorf("Main API query can't come from /dev/incus socket")
 	}
 
 	// Cluster notification with wrong certificate.
 	if isClusterNotification(r) {
-		return false, "", "", fmt.Errorf("Cluster notification isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster notification isn't using trusted server certificate")
 	}
 
 	// Cluster internal client with wrong certificate.
 	if isClusterInternal(r) {
-		return false, "", "", fmt.Errorf("Cluster internal client isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster internal client isn't using trusted server certificate")
 	}
 
 	// Bad query, no TLS found.
 	if r.TLS == nil {
-		return false, "", "", fmt.Errorf("Bad/missing TLS on network query")
+		return false, "", "", nil, fmt.Errorf("Bad/missing TLS on network query")
 	}
 
 	// Load the certificates.
@@ -538,18 +542,18 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
-		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
+		userName, groups, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
 		if err != nil {
-			return false, "", "", err
+			return false, "", "", nil, err
 		}
 
-		return true, userName, api.AuthenticationMethodOIDC, nil
+		return true, userName, api.AuthenticationMethodOIDC, groups, nil
 	}
 
 	// Validate metrics TLS certificates.
@@ -557,7 +561,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
 			if trusted {
-				return true, username, api.AuthenticationMethodTLS, nil
+				return true, username, api.AuthenticationMethodTLS, nil, nil
 			}
 		}
 	}
@@ -566,12 +570,12 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Reject unauthorized.
-	return false, "", "", nil
+	return false, "", "", nil, nil
 }
 
 // State creates a new State instance linked to our internal db and os.
@@ -646,7 +650,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		// Authentication
-		trusted, username, protocol, err := d.Authenticate(w, r)
+		trusted, username, protocol, oidcGroups, err := d.Authenticate(w, r)
 		if err != nil {
 			_, ok := err.(*oidc.AuthError)
 			if ok {
@@ -684,6 +688,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			// Add authentication/authorization context data.
 			ctx := context.WithValue(r.Context(), request.CtxUsername, username)
 			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+			ctx = context.WithValue(ctx, request.CtxOIDCGroups, oidcGroups)
 
 			// Add forwarded requestor data.
 			if protocol == "cluster" {
@@ -691,6 +696,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				ctx = context.WithValue(ctx, request.CtxForwardedAddress, r.Header.Get(request.HeaderForwardedAddress))
 				ctx = context.WithValue(ctx, request.CtxForwardedUsername, r.Header.Get(request.HeaderForwardedUsername))
 				ctx = context.WithValue(ctx, request.CtxForwardedProtocol, r.Header.Get(request.HeaderForwardedProtocol))
+				ctx = context.WithValue(ctx, request.CtxForwardedOIDCGroups, request.ParseForwardedOIDCGroups(r.Header.Get(request.HeaderForwardedOIDCGroups)))
 			}
 
 			r = r.WithContext(ctx)
@@ -800,6 +806,24 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			_ = d.oidcVerifier.WriteHeaders(w)
 		}
 
+		// Record mutations in the audit log once the response is written and track the operations they start.
+		// Forwarded requests are recorded by the member which received them but their operations run here.
+		if trusted && auditIsMutation(r, version) && d.auditEnabled() {
+			auditWriter := &auditResponseWriter{ResponseWriter: w}
+			w = auditWriter
+
+			defer func() {
+				entry := auditEntryFromResponse(r, auditWriter, username, protocol)
+				if auditShouldRecord(r, version, protocol) {
+					d.auditRecord(entry)
+				}
+
+				if entry.OperationID != "" {
+					d.auditTrackOperation(entry.OperationID)
+				}
+			}()
+		}
+
 		// Handle errors
 		err = resp.Render(w)
 		if err != nil {
@@ -1427,13 +1451,17 @@ func (d *Daemon) init() error {
 	bgpASN = d.globalConfig.BGPASN()
 
 	d.proxy = proxy.FromConfig(d.globalConfig.ProxyHTTPS(), d.globalConfig.ProxyHTTP(), d.globalConfig.ProxyIgnoreHosts())
+	imageDownloads.configure(d.globalConfig.ImagesDownloadConcurrency(), d.globalConfig.ImagesDownloadBandwidthLimit())
+	localMigration.SetServerBandwidthLimit(d.globalConfig.MigrationBandwidthLimit())
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	oidcGroupsClaim := d.globalConfig.OIDCGroupsClaim()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
 	authorizationScriptlet := d.globalConfig.AuthorizationScriptlet()
+	authorizationBuiltin := d.globalConfig.AuthorizationBuiltin()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
 	d.globalConfigMu.Unlock()
@@ -1454,7 +1482,7 @@ func (d *Daemon) init() error {
 
 	// Setup OIDC authentication.
 	if oidcIssuer != "" && oidcClientID != "" {
-		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 		if err != nil {
 			return err
 		}
@@ -1476,8 +1504,19 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the built-in authorization.
+	if authorizationBuiltin {
+		err = d.setupBuiltinAuthorization(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Setup BGP listener.
-	d.bgp = bgp.NewServer()
+	d.bgp = bgp.NewServer(func() error {
+		return networkUpdateBGPRoutes(d.State())
+	})
+
 	if bgpAddress != "" && bgpASN != 0 && bgpRouterID != "" {
 		err := d.bgp.Configure(bgpAddress, uint32(bgpASN), net.ParseIP(bgpRouterID))
 		if err != nil {
@@ -1521,6 +1560,13 @@ func (d *Daemon) init() error {
 			resp.Content = strings.TrimSpace(zoneBuilder.String())
 		}
 
+		// Load the DNSSEC keys.
+		resp.Keys, err = zone.DNSSECKeys()
+		if err != nil {
+			logger.Errorf("Failed to load DNSSEC keys of zone %q: %v", name, err)
+			return nil, err
+		}
+
 		return resp, nil
 	})
 	if dnsAddress != "" {
@@ -1682,6 +1728,9 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Take scheduled backups of instances and custom volumes and rotate them (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateAndRotateBackupsTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
@@ -1690,6 +1739,23 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Push metrics to a remote endpoint (configurable interval)
+		d.taskPushMetrics = d.tasks.Add(pushMetricsTask(d))
+
+		// Record the resource usage of instances (every 5 minutes and when they stop)
+		usageRecorder := newInstanceUsageRecorder()
+		instance.RecordUsage = func(inst instance.Instance) { usageRecorder.recordStop(d.State(), inst) }
+		d.tasks.Add(usageRecorder.task(d))
+
+		// Rotate the DNSSEC keys of network zones (hourly)
+		d.tasks.Add(autoRotateNetworkZoneKeysTask(d))
+
+		// Send dynamic updates for network zones (minutely)
+		d.tasks.Add(pushNetworkZonesTask(d))
+
+		// Prune expired audit log entries (daily)
+		d.tasks.Add(pruneExpiredAuditEntriesTask(d))
 	}
 
 	// Start all background tasks
@@ -1698,6 +1764,14 @@ func (d *Daemon) init() error {
 	// Restore instances
 	instancesStart(d.State(), instances)
 
+	// Resume or fail the durable operations interrupted by the last shutdown
+	err = operations.RestoreInterrupted(d.State(), map[operationtype.Type]operations.ResumeFunc{
+		operationtype.ImageDownload: imagesPostResume(d.State()),
+	})
+	if err != nil {
+		logger.Warn("Failed restoring interrupted operations", logger.Ctx{"err": err})
+	}
+
 	// Re-balance in case things changed while the daemon was down
 	deviceTaskBalance(d.State())
 
@@ -2258,6 +2332,73 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	return nil
 }
 
+// Setup built-in authorization.
+func (d *Daemon) setupBuiltinAuthorization(enable bool) error {
+	var err error
+
+	if !enable {
+		// Reset to default authorizer.
+		_, ok := d.authorizer.(*auth.Builtin)
+		if ok {
+			d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	permissionsFunc := func(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error) {
+		var permissions []api.AuthPermission
+		var found bool
+
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			permissions, found, err = tx.GetAuthPermissions(ctx, authenticationMethod, identifier, oidcGroups)
+
+			return err
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		return permissions, found, nil
+	}
+
+	groupsFunc := func(ctx context.Context) ([]api.AuthGroup, error) {
+		var groups []api.AuthGroup
+
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			groups, err = tx.GetAuthGroups(ctx)
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return groups, nil
+	}
+
+	// OpenFGA and the authorization scriptlet take precedence over the built-in authorization.
+	switch d.authorizer.(type) {
+	case *auth.TLS, *auth.Builtin:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverBuiltin, logger.Log, d.clientCerts, auth.WithPermissionsFunc(permissionsFunc), auth.WithGroupsFunc(groupsFunc))
+		if err != nil {
+			return err
+		}
+
+	default:
+		logger.Warn("Ignoring built-in authorization as another authorizer is already set")
+	}
+
+	return nil
+}
+
 // Syslog listener.
 func (d *Daemon) setupSyslogSocket(enable bool) error {
 	// Always cancel the context to ensure that no goroutines leak.
@@ -2522,11 +2663,23 @@ func (d *Daemon) nodeRefreshTask(heartbeatData *cluster.APIHeartbeat, isLeader b
 		logger.Error("Error restarting OVN networks", logger.Ctx{"err": err})
 	}
 
+	// Refresh the peers of WireGuard and VXLAN bridge networks.
+	err = networkUpdatePeers(s, heartbeatData)
+	if err != nil {
+		logger.Error("Error refreshing network peers", logger.Ctx{"err": err})
+	}
+
 	if d.hasMemberStateChanged(heartbeatData) {
 		logger.Info("Cluster status has changed, refreshing")
 
 		// Refresh cluster certificates cached.
 		updateCertificateCache(d)
+
+		// Discard the cached authorization data, as changes may have been missed by members which were offline.
+		builtin, ok := d.authorizer.(*auth.Builtin)
+		if ok {
+			builtin.ResetCache()
+		}
 	}
 
 	// Refresh event listeners from heartbeat members (after certificates refreshed if needed).
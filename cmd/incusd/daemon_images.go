@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
+	"sync"
 	"time"
 
 	incus "github.com/lxc/incus/v6/client"
@@ -36,6 +38,7 @@ type ImageDownloadArgs struct {
 	Server            string
 	Protocol          string
 	Certificate       string
+	TrustedKeys       string
 	Secret            string
 	Alias             string
 	Type              string
@@ -48,6 +51,67 @@ type ImageDownloadArgs struct {
 	SourceProjectName string
 }
 
+// imagePartialDownloadExpiry is how long partially downloaded images are kept around to resume their download.
+const imagePartialDownloadExpiry = 24 * time.Hour
+
+// imageDownloadThrottle limits the number of concurrent image downloads and their combined bandwidth.
+type imageDownloadThrottle struct {
+	mu      sync.Mutex
+	active  int64
+	limit   int64
+	changed chan struct{}
+	limiter *internalIO.RateLimiter
+}
+
+// imageDownloads throttles the image downloads of the server.
+var imageDownloads = &imageDownloadThrottle{
+	changed: make(chan struct{}),
+	limiter: internalIO.NewRateLimiter(0),
+}
+
+// configure updates the maximum number of concurrent downloads and the bandwidth limit (in bytes per second).
+func (t *imageDownloadThrottle) configure(concurrency int64, bandwidthLimit int64) {
+	t.mu.Lock()
+	t.limit = concurrency
+	t.notify()
+	t.mu.Unlock()
+
+	t.limiter.SetLimit(bandwidthLimit)
+}
+
+// notify wakes up the downloads waiting for a slot. Must be called with the lock held.
+func (t *imageDownloadThrottle) notify() {
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// acquire waits for a download slot and returns the function releasing it.
+func (t *imageDownloadThrottle) acquire(ctx context.Context) (func(), error) {
+	for {
+		t.mu.Lock()
+		if t.limit <= 0 || t.active < t.limit {
+			t.active++
+			t.mu.Unlock()
+
+			return func() {
+				t.mu.Lock()
+				t.active--
+				t.notify()
+				t.mu.Unlock()
+			}, nil
+		}
+
+		changed := t.changed
+		t.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
 // imageOperationLock acquires a lock for operating on an image and returns the unlock function.
 func imageOperationLock(ctx context.Context, fingerprint string) (locking.UnlockFunc, error) {
 	l := logger.AddContext(logger.Ctx{"fingerprint": fingerprint})
@@ -85,6 +149,7 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			Proxy:         s.Proxy,
 			CachePath:     s.OS.CacheDir,
 			CacheExpiry:   time.Hour,
+			TrustedKeys:   args.TrustedKeys,
 		}
 
 		if slices.Contains([]string{"incus", "lxd"}, protocol) {
@@ -237,7 +302,7 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 					return err
 				}
 
-				return tx.CreateImageSource(ctx, id, args.Server, args.Protocol, args.Certificate, alias)
+				return tx.CreateImageSource(ctx, id, args.Server, args.Protocol, args.Certificate, alias, args.TrustedKeys)
 			})
 			if err != nil {
 				return nil, false, err
@@ -311,17 +376,34 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		ctxMap = logger.Ctx{"trigger": op.URL(), "fingerprint": fp, "operation": op.ID(), "alias": alias, "server": args.Server}
 	}
 
+	// Wait for a download slot.
+	releaseDownload, err := imageDownloads.acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer releaseDownload()
+
 	logger.Info("Downloading image", ctxMap)
 
 	// Cleanup any leftover from a past attempt
 	destDir := internalUtil.VarPath("images")
 	destName := filepath.Join(destDir, fp)
 
+	// Partial downloads of simplestreams images are kept to be resumed by the next attempt.
+	resume := protocol == "simplestreams"
+
 	failure := true
+	keepPartial := false
 	cleanup := func() {
 		if failure {
 			_ = os.Remove(destName)
 			_ = os.Remove(destName + ".rootfs")
+
+			if !keepPartial {
+				_ = os.Remove(destName + ".partial")
+				_ = os.Remove(destName + ".rootfs.partial")
+			}
 		}
 	}
 	defer cleanup()
@@ -350,15 +432,20 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 	}
 
 	if slices.Contains([]string{"incus", "lxd", "oci", "simplestreams"}, protocol) {
-		// Create the target files
-		dest, err := os.Create(destName)
+		// Create the target files, keeping the data of a previous attempt if resuming.
+		openFlags := os.O_RDWR | os.O_CREATE
+		if !resume {
+			openFlags |= os.O_TRUNC
+		}
+
+		dest, err := os.OpenFile(destName+".partial", openFlags, 0o600)
 		if err != nil {
 			return nil, false, err
 		}
 
 		defer func() { _ = dest.Close() }()
 
-		destRootfs, err := os.Create(destName + ".rootfs")
+		destRootfs, err := os.OpenFile(destName+".rootfs.partial", openFlags, 0o600)
 		if err != nil {
 			return nil, false, err
 		}
@@ -393,13 +480,23 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			return nil, false, fmt.Errorf("Remote image with size %d exceeds allowed bugdget of %d", info.Size, args.Budget)
 		}
 
+		// Limit the bandwidth of the download. Images from OCI registries are retrieved by skopeo and only the
+		// tarballs generated from them locally are written to the target files, so those aren't limited.
+		var metaFile io.ReadWriteSeeker = dest
+		var rootfsFile io.ReadWriteSeeker = destRootfs
+		if protocol != "oci" {
+			metaFile = internalIO.NewRateLimitedFile(dest, imageDownloads.limiter)
+			rootfsFile = internalIO.NewRateLimitedFile(destRootfs, imageDownloads.limiter)
+		}
+
 		// Download the image
 		var resp *incus.ImageFileResponse
 		request := incus.ImageFileRequest{
-			MetaFile:        io.WriteSeeker(dest),
-			RootfsFile:      io.WriteSeeker(destRootfs),
+			MetaFile:        metaFile,
+			RootfsFile:      rootfsFile,
 			ProgressHandler: progress,
 			Canceler:        canceler,
+			Resume:          resume,
 			DeltaSourceRetriever: func(fingerprint string, file string) string {
 				path := internalUtil.VarPath("images", fmt.Sprintf("%s.%s", fingerprint, file))
 				if util.PathExists(path) {
@@ -417,6 +514,9 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		}
 
 		if err != nil {
+			// Keep the partial download around unless its content is corrupted.
+			keepPartial = resume && !errors.Is(err, util.ErrHashMismatch)
+
 			return nil, false, err
 		}
 
@@ -433,14 +533,6 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			return nil, false, err
 		}
 
-		// Deal with unified images
-		if resp.RootfsSize == 0 {
-			err := os.Remove(destName + ".rootfs")
-			if err != nil {
-				return nil, false, err
-			}
-		}
-
 		err = dest.Close()
 		if err != nil {
 			return nil, false, err
@@ -450,6 +542,25 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		if err != nil {
 			return nil, false, err
 		}
+
+		// Move the complete files in place.
+		err = os.Rename(destName+".partial", destName)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Deal with unified images
+		if resp.RootfsSize == 0 {
+			err := os.Remove(destName + ".rootfs.partial")
+			if err != nil {
+				return nil, false, err
+			}
+		} else {
+			err = os.Rename(destName+".rootfs.partial", destName+".rootfs")
+			if err != nil {
+				return nil, false, err
+			}
+		}
 	} else if protocol == "direct" {
 		// Setup HTTP client
 		httpClient, err := localUtil.HTTPClient(args.Certificate, s.Proxy)
@@ -503,7 +614,7 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		sha256 := sha256.New()
 
 		// Download the image
-		writer := internalIO.NewQuotaWriter(io.MultiWriter(f, sha256), args.Budget)
+		writer := internalIO.NewRateLimitedWriter(internalIO.NewQuotaWriter(io.MultiWriter(f, sha256), args.Budget), imageDownloads.limiter)
 		size, err := io.Copy(writer, body)
 		if err != nil {
 			return nil, false, err
@@ -589,7 +700,7 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 				return err
 			}
 
-			return tx.CreateImageSource(ctx, id, args.Server, protocol, args.Certificate, alias)
+			return tx.CreateImageSource(ctx, id, args.Server, protocol, args.Certificate, alias, args.TrustedKeys)
 		})
 		if err != nil {
 			return nil, false, err
@@ -432,6 +432,7 @@ func imgPostRemoteInfo(ctx context.Context, s *state.State, r *http.Request, req
 		Server:            req.Source.Server,
 		Protocol:          req.Source.Protocol,
 		Certificate:       req.Source.Certificate,
+		TrustedKeys:       req.Source.TrustedKeys,
 		Secret:            req.Source.Secret,
 		Alias:             hash,
 		Type:              req.Source.ImageType,
@@ -1147,6 +1148,14 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		// Setup the cleanup function
 		defer cleanup(builddir, post)
 
+		// Record the request so that downloads get resumed should the server restart.
+		if !imageUpload && slices.Contains([]string{"image", "url"}, req.Source.Type) && !isClusterNotification(r) {
+			err = op.Checkpoint(imageDownloadCheckpoint(req))
+			if err != nil {
+				logger.Warn("Failed recording image download checkpoint", logger.Ctx{"err": err})
+			}
+		}
+
 		if imageUpload {
 			/* Processing image upload */
 			info, err = getImgPostInfo(context.TODO(), s, r, builddir, projectName, post, imageMetadata)
@@ -1167,17 +1176,7 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 
 		// Set the metadata if possible, even if there is an error
 		if info != nil {
-			metadata := make(map[string]string)
-			metadata["fingerprint"] = info.Fingerprint
-			metadata["size"] = strconv.FormatInt(info.Size, 10)
-
-			// Keep secret if available
-			secret, ok := op.Metadata()["secret"]
-			if ok {
-				metadata["secret"] = secret.(string)
-			}
-
-			_ = op.UpdateMetadata(metadata)
+			imageSetOperationMetadata(op, info)
 		}
 
 		if err != nil {
@@ -1204,77 +1203,173 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &projectName})
-			if err != nil {
-				return fmt.Errorf("Fetch image %q: %w", info.Fingerprint, err)
+		return imageCreateFinish(s, r, op, projectName, req.Aliases, info)
+	}
+
+	var metadata any
+
+	if imageUpload && imageMetadata != nil {
+		secret, _ := internalUtil.RandomHexString(32)
+		if secret != "" {
+			metadata = map[string]string{
+				"secret": secret,
 			}
+		}
+	}
 
-			for _, alias := range req.Aliases {
-				_, _, err := tx.GetImageAlias(ctx, projectName, alias.Name, true)
-				if !response.IsNotFoundError(err) {
-					if err != nil {
-						return fmt.Errorf("Fetch image alias %q: %w", alias.Name, err)
-					}
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.ImageDownload, nil, metadata, run, nil, nil, r)
+	if err != nil {
+		cleanup(builddir, post)
+		return response.InternalError(err)
+	}
 
-					return fmt.Errorf("Alias already exists: %s", alias.Name)
-				}
+	return operations.OperationResponse(op)
+}
 
-				err = tx.CreateImageAlias(ctx, projectName, alias.Name, imgID, alias.Description)
-				if err != nil {
-					return fmt.Errorf("Add new image alias to the database: %w", err)
-				}
+// imageSetOperationMetadata exposes the fingerprint and size of the image being created through the operation metadata.
+func imageSetOperationMetadata(op *operations.Operation, info *api.Image) {
+	metadata := make(map[string]string)
+	metadata["fingerprint"] = info.Fingerprint
+	metadata["size"] = strconv.FormatInt(info.Size, 10)
+
+	// Keep secret if available
+	secret, ok := op.Metadata()["secret"]
+	if ok {
+		metadata["secret"] = secret.(string)
+	}
+
+	_ = op.UpdateMetadata(metadata)
+}
 
-				// Add the image alias to the authorizer.
-				err = s.Authorizer.AddImageAlias(ctx, projectName, alias.Name)
+// imageCreateFinish adds the aliases of a newly created image, syncs it to the other cluster members and announces it.
+func imageCreateFinish(s *state.State, r *http.Request, op *operations.Operation, projectName string, aliases []api.ImageAlias, info *api.Image) error {
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return fmt.Errorf("Fetch image %q: %w", info.Fingerprint, err)
+		}
+
+		for _, alias := range aliases {
+			_, _, err := tx.GetImageAlias(ctx, projectName, alias.Name, true)
+			if !response.IsNotFoundError(err) {
 				if err != nil {
-					logger.Error("Failed to add image alias to authorizer", logger.Ctx{"name": alias.Name, "project": projectName, "error": err})
+					return fmt.Errorf("Fetch image alias %q: %w", alias.Name, err)
 				}
 
-				s.Events.SendLifecycle(projectName, lifecycle.ImageAliasCreated.Event(alias.Name, projectName, op.Requestor(), logger.Ctx{"target": info.Fingerprint}))
+				return fmt.Errorf("Alias already exists: %s", alias.Name)
 			}
 
-			return nil
-		})
-		if err != nil {
-			return err
+			err = tx.CreateImageAlias(ctx, projectName, alias.Name, imgID, alias.Description)
+			if err != nil {
+				return fmt.Errorf("Add new image alias to the database: %w", err)
+			}
+
+			// Add the image alias to the authorizer.
+			err = s.Authorizer.AddImageAlias(ctx, projectName, alias.Name)
+			if err != nil {
+				logger.Error("Failed to add image alias to authorizer", logger.Ctx{"name": alias.Name, "project": projectName, "error": err})
+			}
+
+			s.Events.SendLifecycle(projectName, lifecycle.ImageAliasCreated.Event(alias.Name, projectName, op.Requestor(), logger.Ctx{"target": info.Fingerprint}))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sync the images between each node in the cluster on demand
+	err = imageSyncBetweenNodes(context.TODO(), s, r, projectName, info.Fingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed syncing image between servers: %w", err)
+	}
+
+	// Add the image to the authorizer.
+	err = s.Authorizer.AddImage(s.ShutdownCtx, projectName, info.Fingerprint)
+	if err != nil {
+		logger.Error("Failed to add image to authorizer", logger.Ctx{"fingerprint": info.Fingerprint, "project": projectName, "error": err})
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.ImageCreated.Event(info.Fingerprint, projectName, op.Requestor(), logger.Ctx{"type": info.Type}))
+
+	return nil
+}
+
+// imageDownloadCheckpoint returns the image download request as recorded to resume it after a restart.
+// Credentials aren't persisted, so private images which can't be downloaded without them fail to resume.
+func imageDownloadCheckpoint(req api.ImagesPost) api.ImagesPost {
+	stripUserinfo := func(rawURL string) string {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.User == nil {
+			return rawURL
 		}
 
-		// Sync the images between each node in the cluster on demand
-		err = imageSyncBetweenNodes(context.TODO(), s, r, projectName, info.Fingerprint)
+		u.User = nil
+
+		return u.String()
+	}
+
+	if req.Source == nil {
+		return req
+	}
+
+	source := *req.Source
+	source.Secret = ""
+	source.Server = stripUserinfo(source.Server)
+	source.URL = stripUserinfo(source.URL)
+	req.Source = &source
+
+	return req
+}
+
+// imagesPostResume resumes an image download interrupted by a restart of the server.
+func imagesPostResume(s *state.State) operations.ResumeFunc {
+	return func(op *operations.Operation, checkpoint json.RawMessage) error {
+		req := api.ImagesPost{}
+		err := json.Unmarshal(checkpoint, &req)
 		if err != nil {
-			return fmt.Errorf("Failed syncing image between servers: %w", err)
+			return fmt.Errorf("Failed decoding image download checkpoint: %w", err)
 		}
 
-		// Add the image to the authorizer.
-		err = s.Authorizer.AddImage(s.ShutdownCtx, projectName, info.Fingerprint)
+		projectName := op.Project()
+
+		var budget int64
+		err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+			budget, err = projectutils.GetImageSpaceBudget(tx, projectName)
+			return err
+		})
 		if err != nil {
-			logger.Error("Failed to add image to authorizer", logger.Ctx{"fingerprint": info.Fingerprint, "project": projectName, "error": err})
+			return err
 		}
 
-		s.Events.SendLifecycle(projectName, lifecycle.ImageCreated.Event(info.Fingerprint, projectName, op.Requestor(), logger.Ctx{"type": info.Type}))
+		// Rebuild a request carrying the identity of the original requestor.
+		r := &http.Request{Header: http.Header{}}
+		requestor := op.Requestor()
+		if requestor != nil {
+			ctx := context.WithValue(context.Background(), request.CtxUsername, requestor.Username)
+			ctx = context.WithValue(ctx, request.CtxProtocol, requestor.Protocol)
+			r = r.WithContext(ctx)
+			r.RemoteAddr = requestor.Address
+		}
 
-		return nil
-	}
+		var info *api.Image
+		if req.Source.Type == "url" {
+			info, err = imgPostURLInfo(s.ShutdownCtx, s, r, req, op, projectName, budget)
+		} else {
+			info, err = imgPostRemoteInfo(s.ShutdownCtx, s, r, req, op, projectName, budget)
+		}
 
-	var metadata any
+		if info != nil {
+			imageSetOperationMetadata(op, info)
+		}
 
-	if imageUpload && imageMetadata != nil {
-		secret, _ := internalUtil.RandomHexString(32)
-		if secret != "" {
-			metadata = map[string]string{
-				"secret": secret,
-			}
+		if err != nil {
+			return err
 		}
-	}
 
-	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.ImageDownload, nil, metadata, run, nil, nil, r)
-	if err != nil {
-		cleanup(builddir, post)
-		return response.InternalError(err)
+		return imageCreateFinish(s, r, op, projectName, req.Aliases, info)
 	}
-
-	return operations.OperationResponse(op)
 }
 
 func getImageMetadata(fname string) (*api.ImageMetadata, string, error) {
@@ -2229,6 +2324,7 @@ func autoUpdateImage(ctx context.Context, s *state.State, op *operations.Operati
 			Server:      source.Server,
 			Protocol:    source.Protocol,
 			Certificate: source.Certificate,
+			TrustedKeys: source.TrustedKeys,
 			Alias:       source.Alias,
 			Type:        info.Type,
 			AutoUpdate:  true,
@@ -2467,6 +2563,14 @@ func pruneLeftoverImages(s *state.State) {
 
 		// Check and delete leftovers
 		for _, entry := range entries {
+			// Keep recent partial downloads so that they can be resumed.
+			if strings.HasSuffix(entry.Name(), ".partial") {
+				info, err := entry.Info()
+				if err == nil && time.Since(info.ModTime()) < imagePartialDownloadExpiry {
+					continue
+				}
+			}
+
 			fp := strings.Split(entry.Name(), ".")[0]
 			if !slices.Contains(images, fp) {
 				err = os.RemoveAll(internalUtil.VarPath("images", entry.Name()))
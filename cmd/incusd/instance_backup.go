@@ -21,8 +21,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
-	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -275,29 +275,12 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(err)
 		}
 
-		base := name + internalInstance.SnapshotDelimiter + "backup"
-		length := len(base)
-		max := 0
-
+		backupNames := make([]string, 0, len(backups))
 		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup.Name(), base) {
-				continue
-			}
-
-			substr := backup.Name()[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= max {
-				max = num + 1
-			}
+			backupNames = append(backupNames, backup.Name())
 		}
 
-		req.Name = fmt.Sprintf("backup%d", max)
+		req.Name = backupNextName(name, backupNames)
 	}
 
 	// Validate the name.
@@ -305,6 +288,36 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Backup names may not contain slashes"))
 	}
 
+	// Validate the incremental base snapshot.
+	if req.IncrementalFrom != "" {
+		if req.InstanceOnly {
+			return response.BadRequest(fmt.Errorf("Incremental backups must include snapshots"))
+		}
+
+		if !req.OptimizedStorage {
+			return response.BadRequest(fmt.Errorf("Incremental backups require optimized storage"))
+		}
+
+		_, err := instance.LoadByProjectAndName(s, projectName, name+internalInstance.SnapshotDelimiter+req.IncrementalFrom)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading incremental base snapshot %q: %w", req.IncrementalFrom, err))
+		}
+	}
+
+	// Validate the target.
+	target, err := instance.BackupTargetLoad(s, req.Target)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	s3Target, ok := target.(*s3.BackupTarget)
+	if ok {
+		err = s3Target.Validate()
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	fullName := name + internalInstance.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly
 
@@ -317,9 +330,10 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			InstanceOnly:         instanceOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			Target:               req.Target,
 		}
 
-		err := backupCreate(s, args, inst, op)
+		err := backupCreate(s, args, inst, req.IncrementalFrom, op)
 		if err != nil {
 			return fmt.Errorf("Create backup: %w", err)
 		}
@@ -654,8 +668,17 @@ func instanceBackupExportGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Stream the tarball from wherever it is stored.
+	tarball, size, err := backup.Target().Open(backup.Path())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	ent := response.FileResponseEntry{
-		Path: internalUtil.VarPath("backups", "instances", project.Instance(projectName, backup.Name())),
+		File:         tarball,
+		FileSize:     size,
+		FileModified: backup.CreationDate(),
+		Cleanup:      func() { _ = tarball.Close() },
 	}
 
 	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupRetrieved.Event(fullName, backup.Instance(), nil))
@@ -310,8 +310,8 @@ func instanceLogDelete(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Log file name %q not valid", file))
 	}
 
-	if !strings.HasSuffix(file, ".log") || file == "lxc.log" || file == "qemu.log" {
-		return response.BadRequest(fmt.Errorf("Only log files excluding qemu.log and lxc.log may be deleted"))
+	if (!strings.HasSuffix(file, ".log") && !strings.HasPrefix(file, "crash_")) || file == "lxc.log" || file == "qemu.log" {
+		return response.BadRequest(fmt.Errorf("Only log files excluding qemu.log and lxc.log and crash dumps may be deleted"))
 	}
 
 	err = os.Remove(internalUtil.LogPath(project.Instance(projectName, name), file))
@@ -641,7 +641,8 @@ func validLogFileName(fname string) bool {
 	 */
 	return slices.Contains([]string{"lxc.log", "qemu.log", "qemu.early.log", "qemu.qmp.log"}, fname) ||
 		strings.HasPrefix(fname, "migration_") ||
-		strings.HasPrefix(fname, "snapshot_")
+		strings.HasPrefix(fname, "snapshot_") ||
+		strings.HasPrefix(fname, "crash_")
 }
 
 func validExecOutputFileName(fName string) bool {
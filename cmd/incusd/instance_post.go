@@ -319,6 +319,24 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		// Apply the placement group policy when moving to another member.
+		if inst.ExpandedConfig()["placement.group"] != "" && (targetMemberInfo == nil || targetMemberInfo.Name != inst.Location()) {
+			err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+				if targetMemberInfo != nil {
+					_, err := instance.PlacementCandidates(ctx, tx, instProject, name, inst.ExpandedConfig(), []db.NodeInfo{*targetMemberInfo}, nil)
+
+					return err
+				}
+
+				targetCandidates, err = instance.PlacementCandidates(ctx, tx, instProject, name, inst.ExpandedConfig(), targetCandidates, nil)
+
+				return err
+			})
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			// If a target was specified, limit the list of candidates to that target.
@@ -6,16 +6,19 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -188,6 +191,13 @@ func instanceStatePut(d *Daemon, r *http.Request) response.Response {
 	do := func(op *operations.Operation) error {
 		inst.SetOperation(op)
 
+		if req.WithDependencies && internalInstance.InstanceAction(req.Action) == internalInstance.Start {
+			err := instanceStartDependencies(s, r, inst, map[string]bool{inst.Name(): true})
+			if err != nil {
+				return err
+			}
+		}
+
 		return doInstanceStatePut(inst, req)
 	}
 
@@ -252,3 +262,84 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 	return fmt.Errorf("Unknown action: '%s'", req.Action)
 }
+
+// instanceStartDependencies starts the dependencies of the instance (and their own dependencies) which aren't running
+// yet, waiting for each of them to be up. Dependencies located on other cluster members are started remotely.
+func instanceStartDependencies(s *state.State, r *http.Request, inst instance.Instance, visited map[string]bool) error {
+	projectName := inst.Project().Name
+
+	for _, dep := range instance.Dependencies(inst.ExpandedConfig()) {
+		if visited[dep] {
+			continue
+		}
+
+		visited[dep] = true
+
+		client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, dep, r)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("Failed locating dependency %q: %w", dep, err)
+		}
+
+		if client != nil {
+			depState, _, err := client.GetInstanceState(dep)
+			if err != nil {
+				return fmt.Errorf("Failed getting state of dependency %q: %w", dep, err)
+			}
+
+			if depState.StatusCode == api.Stopped {
+				op, err := client.UpdateInstanceState(dep, api.InstanceStatePut{Action: "start", Timeout: -1, WithDependencies: true}, "")
+				if err == nil {
+					err = op.Wait()
+				}
+
+				if err != nil {
+					return fmt.Errorf("Failed starting dependency %q: %w", dep, err)
+				}
+			}
+
+			// Have the member running the dependency wait for it to be up.
+			values := url.Values{}
+			values.Set("project", projectName)
+			values.Set("timeout", strconv.Itoa(int(instanceDependencyTimeout(inst.ExpandedConfig()).Seconds())))
+
+			_, _, err = client.RawQuery(http.MethodGet, fmt.Sprintf("/internal/instances/%s/wait-dependency?%s", url.PathEscape(dep), values.Encode()), nil, "")
+			if err != nil {
+				return fmt.Errorf("Failed waiting for dependency %q: %w", dep, err)
+			}
+
+			continue
+		}
+
+		depInst, err := instance.LoadByProjectAndName(s, projectName, dep)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("Failed loading dependency %q: %w", dep, err)
+		}
+
+		err = instanceStartDependencies(s, r, depInst, visited)
+		if err != nil {
+			return err
+		}
+
+		if !depInst.IsRunning() {
+			err = depInst.Start(depInst.IsStateful())
+			if err != nil {
+				return fmt.Errorf("Failed starting dependency %q: %w", dep, err)
+			}
+		}
+
+		err = instanceWaitDependency(s.ShutdownCtx, depInst, instanceDependencyTimeout(inst.ExpandedConfig()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
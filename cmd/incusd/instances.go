@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"sync"
@@ -217,18 +218,21 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	instancesStartMu.Lock()
 	defer instancesStartMu.Unlock()
 
-	// Sort based on instance boot priority.
+	localInstances := make(map[string]instance.Instance, len(instances))
+	for _, inst := range instances {
+		localInstances[inst.Project().Name+"/"+inst.Name()] = inst
+	}
+
+	// Sort based on instance boot priority, then select the instances to start along with their dependencies,
+	// placing the dependencies ahead of the instances depending on them.
 	sort.Sort(instanceAutostartList(instances))
+	instances = instance.AutostartOrder(instances, instanceShouldAutoStart)
 
 	// Let's make up to 3 attempts to start instances.
 	maxAttempts := 3
 
 	// Start the instances
 	for _, inst := range instances {
-		if !instanceShouldAutoStart(inst) {
-			continue
-		}
-
 		// If already running, we're done.
 		if inst.IsRunning() {
 			continue
@@ -241,6 +245,33 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 
 		instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+		// Wait for the dependencies located on this server to be up.
+		var depErr error
+		for _, dep := range instance.Dependencies(config) {
+			depInst := localInstances[inst.Project().Name+"/"+dep]
+			if depInst == nil {
+				continue
+			}
+
+			depErr = instanceWaitDependency(s.ShutdownCtx, depInst, instanceDependencyTimeout(config))
+			if depErr != nil {
+				break
+			}
+		}
+
+		if depErr != nil {
+			warnErr := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, cluster.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, fmt.Sprintf("%v", depErr))
+			})
+			if warnErr != nil {
+				instLogger.Warn("Failed to create instance autostart failure warning", logger.Ctx{"err": warnErr})
+			}
+
+			instLogger.Error("Failed to auto start instance", logger.Ctx{"err": depErr})
+
+			continue
+		}
+
 		// Try to start the instance.
 		attempt := 0
 		for {
@@ -298,6 +329,46 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	}
 }
 
+// instanceDependencyTimeout returns how long to wait for the dependencies of an instance to be up.
+func instanceDependencyTimeout(config map[string]string) time.Duration {
+	timeout, err := strconv.Atoi(config["boot.depends_on.timeout"])
+	if err != nil {
+		timeout = 120
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
+// instanceWaitDependency waits for a dependency to be up before starting the instances depending on it.
+// The dependency must be running and, for virtual machines, its agent must be running within the timeout.
+func instanceWaitDependency(ctx context.Context, inst instance.Instance, timeout time.Duration) error {
+	if !inst.IsRunning() {
+		return fmt.Errorf("Dependency %q isn't running", inst.Name())
+	}
+
+	vm, ok := inst.(instance.VM)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for !vm.AgentRunning() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timed out waiting for the agent of dependency %q", inst.Name())
+		case <-time.After(time.Second):
+		}
+
+		if !inst.IsRunning() {
+			return fmt.Errorf("Dependency %q stopped", inst.Name())
+		}
+	}
+
+	return nil
+}
+
 type instanceStopList []instance.Instance
 
 func (slice instanceStopList) Len() int {
@@ -392,7 +463,9 @@ func instancesOnDisk(s *state.State) ([]instance.Instance, error) {
 }
 
 func instancesShutdown(s *state.State, instances []instance.Instance) {
+	// Sort based on instance stop priority, then move instances ahead of their dependencies.
 	sort.Sort(instanceStopList(instances))
+	instances = instance.StopOrder(instances)
 
 	// Limit shutdown concurrency to number of instances or number of CPU cores (which ever is less).
 	var wg sync.WaitGroup
@@ -448,6 +521,7 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	}
 
 	var currentBatchPriority int
+	var currentBatch []instance.Instance
 	for i, inst := range instances {
 		// Skip stopped instances.
 		if !inst.IsRunning() {
@@ -456,15 +530,23 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 
 		priority, _ := strconv.Atoi(inst.ExpandedConfig()["boot.stop.priority"])
 
+		// Check whether an instance of the current batch depends on this one.
+		hasDependent := slices.ContainsFunc(currentBatch, func(other instance.Instance) bool {
+			return other.Project().Name == inst.Project().Name && slices.Contains(instance.Dependencies(other.ExpandedConfig()), inst.Name())
+		})
+
 		// Shutdown instances in priority batches, logging at the start of each batch.
-		if i == 0 || priority != currentBatchPriority {
+		if i == 0 || priority != currentBatchPriority || hasDependent {
 			currentBatchPriority = priority
+			currentBatch = nil
 
-			// Wait for instances with higher priority to finish before starting next batch.
+			// Wait for instances with higher priority (or depending on this one) to finish before starting next batch.
 			wg.Wait()
 			logger.Info("Stopping instances", logger.Ctx{"stopPriority": currentBatchPriority})
 		}
 
+		currentBatch = append(currentBatch, inst)
+
 		wg.Add(1)
 		instShutdownCh <- inst
 	}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/gorilla/websocket"
@@ -32,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -65,6 +67,7 @@ func ensureDownloadedImageFitWithinBudget(ctx context.Context, s *state.State, r
 		Server:       source.Server,
 		Protocol:     source.Protocol,
 		Certificate:  source.Certificate,
+		TrustedKeys:  source.TrustedKeys,
 		Secret:       source.Secret,
 		Alias:        imgAlias,
 		SetCached:    true,
@@ -688,36 +691,76 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		backupFile = tarFile
 	}
 
-	// Parse the backup information.
-	_, err = backupFile.Seek(0, io.SeekStart)
+	bInfo, err := instanceBackupInfo(r.Context(), s, projectName, backupFile, backupFile.Name(), pool, instanceName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runReverter := reverter.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runReverter.Fail()
+
+		err := instanceBackupRestore(s, bInfo, backupFile, instanceName != "", op)
+		if err != nil {
+			return err
+		}
+
+		runReverter.Success()
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
 	if err != nil {
 		return response.InternalError(err)
 	}
 
-	bInfo, err := backup.GetInfo(backupFile, s.OS, backupFile.Name())
+	reverter.Success()
+	return operations.OperationResponse(op)
+}
+
+// instanceBackupInfo loads the information of an instance backup being restored into the given project and
+// checks that it can be restored.
+func instanceBackupInfo(ctx context.Context, s *state.State, projectName string, backupFile io.ReadSeeker, backupPath string, pool string, instanceName string) (*backup.Info, error) {
+	// Parse the backup information.
+	_, err := backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.BadRequest(err)
+		return nil, err
+	}
+
+	bInfo, err := backup.GetInfo(backupFile, s.OS, backupPath)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "%w", err)
 	}
 
 	// Detect broken legacy backups.
 	if bInfo.Config == nil {
-		return response.BadRequest(fmt.Errorf("Backup file is missing required information"))
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Backup file is missing required information")
 	}
 
 	// Check project permissions.
-	var req api.InstancesPost
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		req = api.InstancesPost{
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		req := api.InstancesPost{
 			InstancePut: bInfo.Config.Container.InstancePut,
 			Name:        bInfo.Name,
 			Source:      api.InstanceSource{}, // Only relevant for "copy" or "migration", but may not be nil.
 			Type:        api.InstanceType(bInfo.Config.Container.Type),
 		}
 
+		// Incremental backups are applied onto an existing instance.
+		if bInfo.IncrementalFrom != "" {
+			return nil
+		}
+
 		return project.AllowInstanceCreation(tx, projectName, req)
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
 	bInfo.Project = projectName
@@ -733,16 +776,22 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	}
 
 	logger.Debug("Backup file info loaded", logger.Ctx{
-		"type":      bInfo.Type,
-		"name":      bInfo.Name,
-		"project":   bInfo.Project,
-		"backend":   bInfo.Backend,
-		"pool":      bInfo.Pool,
-		"optimized": *bInfo.OptimizedStorage,
-		"snapshots": bInfo.Snapshots,
+		"type":            bInfo.Type,
+		"name":            bInfo.Name,
+		"project":         bInfo.Project,
+		"backend":         bInfo.Backend,
+		"pool":            bInfo.Pool,
+		"optimized":       *bInfo.OptimizedStorage,
+		"snapshots":       bInfo.Snapshots,
+		"incrementalFrom": bInfo.IncrementalFrom,
 	})
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	// Incremental backups are applied onto the pool of the existing instance.
+	if bInfo.IncrementalFrom != "" {
+		return bInfo, nil
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check storage pool exists.
 		_, _, _, err = tx.GetStoragePoolInAnyState(ctx, bInfo.Pool)
 
@@ -753,98 +802,339 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		// the backup.yaml) or the pool has been specified directly from the user restoring
 		// the backup then we cannot proceed so return an error.
 		if *bInfo.OptimizedStorage || pool != "" {
-			return response.InternalError(fmt.Errorf("Storage pool not found: %w", err))
+			return nil, fmt.Errorf("Storage pool not found: %w", err)
 		}
 
 		var profile *api.Profile
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Otherwise try and restore to the project's default profile pool.
 			_, profile, err = tx.GetProfile(ctx, bInfo.Project, "default")
 
 			return err
 		})
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get default profile: %w", err))
+			return nil, fmt.Errorf("Failed to get default profile: %w", err)
 		}
 
 		_, v, err := internalInstance.GetRootDiskDevice(profile.Devices)
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get root disk device: %w", err))
+			return nil, fmt.Errorf("Failed to get root disk device: %w", err)
 		}
 
 		// Use the default-profile's root pool.
 		bInfo.Pool = v["pool"]
 	} else if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
-	// Copy reverter so far so we can use it inside run after this function has finished.
-	runReverter := reverter.Clone()
+	return bInfo, nil
+}
+
+// instanceBackupRestore restores an instance backup loaded with instanceBackupInfo.
+// Incremental backups are applied onto the existing instance rather than creating a new one.
+func instanceBackupRestore(s *state.State, bInfo *backup.Info, backupFile io.ReadSeeker, renamed bool, op *operations.Operation) error {
+	if bInfo.IncrementalFrom != "" {
+		return applyIncrementalBackup(s, bInfo, backupFile, op)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	pool, err := storagePools.LoadByName(s, bInfo.Pool)
+	if err != nil {
+		return err
+	}
+
+	// Check if the backup is optimized that the source pool driver matches the target pool driver.
+	if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
+		return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+	}
+
+	// Dump tarball to storage. Because the backup file is unpacked and restored onto the storage
+	// device before the instance is created in the database it is necessary to return two functions;
+	// a post hook that can be run once the instance has been created in the database to run any
+	// storage layer finalisations, and a revert hook that can be run if the instance database load
+	// process fails that will remove anything created thus far.
+	postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, backupFile, nil)
+	if err != nil {
+		return fmt.Errorf("Create instance from backup: %w", err)
+	}
+
+	reverter.Add(revertHook)
+
+	err = internalImportFromBackup(context.TODO(), s, bInfo.Project, bInfo.Name, renamed)
+	if err != nil {
+		return fmt.Errorf("Failed importing backup: %w", err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance: %w", err)
+	}
+
+	// Clean up created instance if the post hook fails below.
+	reverter.Add(func() { _ = inst.Delete(true) })
+
+	// Run the storage post hook to perform any final actions now that the instance has been created
+	// in the database (this normally includes unmounting volumes that were mounted).
+	if postHook != nil {
+		err = postHook(inst)
+		if err != nil {
+			return fmt.Errorf("Post hook failed: %w", err)
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// createFromStoredBackup creates a new instance from an existing instance backup, reading it from the target it's stored on.
+// The backup is restored straight from the target as part of the operation, see backupOpenStored.
+func createFromStoredBackup(s *state.State, r *http.Request, projectName string, req *api.InstancesPost) response.Response {
+	sourceProjectName := req.Source.Project
+	if sourceProjectName == "" {
+		sourceProjectName = projectName
+	}
+
+	instName, _, isBackup := api.GetParentAndSnapshotName(req.Source.Source)
+	if !isBackup {
+		return response.BadRequest(fmt.Errorf("Backup source must be in the form <instance>/<backup>"))
+	}
+
+	// Backups are handled by the member the instance is located on.
+	client, err := cluster.ConnectIfInstanceIsRemote(s, sourceProjectName, instName, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		op, err := client.UseProject(projectName).CreateInstance(*req)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opAPI := op.Get()
+		return operations.ForwardedOperationResponse(projectName, &opAPI)
+	}
+
+	b, err := instance.BackupLoadByName(s, sourceProjectName, req.Source.Source)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Restore onto the pool of the requested root disk if any.
+	pool := ""
+	_, rootDev, err := internalInstance.GetRootDiskDevice(req.Devices)
+	if err == nil {
+		pool = rootDev["pool"]
+	}
 
 	run := func(op *operations.Operation) error {
-		defer func() { _ = backupFile.Close() }()
-		defer runReverter.Fail()
+		tarball, cleanup, err := backupOpenStored(b.Target(), b.Path())
+		if err != nil {
+			return fmt.Errorf("Failed opening backup %q: %w", req.Source.Source, err)
+		}
 
-		pool, err := storagePools.LoadByName(s, bInfo.Pool)
+		defer cleanup()
+
+		bInfo, err := instanceBackupInfo(context.TODO(), s, projectName, tarball, internalUtil.VarPath("backups"), pool, req.Name)
 		if err != nil {
 			return err
 		}
 
-		// Check if the backup is optimized that the source pool driver matches the target pool driver.
-		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
-			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+		err = instanceBackupRestore(s, bInfo, tarball, req.Name != "", op)
+		if err != nil {
+			return err
 		}
 
-		// Dump tarball to storage. Because the backup file is unpacked and restored onto the storage
-		// device before the instance is created in the database it is necessary to return two functions;
-		// a post hook that can be run once the instance has been created in the database to run any
-		// storage layer finalisations, and a revert hook that can be run if the instance database load
-		// process fails that will remove anything created thus far.
-		postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, backupFile, nil)
+		return instanceCreateFinish(s, req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = instName
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// Storage steps of applying an incremental backup, replaced in tests to inject failures.
+var (
+	incrementalBackupVolumeDBCreate = storagePools.VolumeDBCreate
+	incrementalBackupVolumeDBDelete = storagePools.VolumeDBDelete
+	incrementalBackupRefresh        = func(pool storagePools.Pool, inst instance.Instance, bInfo backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+		return pool.RefreshInstanceFromBackup(inst, bInfo, srcData, op)
+	}
+)
+
+// applyIncrementalBackup applies an incremental backup onto the existing instance it was taken from.
+// The snapshots contained in the backup are added to the instance and its volume is replaced by the backup's.
+func applyIncrementalBackup(s *state.State, bInfo *backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance %q to apply incremental backup onto: %w", bInfo.Name, err)
+	}
+
+	if inst.IsRunning() {
+		return fmt.Errorf("Incremental backups can only be applied onto stopped instances")
+	}
+
+	// The incremental backup must follow on from the latest snapshot of the instance.
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return err
+	}
+
+	latestSnapshot := ""
+	if len(snapshots) > 0 {
+		_, latestSnapshot, _ = api.GetParentAndSnapshotName(snapshots[len(snapshots)-1].Name())
+	}
+
+	if latestSnapshot != bInfo.IncrementalFrom {
+		return fmt.Errorf("Incremental backup is based on snapshot %q but the latest snapshot of instance %q is %q", bInfo.IncrementalFrom, bInfo.Name, latestSnapshot)
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	contentType := storagePools.InstanceContentType(inst)
+
+	// Create the instance and volume records of the snapshots contained in the backup.
+	for _, snap := range bInfo.Config.Snapshots {
+		if !slices.Contains(bInfo.Snapshots, snap.Name) {
+			continue
+		}
+
+		snapInstName := inst.Name() + internalInstance.SnapshotDelimiter + snap.Name
+
+		arch, err := osarch.ArchitectureID(snap.Architecture)
 		if err != nil {
-			return fmt.Errorf("Create instance from backup: %w", err)
+			return err
 		}
 
-		runReverter.Add(revertHook)
+		var profiles []api.Profile
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			profiles, err = tx.GetProfiles(ctx, inst.Project().Name, snap.Profiles)
 
-		err = internalImportFromBackup(context.TODO(), s, bInfo.Project, bInfo.Name, instanceName != "")
+			return err
+		})
 		if err != nil {
-			return fmt.Errorf("Failed importing backup: %w", err)
+			return fmt.Errorf("Failed loading profiles (%v) for instance snapshot %q: %w", strings.Join(snap.Profiles, ", "), snapInstName, err)
 		}
 
-		inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+		// Add root device if needed.
+		if snap.Devices == nil {
+			snap.Devices = make(map[string]map[string]string, 0)
+		}
+
+		if snap.ExpandedDevices == nil {
+			snap.ExpandedDevices = make(map[string]map[string]string, 0)
+		}
+
+		internalImportRootDevicePopulate(pool.Name(), snap.Devices, snap.ExpandedDevices, profiles)
+
+		_, snapInstOp, cleanup, err := instance.CreateInternal(s, db.InstanceArgs{
+			Project:      inst.Project().Name,
+			Architecture: arch,
+			BaseImage:    snap.Config["volatile.base_image"],
+			Config:       snap.Config,
+			CreationDate: snap.CreatedAt,
+			Type:         inst.Type(),
+			Snapshot:     true,
+			Devices:      deviceConfig.NewDevices(snap.Devices),
+			Ephemeral:    snap.Ephemeral,
+			LastUsedDate: snap.LastUsedAt,
+			Name:         snapInstName,
+			Profiles:     profiles,
+			Stateful:     snap.Stateful,
+		}, op, true, true)
 		if err != nil {
-			return fmt.Errorf("Failed loading instance: %w", err)
+			return fmt.Errorf("Failed creating instance snapshot record %q: %w", snap.Name, err)
 		}
 
-		// Clean up created instance if the post hook fails below.
-		runReverter.Add(func() { _ = inst.Delete(true) })
+		reverter.Add(cleanup)
+		defer snapInstOp.Done(err)
 
-		// Run the storage post hook to perform any final actions now that the instance has been created
-		// in the database (this normally includes unmounting volumes that were mounted).
-		if postHook != nil {
-			err = postHook(inst)
-			if err != nil {
-				return fmt.Errorf("Post hook failed: %w", err)
+		var volSnapDescription string
+		var volSnapConfig map[string]string
+		var volSnapExpiryDate time.Time
+		volSnapCreationDate := snap.CreatedAt
+
+		for _, volSnap := range bInfo.Config.VolumeSnapshots {
+			if volSnap == nil || volSnap.Name != snap.Name {
+				continue
+			}
+
+			volSnapDescription = volSnap.Description
+			volSnapConfig = volSnap.Config
+
+			if volSnap.ExpiresAt != nil {
+				volSnapExpiryDate = *volSnap.ExpiresAt
+			}
+
+			if !volSnap.CreatedAt.IsZero() {
+				volSnapCreationDate = volSnap.CreatedAt
 			}
 		}
 
-		runReverter.Success()
+		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
+		err = incrementalBackupVolumeDBCreate(pool, inst.Project().Name, snapInstName, volSnapDescription, volType, true, volSnapConfig, volSnapCreationDate, volSnapExpiryDate, contentType, true, true)
+		if err != nil {
+			return fmt.Errorf("Failed creating volume snapshot record %q: %w", snap.Name, err)
+		}
 
-		return instanceCreateFinish(s, &req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+		reverter.Add(func() { _ = incrementalBackupVolumeDBDelete(pool, inst.Project().Name, snapInstName, volType) })
 	}
 
-	resources := map[string][]api.URL{}
-	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+	// Apply the incremental backup onto the instance volume.
+	err = incrementalBackupRefresh(pool, inst, *bInfo, srcData, op)
+	if err != nil {
+		return fmt.Errorf("Refresh instance from backup: %w", err)
+	}
 
-	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	// Recreate missing snapshot mountpoints.
+	for _, snapName := range bInfo.Snapshots {
+		volStorageName := project.Instance(inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+snapName)
+		snapshotMountPoint := storageDrivers.GetVolumeMountPath(pool.Name(), volType, volStorageName)
+		snapshotPath := storagePools.InstancePath(inst.Type(), inst.Project().Name, inst.Name(), true)
+		snapshotTargetPath := storageDrivers.GetVolumeSnapshotDir(pool.Name(), volType, volStorageName)
+
+		err = storagePools.CreateSnapshotMountpoint(snapshotMountPoint, snapshotTargetPath, snapshotPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the backup file with the current instance config.
+	err = inst.UpdateBackupFile()
 	if err != nil {
-		return response.InternalError(err)
+		return err
 	}
 
 	reverter.Success()
-	return operations.OperationResponse(op)
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceUpdated.Event(inst, nil))
+
+	return nil
 }
 
 // swagger:operation POST /1.0/instances instances instances_post
@@ -938,6 +1228,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Stored backups are restored on the member holding them rather than through the scheduler.
+	if req.Source.Type == "backup" {
+		return createFromStoredBackup(s, r, targetProjectName, &req)
+	}
+
 	// Special handling for instance refresh.
 	// For all other situations, we're headed towards the scheduler, but for this case, we can short circuit it.
 	if s.ServerClustered && !clusterNotification && req.Source.Type == "migration" && req.Source.Refresh {
@@ -1189,6 +1484,19 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			candidateMembers = []db.NodeInfo{*targetMemberInfo}
 		}
 
+		// Apply the placement group policy.
+		expandedConfig := db.ExpandInstanceConfig(req.Config, profiles)
+		if expandedConfig["placement.group"] != "" {
+			err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+				candidateMembers, err = instance.PlacementCandidates(ctx, tx, targetProjectName, req.Name, expandedConfig, candidateMembers, nil)
+
+				return err
+			})
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			leaderAddress, err := s.Cluster.LeaderAddress()
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	backupConfig "github.com/lxc/incus/v6/internal/server/backup/config"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+type instancesPostTestSuite struct {
+	daemonTestSuite
+}
+
+// createInstance creates the records of an instance and of its snapshots.
+func (suite *instancesPostTestSuite) createInstance(name string, snapshots ...string) instance.Instance {
+	inst, op, _, err := instance.CreateInternal(suite.d.State(), db.InstanceArgs{
+		Type: instancetype.Container,
+		Name: name,
+	}, nil, true, true)
+	suite.Req.Nil(err)
+	op.Done(nil)
+
+	for _, snapName := range snapshots {
+		_, op, _, err := instance.CreateInternal(suite.d.State(), db.InstanceArgs{
+			Type:     instancetype.Container,
+			Name:     name + internalInstance.SnapshotDelimiter + snapName,
+			Snapshot: true,
+		}, nil, true, true)
+		suite.Req.Nil(err)
+		op.Done(nil)
+	}
+
+	return inst
+}
+
+// incrementalBackup returns the information of an incremental backup of the given instance.
+func (suite *instancesPostTestSuite) incrementalBackup(name string, incrementalFrom string, snapshots ...string) *backup.Info {
+	optimized := true
+	bInfo := &backup.Info{
+		Project:          api.ProjectDefaultName,
+		Name:             name,
+		Backend:          "mock",
+		OptimizedStorage: &optimized,
+		Snapshots:        snapshots,
+		IncrementalFrom:  incrementalFrom,
+		Config:           &backupConfig.Config{},
+	}
+
+	arch, err := osarch.ArchitectureName(suite.d.os.Architectures[0])
+	suite.Req.Nil(err)
+
+	for _, snapName := range snapshots {
+		bInfo.Config.Snapshots = append(bInfo.Config.Snapshots, &api.InstanceSnapshot{
+			Name:         snapName,
+			Architecture: arch,
+			Profiles:     []string{"default"},
+		})
+	}
+
+	return bInfo
+}
+
+// Test that incremental backups are only applied onto the snapshot they are based on.
+func (suite *instancesPostTestSuite) TestApplyIncrementalBackup_BaseSnapshot() {
+	inst := suite.createInstance("c1", "snap0", "snap1")
+	defer func() { _ = inst.Delete(true) }()
+
+	err := applyIncrementalBackup(suite.d.State(), suite.incrementalBackup("c1", "snap0", "snap2"), nil, nil)
+	suite.Req.ErrorContains(err, `latest snapshot of instance "c1" is "snap1"`)
+
+	err = applyIncrementalBackup(suite.d.State(), suite.incrementalBackup("c2", "snap1", "snap2"), nil, nil)
+	suite.Req.ErrorContains(err, `Failed loading instance "c2"`)
+
+	snapshots, err := inst.Snapshots()
+	suite.Req.Nil(err)
+	suite.Len(snapshots, 2)
+}
+
+// Test that every step is reverted when applying the backup fails.
+func (suite *instancesPostTestSuite) TestApplyIncrementalBackup_Revert() {
+	volumeDBCreate := incrementalBackupVolumeDBCreate
+	volumeDBDelete := incrementalBackupVolumeDBDelete
+	refresh := incrementalBackupRefresh
+
+	defer func() {
+		incrementalBackupVolumeDBCreate = volumeDBCreate
+		incrementalBackupVolumeDBDelete = volumeDBDelete
+		incrementalBackupRefresh = refresh
+	}()
+
+	tests := []struct {
+		name      string
+		failStep  string
+		wantErr   string
+		wantSteps []string
+	}{
+		{
+			name:      "Volume record of the second snapshot",
+			failStep:  "create c1/snap2",
+			wantErr:   `Failed creating volume snapshot record "snap2"`,
+			wantSteps: []string{"create c1/snap1", "create c1/snap2", "delete c1/snap1"},
+		},
+		{
+			name:      "Refresh of the instance volume",
+			failStep:  "refresh c1",
+			wantErr:   "Refresh instance from backup",
+			wantSteps: []string{"create c1/snap1", "create c1/snap2", "refresh c1", "delete c1/snap2", "delete c1/snap1"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			inst := suite.createInstance("c1", "snap0")
+			defer func() { _ = inst.Delete(true) }()
+
+			// Record the storage steps, failing the requested one.
+			steps := []string{}
+			step := func(name string) error {
+				steps = append(steps, name)
+				if name == tt.failStep {
+					return fmt.Errorf("Injected failure")
+				}
+
+				return nil
+			}
+
+			incrementalBackupVolumeDBCreate = func(pool storagePools.Pool, projectName string, volumeName string, volumeDescription string, volumeType storageDrivers.VolumeType, snapshot bool, volumeConfig map[string]string, creationDate time.Time, expiryDate time.Time, contentType storageDrivers.ContentType, removeUnknownKeys bool, hasSource bool) error {
+				return step("create " + volumeName)
+			}
+
+			incrementalBackupVolumeDBDelete = func(pool storagePools.Pool, projectName string, volumeName string, volumeType storageDrivers.VolumeType) error {
+				return step("delete " + volumeName)
+			}
+
+			incrementalBackupRefresh = func(pool storagePools.Pool, inst instance.Instance, bInfo backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+				return step("refresh " + inst.Name())
+			}
+
+			err := applyIncrementalBackup(suite.d.State(), suite.incrementalBackup("c1", "snap0", "snap1", "snap2"), nil, nil)
+			suite.Req.ErrorContains(err, tt.wantErr)
+			suite.Equal(tt.wantSteps, steps)
+
+			// The instance snapshot records are removed again.
+			snapshots, err := inst.Snapshots()
+			suite.Req.Nil(err)
+			suite.Len(snapshots, 1)
+			suite.Equal("c1/snap0", snapshots[0].Name())
+		})
+	}
+}
+
+func TestInstancesPost(t *testing.T) {
+	suite.Run(t, &instancesPostTestSuite{})
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// instanceUsageInterval is how often the resource usage of local instances is recorded.
+const instanceUsageInterval = 5 * time.Minute
+
+// instanceUsageSample holds the usage counters of an instance at a given time.
+type instanceUsageSample struct {
+	timestamp time.Time
+	usage     metrics.Usage
+}
+
+// instanceUsageRecorder records the resource usage of the local instances into the cluster database.
+//
+// Usage is computed from the difference between consecutive samples of the instance counters, which
+// are taken every instanceUsageInterval and when an instance stops. Some usage can't be accounted for:
+//   - The counters of instances already running when the daemon starts have no known starting point,
+//     so their usage since the last sample taken before the daemon stopped is lost.
+//   - Virtual machines shut down from within the guest lose their usage since their last sample as
+//     their counters are gone by the time the daemon notices.
+type instanceUsageRecorder struct {
+	mu      sync.Mutex
+	lastRun time.Time
+
+	// Latest samples keyed by instance ID.
+	samples map[int]instanceUsageSample
+}
+
+// newInstanceUsageRecorder returns a new instanceUsageRecorder.
+func newInstanceUsageRecorder() *instanceUsageRecorder {
+	return &instanceUsageRecorder{
+		samples: map[int]instanceUsageSample{},
+	}
+}
+
+// sample returns the current usage counters of an instance.
+func (r *instanceUsageRecorder) sample(inst instance.Instance, hostInterfaces []net.Interface) (*instanceUsageSample, error) {
+	instMetrics, err := inst.Metrics(hostInterfaces)
+	if err != nil {
+		if !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+			logger.Warn("Failed getting instance usage", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+		}
+
+		return nil, err
+	}
+
+	return &instanceUsageSample{timestamp: time.Now(), usage: instMetrics.Usage()}, nil
+}
+
+// usage returns the usage of an instance since its previous sample, or nil if it can't be known.
+// Must be called with the lock held.
+func (r *instanceUsageRecorder) usage(inst instance.Instance, sample instanceUsageSample) *db.InstanceUsage {
+	prev, ok := r.samples[inst.ID()]
+	if !ok {
+		// Counters of instances running since before the first run have no known starting point.
+		if r.lastRun.IsZero() {
+			return nil
+		}
+
+		// The instance was started since the last run, so its counters started from zero.
+		prev = instanceUsageSample{timestamp: r.lastRun}
+	}
+
+	delta := sample.usage.Sub(prev.usage)
+
+	return &db.InstanceUsage{
+		Project:              inst.Project().Name,
+		InstanceID:           inst.ID(),
+		Instance:             inst.Name(),
+		Type:                 inst.Type(),
+		CPUSeconds:           delta.CPUSeconds,
+		MemoryByteSeconds:    delta.MemoryBytes * sample.timestamp.Sub(prev.timestamp).Seconds(),
+		DiskReadBytes:        int64(delta.DiskReadBytes),
+		DiskWrittenBytes:     int64(delta.DiskWrittenBytes),
+		NetworkReceivedBytes: int64(delta.NetworkReceivedBytes),
+		NetworkSentBytes:     int64(delta.NetworkSentBytes),
+	}
+}
+
+// recordStop records the usage of an instance which is about to stop.
+// The final sample is kept until the next run so that stopping it again doesn't account for its usage twice.
+func (r *instanceUsageRecorder) recordStop(s *state.State, inst instance.Instance) {
+	hostInterfaces, _ := net.Interfaces()
+
+	sample, err := r.sample(inst, hostInterfaces)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage := r.usage(inst, *sample)
+	if usage == nil {
+		return
+	}
+
+	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.AddInstanceUsage(ctx, sample.timestamp, []db.InstanceUsage{*usage})
+	})
+	if err != nil {
+		logger.Warn("Failed recording instance usage", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+		return
+	}
+
+	r.samples[inst.ID()] = *sample
+}
+
+// task returns a task recording the resource usage of the running local instances.
+func (r *instanceUsageRecorder) task(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for usage accounting", logger.Ctx{"err": err})
+			return
+		}
+
+		// Gather information about host interfaces once.
+		hostInterfaces, _ := net.Interfaces()
+
+		// Sample the instances without holding the lock as this may take a while.
+		now := time.Now()
+		samples := make(map[int]instanceUsageSample, len(instances))
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			sample, err := r.sample(inst, hostInterfaces)
+			if err != nil {
+				continue
+			}
+
+			samples[inst.ID()] = *sample
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		newSamples := make(map[int]instanceUsageSample, len(samples))
+		usages := make([]db.InstanceUsage, 0, len(samples))
+
+		for _, inst := range instances {
+			prev, ok := r.samples[inst.ID()]
+
+			sample, sampled := samples[inst.ID()]
+			if !sampled || (ok && prev.timestamp.After(sample.timestamp)) {
+				// Keep the previous counters of running instances which couldn't be sampled or were
+				// sampled again when stopping, so that their usage is accounted for on the next run.
+				if ok && inst.IsRunning() {
+					newSamples[inst.ID()] = prev
+				}
+
+				continue
+			}
+
+			newSamples[inst.ID()] = sample
+
+			usage := r.usage(inst, sample)
+			if usage != nil {
+				usages = append(usages, *usage)
+			}
+		}
+
+		if len(usages) > 0 {
+			err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.AddInstanceUsage(ctx, now, usages)
+			})
+			if err != nil {
+				// Keep the previous counters so that the usage is accounted for on the next run.
+				logger.Warn("Failed recording instance usage", logger.Ctx{"err": err})
+				return
+			}
+		}
+
+		r.samples = newSamples
+		r.lastRun = now
+	}
+
+	return f, task.Every(instanceUsageInterval)
+}
@@ -138,6 +138,13 @@ func (c *cmdActivateifneeded) Run(cmd *cobra.Command, args []string) error {
 			_, err := incus.ConnectIncusUnix("", nil)
 			return err
 		}
+
+		// Check for scheduled instance backups
+		if config["backups.schedule"] != "" {
+			logger.Debugf("Daemon has scheduled instance backups, activating...")
+			_, err := incus.ConnectIncusUnix("", nil)
+			return err
+		}
 	}
 
 	// Check for scheduled volume snapshots
@@ -160,6 +167,12 @@ func (c *cmdActivateifneeded) Run(cmd *cobra.Command, args []string) error {
 			_, err := incus.ConnectIncusUnix("", nil)
 			return err
 		}
+
+		if vol.Config["backups.schedule"] != "" {
+			logger.Debugf("Daemon has scheduled volume backups, activating...")
+			_, err := incus.ConnectIncusUnix("", nil)
+			return err
+		}
 	}
 
 	logger.Debugf("No need to start the daemon now")
@@ -171,6 +171,9 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		}
 	}
 
+	// Offer to compress the migration stream.
+	offerHeader.Compression = localMigration.CompressionTypes(state.GlobalConfig.MigrationCompression())
+
 	// Send offer to target.
 	err = s.send(offerHeader)
 	if err != nil {
@@ -226,6 +229,13 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		return err
 	}
 
+	// Compress and rate limit the migration stream as negotiated with the target.
+	fsConn, err = localMigration.NewStreamConn(fsConn, localMigration.MatchCompression(respHeader.GetCompression()), localMigration.BandwidthLimit(pool.Driver().Config()["migration.bandwidth_limit"]))
+	if err != nil {
+		s.sendControl(err)
+		return err
+	}
+
 	err = pool.MigrateCustomVolume(projectName, fsConn, volSourceArgs, migrateOp)
 	if err != nil {
 		s.sendControl(err)
@@ -366,6 +376,12 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 	respHeader.Refresh = &c.refresh
 	respHeader.VolumeSize = offerHeader.VolumeSize
 
+	// Pick the compression of the migration stream.
+	compression := localMigration.MatchCompression(offerHeader.GetCompression())
+	if compression != "" {
+		respHeader.Compression = []string{compression}
+	}
+
 	// Translate the legacy MigrationSinkArgs to a VolumeTargetArgs suitable for use
 	// with the new storage layer.
 	myTarget = func(conn io.ReadWriteCloser, op *operations.Operation, args migrationSinkArgs) error {
@@ -484,6 +500,12 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 				return
 			}
 
+			fsConn, err = localMigration.NewStreamConn(fsConn, compression, 0)
+			if err != nil {
+				fsTransfer <- err
+				return
+			}
+
 			err = myTarget(fsConn, op, args)
 			if err != nil {
 				fsTransfer <- err
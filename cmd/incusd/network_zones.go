@@ -3,14 +3,17 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/lxc/incus/v6/internal/filter"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
 	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
@@ -18,6 +21,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -226,6 +230,9 @@ func networkZonesGet(d *Daemon, r *http.Request) response.Response {
 			netzoneInfo.UsedBy, _ = netzone.UsedBy() // Ignore errors in UsedBy, will return nil.
 			netzoneInfo.Project = projectName
 
+			// Ignore errors in DSRecords, will return nil.
+			netzoneInfo.DSRecords, _ = netzone.DSRecords()
+
 			if clauses != nil && len(clauses.Clauses) > 0 {
 				match, err := filter.Match(*netzoneInfo, *clauses)
 				if err != nil {
@@ -446,6 +453,11 @@ func networkZoneGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	info.DSRecords, err = netzone.DSRecords()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	return response.SyncResponseETag(true, info, netzone.Etag())
 }
 
@@ -573,3 +585,100 @@ func networkZonePut(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// autoRotateNetworkZoneKeysTask returns a task generating and rotating the DNSSEC keys of the network zones.
+func autoRotateNetworkZoneKeysTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Only the leader rotates the keys to avoid concurrent rollovers.
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			return
+		}
+
+		var zoneNames map[string]string
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			zoneNames, err = tx.GetNetworkZones(ctx)
+
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed loading network zones", logger.Ctx{"err": err})
+			return
+		}
+
+		for zoneName := range zoneNames {
+			netzone, err := zone.LoadByName(s, zoneName)
+			if err != nil {
+				logger.Error("Failed loading network zone", logger.Ctx{"zone": zoneName, "err": err})
+				continue
+			}
+
+			if !util.IsTrue(netzone.Info().Config["dnssec.enabled"]) {
+				continue
+			}
+
+			err = netzone.RotateDNSSECKeys()
+			if err != nil {
+				logger.Error("Failed rotating DNSSEC keys", logger.Ctx{"zone": zoneName, "err": err})
+			}
+		}
+	}
+
+	return f, task.Hourly()
+}
+
+// pushNetworkZonesTask returns a task sending the changes to the records of the network zones to
+// their configured primary DNS servers.
+func pushNetworkZonesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Only the leader sends updates to avoid conflicting changes.
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			return
+		}
+
+		var zoneNames map[string]string
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			zoneNames, err = tx.GetNetworkZones(ctx)
+
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed loading network zones", logger.Ctx{"err": err})
+			return
+		}
+
+		for zoneName := range zoneNames {
+			netzone, err := zone.LoadByName(s, zoneName)
+			if err != nil {
+				logger.Error("Failed loading network zone", logger.Ctx{"zone": zoneName, "err": err})
+				continue
+			}
+
+			if netzone.Info().Config["push.address"] == "" {
+				continue
+			}
+
+			err = netzone.Push()
+			if err != nil {
+				logger.Warn("Failed sending dynamic DNS updates", logger.Ctx{"zone": zoneName, "err": err})
+			}
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
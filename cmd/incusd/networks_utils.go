@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/network"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 )
 
@@ -39,3 +45,100 @@ func networkUpdateOVNChassis(s *state.State, heartbeatData *cluster.APIHeartbeat
 	networkOVNChassis = &runChassis
 	return nil
 }
+
+// networkUpdatePeers gets called on heartbeats to refresh the peers of the WireGuard networks and the
+// flood lists of the bridge networks using automatic VXLAN tunnels.
+func networkUpdatePeers(s *state.State, heartbeatData *cluster.APIHeartbeat) error {
+	var projectNetworks map[string]map[int64]api.Network
+
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load networks: %w", err)
+	}
+
+	// Keep going when a network fails so that it doesn't prevent the others from being refreshed.
+	var errs []error
+
+	for projectName, networks := range projectNetworks {
+		for _, netInfo := range networks {
+			if netInfo.Type != "wireguard" && (netInfo.Type != "bridge" || netInfo.Config["vxlan.mode"] == "") {
+				continue
+			}
+
+			n, err := network.LoadByName(s, projectName, netInfo.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed to load network %q in project %q: %w", netInfo.Name, projectName, err))
+				continue
+			}
+
+			err = n.HandleHeartbeat(heartbeatData)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed to refresh peers of network %q in project %q: %w", netInfo.Name, projectName, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// networkUpdateBGPRoutes gets called when the routes received from the BGP peers change to refresh the routes
+// imported by the bridge networks and by the OVN networks using them or physical networks as uplink.
+func networkUpdateBGPRoutes(s *state.State) error {
+	var projectNetworks map[string]map[int64]api.Network
+
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load networks: %w", err)
+	}
+
+	// Find the uplink networks importing routes.
+	importUplinks := map[string]bool{}
+	for _, netInfo := range projectNetworks[api.ProjectDefaultName] {
+		if netInfo.Config["bgp.ipv4.import"] != "" || netInfo.Config["bgp.ipv6.import"] != "" {
+			importUplinks[netInfo.Name] = true
+		}
+	}
+
+	for projectName, networks := range projectNetworks {
+		for _, netInfo := range networks {
+			switch netInfo.Type {
+			case "bridge":
+				if projectName != api.ProjectDefaultName || !importUplinks[netInfo.Name] {
+					continue
+				}
+
+			case "ovn":
+				if !importUplinks[netInfo.Config["network"]] {
+					continue
+				}
+
+			default:
+				continue
+			}
+
+			n, err := network.LoadByName(s, projectName, netInfo.Name)
+			if err != nil {
+				return fmt.Errorf("Failed to load network %q in project %q: %w", netInfo.Name, projectName, err)
+			}
+
+			err = n.HandleBGPRoutes()
+			if err != nil {
+				logger.Error("Failed to refresh BGP routes", logger.Ctx{"project": projectName, "network": netInfo.Name, "err": err})
+			}
+		}
+	}
+
+	return nil
+}
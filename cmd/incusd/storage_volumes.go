@@ -759,6 +759,8 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return doVolumeCreateOrCopy(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	case "migration":
 		return doVolumeMigration(s, r, request.ProjectParam(r), projectName, poolName, &req)
+	case "backup":
+		return createStoragePoolVolumeFromStoredBackup(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown source type %q", req.Source.Type))
 	}
@@ -2278,6 +2280,79 @@ func createStoragePoolVolumeFromISO(s *state.State, r *http.Request, requestProj
 	return operations.OperationResponse(op)
 }
 
+// createStoragePoolVolumeFromStoredBackup creates a new custom volume from an existing volume backup.
+// The backup is restored straight from the target it's stored on as part of the operation, see backupOpenStored.
+func createStoragePoolVolumeFromStoredBackup(s *state.State, r *http.Request, requestProjectName string, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
+	sourceProjectName := req.Source.Project
+	if sourceProjectName == "" {
+		sourceProjectName = projectName
+	}
+
+	sourcePoolName := req.Source.Pool
+	if sourcePoolName == "" {
+		sourcePoolName = poolName
+	}
+
+	volName, _, isBackup := api.GetParentAndSnapshotName(req.Source.Name)
+	if !isBackup {
+		return response.BadRequest(fmt.Errorf("Backup source must be in the form <volume>/<backup>"))
+	}
+
+	// Backups are handled by the member the volume is located on.
+	client, err := cluster.ConnectIfVolumeIsRemote(s, sourcePoolName, sourceProjectName, volName, db.StoragePoolVolumeTypeCustom, s.Endpoints.NetworkCert(), s.ServerCert(), r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		// The request body was already consumed, so send it again.
+		body, err := json.Marshal(req)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		return response.ForwardedResponse(client, r)
+	}
+
+	b, err := storagePoolVolumeBackupLoadByName(r.Context(), s, sourceProjectName, sourcePoolName, req.Source.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		tarball, cleanup, err := backupOpenStored(b.Target(), b.Path())
+		if err != nil {
+			return fmt.Errorf("Failed opening backup %q: %w", req.Source.Name, err)
+		}
+
+		defer cleanup()
+
+		bInfo, err := storagePoolVolumeBackupInfo(context.TODO(), s, projectName, tarball, internalUtil.VarPath("backups"), poolName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		return storagePoolVolumeBackupRestore(s, bInfo, tarball)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = volName
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", "custom", name)}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
 func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestProjectName string, projectName string, data io.Reader, pool string, volName string) response.Response {
 	reverter := revert.New()
 	defer reverter.Fail()
@@ -2334,16 +2409,52 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		backupFile = tarFile
 	}
 
-	// Parse the backup information.
-	_, err = backupFile.Seek(0, io.SeekStart)
+	bInfo, err := storagePoolVolumeBackupInfo(r.Context(), s, projectName, backupFile, backupFile.Name(), pool, volName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runReverter := reverter.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runReverter.Fail()
+
+		err := storagePoolVolumeBackupRestore(s, bInfo, backupFile)
+		if err != nil {
+			return err
+		}
+
+		runReverter.Success()
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", bInfo.Pool, "volumes", string(bInfo.Type), bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
 	if err != nil {
 		return response.InternalError(err)
 	}
 
+	reverter.Success()
+	return operations.OperationResponse(op)
+}
+
+// storagePoolVolumeBackupInfo loads the information of a custom volume backup being restored into the given
+// project and resolves the pool to restore it onto.
+func storagePoolVolumeBackupInfo(ctx context.Context, s *state.State, projectName string, backupFile io.ReadSeeker, backupPath string, pool string, volName string) (*backup.Info, error) {
+	// Parse the backup information.
+	_, err := backupFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Debug("Reading backup file info")
-	bInfo, err := backup.GetInfo(backupFile, s.OS, backupFile.Name())
+	bInfo, err := backup.GetInfo(backupFile, s.OS, backupPath)
 	if err != nil {
-		return response.BadRequest(err)
+		return nil, api.StatusErrorf(http.StatusBadRequest, "%w", err)
 	}
 
 	bInfo.Project = projectName
@@ -2368,7 +2479,7 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		"snapshots": bInfo.Snapshots,
 	})
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check storage pool exists.
 		_, _, _, err = tx.GetStoragePoolInAnyState(ctx, bInfo.Pool)
 
@@ -2379,67 +2490,52 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		// the backup.yaml) or the pool has been specified directly from the user restoring
 		// the backup then we cannot proceed so return an error.
 		if *bInfo.OptimizedStorage || pool != "" {
-			return response.InternalError(fmt.Errorf("Storage pool not found: %w", err))
+			return nil, fmt.Errorf("Storage pool not found: %w", err)
 		}
 
 		var profile *api.Profile
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Otherwise try and restore to the project's default profile pool.
 			_, profile, err = tx.GetProfile(ctx, bInfo.Project, "default")
 
 			return err
 		})
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get default profile: %w", err))
+			return nil, fmt.Errorf("Failed to get default profile: %w", err)
 		}
 
 		_, v, err := internalInstance.GetRootDiskDevice(profile.Devices)
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get root disk device: %w", err))
+			return nil, fmt.Errorf("Failed to get root disk device: %w", err)
 		}
 
 		// Use the default-profile's root pool.
 		bInfo.Pool = v["pool"]
 	} else if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
-	// Copy reverter so far so we can use it inside run after this function has finished.
-	runReverter := reverter.Clone()
-
-	run := func(op *operations.Operation) error {
-		defer func() { _ = backupFile.Close() }()
-		defer runReverter.Fail()
-
-		pool, err := storagePools.LoadByName(s, bInfo.Pool)
-		if err != nil {
-			return err
-		}
-
-		// Check if the backup is optimized that the source pool driver matches the target pool driver.
-		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
-			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
-		}
-
-		// Dump tarball to storage.
-		err = pool.CreateCustomVolumeFromBackup(*bInfo, backupFile, nil)
-		if err != nil {
-			return fmt.Errorf("Create custom volume from backup: %w", err)
-		}
+	return bInfo, nil
+}
 
-		runReverter.Success()
-		return nil
+// storagePoolVolumeBackupRestore restores a custom volume backup loaded with storagePoolVolumeBackupInfo.
+func storagePoolVolumeBackupRestore(s *state.State, bInfo *backup.Info, backupFile io.ReadSeeker) error {
+	pool, err := storagePools.LoadByName(s, bInfo.Pool)
+	if err != nil {
+		return err
 	}
 
-	resources := map[string][]api.URL{}
-	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", bInfo.Pool, "volumes", string(bInfo.Type), bInfo.Name)}
+	// Check if the backup is optimized that the source pool driver matches the target pool driver.
+	if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
+		return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+	}
 
-	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
+	// Dump tarball to storage.
+	err = pool.CreateCustomVolumeFromBackup(*bInfo, backupFile, nil)
 	if err != nil {
-		return response.InternalError(err)
+		return fmt.Errorf("Create custom volume from backup: %w", err)
 	}
 
-	reverter.Success()
-	return operations.OperationResponse(op)
+	return nil
 }
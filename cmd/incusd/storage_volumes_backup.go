@@ -17,14 +17,15 @@ import (
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
-	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -224,7 +225,12 @@ func storagePoolVolumeTypeCustomBackupsGet(d *Daemon, r *http.Request) response.
 	backups := make([]*backup.VolumeBackup, len(volumeBackups))
 
 	for i, b := range volumeBackups {
-		backups[i] = backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+		target, err := instance.BackupTargetLoad(s, b.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		backups[i] = backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, target)
 	}
 
 	resultString := []string{}
@@ -398,29 +404,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			return response.BadRequest(err)
 		}
 
-		base := volumeName + internalInstance.SnapshotDelimiter + "backup"
-		length := len(base)
-		max := 0
-
-		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup, base) {
-				continue
-			}
-
-			substr := backup[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= max {
-				max = num + 1
-			}
-		}
-
-		req.Name = fmt.Sprintf("backup%d", max)
+		req.Name = backupNextName(volumeName, backups)
 	}
 
 	// Validate the name.
@@ -428,6 +412,20 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		return response.BadRequest(fmt.Errorf("Backup names may not contain slashes"))
 	}
 
+	// Validate the target.
+	target, err := instance.BackupTargetLoad(s, req.Target)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	s3Target, ok := target.(*s3.BackupTarget)
+	if ok {
+		err = s3Target.Validate()
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	fullName := volumeName + internalInstance.SnapshotDelimiter + req.Name
 	volumeOnly := req.VolumeOnly
 
@@ -440,6 +438,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			VolumeOnly:           volumeOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			Target:               req.Target,
 		}
 
 		err := volumeBackupCreate(s, args, projectName, poolName, volumeName)
@@ -902,13 +901,22 @@ func storagePoolVolumeTypeCustomBackupExportGet(d *Daemon, r *http.Request) resp
 	fullName := volumeName + internalInstance.SnapshotDelimiter + backupName
 
 	// Ensure the volume exists
-	_, err = storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, fullName)
+	backup, err := storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, fullName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Stream the tarball from wherever it is stored.
+	tarball, size, err := backup.Target().Open(backup.Path())
 	if err != nil {
 		return response.SmartError(err)
 	}
 
 	ent := response.FileResponseEntry{
-		Path: internalUtil.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName)),
+		File:         tarball,
+		FileSize:     size,
+		FileModified: backup.CreationDate(),
+		Cleanup:      func() { _ = tarball.Close() },
 	}
 
 	s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupRetrieved.Event(poolName, volumeTypeName, fullName, projectName, request.CreateRequestor(r), nil))
@@ -42,6 +42,12 @@ var storagePoolVolumeSnapshotsTypeCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: storagePoolVolumeSnapshotsTypePost, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanManageSnapshots, "poolName", "type", "volumeName", "location")},
 }
 
+var storagePoolVolumeSnapshotTypeDiffCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff",
+
+	Get: APIEndpointAction{Handler: storagePoolVolumeSnapshotTypeDiffGet, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName", "location")},
+}
+
 var storagePoolVolumeSnapshotTypeCmd = APIEndpoint{
 	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}",
 
@@ -772,6 +778,142 @@ func storagePoolVolumeSnapshotTypeGet(d *Daemon, r *http.Request) response.Respo
 	return response.SyncResponseETag(true, &snapshot, etag)
 }
 
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff storage storage_pool_volumes_type_snapshot_diff_get
+//
+//	Get the storage volume snapshot diff
+//
+//	Lists the paths that were added, removed or modified between two snapshots of a filesystem volume.
+//	When no source snapshot is given, the changes made to the volume since the snapshot are listed instead.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: query
+//	    name: from
+//	    description: Name of the snapshot to compare against
+//	    type: string
+//	    example: snap0
+//	responses:
+//	  "200":
+//	    description: Storage volume snapshot diff
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of changed paths
+//	          items:
+//	            $ref: "#/definitions/StorageVolumeSnapshotDiff"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeSnapshotTypeDiffGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the storage pool the volume is supposed to be
+	// attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the storage volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the snapshot.
+	snapshotName, err := url.PathUnescape(mux.Vars(r)["snapshotName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	fromSnapshotName := request.QueryParam(r, "from")
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	volType, err := storagePools.VolumeDBTypeToType(volumeType)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get the project name.
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), volumeType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward if needed.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, volumeType)
+	if resp != nil {
+		return resp
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Without a source snapshot, list the changes made to the volume since the snapshot.
+	var diff []api.StorageVolumeSnapshotDiff
+	if fromSnapshotName == "" {
+		diff, err = pool.DiffVolumeSnapshots(projectName, volumeName, volType, snapshotName, "", nil)
+	} else {
+		diff, err = pool.DiffVolumeSnapshots(projectName, volumeName, volType, fromSnapshotName, snapshotName, nil)
+	}
+
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, diff)
+}
+
 // swagger:operation PUT /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName} storage storage_pool_volumes_type_snapshot_put
 //
 //	Update the storage volume snapshot
@@ -152,8 +152,14 @@ func storagePoolVolumeBackupLoadByName(ctx context.Context, s *state.State, proj
 		return nil, err
 	}
 
+	// Load the target the backup is stored on.
+	target, err := instance.BackupTargetLoad(s, b.Target)
+	if err != nil {
+		return nil, err
+	}
+
 	volumeName := strings.Split(backupName, "/")[0]
-	backup := backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+	backup := backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, target)
 
 	return backup, nil
 }
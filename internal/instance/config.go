@@ -27,8 +27,64 @@ var HugePageSizeKeys = [...]string{"limits.hugepages.64KB", "limits.hugepages.1M
 // HugePageSizeSuffix contains the list of known hugepage size suffixes.
 var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
+// BackupScheduleAliases are the schedule aliases accepted by the backups.schedule configuration keys.
+var BackupScheduleAliases = []string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"}
+
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`, `@never`), or leave empty to disable automatic backups.
+	//
+	// Note that unlike most other configuration keys, this one must be comma-and-space-separated and not just comma-separated as cron expression can themselves contain commas.
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron(BackupScheduleAliases)),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule.stopped)
+	//
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether to automatically back up stopped instances
+	"backups.schedule.stopped": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention)
+	// When a scheduled backup is created, the oldest scheduled backups of the instance are deleted until at most this number of them remain.
+	// Backups created manually are never deleted by the rotation.
+	// A value of `0` disables the rotation.
+	// ---
+	//  type: integer
+	//  defaultdesc: empty (unlimited)
+	//  liveupdate: no
+	//  shortdesc: Maximum number of backups to keep
+	"backups.retention": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target)
+	// Set to `s3` to store scheduled backups on the S3 target configured through the `backups.s3.*` server options.
+	// ---
+	//  type: string
+	//  defaultdesc: empty (local storage)
+	//  liveupdate: no
+	//  shortdesc: Where to store scheduled backups
+	"backups.target": validate.Optional(validate.IsOneOf("s3")),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.autorestart)
 	// If set to `true` will attempt up to 10 restarts over a 1 minute period upon unexpected instance exit.
 	// ---
@@ -63,6 +119,27 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on)
+	// Comma-separated list of instances (in the same project) that must be running before this instance is started.
+	// Dependencies are started first and are shut down after this instance.
+	//
+	// See {ref}`instance-options-boot-dependencies` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances this instance depends on
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on.timeout)
+	// The number of seconds to wait for the dependencies to be up before giving up on starting the instance.
+	// For virtual machines, this includes waiting for their agent.
+	// ---
+	//  type: integer
+	//  defaultdesc: 120
+	//  liveupdate: yes
+	//  shortdesc: How long to wait for the dependencies
+	"boot.depends_on.timeout": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -177,6 +254,29 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.group)
+	// Instances of a project sharing the same placement group are placed on the cluster members according to
+	// the {config:option}`instance-miscellaneous:placement.policy` of the instance being placed.
+	//
+	// See {ref}`clustering-instance-placement-groups` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Placement group of the instance
+	"placement.group": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.policy)
+	// Possible values are `anti-affinity`, `affinity`, `soft-anti-affinity` and `soft-affinity`.
+	//
+	// See {ref}`clustering-instance-placement-groups` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `anti-affinity`
+	//  liveupdate: yes
+	//  condition: `placement.group` is set
+	//  shortdesc: How to place the instance relative to the other instances of its placement group
+	"placement.policy": validate.Optional(validate.IsOneOf("affinity", "anti-affinity", "soft-affinity", "soft-anti-affinity")),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -614,6 +714,16 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Percentage of memory to have in sync before stopping the instance
 	"migration.incremental.memory.goal": validate.Optional(validate.IsUint32),
 
+	// gendoc:generate(entity=instance, group=migration, key=migration.bandwidth_limit)
+	// This limits the data sent when migrating the instance to another server, on top of the server-wide `migration.bandwidth_limit` option.
+	// It takes precedence over the `migration.bandwidth_limit` option of the storage pool.
+	// ---
+	//  type: string
+	//  defaultdesc: same as the storage pool's `migration.bandwidth_limit`
+	//  liveupdate: yes
+	//  shortdesc: Maximum bandwidth (in bit/s) used when migrating the instance (various suffixes supported, see {ref}`instances-limit-units`)
+	"migration.bandwidth_limit": validate.Optional(validate.IsBitrate),
+
 	// gendoc:generate(entity=instance, group=nvidia, key=nvidia.runtime)
 	//
 	// ---
@@ -1057,6 +1167,29 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to enable virtual IOMMU, useful for device passthrough and nesting
 	"security.iommu": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.pvpanic)
+	// The guest reports kernel panics to the host through a `pvpanic` device, after which the action set in {config:option}`instance-security:security.pvpanic.action` is applied.
+	// When disabled, a panicking guest is paused.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to handle guest kernel panics
+	"security.pvpanic": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=security, key=security.pvpanic.action)
+	// Possible values are `reset` (restart the instance), `poweroff` (stop the instance, subject to {config:option}`instance-boot:boot.autorestart`) and `dump` (write the guest memory to a `crash_*.dump` log file, then restart the instance).
+	//
+	// See {ref}`instances-crash-handling` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `reset`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Action to take when the guest kernel panics
+	"security.pvpanic.action": validate.Optional(validate.IsOneOf("reset", "poweroff", "dump")),
+
 	// gendoc:generate(entity=instance, group=security, key=security.secureboot)
 	// When disabling this option, consider enabling {config:option}`instance-security:security.csm`.
 	// ---
@@ -1107,6 +1240,42 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: The guest owner's `base64`-encoded session blob
 	"security.sev.session.data": validate.Optional(validate.IsAny),
 
+	// gendoc:generate(entity=instance, group=security, key=security.watchdog)
+	// This adds an emulated hardware watchdog (`i6300esb`, or `diag288` on s390x) which the guest must keep resetting.
+	// When it expires, the action set in {config:option}`instance-security:security.watchdog.action` is applied.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to add a watchdog device
+	"security.watchdog": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=security, key=security.watchdog.action)
+	// Possible values are `reset` (restart the instance), `poweroff` (stop the instance, subject to {config:option}`instance-boot:boot.autorestart`) and `dump` (write the guest memory to a `crash_*.dump` log file, then restart the instance).
+	//
+	// See {ref}`instances-crash-handling` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `reset`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Action to take when the watchdog expires
+	"security.watchdog.action": validate.Optional(validate.IsOneOf("reset", "poweroff", "dump")),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.quiesce)
+	// When enabled, the guest filesystems are flushed and frozen through the VM agent while taking a snapshot or a backup of a running instance.
+	// If the agent isn't running, the snapshot or backup is taken without freezing the filesystems.
+	//
+	// See {ref}`instances-snapshots-quiesce` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to freeze the guest filesystems during snapshots and backups
+	"snapshots.quiesce": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.nic_config)
 	// For containers, the name and MTU of the default network interfaces is used for the instance devices.
 	// For virtual machines, set this option to `true` to set the name and MTU of the default network interfaces to be the same as the instance devices.
@@ -0,0 +1,103 @@
+package io
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the combined throughput of the writers sharing it.
+type RateLimiter struct {
+	mu    sync.Mutex
+	limit int64
+	next  time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter allowing limit bytes per second.
+//
+// If the given limit is zero or negative, then no limit is applied.
+func NewRateLimiter(limit int64) *RateLimiter {
+	return &RateLimiter{limit: limit}
+}
+
+// SetLimit changes the number of bytes per second allowed by the limiter.
+func (l *RateLimiter) SetLimit(limit int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+}
+
+// wait blocks until n bytes can be transferred.
+func (l *RateLimiter) wait(n int) {
+	l.mu.Lock()
+
+	if l.limit <= 0 {
+		l.mu.Unlock()
+		return
+	}
+
+	// Reserve the time needed to transfer the bytes after the previous reservations.
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.limit))
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// RateLimitedWriter is a writer whose throughput is limited by a RateLimiter.
+type RateLimitedWriter struct {
+	writer  io.Writer
+	limiter *RateLimiter
+}
+
+// NewRateLimitedWriter returns a new RateLimitedWriter wrapping the given writer.
+func NewRateLimitedWriter(writer io.Writer, limiter *RateLimiter) *RateLimitedWriter {
+	return &RateLimitedWriter{
+		writer:  writer,
+		limiter: limiter,
+	}
+}
+
+// Write implements the Writer interface.
+func (w *RateLimitedWriter) Write(p []byte) (int, error) {
+	w.limiter.wait(len(p))
+
+	return w.writer.Write(p)
+}
+
+// RateLimitedFile is a file whose write throughput is limited by a RateLimiter.
+type RateLimitedFile struct {
+	file   io.ReadWriteSeeker
+	writer *RateLimitedWriter
+}
+
+// NewRateLimitedFile returns a new RateLimitedFile wrapping the given file.
+func NewRateLimitedFile(file io.ReadWriteSeeker, limiter *RateLimiter) *RateLimitedFile {
+	return &RateLimitedFile{
+		file:   file,
+		writer: NewRateLimitedWriter(file, limiter),
+	}
+}
+
+// Read implements the Reader interface.
+func (f *RateLimitedFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Write implements the Writer interface.
+func (f *RateLimitedFile) Write(p []byte) (int, error) {
+	return f.writer.Write(p)
+}
+
+// Seek implements the Seeker interface.
+func (f *RateLimitedFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
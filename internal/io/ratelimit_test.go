@@ -0,0 +1,70 @@
+package io
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitedWriter(t *testing.T) {
+	limiter := NewRateLimiter(10000)
+	buf := bytes.Buffer{}
+	w := NewRateLimitedWriter(&buf, limiter)
+
+	// The first write goes through, the next ones wait for the previous ones.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		n, err := w.Write(make([]byte, 1000))
+		require.NoError(t, err)
+		assert.Equal(t, 1000, n)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, 3000, buf.Len())
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	limiter := NewRateLimiter(10000)
+
+	// The limit applies to the combined throughput of the writers.
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			w := NewRateLimitedWriter(&bytes.Buffer{}, limiter)
+			_, _ = w.Write(make([]byte, 1000))
+			_, _ = w.Write(make([]byte, 1000))
+		}()
+	}
+
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRateLimiterNoLimit(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	w := NewRateLimitedWriter(&bytes.Buffer{}, limiter)
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		_, err := w.Write(make([]byte, 100000))
+		require.NoError(t, err)
+	}
+
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The limit can be changed while in use.
+	limiter.SetLimit(10000)
+
+	start = time.Now()
+	_, _ = w.Write(make([]byte, 1000))
+	_, _ = w.Write(make([]byte, 1000))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
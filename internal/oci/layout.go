@@ -0,0 +1,517 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/archive"
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+// Media types of the OCI image format.
+const (
+	MediaTypeImageIndex     = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest  = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageConfig    = "application/vnd.oci.image.config.v1+json"
+	MediaTypeImageLayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// AnnotationRefName is the annotation holding the tag of a manifest in an image layout.
+const AnnotationRefName = "org.opencontainers.image.ref.name"
+
+// LabelPrefix is the prefix of the labels holding the Incus image properties.
+const LabelPrefix = "org.linuxcontainers.image."
+
+// Descriptor describes the content of a blob.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *Platform         `json:"platform,omitempty"`
+}
+
+// Platform describes the platform an image runs on.
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Index is the entrypoint of an image layout, listing the tagged manifests.
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// Manifest lists the configuration and layers of an image.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// ImageConfig is the configuration of an image.
+type ImageConfig struct {
+	Created      *time.Time         `json:"created,omitempty"`
+	Architecture string             `json:"architecture"`
+	OS           string             `json:"os"`
+	Variant      string             `json:"variant,omitempty"`
+	Config       ImageRuntimeConfig `json:"config"`
+	RootFS       ImageRootFS        `json:"rootfs"`
+}
+
+// ImageRuntimeConfig holds the execution parameters of an image.
+type ImageRuntimeConfig struct {
+	Env    []string          `json:"Env,omitempty"`
+	Cmd    []string          `json:"Cmd,omitempty"`
+	Labels map[string]string `json:"Labels,omitempty"`
+}
+
+// ImageRootFS lists the uncompressed digests of the layers of an image.
+type ImageRootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// platforms maps the Incus architectures to the OCI platforms.
+var platforms = map[int]Platform{
+	osarch.ARCH_32BIT_INTEL_X86:             {Architecture: "386"},
+	osarch.ARCH_64BIT_INTEL_X86:             {Architecture: "amd64"},
+	osarch.ARCH_32BIT_ARMV6_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v6"},
+	osarch.ARCH_32BIT_ARMV7_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v7"},
+	osarch.ARCH_32BIT_ARMV8_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v8"},
+	osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN:   {Architecture: "arm64", Variant: "v8"},
+	osarch.ARCH_64BIT_POWERPC_BIG_ENDIAN:    {Architecture: "ppc64"},
+	osarch.ARCH_64BIT_POWERPC_LITTLE_ENDIAN: {Architecture: "ppc64le"},
+	osarch.ARCH_64BIT_S390_BIG_ENDIAN:       {Architecture: "s390x"},
+	osarch.ARCH_64BIT_RISCV_LITTLE_ENDIAN:   {Architecture: "riscv64"},
+	osarch.ARCH_64BIT_LOONGARCH:             {Architecture: "loong64"},
+}
+
+// WriteLayout converts an Incus container image into an OCI image tagged with the given name in the image layout at path.
+// The rootfs path is empty for unified images. The layout is created if missing and an existing image with the same tag is replaced.
+func WriteLayout(path string, tag string, metaPath string, rootfsPath string) (*Descriptor, error) {
+	blobsPath := filepath.Join(path, "blobs", "sha256")
+	err := os.MkdirAll(blobsPath, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the root filesystem into a single layer.
+	var metadata *api.ImageMetadata
+	var layer *Descriptor
+	var diffID string
+
+	if rootfsPath == "" {
+		metadata, layer, diffID, err = writeLayer(blobsPath, metaPath, "rootfs/")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		metadata, _, _, err = writeLayer("", metaPath, "")
+		if err != nil {
+			return nil, err
+		}
+
+		_, layer, diffID, err = writeLayer(blobsPath, rootfsPath, "./")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if metadata == nil {
+		return nil, errors.New("Image metadata (metadata.yaml) not found")
+	}
+
+	archID, err := osarch.ArchitectureID(metadata.Architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, ok := platforms[archID]
+	if !ok {
+		return nil, fmt.Errorf("Architecture %q isn't supported by OCI images", metadata.Architecture)
+	}
+
+	platform.OS = "linux"
+
+	// Write the image configuration.
+	config := ImageConfig{
+		Architecture: platform.Architecture,
+		OS:           platform.OS,
+		Variant:      platform.Variant,
+		Config: ImageRuntimeConfig{
+			Env:    []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+			Cmd:    []string{"/sbin/init"},
+			Labels: map[string]string{},
+		},
+		RootFS: ImageRootFS{
+			Type:    "layers",
+			DiffIDs: []string{diffID},
+		},
+	}
+
+	annotations := map[string]string{}
+	if metadata.CreationDate > 0 {
+		created := time.Unix(metadata.CreationDate, 0).UTC()
+		config.Created = &created
+		annotations["org.opencontainers.image.created"] = created.Format(time.RFC3339)
+	}
+
+	for key, value := range metadata.Properties {
+		config.Config.Labels[LabelPrefix+key] = value
+	}
+
+	if metadata.Properties["description"] != "" {
+		annotations["org.opencontainers.image.description"] = metadata.Properties["description"]
+	}
+
+	configDescriptor, err := writeJSONBlob(blobsPath, MediaTypeImageConfig, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the manifest.
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        *configDescriptor,
+		Layers:        []Descriptor{*layer},
+		Annotations:   annotations,
+	}
+
+	manifestDescriptor, err := writeJSONBlob(blobsPath, MediaTypeImageManifest, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestDescriptor.Platform = &platform
+	manifestDescriptor.Annotations = map[string]string{AnnotationRefName: tag}
+
+	// Reference the manifest from the index.
+	index := Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests:     []Descriptor{},
+	}
+
+	content, err := os.ReadFile(filepath.Join(path, "index.json"))
+	if err == nil {
+		err = json.Unmarshal(content, &index)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing the existing image layout index: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	manifests := []Descriptor{}
+	for _, entry := range index.Manifests {
+		if entry.Annotations[AnnotationRefName] != tag {
+			manifests = append(manifests, entry)
+		}
+	}
+
+	index.Manifests = append(manifests, *manifestDescriptor)
+
+	content, err = json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(path, "index.json"), content, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(path, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifestDescriptor, nil
+}
+
+// writeLayer copies the entries of the tarball under the given prefix into a compressed layer blob.
+// The image metadata is returned if found in the tarball. No layer is written if blobsPath is empty.
+func writeLayer(blobsPath string, tarballPath string, prefix string) (*api.ImageMetadata, *Descriptor, string, error) {
+	reader, err := openTarball(tarballPath)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	// Prepare the layer.
+	var layerFile *os.File
+	var tw *tar.Writer
+	var gzw *gzip.Writer
+	layerHash := sha256.New()
+	diffHash := sha256.New()
+	size := &countWriter{}
+
+	if blobsPath != "" {
+		layerFile, err = os.CreateTemp(blobsPath, ".layer-")
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		defer func() {
+			_ = layerFile.Close()
+			_ = os.Remove(layerFile.Name())
+		}()
+
+		gzw = gzip.NewWriter(io.MultiWriter(layerFile, layerHash, size))
+		tw = tar.NewWriter(io.MultiWriter(gzw, diffHash))
+	}
+
+	var metadata *api.ImageMetadata
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("Failed reading %q: %w", tarballPath, err)
+		}
+
+		name := strings.TrimPrefix(hdr.Name, "./")
+		if name == "metadata.yaml" && prefix != "./" {
+			metadata = &api.ImageMetadata{}
+
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, nil, "", err
+			}
+
+			err = yaml.Unmarshal(content, metadata)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("Failed parsing the image metadata: %w", err)
+			}
+
+			continue
+		}
+
+		if tw == nil {
+			continue
+		}
+
+		name, ok := layerPath(hdr.Name, prefix)
+		if !ok {
+			continue
+		}
+
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname, ok = layerPath(hdr.Linkname, prefix)
+			if !ok {
+				return nil, nil, "", fmt.Errorf("Invalid hard link target for %q", hdr.Name)
+			}
+		}
+
+		// Let the writer pick a format able to represent the entry.
+		hdr.Format = tar.FormatUnknown
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return nil, nil, "", err
+		}
+	}
+
+	if tw == nil {
+		return metadata, nil, "", nil
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	err = gzw.Close()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	err = layerFile.Close()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	digest := hex.EncodeToString(layerHash.Sum(nil))
+	err = os.Rename(layerFile.Name(), filepath.Join(blobsPath, digest))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	layer := &Descriptor{
+		MediaType: MediaTypeImageLayerGzip,
+		Digest:    "sha256:" + digest,
+		Size:      size.n,
+	}
+
+	return metadata, layer, "sha256:" + hex.EncodeToString(diffHash.Sum(nil)), nil
+}
+
+// layerPath returns the path of a tarball entry in the layer, or false if the entry isn't part of it.
+func layerPath(name string, prefix string) (string, bool) {
+	name = strings.TrimPrefix(name, "./")
+	if prefix != "./" {
+		if !strings.HasPrefix(name, prefix) {
+			return "", false
+		}
+
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || name == "." {
+		return "", false
+	}
+
+	return name, true
+}
+
+// writeJSONBlob stores the JSON representation of the object as a blob.
+func writeJSONBlob(blobsPath string, mediaType string, object any) (*Descriptor, error) {
+	content, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	digest := hex.EncodeToString(hash[:])
+
+	err = os.WriteFile(filepath.Join(blobsPath, digest), content, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + digest,
+		Size:      int64(len(content)),
+	}, nil
+}
+
+// openTarball returns a reader for the uncompressed content of a tarball or squashfs image.
+func openTarball(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, extension, command, err := archive.DetectCompressionFile(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Failed detecting the format of %q: %w", path, err)
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	switch extension {
+	case ".tar":
+		return f, nil
+	case ".tar.gz":
+		gzr, err := gzip.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+
+		return &commandReader{Reader: gzr, file: f}, nil
+	case ".qcow2", ".vmdk":
+		_ = f.Close()
+		return nil, errors.New("Only container images can be converted to OCI images")
+	}
+
+	_, err = exec.LookPath(command[0])
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Converting %s images requires %q be present on the system", strings.TrimPrefix(extension, "."), command[0])
+	}
+
+	// The squashfs tools don't support reading from stdin.
+	args := command[1:]
+	if extension == ".squashfs" {
+		args = append(args, path)
+	}
+
+	cmd := exec.Command(command[0], args...)
+	if extension != ".squashfs" {
+		cmd.Stdin = f
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &commandReader{Reader: stdout, file: f, cmd: cmd}, nil
+}
+
+// commandReader reads the output of a decompression and releases its resources on Close.
+type commandReader struct {
+	io.Reader
+
+	file *os.File
+	cmd  *exec.Cmd
+}
+
+// Close stops the decompression and closes the source file.
+func (r *commandReader) Close() error {
+	if r.cmd != nil {
+		_ = r.cmd.Process.Kill()
+		_ = r.cmd.Wait()
+	}
+
+	return r.file.Close()
+}
+
+// countWriter counts the bytes written to it.
+type countWriter struct {
+	n int64
+}
+
+// Write counts the written bytes.
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
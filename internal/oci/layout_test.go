@@ -0,0 +1,189 @@
+package oci_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/oci"
+)
+
+// writeTarball creates a gzip compressed tarball with the given files (directories end with a slash).
+func writeTarball(t *testing.T, path string, files map[string]string, order []string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	for _, name := range order {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			hdr = &tar.Header{Name: name, Mode: 0o755, Typeflag: tar.TypeDir}
+		}
+
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+}
+
+// readBlob parses the JSON blob with the given digest from the layout.
+func readBlob(t *testing.T, layoutPath string, digest string, object any) {
+	content, err := os.ReadFile(filepath.Join(layoutPath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:")))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, object))
+}
+
+// layerFiles returns the entries of the layer with the given digest.
+func layerFiles(t *testing.T, layoutPath string, digest string) map[string]string {
+	f, err := os.Open(filepath.Join(layoutPath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:")))
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	gzr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		files[hdr.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestWriteLayoutUnified(t *testing.T) {
+	tmpDir := t.TempDir()
+	imagePath := filepath.Join(tmpDir, "image.tar.gz")
+	layoutPath := filepath.Join(tmpDir, "layout")
+
+	files := map[string]string{
+		"metadata.yaml":       "architecture: x86_64\ncreation_date: 1700000000\nproperties:\n  os: Debian\n  description: Debian 12\n",
+		"templates/":          "",
+		"templates/hosts.tpl": "127.0.0.1 localhost",
+		"rootfs/":             "",
+		"rootfs/etc/":         "",
+		"rootfs/etc/hostname": "debian",
+	}
+
+	writeTarball(t, imagePath, files, []string{"metadata.yaml", "templates/", "templates/hosts.tpl", "rootfs/", "rootfs/etc/", "rootfs/etc/hostname"})
+
+	_, err := oci.WriteLayout(layoutPath, "latest", imagePath, "")
+	require.NoError(t, err)
+
+	// Check the index.
+	index := oci.Index{}
+	content, err := os.ReadFile(filepath.Join(layoutPath, "index.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "latest", index.Manifests[0].Annotations[oci.AnnotationRefName])
+	assert.Equal(t, "amd64", index.Manifests[0].Platform.Architecture)
+
+	// Check the manifest and configuration.
+	manifest := oci.Manifest{}
+	readBlob(t, layoutPath, index.Manifests[0].Digest, &manifest)
+	assert.Equal(t, "Debian 12", manifest.Annotations["org.opencontainers.image.description"])
+	require.Len(t, manifest.Layers, 1)
+
+	config := oci.ImageConfig{}
+	readBlob(t, layoutPath, manifest.Config.Digest, &config)
+	assert.Equal(t, "amd64", config.Architecture)
+	assert.Equal(t, "linux", config.OS)
+	assert.Equal(t, "Debian", config.Config.Labels[oci.LabelPrefix+"os"])
+	assert.Len(t, config.RootFS.DiffIDs, 1)
+
+	// Check that only the root filesystem ended up in the layer.
+	assert.Equal(t, map[string]string{"etc/": "", "etc/hostname": "debian"}, layerFiles(t, layoutPath, manifest.Layers[0].Digest))
+
+	// Check that a second image with the same tag replaces the first one.
+	_, err = oci.WriteLayout(layoutPath, "latest", imagePath, "")
+	require.NoError(t, err)
+	_, err = oci.WriteLayout(layoutPath, "other", imagePath, "")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(layoutPath, "index.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &index))
+	assert.Len(t, index.Manifests, 2)
+}
+
+func TestWriteLayoutSplit(t *testing.T) {
+	tmpDir := t.TempDir()
+	metaPath := filepath.Join(tmpDir, "meta.tar.gz")
+	rootfsPath := filepath.Join(tmpDir, "rootfs.tar.gz")
+	layoutPath := filepath.Join(tmpDir, "layout")
+
+	writeTarball(t, metaPath, map[string]string{"metadata.yaml": "architecture: aarch64\n"}, []string{"metadata.yaml"})
+	writeTarball(t, rootfsPath, map[string]string{"./": "", "./etc/": "", "./etc/hostname": "debian"}, []string{"./", "./etc/", "./etc/hostname"})
+
+	descriptor, err := oci.WriteLayout(layoutPath, "latest", metaPath, rootfsPath)
+	require.NoError(t, err)
+	assert.Equal(t, &oci.Platform{Architecture: "arm64", OS: "linux", Variant: "v8"}, descriptor.Platform)
+
+	manifest := oci.Manifest{}
+	readBlob(t, layoutPath, descriptor.Digest, &manifest)
+	assert.Equal(t, map[string]string{"etc/": "", "etc/hostname": "debian"}, layerFiles(t, layoutPath, manifest.Layers[0].Digest))
+}
+
+func TestWriteLayoutMissingMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	imagePath := filepath.Join(tmpDir, "image.tar.gz")
+
+	writeTarball(t, imagePath, map[string]string{"rootfs/": ""}, []string{"rootfs/"})
+
+	_, err := oci.WriteLayout(filepath.Join(tmpDir, "layout"), "latest", imagePath, "")
+	assert.Error(t, err)
+}
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		reference  string
+		repository string
+		tag        string
+	}{
+		{"oci://harbor.example.net/system/debian:12", "harbor.example.net/system/debian", "12"},
+		{"oci://harbor.example.net/debian", "harbor.example.net/debian", "latest"},
+		{"localhost:5000/debian", "localhost:5000/debian", "latest"},
+		{"localhost:5000/debian:12", "localhost:5000/debian", "12"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.reference, func(t *testing.T) {
+			repository, tag, err := oci.ParseReference(c.reference)
+			require.NoError(t, err)
+			assert.Equal(t, c.repository, repository)
+			assert.Equal(t, c.tag, tag)
+		})
+	}
+
+	for _, reference := range []string{"oci://debian", "oci://harbor.example.net/", "oci://harbor.example.net/debian:", "harbor.example.net/debian@sha256:abcd"} {
+		_, _, err := oci.ParseReference(reference)
+		assert.Error(t, err, reference)
+	}
+}
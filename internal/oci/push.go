@@ -0,0 +1,481 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/version"
+)
+
+// PushArgs represents the options of an image upload to a registry.
+type PushArgs struct {
+	// Whether to skip the TLS verification and allow plain HTTP registries.
+	Insecure bool
+}
+
+// ParseReference splits a "registry/repository[:tag]" reference, defaulting the tag to "latest".
+func ParseReference(reference string) (string, string, error) {
+	reference = strings.TrimPrefix(reference, "oci://")
+	reference = strings.TrimPrefix(reference, "docker://")
+
+	registry, repository, ok := strings.Cut(reference, "/")
+	if !ok || registry == "" || repository == "" {
+		return "", "", fmt.Errorf("Invalid OCI reference %q, expected \"<registry>/<repository>[:<tag>]\"", reference)
+	}
+
+	tag := "latest"
+	colon := strings.LastIndex(repository, ":")
+	if colon > strings.LastIndex(repository, "/") {
+		tag = repository[colon+1:]
+		repository = repository[:colon]
+	}
+
+	if repository == "" || tag == "" || strings.Contains(repository, "@") {
+		return "", "", fmt.Errorf("Invalid OCI reference %q, expected \"<registry>/<repository>[:<tag>]\"", reference)
+	}
+
+	return registry + "/" + repository, tag, nil
+}
+
+// Push uploads the image tagged with the given name in the image layout at path to the registry as "<registry>/<repository>[:<tag>]".
+// The registry credentials are taken from the authentication file of the container tools (see "skopeo login").
+func Push(ctx context.Context, path string, tag string, reference string, args PushArgs) error {
+	repository, destinationTag, err := ParseReference(reference)
+	if err != nil {
+		return err
+	}
+
+	registry, name, _ := strings.Cut(repository, "/")
+
+	// Load the manifest of the image.
+	manifestDescriptor, err := layoutManifest(path, tag)
+	if err != nil {
+		return err
+	}
+
+	manifestContent, err := os.ReadFile(blobPath(path, manifestDescriptor.Digest))
+	if err != nil {
+		return err
+	}
+
+	manifest := Manifest{}
+	err = json.Unmarshal(manifestContent, &manifest)
+	if err != nil {
+		return fmt.Errorf("Failed parsing the image manifest: %w", err)
+	}
+
+	client, err := newRegistryClient(ctx, registry, name, args)
+	if err != nil {
+		return fmt.Errorf("Failed pushing the image to %q: %w", reference, err)
+	}
+
+	// Upload the configuration and layers, then the manifest referencing them.
+	for _, blob := range append([]Descriptor{manifest.Config}, manifest.Layers...) {
+		err = client.uploadBlob(blobPath(path, blob.Digest), blob)
+		if err != nil {
+			return fmt.Errorf("Failed pushing the image to %q: %w", reference, err)
+		}
+	}
+
+	err = client.putManifest(destinationTag, manifestDescriptor.MediaType, manifestContent)
+	if err != nil {
+		return fmt.Errorf("Failed pushing the image to %q: %w", reference, err)
+	}
+
+	return nil
+}
+
+// layoutManifest returns the descriptor of the manifest tagged with the given name in the image layout at path.
+func layoutManifest(path string, tag string) (*Descriptor, error) {
+	content, err := os.ReadFile(filepath.Join(path, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	index := Index{}
+	err = json.Unmarshal(content, &index)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing the image layout index: %w", err)
+	}
+
+	for _, entry := range index.Manifests {
+		if entry.Annotations[AnnotationRefName] == tag {
+			if entry.MediaType == "" {
+				entry.MediaType = MediaTypeImageManifest
+			}
+
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Image %q not found in the image layout", tag)
+}
+
+// blobPath returns the path of the blob with the given digest in the image layout at path.
+func blobPath(path string, digest string) string {
+	algorithm, hash, _ := strings.Cut(digest, ":")
+
+	return filepath.Join(path, "blobs", algorithm, hash)
+}
+
+// registryClient uploads content to a repository of a registry using the OCI distribution API.
+type registryClient struct {
+	ctx        context.Context
+	httpClient *http.Client
+	baseURL    string
+	repository string
+
+	// Credentials from the authentication file and the current authorization header.
+	username      string
+	password      string
+	authorization string
+}
+
+// newRegistryClient returns a client for the repository of the registry.
+func newRegistryClient(ctx context.Context, registry string, repository string, args PushArgs) (*registryClient, error) {
+	// Images without a namespace on Docker Hub are under "library/".
+	host := registry
+	if registry == "docker.io" {
+		host = "registry-1.docker.io"
+
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if args.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	c := &registryClient{
+		ctx:        ctx,
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "https://" + host,
+		repository: repository,
+	}
+
+	var err error
+	c.username, c.password, err = registryCredentials(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the API is available, falling back to plain HTTP for insecure registries.
+	// Authentication happens on the first request requiring it.
+	err = c.ping()
+	if err != nil && args.Insecure {
+		c.baseURL = "http://" + host
+		err = c.ping()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to registry %q: %w", registry, err)
+	}
+
+	return c, nil
+}
+
+// ping checks the registry supports the OCI distribution API.
+func (c *registryClient) ping() error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.baseURL+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("Unsupported registry API: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// do sends a request to the registry, authenticating when requested. The body is opened again for each attempt.
+func (c *registryClient) do(method string, target string, contentType string, body func() (io.ReadCloser, error), length int64) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(c.ctx, method, target, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if body != nil {
+			req.Body, err = body()
+			if err != nil {
+				return nil, err
+			}
+
+			req.ContentLength = length
+		}
+
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		if c.authorization != "" {
+			req.Header.Set("Authorization", c.authorization)
+		}
+
+		req.Header.Set("User-Agent", version.UserAgent)
+
+		return c.httpClient.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Authenticate as requested by the registry and try again.
+	_ = resp.Body.Close()
+
+	err = c.authenticate(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, err
+	}
+
+	return send()
+}
+
+// authenticate sets the authorization header matching the challenge of the registry.
+func (c *registryClient) authenticate(challenge string) error {
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if c.username == "" {
+			return errors.New("The registry requires authentication, log in with \"skopeo login\" first")
+		}
+
+		c.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password))
+
+		return nil
+
+	case "bearer":
+		if params["realm"] == "" {
+			return errors.New("The registry didn't provide a token realm")
+		}
+
+		// Request a token allowing to push to the repository.
+		tokenURL, err := url.Parse(params["realm"])
+		if err != nil {
+			return err
+		}
+
+		query := tokenURL.Query()
+		if params["service"] != "" {
+			query.Set("service", params["service"])
+		}
+
+		query.Set("scope", fmt.Sprintf("repository:%s:pull,push", c.repository))
+		tokenURL.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, tokenURL.String(), nil)
+		if err != nil {
+			return err
+		}
+
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		req.Header.Set("User-Agent", version.UserAgent)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Failed getting a registry token: %s", resp.Status)
+		}
+
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+
+		err = json.NewDecoder(resp.Body).Decode(&token)
+		if err != nil {
+			return fmt.Errorf("Failed parsing the registry token: %w", err)
+		}
+
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+
+		c.authorization = "Bearer " + token.Token
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported registry authentication %q", challenge)
+}
+
+// uploadBlob uploads the blob at path, unless the registry already has it.
+func (c *registryClient) uploadBlob(path string, blob Descriptor) error {
+	resp, err := c.do(http.MethodHead, fmt.Sprintf("%s/v2/%s/blobs/%s", c.baseURL, c.repository, blob.Digest), "", nil, 0)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// Start the upload.
+	resp, err = c.do(http.MethodPost, fmt.Sprintf("%s/v2/%s/blobs/uploads/", c.baseURL, c.repository), "", nil, 0)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("Failed starting the upload of blob %q: %s", blob.Digest, resp.Status)
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("Failed starting the upload of blob %q: %w", blob.Digest, err)
+	}
+
+	query := location.Query()
+	query.Set("digest", blob.Digest)
+	location.RawQuery = query.Encode()
+
+	// Send the content in a single request.
+	body := func() (io.ReadCloser, error) { return os.Open(path) }
+
+	resp, err = c.do(http.MethodPut, location.String(), "application/octet-stream", body, blob.Size)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Failed uploading blob %q: %s", blob.Digest, resp.Status)
+	}
+
+	return nil
+}
+
+// putManifest uploads the manifest with the given tag.
+func (c *registryClient) putManifest(tag string, mediaType string, content []byte) error {
+	body := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(content)), nil }
+
+	resp, err := c.do(http.MethodPut, fmt.Sprintf("%s/v2/%s/manifests/%s", c.baseURL, c.repository, tag), mediaType, body, int64(len(content)))
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Failed uploading the manifest: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its lower case scheme and its parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+
+	for rest != "" {
+		var key, value string
+
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+// registryCredentials returns the credentials of the registry from the authentication file of the container tools.
+func registryCredentials(registry string) (string, string, error) {
+	paths := []string{}
+	if os.Getenv("REGISTRY_AUTH_FILE") != "" {
+		paths = append(paths, os.Getenv("REGISTRY_AUTH_FILE"))
+	}
+
+	if os.Getenv("XDG_RUNTIME_DIR") != "" {
+		paths = append(paths, filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "containers", "auth.json"))
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "containers", "auth.json"), filepath.Join(home, ".docker", "config.json"))
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return "", "", err
+		}
+
+		authFile := struct {
+			Auths map[string]struct {
+				Auth string `json:"auth"`
+			} `json:"auths"`
+		}{}
+
+		err = json.Unmarshal(content, &authFile)
+		if err != nil {
+			return "", "", fmt.Errorf("Failed parsing %q: %w", path, err)
+		}
+
+		entry, ok := authFile.Auths[registry]
+		if !ok {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid credentials for %q in %q: %w", registry, path, err)
+		}
+
+		username, password, _ := strings.Cut(string(decoded), ":")
+
+		return username, password, nil
+	}
+
+	return "", "", nil
+}
@@ -0,0 +1,209 @@
+package oci_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/oci"
+)
+
+// testRegistry is a registry storing the uploaded blobs and manifests in memory.
+// Pushing requires a token, obtained with the "user:secret" credentials.
+type testRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	types     map[string]string
+	uploads   int
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Hand out tokens to authenticated clients.
+	if req.URL.Path == "/token" {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "user" || password != "secret" || req.URL.Query().Get("scope") != "repository:incus/debian:pull,push" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"token": "registry-token"}`))
+		return
+	}
+
+	if req.Header.Get("Authorization") != "Bearer registry-token" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="test"`, req.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := req.URL.Path
+	switch {
+	case req.Method == http.MethodGet && path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+
+	case req.Method == http.MethodHead && strings.HasPrefix(path, "/v2/incus/debian/blobs/"):
+		_, ok := r.blobs[strings.TrimPrefix(path, "/v2/incus/debian/blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+	case req.Method == http.MethodPost && path == "/v2/incus/debian/blobs/uploads/":
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/incus/debian/blobs/uploads/%d?state=test", r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/v2/incus/debian/blobs/uploads/"):
+		content, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// Check the content matches its digest.
+		hash := sha256.Sum256(content)
+		digest := "sha256:" + hex.EncodeToString(hash[:])
+		if req.URL.Query().Get("state") != "test" || req.URL.Query().Get("digest") != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		r.blobs[digest] = content
+		w.WriteHeader(http.StatusCreated)
+
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/v2/incus/debian/manifests/"):
+		content, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		tag := strings.TrimPrefix(path, "/v2/incus/debian/manifests/")
+		r.manifests[tag] = content
+		r.types[tag] = req.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestRegistry starts a registry and returns it with its address.
+func newTestRegistry(t *testing.T) (*testRegistry, string) {
+	registry := &testRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}, types: map[string]string{}}
+
+	server := httptest.NewTLSServer(registry)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return registry, serverURL.Host
+}
+
+// setRegistryCredentials makes the given credentials the only ones available for the registry.
+func setRegistryCredentials(t *testing.T, address string, username string, password string) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_RUNTIME_DIR", tmpDir)
+
+	authFile := filepath.Join(tmpDir, "auth.json")
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	require.NoError(t, os.WriteFile(authFile, []byte(fmt.Sprintf(`{"auths": {%q: {"auth": %q}}}`, address, auth)), 0o600))
+	t.Setenv("REGISTRY_AUTH_FILE", authFile)
+}
+
+func TestPush(t *testing.T) {
+	tmpDir := t.TempDir()
+	imagePath := filepath.Join(tmpDir, "image.tar.gz")
+	layoutPath := filepath.Join(tmpDir, "layout")
+
+	writeTarball(t, imagePath, map[string]string{
+		"metadata.yaml":       "architecture: x86_64\n",
+		"rootfs/":             "",
+		"rootfs/etc/":         "",
+		"rootfs/etc/hostname": "debian",
+	}, []string{"metadata.yaml", "rootfs/", "rootfs/etc/", "rootfs/etc/hostname"})
+
+	descriptor, err := oci.WriteLayout(layoutPath, "latest", imagePath, "")
+	require.NoError(t, err)
+
+	manifestContent, err := os.ReadFile(filepath.Join(layoutPath, "blobs", "sha256", strings.TrimPrefix(descriptor.Digest, "sha256:")))
+	require.NoError(t, err)
+
+	manifest := oci.Manifest{}
+	readBlob(t, layoutPath, descriptor.Digest, &manifest)
+
+	registry, address := newTestRegistry(t)
+	setRegistryCredentials(t, address, "user", "secret")
+
+	// The configuration and layer are uploaded, followed by the manifest.
+	err = oci.Push(context.Background(), layoutPath, "latest", "oci://"+address+"/incus/debian:12", oci.PushArgs{Insecure: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, registry.uploads)
+	require.Len(t, registry.blobs, 2)
+
+	for _, blob := range []oci.Descriptor{manifest.Config, manifest.Layers[0]} {
+		content, err := os.ReadFile(filepath.Join(layoutPath, "blobs", "sha256", strings.TrimPrefix(blob.Digest, "sha256:")))
+		require.NoError(t, err)
+		assert.Equal(t, content, registry.blobs[blob.Digest])
+	}
+
+	assert.Equal(t, manifestContent, registry.manifests["12"])
+	assert.Equal(t, oci.MediaTypeImageManifest, registry.types["12"])
+
+	// Blobs which are already in the registry aren't uploaded again.
+	err = oci.Push(context.Background(), layoutPath, "latest", "oci://"+address+"/incus/debian", oci.PushArgs{Insecure: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, registry.uploads)
+	assert.Equal(t, manifestContent, registry.manifests["latest"])
+
+	// The certificate of the registry is checked unless insecure.
+	err = oci.Push(context.Background(), layoutPath, "latest", "oci://"+address+"/incus/debian", oci.PushArgs{})
+	assert.Error(t, err)
+
+	// Missing tags are reported.
+	err = oci.Push(context.Background(), layoutPath, "missing", "oci://"+address+"/incus/debian", oci.PushArgs{Insecure: true})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestPushUnauthorized(t *testing.T) {
+	tmpDir := t.TempDir()
+	imagePath := filepath.Join(tmpDir, "image.tar.gz")
+	layoutPath := filepath.Join(tmpDir, "layout")
+
+	writeTarball(t, imagePath, map[string]string{"metadata.yaml": "architecture: x86_64\n", "rootfs/": ""}, []string{"metadata.yaml", "rootfs/"})
+
+	_, err := oci.WriteLayout(layoutPath, "latest", imagePath, "")
+	require.NoError(t, err)
+
+	registry, address := newTestRegistry(t)
+	setRegistryCredentials(t, address, "user", "wrong")
+
+	err = oci.Push(context.Background(), layoutPath, "latest", "oci://"+address+"/incus/debian", oci.PushArgs{Insecure: true})
+	assert.ErrorContains(t, err, "token")
+	assert.Empty(t, registry.blobs)
+	assert.Empty(t, registry.manifests)
+}
@@ -19,6 +19,10 @@ const (
 
 	// DriverScriptlet provides scriptlet-based authorization. It is compatible with any authentication method.
 	DriverScriptlet string = "scriptlet"
+
+	// DriverBuiltin provides authorization based on identities, groups and permissions stored in the database.
+	// It is compatible with any authentication method.
+	DriverBuiltin string = "builtin"
 )
 
 // ErrUnknownDriver is the "Unknown driver" error.
@@ -28,6 +32,7 @@ var authorizers = map[string]func() authorizer{
 	DriverTLS:       func() authorizer { return &TLS{} },
 	DriverOpenFGA:   func() authorizer { return &FGA{} },
 	DriverScriptlet: func() authorizer { return &Scriptlet{} },
+	DriverBuiltin:   func() authorizer { return &Builtin{} },
 }
 
 type authorizer interface {
@@ -111,6 +116,8 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+	permissionsFunc func(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error)
+	groupsFunc      func(ctx context.Context) ([]api.AuthGroup, error)
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
@@ -152,6 +159,20 @@ func WithResourcesFunc(f func() (*Resources, error)) func(*Opts) {
 	}
 }
 
+// WithPermissionsFunc should be passed into LoadAuthorizer when DriverBuiltin is used.
+func WithPermissionsFunc(f func(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.permissionsFunc = f
+	}
+}
+
+// WithGroupsFunc should be passed into LoadAuthorizer when DriverBuiltin is used.
+func WithGroupsFunc(f func(ctx context.Context) ([]api.AuthGroup, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.groupsFunc = f
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initializes an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *certificate.Cache, options ...func(opts *Opts)) (Authorizer, error) {
 	opts := &Opts{}
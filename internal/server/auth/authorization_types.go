@@ -1,5 +1,9 @@
 package auth
 
+import (
+	"slices"
+)
+
 // Entitlement is a type representation of a permission as it applies to a particular ObjectType.
 type Entitlement string
 
@@ -102,3 +106,33 @@ const (
 	relationServer  = "server"
 	relationProject = "project"
 )
+
+// instanceEntitlements are the entitlements which apply to instances.
+var instanceEntitlements = []Entitlement{EntitlementCanEdit, EntitlementCanView, EntitlementCanAccessConsole, EntitlementCanAccessFiles, EntitlementCanConnectSFTP, EntitlementCanExec, EntitlementCanUpdateState, EntitlementCanManageBackups, EntitlementCanManageSnapshots}
+
+// projectEntitlements are the entitlements which apply to projects.
+var projectEntitlements = []Entitlement{EntitlementCanEdit, EntitlementCanView, EntitlementCanCreateImageAliases, EntitlementCanCreateImages, EntitlementCanCreateInstances, EntitlementCanCreateNetworkACLs, EntitlementCanCreateNetworkAddressSets, EntitlementCanCreateNetworks, EntitlementCanCreateNetworkZones, EntitlementCanCreateProfiles, EntitlementCanCreateStorageBuckets, EntitlementCanCreateStorageVolumes, EntitlementCanViewEvents, EntitlementCanViewOperations}
+
+// serverEntitlements are the entitlements which apply to the server.
+var serverEntitlements = []Entitlement{EntitlementCanEdit, EntitlementCanView, EntitlementCanCreateCertificates, EntitlementCanCreateNetworkIntegrations, EntitlementCanCreateProjects, EntitlementCanCreateStoragePools, EntitlementCanOverrideClusterTargetRestriction, EntitlementCanViewMetrics, EntitlementCanViewPrivilegedEvents, EntitlementCanViewResources, EntitlementCanViewSensitive}
+
+// objectTypeEntitlements maps each object type to the entitlements which can be granted on its objects.
+// Entitlements granted on a project also apply to the objects of the project and entitlements granted on the
+// server apply to all objects.
+var objectTypeEntitlements = map[ObjectType][]Entitlement{
+	ObjectTypeServer:             append(append(slices.Clone(serverEntitlements), projectEntitlements[2:]...), instanceEntitlements[2:]...),
+	ObjectTypeCertificate:        {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeStoragePool:        {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeProject:            append(slices.Clone(projectEntitlements), instanceEntitlements[2:]...),
+	ObjectTypeImage:              {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeImageAlias:         {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeInstance:           instanceEntitlements,
+	ObjectTypeNetwork:            {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkACL:         {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkAddressSet:  {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkIntegration: {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkZone:        {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeProfile:            {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeStorageBucket:      {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeStorageVolume:      {EntitlementCanEdit, EntitlementCanView, EntitlementCanManageBackups, EntitlementCanManageSnapshots},
+}
@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Builtin represents the built-in authorization driver. Permissions are granted to groups of identities and stored
+// in the database. TLS clients which aren't a member of any group are authorized as with the TLS driver.
+//
+// The groups and permissions are cached, so ResetCache must be called whenever groups or identities change.
+type Builtin struct {
+	TLS
+
+	permissionsFunc func(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error)
+	groupsFunc      func(ctx context.Context) ([]api.AuthGroup, error)
+
+	cacheMu          sync.Mutex
+	cacheGeneration  uint64
+	permissionsCache map[string]builtinPermissions
+	groupsCache      []api.AuthGroup
+}
+
+// builtinPermissions is a cached result of the permissions function.
+type builtinPermissions struct {
+	permissions []api.AuthPermission
+	found       bool
+}
+
+// Maximum number of requestors whose permissions are cached.
+const builtinPermissionsCacheSize = 10000
+
+func (b *Builtin) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	if opts.permissionsFunc == nil || opts.groupsFunc == nil {
+		return errors.New("Built-in authorization driver requires permissions and groups functions")
+	}
+
+	b.permissionsFunc = opts.permissionsFunc
+	b.groupsFunc = opts.groupsFunc
+	b.permissionsCache = map[string]builtinPermissions{}
+
+	return b.TLS.load(ctx, certificateCache, opts)
+}
+
+// ResetCache discards the cached groups and permissions.
+func (b *Builtin) ResetCache() {
+	b.cacheMu.Lock()
+	defer b.cacheMu.Unlock()
+
+	b.cacheGeneration++
+	b.permissionsCache = map[string]builtinPermissions{}
+	b.groupsCache = nil
+}
+
+// cachedPermissions returns the permissions of the identity and OIDC groups, loading them if not cached.
+func (b *Builtin) cachedPermissions(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error) {
+	sortedGroups := slices.Clone(oidcGroups)
+	slices.Sort(sortedGroups)
+
+	key := strings.Join(append([]string{authenticationMethod, identifier}, sortedGroups...), "\x00")
+
+	b.cacheMu.Lock()
+	cached, ok := b.permissionsCache[key]
+	generation := b.cacheGeneration
+	b.cacheMu.Unlock()
+
+	if ok {
+		return cached.permissions, cached.found, nil
+	}
+
+	permissions, found, err := b.permissionsFunc(ctx, authenticationMethod, identifier, oidcGroups)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b.cacheMu.Lock()
+	defer b.cacheMu.Unlock()
+
+	// Don't cache permissions loaded before the cache was reset.
+	if generation == b.cacheGeneration {
+		if len(b.permissionsCache) >= builtinPermissionsCacheSize {
+			b.permissionsCache = map[string]builtinPermissions{}
+		}
+
+		b.permissionsCache[key] = builtinPermissions{permissions: permissions, found: found}
+	}
+
+	return permissions, found, nil
+}
+
+// cachedGroups returns all the groups, loading them if not cached.
+func (b *Builtin) cachedGroups(ctx context.Context) ([]api.AuthGroup, error) {
+	b.cacheMu.Lock()
+	groups := b.groupsCache
+	generation := b.cacheGeneration
+	b.cacheMu.Unlock()
+
+	if groups != nil {
+		return groups, nil
+	}
+
+	groups, err := b.groupsFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b.cacheMu.Lock()
+	defer b.cacheMu.Unlock()
+
+	if generation == b.cacheGeneration {
+		b.groupsCache = groups
+	}
+
+	return groups, nil
+}
+
+// permissions returns the permissions of the requestor, whether these permissions should be used (as opposed to
+// falling back to the TLS driver) and the request details.
+func (b *Builtin) permissions(ctx context.Context, r *http.Request) ([]api.AuthPermission, bool, *requestDetails, error) {
+	details, err := b.requestDetails(r)
+	if err != nil {
+		return nil, false, nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return nil, false, details, nil
+	}
+
+	authenticationProtocol := details.authenticationProtocol()
+	permissions, found, err := b.cachedPermissions(ctx, authenticationProtocol, details.username(), details.oidcGroups())
+	if err != nil {
+		return nil, false, nil, api.StatusErrorf(http.StatusForbidden, "Failed to load permissions: %v", err)
+	}
+
+	// TLS clients which aren't a member of any group are handled by the TLS driver.
+	if !found && authenticationProtocol == api.AuthenticationMethodTLS {
+		return nil, false, details, nil
+	}
+
+	return permissions, true, details, nil
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (b *Builtin) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	permissions, ok, _, err := b.permissions(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return b.TLS.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	if builtinAllowed(permissions, object, entitlement) {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "Permission denied")
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (b *Builtin) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	permissions, ok, _, err := b.permissions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return b.TLS.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
+	return func(object Object) bool {
+		return builtinAllowed(permissions, object, entitlement)
+	}, nil
+}
+
+// GetInstanceAccess returns the list of entities who have access to the instance.
+func (b *Builtin) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
+	return b.access(ctx, ObjectInstance(projectName, instanceName))
+}
+
+// GetProjectAccess returns the list of entities who have access to the project.
+func (b *Builtin) GetProjectAccess(ctx context.Context, projectName string) (*api.Access, error) {
+	return b.access(ctx, ObjectProject(projectName))
+}
+
+// access returns the TLS clients having access to the object along with the members of the groups having a
+// permission on the object (the role being the entitlement).
+func (b *Builtin) access(ctx context.Context, object Object) (*api.Access, error) {
+	var access *api.Access
+	var err error
+
+	if object.Type() == ObjectTypeInstance {
+		access, err = b.TLS.GetInstanceAccess(ctx, object.Project(), object.Elements()[0])
+	} else {
+		access, err = b.TLS.GetProjectAccess(ctx, object.Project())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := b.cachedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		for _, permission := range group.Permissions {
+			if !builtinAllowed([]api.AuthPermission{permission}, object, Entitlement(permission.Entitlement)) {
+				continue
+			}
+
+			for _, identityURL := range group.Identities {
+				identity := path.Base(identityURL)
+
+				entry := api.AccessEntry{
+					Identifier: identity,
+					Role:       permission.Entitlement,
+					Provider:   DriverBuiltin,
+				}
+
+				if !slices.Contains(*access, entry) {
+					*access = append(*access, entry)
+				}
+			}
+		}
+	}
+
+	return access, nil
+}
+
+// builtinAllowed returns whether the permissions grant the entitlement on the object.
+//
+// A permission grants its entitlement on its object, `can_edit` granting every entitlement. Permissions on a project
+// apply to the objects of the project and permissions on the server apply to all objects. Having any permission
+// within a project grants `can_view` on the project. All identities can view the server and its storage pools.
+func builtinAllowed(permissions []api.AuthPermission, object Object, entitlement Entitlement) bool {
+	objectType := object.Type()
+
+	// Entitlements granted to all authenticated identities.
+	if objectType == ObjectTypeServer && slices.Contains([]Entitlement{EntitlementCanView, EntitlementCanViewResources, EntitlementCanViewMetrics}, entitlement) {
+		return true
+	}
+
+	if objectType == ObjectTypeStoragePool && entitlement == EntitlementCanView {
+		return true
+	}
+
+	projectName := object.Project()
+
+	for _, permission := range permissions {
+		permissionObject := Object(permission.Object)
+		permissionEntitlement := Entitlement(permission.Entitlement)
+
+		var applies bool
+		switch permissionObject.Type() {
+		case ObjectTypeServer:
+			applies = true
+		case ObjectTypeProject:
+			applies = projectName != "" && permissionObject.Project() == projectName
+
+			// Any permission within a project allows viewing it.
+			if applies && objectType == ObjectTypeProject && entitlement == EntitlementCanView {
+				return true
+			}
+
+		default:
+			applies = permissionObject == object
+
+			// Any permission on an object of a project allows viewing the project.
+			if objectType == ObjectTypeProject && entitlement == EntitlementCanView && permissionObject.Project() == projectName {
+				return true
+			}
+		}
+
+		if !applies {
+			continue
+		}
+
+		if permissionEntitlement == EntitlementCanEdit || permissionEntitlement == entitlement {
+			return true
+		}
+
+		// Viewing a server or project allows viewing everything within it, including events and operations.
+		if permissionEntitlement == EntitlementCanView && slices.Contains([]Entitlement{EntitlementCanViewEvents, EntitlementCanViewOperations}, entitlement) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidatePermission checks that the entitlement can be granted on the object.
+func ValidatePermission(permission api.AuthPermission) error {
+	object, err := ObjectFromString(permission.Object)
+	if err != nil {
+		return fmt.Errorf("Invalid object %q: %w", permission.Object, err)
+	}
+
+	if object.Type() == ObjectTypeServer && object != ObjectServer() {
+		return fmt.Errorf("Invalid object %q: The server object is %q", permission.Object, ObjectServer())
+	}
+
+	if !slices.Contains(objectTypeEntitlements[object.Type()], Entitlement(permission.Entitlement)) {
+		return fmt.Errorf("Entitlement %q can't be granted on objects of type %q", permission.Entitlement, object.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_builtinAllowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []api.AuthPermission
+		object      Object
+		entitlement Entitlement
+		want        bool
+	}{
+		{
+			"No permissions can view the server",
+			nil,
+			ObjectServer(),
+			EntitlementCanView,
+			true,
+		},
+		{
+			"No permissions can't view an instance",
+			nil,
+			ObjectInstance("default", "c1"),
+			EntitlementCanView,
+			false,
+		},
+		{
+			"Server can_edit grants everything",
+			[]api.AuthPermission{{Entitlement: "can_edit", Object: "server:incus"}},
+			ObjectInstance("foo", "c1"),
+			EntitlementCanExec,
+			true,
+		},
+		{
+			"Server entitlement applies to all instances",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "server:incus"}},
+			ObjectInstance("foo", "c1"),
+			EntitlementCanExec,
+			true,
+		},
+		{
+			"Project entitlement applies to the instances of the project",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}},
+			ObjectInstance("default", "c1"),
+			EntitlementCanExec,
+			true,
+		},
+		{
+			"Project entitlement doesn't apply to other projects",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}},
+			ObjectInstance("foo", "c1"),
+			EntitlementCanExec,
+			false,
+		},
+		{
+			"Project entitlement doesn't grant other entitlements",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}},
+			ObjectInstance("default", "c1"),
+			EntitlementCanEdit,
+			false,
+		},
+		{
+			"Project can_edit grants everything in the project",
+			[]api.AuthPermission{{Entitlement: "can_edit", Object: "project:default"}},
+			ObjectNetwork("default", "n1"),
+			EntitlementCanEdit,
+			true,
+		},
+		{
+			"Project can_edit doesn't grant server entitlements",
+			[]api.AuthPermission{{Entitlement: "can_edit", Object: "project:default"}},
+			ObjectServer(),
+			EntitlementCanCreateProjects,
+			false,
+		},
+		{
+			"Project can_view grants viewing events",
+			[]api.AuthPermission{{Entitlement: "can_view", Object: "project:default"}},
+			ObjectProject("default"),
+			EntitlementCanViewEvents,
+			true,
+		},
+		{
+			"Instance permission allows viewing the project",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "instance:default/c1"}},
+			ObjectProject("default"),
+			EntitlementCanView,
+			true,
+		},
+		{
+			"Instance permission doesn't apply to other instances",
+			[]api.AuthPermission{{Entitlement: "can_exec", Object: "instance:default/c1"}},
+			ObjectInstance("default", "c2"),
+			EntitlementCanExec,
+			false,
+		},
+		{
+			"Instance can_edit grants everything on the instance",
+			[]api.AuthPermission{{Entitlement: "can_edit", Object: "instance:default/c1"}},
+			ObjectInstance("default", "c1"),
+			EntitlementCanManageSnapshots,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, builtinAllowed(tt.permissions, tt.object, tt.entitlement))
+		})
+	}
+}
+
+func TestValidatePermission(t *testing.T) {
+	tests := []struct {
+		permission api.AuthPermission
+		wantErr    bool
+	}{
+		{api.AuthPermission{Entitlement: "can_edit", Object: "server:incus"}, false},
+		{api.AuthPermission{Entitlement: "can_exec", Object: "server:incus"}, false},
+		{api.AuthPermission{Entitlement: "can_edit", Object: "server:other"}, true},
+		{api.AuthPermission{Entitlement: "can_create_instances", Object: "project:default"}, false},
+		{api.AuthPermission{Entitlement: "can_create_projects", Object: "project:default"}, true},
+		{api.AuthPermission{Entitlement: "can_exec", Object: "instance:default/c1"}, false},
+		{api.AuthPermission{Entitlement: "can_exec", Object: "network:default/n1"}, true},
+		{api.AuthPermission{Entitlement: "can_view", Object: "instance:c1"}, true},
+		{api.AuthPermission{Entitlement: "can_view", Object: "unknown:c1"}, true},
+		{api.AuthPermission{Entitlement: "can_view", Object: "user:jane"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission.Entitlement+" on "+tt.permission.Object, func(t *testing.T) {
+			err := ValidatePermission(tt.permission)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuiltinCache(t *testing.T) {
+	permissionsCalls := 0
+	groupsCalls := 0
+
+	b := &Builtin{
+		permissionsFunc: func(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error) {
+			permissionsCalls++
+
+			if identifier != "jane@example.com" {
+				return nil, false, nil
+			}
+
+			return []api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}}, true, nil
+		},
+		groupsFunc: func(ctx context.Context) ([]api.AuthGroup, error) {
+			groupsCalls++
+
+			return []api.AuthGroup{{Name: "operators"}}, nil
+		},
+		permissionsCache: map[string]builtinPermissions{},
+	}
+
+	ctx := context.Background()
+
+	// Permissions are loaded once per identity and set of OIDC groups.
+	for range 2 {
+		permissions, found, err := b.cachedPermissions(ctx, api.AuthenticationMethodOIDC, "jane@example.com", []string{"b", "a"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Len(t, permissions, 1)
+	}
+
+	_, _, err := b.cachedPermissions(ctx, api.AuthenticationMethodOIDC, "jane@example.com", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, permissionsCalls)
+
+	_, found, err := b.cachedPermissions(ctx, api.AuthenticationMethodOIDC, "john@example.com", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, 2, permissionsCalls)
+
+	_, _, err = b.cachedPermissions(ctx, api.AuthenticationMethodOIDC, "jane@example.com", []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, permissionsCalls)
+
+	for range 2 {
+		groups, err := b.cachedGroups(ctx)
+		require.NoError(t, err)
+		assert.Len(t, groups, 1)
+	}
+
+	assert.Equal(t, 1, groupsCalls)
+
+	// Everything is loaded again after a reset.
+	b.ResetCache()
+
+	_, _, err = b.cachedPermissions(ctx, api.AuthenticationMethodOIDC, "jane@example.com", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, permissionsCalls)
+
+	_, err = b.cachedGroups(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, groupsCalls)
+}
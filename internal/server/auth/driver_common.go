@@ -32,8 +32,10 @@ func (c *commonAuthorizer) init(driverName string, l logger.Logger) error {
 type requestDetails struct {
 	common.RequestDetails
 
-	forwardedUsername string
-	forwardedProtocol string
+	forwardedUsername   string
+	forwardedProtocol   string
+	oidcGroupsList      []string
+	forwardedOIDCGroups []string
 }
 
 func (r *requestDetails) isInternalOrUnix() bool {
@@ -64,6 +66,14 @@ func (r *requestDetails) authenticationProtocol() string {
 	return r.Protocol
 }
 
+func (r *requestDetails) oidcGroups() []string {
+	if r.Protocol == "cluster" {
+		return r.forwardedOIDCGroups
+	}
+
+	return r.oidcGroupsList
+}
+
 func (r *requestDetails) actualDetails() *common.RequestDetails {
 	return &common.RequestDetails{
 		Username:             r.username(),
@@ -118,6 +128,9 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 		}
 	}
 
+	oidcGroups, _ := r.Context().Value(request.CtxOIDCGroups).([]string)
+	forwardedOIDCGroups, _ := r.Context().Value(request.CtxForwardedOIDCGroups).([]string)
+
 	values, err := url.ParseQuery(r.URL.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse request query parameters: %w", err)
@@ -131,8 +144,10 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 			ProjectName:          request.ProjectParam(r),
 		},
 
-		forwardedUsername: forwardedUsername,
-		forwardedProtocol: forwardedProtocol,
+		forwardedUsername:   forwardedUsername,
+		forwardedProtocol:   forwardedProtocol,
+		oidcGroupsList:      oidcGroups,
+		forwardedOIDCGroups: forwardedOIDCGroups,
 	}, nil
 }
 
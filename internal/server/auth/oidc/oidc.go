@@ -22,12 +22,13 @@ import (
 type Verifier struct {
 	accessTokenVerifier *op.AccessTokenVerifier
 
-	clientID  string
-	issuer    string
-	scopes    []string
-	audience  string
-	claim     string
-	cookieKey []byte
+	clientID    string
+	issuer      string
+	scopes      []string
+	audience    string
+	claim       string
+	groupsClaim string
+	cookieKey   []byte
 }
 
 // AuthError represents an authentication error.
@@ -43,8 +44,9 @@ func (e AuthError) Unwrap() error {
 	return e.Err
 }
 
-// Auth extracts the token, validates it and returns the user information.
-func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+// Auth extracts the token, validates it and returns the user information along with the groups found in the
+// groups claim (if configured).
+func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, []string, error) {
 	var token string
 
 	auth := r.Header.Get("Authorization")
@@ -57,7 +59,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// Both returned errors contain information which are needed for the client to authenticate.
 		parts := strings.Split(auth, "Bearer ")
 		if len(parts) != 2 {
-			return "", &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
+			return "", nil, &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
 		}
 
 		token = parts[1]
@@ -65,7 +67,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// When not using a Bearer token, fetch the equivalent from a cookie and move on with it.
 		cookie, err := r.Cookie("oidc_access")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		token = cookie.Value
@@ -76,7 +78,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 
 		o.accessTokenVerifier, err = getAccessTokenVerifier(o.issuer)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 	}
 
@@ -85,25 +87,25 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// See if we can refresh the access token.
 		cookie, cookieErr := r.Cookie("oidc_refresh")
 		if cookieErr != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Get the provider.
 		provider, err := o.getProvider(r)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Attempt the refresh.
 		tokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](context.TODO(), provider, cookie.Value, "", "")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Validate the refreshed token.
 		claims, err = o.VerifyAccessToken(ctx, tokens.AccessToken)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// If we have a ResponseWriter, refresh the cookies.
@@ -136,22 +138,48 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	groups := o.groups(claims.Claims)
+
 	if o.claim != "" {
 		claim := claims.Claims[o.claim]
 		username, ok := claim.(string)
 		if claim == nil || !ok || username == "" {
-			return "", fmt.Errorf("OIDC user is missing required claim %q", o.claim)
+			return "", nil, fmt.Errorf("OIDC user is missing required claim %q", o.claim)
 		}
 
-		return username, nil
+		return username, groups, nil
 	}
 
 	user, ok := claims.Claims["email"]
 	if ok && user != nil && user.(string) != "" {
-		return user.(string), nil
+		return user.(string), groups, nil
+	}
+
+	return claims.Subject, groups, nil
+}
+
+// groups returns the list of groups found in the groups claim.
+func (o *Verifier) groups(claims map[string]any) []string {
+	if o.groupsClaim == "" {
+		return nil
 	}
 
-	return claims.Subject, nil
+	switch value := claims[o.groupsClaim].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		groups := make([]string, 0, len(value))
+		for _, entry := range value {
+			group, ok := entry.(string)
+			if ok {
+				groups = append(groups, group)
+			}
+		}
+
+		return groups
+	}
+
+	return nil
 }
 
 func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
@@ -351,14 +379,14 @@ func getAccessTokenVerifier(issuer string) (*op.AccessTokenVerifier, error) {
 }
 
 // NewVerifier returns a Verifier.
-func NewVerifier(issuer string, clientid string, scope string, audience string, claim string) (*Verifier, error) {
+func NewVerifier(issuer string, clientid string, scope string, audience string, claim string, groupsClaim string) (*Verifier, error) {
 	cookieKey, err := uuid.New().MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create UUID: %w", err)
 	}
 
 	scopes := util.SplitNTrimSpace(scope, ",", -1, false)
-	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim}
+	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim, groupsClaim: groupsClaim}
 	verifier.accessTokenVerifier, _ = getAccessTokenVerifier(issuer)
 
 	return verifier, nil
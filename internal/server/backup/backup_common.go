@@ -40,3 +40,8 @@ func (b *CommonBackup) SetCompressionAlgorithm(compression string) {
 func (b *CommonBackup) OptimizedStorage() bool {
 	return b.optimizedStorage
 }
+
+// CreationDate returns the date and time at which the backup was created.
+func (b *CommonBackup) CreationDate() time.Time {
+	return b.creationDate
+}
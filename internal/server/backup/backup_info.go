@@ -54,6 +54,7 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	IncrementalFrom  string         `json:"incremental_from,omitempty" yaml:"incremental_from,omitempty"` // Snapshot an incremental backup is based on (empty for full backups).
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
@@ -2,7 +2,6 @@ package backup
 
 import (
 	"context"
-	"os"
 	"strings"
 	"time"
 
@@ -11,9 +10,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
-	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
-	"github.com/lxc/incus/v6/shared/util"
 )
 
 // Instance represents the backup relevant subset of an instance.
@@ -30,10 +27,11 @@ type InstanceBackup struct {
 
 	instance     Instance
 	instanceOnly bool
+	target       Target
 }
 
 // NewInstanceBackup instantiates a new InstanceBackup struct.
-func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool) *InstanceBackup {
+func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool, target Target) *InstanceBackup {
 	return &InstanceBackup{
 		CommonBackup: CommonBackup{
 			state:            state,
@@ -45,6 +43,7 @@ func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, c
 		},
 		instance:     inst,
 		instanceOnly: instanceOnly,
+		target:       target,
 	}
 }
 
@@ -58,42 +57,30 @@ func (b *InstanceBackup) Instance() Instance {
 	return b.instance
 }
 
+// Target returns the target the backup tarball is stored on.
+func (b *InstanceBackup) Target() Target {
+	return b.target
+}
+
+// Path returns the path of the backup tarball relative to its target.
+func (b *InstanceBackup) Path() string {
+	return instanceBackupPath(b.instance.Project().Name, b.name)
+}
+
+// instanceBackupPath returns the target relative path of an instance backup tarball.
+func instanceBackupPath(projectName string, name string) string {
+	return "instances/" + project.Instance(projectName, name)
+}
+
 // Rename renames an instance backup.
 func (b *InstanceBackup) Rename(newName string) error {
-	oldBackupPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, b.name))
-	newBackupPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, newName))
-
-	// Extract the old and new parent backup paths from the old and new backup names rather than use
-	// instance.Name() as this may be in flux if the instance itself is being renamed, whereas the relevant
-	// instance name is encoded into the backup names.
-	oldParentName, _, _ := api.GetParentAndSnapshotName(b.name)
-	oldParentBackupsPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, oldParentName))
-	newParentName, _, _ := api.GetParentAndSnapshotName(newName)
-	newParentBackupsPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, newParentName))
-
-	// Create the new backup path if doesn't exist.
-	if !util.PathExists(newParentBackupsPath) {
-		err := os.MkdirAll(newParentBackupsPath, 0o700)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Rename the backup directory.
-	err := os.Rename(oldBackupPath, newBackupPath)
+	// Use the old and new backup names rather than instance.Name() as this may be in flux if the
+	// instance itself is being renamed, whereas the relevant instance name is encoded into the backup names.
+	err := b.target.Rename(b.Path(), instanceBackupPath(b.instance.Project().Name, newName))
 	if err != nil {
 		return err
 	}
 
-	// Check if we can remove the old parent directory.
-	empty, _ := internalUtil.PathIsEmpty(oldParentBackupsPath)
-	if empty {
-		err := os.Remove(oldParentBackupsPath)
-		if err != nil {
-			return err
-		}
-	}
-
 	// Rename the database record.
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.RenameInstanceBackup(ctx, b.name, newName)
@@ -110,28 +97,14 @@ func (b *InstanceBackup) Rename(newName string) error {
 
 // Delete removes an instance backup.
 func (b *InstanceBackup) Delete() error {
-	backupPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, b.name))
-
-	// Delete the on-disk data.
-	if util.PathExists(backupPath) {
-		err := os.RemoveAll(backupPath)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Check if we can remove the instance directory.
-	backupsPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, b.instance.Name()))
-	empty, _ := internalUtil.PathIsEmpty(backupsPath)
-	if empty {
-		err := os.Remove(backupsPath)
-		if err != nil {
-			return err
-		}
+	// Delete the stored data.
+	err := b.target.Delete(b.Path())
+	if err != nil {
+		return err
 	}
 
 	// Remove the database record.
-	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.DeleteInstanceBackup(ctx, b.name)
 	})
 	if err != nil {
@@ -151,5 +124,6 @@ func (b *InstanceBackup) Render() *api.InstanceBackup {
 		ExpiresAt:        b.expiryDate,
 		InstanceOnly:     b.instanceOnly,
 		OptimizedStorage: b.optimizedStorage,
+		Target:           b.target.Name(),
 	}
 }
@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// TargetLocal is the name of the target storing backups on the local disk.
+const TargetLocal = ""
+
+// TargetS3 is the name of the target storing backups on the configured S3 endpoint.
+const TargetS3 = "s3"
+
+// Target represents a location backup tarballs can be stored on.
+// Paths are relative to the root of the target and use forward slashes.
+type Target interface {
+	// Name returns the name of the target.
+	Name() string
+
+	// Create returns a writer for a new backup tarball. The tarball is only
+	// guaranteed to be stored once the writer has been closed without error.
+	Create(path string) (io.WriteCloser, error)
+
+	// Open returns a reader for an existing backup tarball along with its size.
+	Open(path string) (io.ReadSeekCloser, int64, error)
+
+	// Rename moves an existing backup tarball to a new path.
+	Rename(oldPath string, newPath string) error
+
+	// Delete removes a backup tarball. Missing tarballs aren't considered an error.
+	Delete(path string) error
+}
+
+// LocalTarget stores backup tarballs below the daemon's backups directory.
+type LocalTarget struct {
+	root string
+}
+
+// NewLocalTarget instantiates a new LocalTarget struct.
+func NewLocalTarget() *LocalTarget {
+	return &LocalTarget{root: internalUtil.VarPath("backups")}
+}
+
+// Name returns the name of the target.
+func (t *LocalTarget) Name() string {
+	return TargetLocal
+}
+
+// Create returns a writer for a new backup tarball.
+func (t *LocalTarget) Create(path string) (io.WriteCloser, error) {
+	fullPath := t.fullPath(path)
+
+	// Create the parent path if needed.
+	parentPath := filepath.Dir(fullPath)
+	if !util.PathExists(parentPath) {
+		err := os.MkdirAll(parentPath, 0o700)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY, 0o600)
+}
+
+// Open returns a reader for an existing backup tarball along with its size.
+func (t *LocalTarget) Open(path string) (io.ReadSeekCloser, int64, error) {
+	f, err := os.Open(t.fullPath(path))
+	if err != nil {
+		return nil, -1, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, -1, err
+	}
+
+	return f, fi.Size(), nil
+}
+
+// Rename moves an existing backup tarball to a new path.
+func (t *LocalTarget) Rename(oldPath string, newPath string) error {
+	oldFullPath := t.fullPath(oldPath)
+	newFullPath := t.fullPath(newPath)
+
+	// Create the new parent path if doesn't exist.
+	newParentPath := filepath.Dir(newFullPath)
+	if !util.PathExists(newParentPath) {
+		err := os.MkdirAll(newParentPath, 0o700)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.Rename(oldFullPath, newFullPath)
+	if err != nil {
+		return err
+	}
+
+	return t.removeEmptyParent(oldFullPath)
+}
+
+// Delete removes a backup tarball.
+func (t *LocalTarget) Delete(path string) error {
+	fullPath := t.fullPath(path)
+
+	if util.PathExists(fullPath) {
+		err := os.RemoveAll(fullPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return t.removeEmptyParent(fullPath)
+}
+
+func (t *LocalTarget) fullPath(path string) string {
+	return filepath.Join(t.root, filepath.FromSlash(path))
+}
+
+// removeEmptyParent removes the parent directory of the given path if it's now empty.
+func (t *LocalTarget) removeEmptyParent(fullPath string) error {
+	parentPath := filepath.Dir(fullPath)
+
+	empty, _ := internalUtil.PathIsEmpty(parentPath)
+	if empty {
+		err := os.Remove(parentPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,17 +2,14 @@ package backup
 
 import (
 	"context"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
-	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/revert"
-	"github.com/lxc/incus/v6/shared/util"
 )
 
 // VolumeBackup represents a custom volume backup.
@@ -23,10 +20,11 @@ type VolumeBackup struct {
 	poolName    string
 	volumeName  string
 	volumeOnly  bool
+	target      Target
 }
 
 // NewVolumeBackup instantiates a new VolumeBackup struct.
-func NewVolumeBackup(state *state.State, projectName, poolName, volumeName string, ID int, name string, creationDate, expiryDate time.Time, volumeOnly, optimizedStorage bool) *VolumeBackup {
+func NewVolumeBackup(state *state.State, projectName, poolName, volumeName string, ID int, name string, creationDate, expiryDate time.Time, volumeOnly, optimizedStorage bool, target Target) *VolumeBackup {
 	return &VolumeBackup{
 		CommonBackup: CommonBackup{
 			state:            state,
@@ -40,6 +38,7 @@ func NewVolumeBackup(state *state.State, projectName, poolName, volumeName strin
 		poolName:    poolName,
 		volumeName:  volumeName,
 		volumeOnly:  volumeOnly,
+		target:      target,
 	}
 }
 
@@ -53,46 +52,37 @@ func (b *VolumeBackup) OptimizedStorage() bool {
 	return b.optimizedStorage
 }
 
-// Rename renames a volume backup.
-func (b *VolumeBackup) Rename(newName string) error {
-	oldBackupPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, b.name))
-	newBackupPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, newName))
+// Target returns the target the backup tarball is stored on.
+func (b *VolumeBackup) Target() Target {
+	return b.target
+}
+
+// Path returns the path of the backup tarball relative to its target.
+func (b *VolumeBackup) Path() string {
+	return volumeBackupPath(b.poolName, b.projectName, b.name)
+}
 
-	// Extract the old and new parent backup paths from the old and new backup names rather than use
-	// instance.Name() as this may be in flux if the instance itself is being renamed, whereas the relevant
-	// instance name is encoded into the backup names.
-	oldParentName, _, _ := api.GetParentAndSnapshotName(b.name)
-	oldParentBackupsPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, oldParentName))
-	newParentName, _, _ := api.GetParentAndSnapshotName(newName)
-	newParentBackupsPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, newParentName))
+// volumeBackupPath returns the target relative path of a volume backup tarball.
+func volumeBackupPath(poolName string, projectName string, name string) string {
+	return "custom/" + poolName + "/" + project.StorageVolume(projectName, name)
+}
 
+// Rename renames a volume backup.
+func (b *VolumeBackup) Rename(newName string) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
-	// Create the new backup path if doesn't exist.
-	if !util.PathExists(newParentBackupsPath) {
-		err := os.MkdirAll(newParentBackupsPath, 0o700)
-		if err != nil {
-			return err
-		}
-	}
+	// Use the old and new backup names rather than the volume name as this may be in flux if the
+	// volume itself is being renamed, whereas the relevant volume name is encoded into the backup names.
+	oldPath := b.Path()
+	newPath := volumeBackupPath(b.poolName, b.projectName, newName)
 
-	// Rename the backup directory.
-	err := os.Rename(oldBackupPath, newBackupPath)
+	err := b.target.Rename(oldPath, newPath)
 	if err != nil {
 		return err
 	}
 
-	reverter.Add(func() { _ = os.Rename(newBackupPath, oldBackupPath) })
-
-	// Check if we can remove the old parent directory.
-	empty, _ := internalUtil.PathIsEmpty(oldParentBackupsPath)
-	if empty {
-		err := os.Remove(oldParentBackupsPath)
-		if err != nil {
-			return err
-		}
-	}
+	reverter.Add(func() { _ = b.target.Rename(newPath, oldPath) })
 
 	// Rename the database record.
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -108,27 +98,14 @@ func (b *VolumeBackup) Rename(newName string) error {
 
 // Delete removes a volume backup.
 func (b *VolumeBackup) Delete() error {
-	backupPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, b.name))
-	// Delete the on-disk data.
-	if util.PathExists(backupPath) {
-		err := os.RemoveAll(backupPath)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Check if we can remove the volume directory.
-	backupsPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, b.volumeName))
-	empty, _ := internalUtil.PathIsEmpty(backupsPath)
-	if empty {
-		err := os.Remove(backupsPath)
-		if err != nil {
-			return err
-		}
+	// Delete the stored data.
+	err := b.target.Delete(b.Path())
+	if err != nil {
+		return err
 	}
 
 	// Remove the database record.
-	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.DeleteStoragePoolVolumeBackup(ctx, b.name)
 	})
 	if err != nil {
@@ -146,5 +123,6 @@ func (b *VolumeBackup) Render() *api.StorageVolumeBackup {
 		ExpiresAt:        b.expiryDate,
 		VolumeOnly:       b.volumeOnly,
 		OptimizedStorage: b.optimizedStorage,
+		Target:           b.target.Name(),
 	}
 }
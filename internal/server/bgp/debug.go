@@ -2,9 +2,10 @@ package bgp
 
 // DebugInfo represents the internal debug state of the BGP server.
 type DebugInfo struct {
-	Server   DebugInfoServer   `json:"server" yaml:"server"`
-	Prefixes []DebugInfoPrefix `json:"prefixes" yaml:"prefixes"`
-	Peers    []DebugInfoPeer   `json:"peers" yaml:"peers"`
+	Server     DebugInfoServer      `json:"server" yaml:"server"`
+	Prefixes   []DebugInfoPrefix    `json:"prefixes" yaml:"prefixes"`
+	EVPNRoutes []DebugInfoEVPNRoute `json:"evpn_routes" yaml:"evpn_routes"`
+	Peers      []DebugInfoPeer      `json:"peers" yaml:"peers"`
 }
 
 // DebugInfoServer exposes the shared listener configuration.
@@ -22,6 +23,13 @@ type DebugInfoPrefix struct {
 	Nexthop string `json:"nexthop" yaml:"nexthop"`
 }
 
+// DebugInfoEVPNRoute exposes details on a single EVPN route.
+type DebugInfoEVPNRoute struct {
+	Owner string `json:"owner" yaml:"owner"`
+	VNI   uint32 `json:"vni" yaml:"vni"`
+	VTEP  string `json:"vtep" yaml:"vtep"`
+}
+
 // DebugInfoPeer exposes details on a single BGP peer.
 type DebugInfoPeer struct {
 	Address  string `json:"address" yaml:"address"`
@@ -73,5 +81,16 @@ func (s *Server) debug() DebugInfo {
 		debug.Prefixes = append(debug.Prefixes, entry)
 	}
 
+	// Fill in the EVPN routes.
+	debug.EVPNRoutes = []DebugInfoEVPNRoute{}
+	for _, route := range s.evpnRoutes {
+		entry := DebugInfoEVPNRoute{}
+		entry.Owner = route.owner
+		entry.VNI = route.vni
+		entry.VTEP = route.vtep.String()
+
+		debug.EVPNRoutes = append(debug.EVPNRoutes, entry)
+	}
+
 	return debug
 }
@@ -0,0 +1,230 @@
+package bgp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"slices"
+
+	"github.com/google/uuid"
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// evpnFamily is the address family used for EVPN routes.
+var evpnFamily = &bgpAPI.Family{Afi: bgpAPI.Family_AFI_L2VPN, Safi: bgpAPI.Family_SAFI_EVPN}
+
+// evpnTunnelTypeVXLAN is the BGP encapsulation type of VXLAN (RFC 8365).
+const evpnTunnelTypeVXLAN = 8
+
+// evpnPMSITunnelTypeIngressReplication is the PMSI tunnel type used for head-end replication (RFC 6514).
+const evpnPMSITunnelTypeIngressReplication = 6
+
+type evpnRoute struct {
+	owner string
+	vni   uint32
+	vtep  net.IP
+}
+
+// AddEVPNRoute advertises an EVPN inclusive multicast route (type 3) for the VNI and local VTEP address.
+// This lets the other VTEPs of the VNI add the local one to their flood list.
+func (s *Server) AddEVPNRoute(vni uint32, vtep net.IP, owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addEVPNRoute(vni, vtep, owner)
+}
+
+func (s *Server) addEVPNRoute(vni uint32, vtep net.IP, owner string) error {
+	// Check for an existing entry.
+	for _, route := range s.evpnRoutes {
+		if route.owner == owner && route.vni == vni && route.vtep.Equal(vtep) {
+			return nil
+		}
+	}
+
+	// Add the route to the server.
+	var routeUUID string
+	if s.bgp != nil {
+		// Route distinguisher (router-id:VNI) and route target (ASN:VNI) as auto-derived in RFC 8365.
+		rd, _ := anypb.New(&bgpAPI.RouteDistinguisherIPAddress{
+			Admin:    s.routerID.String(),
+			Assigned: vni,
+		})
+
+		nlri, _ := anypb.New(&bgpAPI.EVPNInclusiveMulticastEthernetTagRoute{
+			Rd:        rd,
+			IpAddress: vtep.String(),
+		})
+
+		aOrigin, _ := anypb.New(&bgpAPI.OriginAttribute{
+			Origin: 0,
+		})
+
+		aNextHop, _ := anypb.New(&bgpAPI.MpReachNLRIAttribute{
+			Family:   evpnFamily,
+			NextHops: []string{vtep.String()},
+			Nlris:    []*anypb.Any{nlri},
+		})
+
+		// Route targets only carry 2 bytes of ASN alongside the VNI.
+		routeTarget, _ := anypb.New(&bgpAPI.TwoOctetAsSpecificExtended{
+			IsTransitive: true,
+			SubType:      0x02,
+			Asn:          s.asn & 0xffff,
+			LocalAdmin:   vni,
+		})
+
+		encap, _ := anypb.New(&bgpAPI.EncapExtended{
+			TunnelType: evpnTunnelTypeVXLAN,
+		})
+
+		aCommunities, _ := anypb.New(&bgpAPI.ExtendedCommunitiesAttribute{
+			Communities: []*anypb.Any{routeTarget, encap},
+		})
+
+		tunnelID := vtep.To4()
+		if tunnelID == nil {
+			tunnelID = vtep.To16()
+		}
+
+		aPMSI, _ := anypb.New(&bgpAPI.PmsiTunnelAttribute{
+			Type:  evpnPMSITunnelTypeIngressReplication,
+			Label: vni,
+			Id:    tunnelID,
+		})
+
+		resp, err := s.bgp.AddPath(context.Background(), &bgpAPI.AddPathRequest{
+			Path: &bgpAPI.Path{
+				Family: evpnFamily,
+				Nlri:   nlri,
+				Pattrs: []*anypb.Any{aOrigin, aNextHop, aCommunities, aPMSI},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		routeUUID = string(resp.Uuid)
+	} else {
+		// Generate a dummy UUID.
+		routeUUID = uuid.New().String()
+	}
+
+	// Add route to the map.
+	s.evpnRoutes[routeUUID] = evpnRoute{
+		owner: owner,
+		vni:   vni,
+		vtep:  vtep,
+	}
+
+	return nil
+}
+
+// RemoveEVPNRoutesByOwner removes all EVPN routes for the provided owner.
+func (s *Server) RemoveEVPNRoutesByOwner(owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Make a copy of the routes dict to safely iterate (route removal mutates it).
+	routes := map[string]evpnRoute{}
+	for routeUUID, route := range s.evpnRoutes {
+		routes[routeUUID] = route
+	}
+
+	for routeUUID, route := range routes {
+		if route.owner != owner {
+			continue
+		}
+
+		// Remove it from the BGP server.
+		if s.bgp != nil {
+			err := s.bgp.DeletePath(context.Background(), &bgpAPI.DeletePathRequest{Uuid: []byte(routeUUID)})
+			if err != nil && err.Error() != "can't find a specified path" {
+				return err
+			}
+		}
+
+		delete(s.evpnRoutes, routeUUID)
+	}
+
+	return nil
+}
+
+// EVPNRemoteVTEPs returns the addresses of the remote VTEPs advertising an inclusive multicast route for the VNI.
+func (s *Server) EVPNRemoteVTEPs(vni uint32) ([]net.IP, error) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vteps := []net.IP{}
+
+	if s.bgp == nil {
+		return vteps, nil
+	}
+
+	// Get the local VTEPs to skip our own routes.
+	localVTEPs := []net.IP{}
+	for _, route := range s.evpnRoutes {
+		if route.vni == vni {
+			localVTEPs = append(localVTEPs, route.vtep)
+		}
+	}
+
+	hasVTEP := func(list []net.IP, vtep net.IP) bool {
+		return slices.ContainsFunc(list, func(entry net.IP) bool { return entry.Equal(vtep) })
+	}
+
+	err := s.bgp.ListPath(context.Background(), &bgpAPI.ListPathRequest{TableType: bgpAPI.TableType_GLOBAL, Family: evpnFamily}, func(dst *bgpAPI.Destination) {
+		for _, path := range dst.Paths {
+			if path.IsWithdraw {
+				continue
+			}
+
+			nlri, err := path.Nlri.UnmarshalNew()
+			if err != nil {
+				continue
+			}
+
+			route, ok := nlri.(*bgpAPI.EVPNInclusiveMulticastEthernetTagRoute)
+			if !ok {
+				continue
+			}
+
+			// The VNI is carried in the label of the PMSI tunnel attribute.
+			var pmsi *bgpAPI.PmsiTunnelAttribute
+			for _, pattr := range path.Pattrs {
+				attr, err := pattr.UnmarshalNew()
+				if err != nil {
+					continue
+				}
+
+				entry, ok := attr.(*bgpAPI.PmsiTunnelAttribute)
+				if ok {
+					pmsi = entry
+					break
+				}
+			}
+
+			if pmsi == nil || pmsi.Label != vni {
+				continue
+			}
+
+			vtep := net.ParseIP(route.IpAddress)
+			if vtep == nil || hasVTEP(localVTEPs, vtep) || hasVTEP(vteps, vtep) {
+				continue
+			}
+
+			vteps = append(vteps, vtep)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(vteps, func(a net.IP, b net.IP) int { return bytes.Compare(a.To16(), b.To16()) })
+
+	return vteps, nil
+}
@@ -0,0 +1,118 @@
+package bgp
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// newTestServer returns a started server which doesn't listen for connections.
+func newTestServer(t *testing.T) *Server {
+	s := NewServer(nil)
+
+	// A negative port disables the listener.
+	err := s.start("127.0.0.1:-1", 65000, net.ParseIP("192.0.2.1"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = s.stop() })
+
+	return s
+}
+
+// addTestEVPNPath injects an inclusive multicast route as advertised by another VTEP.
+func addTestEVPNPath(t *testing.T, s *Server, vni uint32, vtep string) {
+	rd, _ := anypb.New(&bgpAPI.RouteDistinguisherIPAddress{Admin: vtep, Assigned: vni})
+	nlri, _ := anypb.New(&bgpAPI.EVPNInclusiveMulticastEthernetTagRoute{Rd: rd, IpAddress: vtep})
+	aOrigin, _ := anypb.New(&bgpAPI.OriginAttribute{Origin: 0})
+	aNextHop, _ := anypb.New(&bgpAPI.MpReachNLRIAttribute{Family: evpnFamily, NextHops: []string{vtep}, Nlris: []*anypb.Any{nlri}})
+	aPMSI, _ := anypb.New(&bgpAPI.PmsiTunnelAttribute{Type: evpnPMSITunnelTypeIngressReplication, Label: vni, Id: net.ParseIP(vtep).To4()})
+
+	_, err := s.bgp.AddPath(context.Background(), &bgpAPI.AddPathRequest{
+		Path: &bgpAPI.Path{
+			Family: evpnFamily,
+			Nlri:   nlri,
+			Pattrs: []*anypb.Any{aOrigin, aNextHop, aPMSI},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestEVPNRemoteVTEPs(t *testing.T) {
+	s := newTestServer(t)
+
+	err := s.AddEVPNRoute(100, net.ParseIP("192.0.2.1"), "net1")
+	require.NoError(t, err)
+
+	// Adding the same route twice is a no-op.
+	err = s.AddEVPNRoute(100, net.ParseIP("192.0.2.1"), "net1")
+	require.NoError(t, err)
+	assert.Len(t, s.evpnRoutes, 1)
+
+	addTestEVPNPath(t, s, 100, "192.0.2.30")
+	addTestEVPNPath(t, s, 100, "192.0.2.20")
+	addTestEVPNPath(t, s, 200, "192.0.2.40")
+
+	// The local VTEP and the VTEPs of other VNIs are left out.
+	vteps, err := s.EVPNRemoteVTEPs(100)
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("192.0.2.20"), net.ParseIP("192.0.2.30")}, vteps)
+
+	vteps, err = s.EVPNRemoteVTEPs(200)
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("192.0.2.40")}, vteps)
+
+	vteps, err = s.EVPNRemoteVTEPs(300)
+	require.NoError(t, err)
+	assert.Empty(t, vteps)
+}
+
+func TestEVPNRemoveRoutesByOwner(t *testing.T) {
+	s := newTestServer(t)
+
+	require.NoError(t, s.AddEVPNRoute(100, net.ParseIP("192.0.2.1"), "net1"))
+	require.NoError(t, s.AddEVPNRoute(200, net.ParseIP("192.0.2.1"), "net2"))
+
+	require.NoError(t, s.RemoveEVPNRoutesByOwner("net1"))
+	assert.Len(t, s.evpnRoutes, 1)
+
+	// The route is withdrawn from the BGP table.
+	count := 0
+	err := s.bgp.ListPath(context.Background(), &bgpAPI.ListPathRequest{TableType: bgpAPI.TableType_GLOBAL, Family: evpnFamily}, func(dst *bgpAPI.Destination) {
+		count += len(dst.Paths)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Removing an unknown owner is a no-op.
+	require.NoError(t, s.RemoveEVPNRoutesByOwner("missing"))
+	assert.Len(t, s.evpnRoutes, 1)
+}
+
+func TestEVPNRoutesRestart(t *testing.T) {
+	s := NewServer(nil)
+
+	// Routes can be added before the listener is started.
+	require.NoError(t, s.AddEVPNRoute(100, net.ParseIP("192.0.2.1"), "net1"))
+
+	vteps, err := s.EVPNRemoteVTEPs(100)
+	require.NoError(t, err)
+	assert.Empty(t, vteps)
+
+	// The routes are advertised once started.
+	require.NoError(t, s.start("127.0.0.1:-1", 65000, net.ParseIP("192.0.2.1")))
+	t.Cleanup(func() { _ = s.stop() })
+
+	assert.Len(t, s.evpnRoutes, 1)
+
+	count := 0
+	err = s.bgp.ListPath(context.Background(), &bgpAPI.ListPathRequest{TableType: bgpAPI.TableType_GLOBAL, Family: evpnFamily}, func(dst *bgpAPI.Destination) {
+		count += len(dst.Paths)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,183 @@
+package bgp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"slices"
+	"time"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// routesHandlerDelay is how long to wait for more route changes before calling the routes handler.
+const routesHandlerDelay = 2 * time.Second
+
+// RoutesHandler is called when the routes received from the peers change.
+type RoutesHandler func() error
+
+// Route represents a route received from a BGP peer.
+type Route struct {
+	Prefix  net.IPNet
+	Nexthop net.IP
+	Peer    net.IP
+}
+
+// ReceivedRoutes returns the routes received from the given peers, sorted by prefix.
+// For each prefix, the best path is returned (or the first path if none of the peers provides the best path).
+// Prefixes which are advertised locally are skipped.
+func (s *Server) ReceivedRoutes(peers []net.IP) ([]Route, error) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	routes := []Route{}
+
+	if s.bgp == nil || len(peers) == 0 {
+		return routes, nil
+	}
+
+	isPeer := func(address string) bool {
+		addr := net.ParseIP(address)
+		return addr != nil && slices.ContainsFunc(peers, func(peer net.IP) bool { return peer.Equal(addr) })
+	}
+
+	isLocal := func(prefix net.IPNet) bool {
+		for _, path := range s.paths {
+			if path.prefix.String() == prefix.String() {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, family := range []*bgpAPI.Family{
+		{Afi: bgpAPI.Family_AFI_IP, Safi: bgpAPI.Family_SAFI_UNICAST},
+		{Afi: bgpAPI.Family_AFI_IP6, Safi: bgpAPI.Family_SAFI_UNICAST},
+	} {
+		err := s.bgp.ListPath(context.Background(), &bgpAPI.ListPathRequest{TableType: bgpAPI.TableType_GLOBAL, Family: family}, func(dst *bgpAPI.Destination) {
+			var selected *bgpAPI.Path
+			for _, path := range dst.Paths {
+				if path.IsWithdraw || !isPeer(path.NeighborIp) {
+					continue
+				}
+
+				if selected == nil || (path.Best && !selected.Best) {
+					selected = path
+				}
+			}
+
+			if selected == nil {
+				return
+			}
+
+			_, prefix, err := net.ParseCIDR(dst.Prefix)
+			if err != nil || isLocal(*prefix) {
+				return
+			}
+
+			nexthop := pathNexthop(selected)
+			if nexthop == nil {
+				return
+			}
+
+			routes = append(routes, Route{
+				Prefix:  *prefix,
+				Nexthop: nexthop,
+				Peer:    net.ParseIP(selected.NeighborIp),
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(routes, func(a Route, b Route) int {
+		cmp := bytes.Compare(a.Prefix.IP.To16(), b.Prefix.IP.To16())
+		if cmp != 0 {
+			return cmp
+		}
+
+		return bytes.Compare(a.Prefix.Mask, b.Prefix.Mask)
+	})
+
+	return routes, nil
+}
+
+// pathNexthop returns the next-hop of a path, preferring global addresses for IPv6.
+func pathNexthop(path *bgpAPI.Path) net.IP {
+	for _, pattr := range path.Pattrs {
+		attr, err := pattr.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+
+		switch entry := attr.(type) {
+		case *bgpAPI.NextHopAttribute:
+			return net.ParseIP(entry.NextHop)
+		case *bgpAPI.MpReachNLRIAttribute:
+			for _, nexthop := range entry.NextHops {
+				addr := net.ParseIP(nexthop)
+				if addr != nil && !addr.IsLinkLocalUnicast() && !addr.IsUnspecified() {
+					return addr
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// watchRoutes calls the routes handler whenever the best paths change.
+func (s *Server) watchRoutes() error {
+	if s.routesHandler == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := s.bgp.WatchEvent(ctx, &bgpAPI.WatchEventRequest{
+		Table: &bgpAPI.WatchEventRequest_Table{
+			Filters: []*bgpAPI.WatchEventRequest_Table_Filter{{Type: bgpAPI.WatchEventRequest_Table_Filter_BEST}},
+		},
+	}, func(_ *bgpAPI.WatchEventResponse) {
+		s.routesChanged()
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.routesWatchCancel = cancel
+
+	return nil
+}
+
+// routesChanged schedules a call to the routes handler.
+// Changes are batched so that the handler is called once for a burst of updates.
+func (s *Server) routesChanged() {
+	if s.routesHandler == nil {
+		return
+	}
+
+	s.routesMu.Lock()
+	defer s.routesMu.Unlock()
+
+	if s.routesTimer != nil {
+		return
+	}
+
+	s.routesTimer = time.AfterFunc(routesHandlerDelay, func() {
+		s.routesMu.Lock()
+		s.routesTimer = nil
+		s.routesMu.Unlock()
+
+		err := s.routesHandler()
+		if err != nil {
+			logger.Error("Failed handling BGP route changes", logger.Ctx{"err": err})
+		}
+	})
+}
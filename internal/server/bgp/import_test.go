@@ -0,0 +1,166 @@
+package bgp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	bgpServer "github.com/osrg/gobgp/v3/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// freePort returns a TCP port which is free on the loopback address.
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// newTestPeer starts a BGP router connecting to the server listening on the given port.
+func newTestPeer(t *testing.T, port int) *bgpServer.BgpServer {
+	peer := bgpServer.NewBgpServer()
+	go peer.Serve()
+
+	err := peer.StartBgp(context.Background(), &bgpAPI.StartBgpRequest{Global: &bgpAPI.Global{
+		RouterId:   "192.0.2.2",
+		Asn:        65001,
+		ListenPort: -1,
+	}})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = peer.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{}) })
+
+	afiSafis := []*bgpAPI.AfiSafi{}
+	for _, family := range []*bgpAPI.Family{
+		{Afi: bgpAPI.Family_AFI_IP, Safi: bgpAPI.Family_SAFI_UNICAST},
+		{Afi: bgpAPI.Family_AFI_IP6, Safi: bgpAPI.Family_SAFI_UNICAST},
+	} {
+		afiSafis = append(afiSafis, &bgpAPI.AfiSafi{Config: &bgpAPI.AfiSafiConfig{Family: family}})
+	}
+
+	err = peer.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: &bgpAPI.Peer{
+		Conf:      &bgpAPI.PeerConf{NeighborAddress: "127.0.0.1", PeerAsn: 65000},
+		Transport: &bgpAPI.Transport{RemotePort: uint32(port)},
+		Timers:    &bgpAPI.Timers{Config: &bgpAPI.TimersConfig{ConnectRetry: 1}},
+		AfiSafis:  afiSafis,
+	}})
+	require.NoError(t, err)
+
+	return peer
+}
+
+// advertise adds a route to the BGP router.
+func advertise(t *testing.T, peer *bgpServer.BgpServer, prefix string, nexthop string) {
+	_, subnet, err := net.ParseCIDR(prefix)
+	require.NoError(t, err)
+
+	prefixLen, _ := subnet.Mask.Size()
+	nlri, _ := anypb.New(&bgpAPI.IPAddressPrefix{Prefix: subnet.IP.String(), PrefixLen: uint32(prefixLen)})
+	aOrigin, _ := anypb.New(&bgpAPI.OriginAttribute{Origin: 0})
+
+	family := &bgpAPI.Family{Afi: bgpAPI.Family_AFI_IP, Safi: bgpAPI.Family_SAFI_UNICAST}
+	aNextHop, _ := anypb.New(&bgpAPI.NextHopAttribute{NextHop: nexthop})
+	if subnet.IP.To4() == nil {
+		family = &bgpAPI.Family{Afi: bgpAPI.Family_AFI_IP6, Safi: bgpAPI.Family_SAFI_UNICAST}
+		aNextHop, _ = anypb.New(&bgpAPI.MpReachNLRIAttribute{Family: family, NextHops: []string{nexthop}, Nlris: []*anypb.Any{nlri}})
+	}
+
+	_, err = peer.AddPath(context.Background(), &bgpAPI.AddPathRequest{Path: &bgpAPI.Path{
+		Family: family,
+		Nlri:   nlri,
+		Pattrs: []*anypb.Any{aOrigin, aNextHop},
+	}})
+	require.NoError(t, err)
+}
+
+// routeStrings returns the routes as strings.
+func routeStrings(routes []Route) []string {
+	result := []string{}
+	for _, route := range routes {
+		result = append(result, fmt.Sprintf("%s via %s from %s", route.Prefix.String(), route.Nexthop, route.Peer))
+	}
+
+	return result
+}
+
+func TestReceivedRoutes(t *testing.T) {
+	changed := make(chan struct{}, 10)
+	s := NewServer(func() error {
+		changed <- struct{}{}
+		return nil
+	})
+
+	port := freePort(t)
+	require.NoError(t, s.start(fmt.Sprintf("127.0.0.1:%d", port), 65000, net.ParseIP("192.0.2.1")))
+	t.Cleanup(func() { _ = s.stop() })
+
+	require.NoError(t, s.AddPeer(net.ParseIP("127.0.0.1"), 65001, "", 0))
+
+	// Prefixes advertised locally are never imported.
+	_, local, _ := net.ParseCIDR("203.0.113.0/24")
+	require.NoError(t, s.AddPrefix(*local, net.ParseIP("192.0.2.1"), "test"))
+
+	peer := newTestPeer(t, port)
+	advertise(t, peer, "198.51.100.0/24", "192.0.2.10")
+	advertise(t, peer, "198.51.100.128/25", "192.0.2.11")
+	advertise(t, peer, "203.0.113.0/24", "192.0.2.12")
+	advertise(t, peer, "2001:db8:1::/48", "2001:db8::10")
+
+	wantRoutes := []string{
+		"198.51.100.0/24 via 192.0.2.10 from 127.0.0.1",
+		"198.51.100.128/25 via 192.0.2.11 from 127.0.0.1",
+		"2001:db8:1::/48 via 2001:db8::10 from 127.0.0.1",
+	}
+
+	// The routes received from the peer are sorted by prefix.
+	require.Eventually(t, func() bool {
+		routes, err := s.ReceivedRoutes([]net.IP{net.ParseIP("127.0.0.1")})
+		return err == nil && assert.ObjectsAreEqual(wantRoutes, routeStrings(routes))
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// The routes handler is called once the changes settle.
+	select {
+	case <-changed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Routes handler wasn't called")
+	}
+
+	// Only the routes of the requested peers are returned.
+	routes, err := s.ReceivedRoutes([]net.IP{net.ParseIP("192.0.2.99")})
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+
+	routes, err = s.ReceivedRoutes(nil)
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+}
+
+func TestReceivedRoutesNotRunning(t *testing.T) {
+	s := NewServer(nil)
+
+	routes, err := s.ReceivedRoutes([]net.IP{net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+}
+
+func TestPathNexthop(t *testing.T) {
+	nextHop, _ := anypb.New(&bgpAPI.NextHopAttribute{NextHop: "192.0.2.10"})
+	assert.Equal(t, net.ParseIP("192.0.2.10"), pathNexthop(&bgpAPI.Path{Pattrs: []*anypb.Any{nextHop}}))
+
+	// Global IPv6 next-hops are preferred over link-local ones.
+	mpReach, _ := anypb.New(&bgpAPI.MpReachNLRIAttribute{NextHops: []string{"fe80::1", "2001:db8::10"}})
+	assert.Equal(t, net.ParseIP("2001:db8::10"), pathNexthop(&bgpAPI.Path{Pattrs: []*anypb.Any{mpReach}}))
+
+	mpReach, _ = anypb.New(&bgpAPI.MpReachNLRIAttribute{NextHops: []string{"fe80::1"}})
+	assert.Nil(t, pathNexthop(&bgpAPI.Path{Pattrs: []*anypb.Any{mpReach}}))
+
+	assert.Nil(t, pathNexthop(&bgpAPI.Path{}))
+}
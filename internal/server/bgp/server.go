@@ -6,6 +6,7 @@ import (
 	"net"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	bgpAPI "github.com/osrg/gobgp/v3/api"
@@ -23,11 +24,18 @@ type Server struct {
 	bgp *bgpServer.BgpServer
 
 	// Internal state (to handle reconfiguration)
-	address  string
-	asn      uint32
-	routerID net.IP
-	paths    map[string]path
-	peers    map[string]peer
+	address    string
+	asn        uint32
+	routerID   net.IP
+	paths      map[string]path
+	evpnRoutes map[string]evpnRoute
+	peers      map[string]peer
+
+	// Received routes handling.
+	routesHandler     RoutesHandler
+	routesWatchCancel context.CancelFunc
+	routesTimer       *time.Timer
+	routesMu          sync.Mutex
 
 	mu sync.Mutex
 }
@@ -47,11 +55,14 @@ type peer struct {
 }
 
 // NewServer returns a new server instance.
-func NewServer() *Server {
+// The routes handler is called whenever the routes received from the peers change.
+func NewServer(routesHandler RoutesHandler) *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:         map[string]path{},
+		evpnRoutes:    map[string]evpnRoute{},
+		peers:         map[string]peer{},
+		routesHandler: routesHandler,
 	}
 
 	return s
@@ -97,8 +108,8 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		RouterId: routerID.String(),
 		Asn:      asn,
 
-		// Always setup for IPv4 and IPv6.
-		Families: []uint32{0, 1},
+		// Always setup for IPv4, IPv6 and EVPN.
+		Families: []uint32{0, 1, 9},
 
 		// Listen address.
 		ListenAddresses: []string{addrHost},
@@ -111,6 +122,11 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		return err
 	}
 
+	// Record the address.
+	s.address = address
+	s.asn = asn
+	s.routerID = routerID
+
 	// Copy the path list
 	oldPaths := map[string]path{}
 	for pathUUID, path := range s.paths {
@@ -126,6 +142,21 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
+	// Copy the EVPN route list.
+	oldEVPNRoutes := map[string]evpnRoute{}
+	for routeUUID, route := range s.evpnRoutes {
+		oldEVPNRoutes[routeUUID] = route
+	}
+
+	// Add existing EVPN routes.
+	s.evpnRoutes = map[string]evpnRoute{}
+	for _, route := range oldEVPNRoutes {
+		err := s.addEVPNRoute(route.vni, route.vtep, route.owner)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Copy the peer list.
 	oldPeers := map[string]peer{}
 	for peerUUID, peer := range s.peers {
@@ -141,10 +172,11 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
-	// Record the address.
-	s.address = address
-	s.asn = asn
-	s.routerID = routerID
+	// Watch for changes to the received routes.
+	err = s.watchRoutes()
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -173,6 +205,12 @@ func (s *Server) stop() error {
 	// Restore peer list.
 	s.peers = oldPeers
 
+	// Stop watching the received routes.
+	if s.routesWatchCancel != nil {
+		s.routesWatchCancel()
+		s.routesWatchCancel = nil
+	}
+
 	// Stop the listener.
 	err := s.bgp.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{})
 	if err != nil {
@@ -223,6 +261,9 @@ func (s *Server) configure(address string, asn uint32, routerID net.IP) error {
 		if err != nil {
 			return fmt.Errorf("Failed to start new listener: %w", err)
 		}
+	} else if oldAddress != "" {
+		// Let the networks remove the routes received from the peers.
+		s.routesChanged()
 	}
 
 	// All done.
@@ -454,9 +495,9 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		}
 	}
 
-	// Setup peer for dual-stack.
+	// Setup peer for dual-stack and EVPN.
 	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0)
-	for _, f := range []string{"ipv4-unicast", "ipv6-unicast"} {
+	for _, f := range []string{"ipv4-unicast", "ipv6-unicast", "l2vpn-evpn"} {
 		rf, err := bgpPacket.GetRouteFamily(f)
 		if err != nil {
 			return err
@@ -13,6 +13,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -39,16 +40,57 @@ func Load(ctx context.Context, tx *db.ClusterTx) (*Config, error) {
 	return &Config{tx: tx, m: m}, nil
 }
 
+// AuditEnabled returns whether API mutations are recorded in the audit log.
+func (c *Config) AuditEnabled() bool {
+	return c.m.GetBool("audit.enabled")
+}
+
+// AuditExpiryDays returns the number of days after which audit log entries are deleted.
+func (c *Config) AuditExpiryDays() int64 {
+	return c.m.GetInt64("audit.expiry")
+}
+
 // BackupsCompressionAlgorithm returns the compression algorithm to use for backups.
 func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
 }
 
+// BackupsS3 returns the endpoint, bucket, access key, secret key and prefix of the S3 backup target.
+func (c *Config) BackupsS3() (string, string, string, string, string) {
+	return c.m.GetString("backups.s3.endpoint"), c.m.GetString("backups.s3.bucket"), c.m.GetString("backups.s3.access_key"), c.m.GetString("backups.s3.secret_key"), c.m.GetString("backups.s3.prefix")
+}
+
 // MetricsAuthentication checks whether metrics API requires authentication.
 func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
 }
 
+// MetricsPushTarget returns the type, address, user name, password, bearer token and CA certificate of the metrics push endpoint.
+func (c *Config) MetricsPushTarget() (string, string, string, string, string, string) {
+	return c.m.GetString("metrics.push.type"), c.m.GetString("metrics.push.address"), c.m.GetString("metrics.push.username"), c.m.GetString("metrics.push.password"), c.m.GetString("metrics.push.token"), c.m.GetString("metrics.push.ca_cert")
+}
+
+// MetricsPushInterval returns the interval between two metrics pushes.
+func (c *Config) MetricsPushInterval() time.Duration {
+	return time.Duration(c.m.GetInt64("metrics.push.interval")) * time.Second
+}
+
+// MetricsPushLabels returns the extra labels to add to pushed metrics.
+func (c *Config) MetricsPushLabels() map[string]string {
+	labels := map[string]string{}
+
+	for _, entry := range strings.Split(c.m.GetString("metrics.push.labels"), ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+
+		labels[key] = value
+	}
+
+	return labels
+}
+
 // BGPASN returns the BGP ASN setting.
 func (c *Config) BGPASN() int64 {
 	return c.m.GetInt64("core.bgp_asn")
@@ -198,11 +240,45 @@ func (c *Config) ImagesAutoUpdateIntervalHours() int64 {
 	return c.m.GetInt64("images.auto_update_interval")
 }
 
+// ImagesDownloadBandwidthLimit returns the maximum number of bytes per second used by image downloads on each server.
+func (c *Config) ImagesDownloadBandwidthLimit() int64 {
+	limit := c.m.GetString("images.download.bandwidth_limit")
+	if limit == "" {
+		return 0
+	}
+
+	bits, _ := units.ParseBitSizeString(limit)
+
+	return bits / 8
+}
+
+// ImagesDownloadConcurrency returns the maximum number of concurrent image downloads on each server.
+func (c *Config) ImagesDownloadConcurrency() int64 {
+	return c.m.GetInt64("images.download.concurrency")
+}
+
 // ImagesRemoteCacheExpiryDays returns the number of days after which an unused cached remote image will be flushed.
 func (c *Config) ImagesRemoteCacheExpiryDays() int64 {
 	return c.m.GetInt64("images.remote_cache_expiry")
 }
 
+// MigrationBandwidthLimit returns the maximum number of bytes per second used by the migrations sent by each server.
+func (c *Config) MigrationBandwidthLimit() int64 {
+	limit := c.m.GetString("migration.bandwidth_limit")
+	if limit == "" {
+		return 0
+	}
+
+	bits, _ := units.ParseBitSizeString(limit)
+
+	return bits / 8
+}
+
+// MigrationCompression returns the compression algorithm to use for the data sent by migrations.
+func (c *Config) MigrationCompression() string {
+	return c.m.GetString("migration.compression")
+}
+
 // InstancesNICHostname returns hostname mode to use for instance NICs.
 func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
@@ -218,6 +294,11 @@ func (c *Config) AuthorizationScriptlet() string {
 	return c.m.GetString("authorization.scriptlet")
 }
 
+// AuthorizationBuiltin returns whether the built-in authorization driver is enabled.
+func (c *Config) AuthorizationBuiltin() bool {
+	return c.m.GetBool("authorization.builtin")
+}
+
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
 	return c.m.GetBool("instances.lxcfs.per_instance")
@@ -297,6 +378,11 @@ func (c *Config) OIDCServer() (string, string, string, string, string) {
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
 }
 
+// OIDCGroupsClaim returns the name of the OpenID Connect claim holding the groups of the user.
+func (c *Config) OIDCGroupsClaim() string {
+	return c.m.GetString("oidc.groups.claim")
+}
+
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
 // number of seconds after which an offline node will be evacuated automatically. If the config key
 // is set but its value is lower than cluster.offline_threshold it returns
@@ -400,6 +486,33 @@ func (c *Config) LoggingConfigForLoki(loggerName string) (string, string, string
 	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), c.m.GetString(instanceKey), c.m.GetString(labelsKey), int(c.m.GetInt64(retryKey))
 }
 
+// LoggingConfigForOTLP returns all the OTLP settings needed to connect to a collector.
+func (c *Config) LoggingConfigForOTLP(loggerName string) (string, string, string, string, string, int) {
+	prefix := fmt.Sprintf("logging.%s", loggerName)
+	addressKey := fmt.Sprintf("%s.%s", prefix, "target.address")
+	usernameKey := fmt.Sprintf("%s.%s", prefix, "target.username")
+	passwordKey := fmt.Sprintf("%s.%s", prefix, "target.password")
+	caCertKey := fmt.Sprintf("%s.%s", prefix, "target.ca_cert")
+	instanceKey := fmt.Sprintf("%s.%s", prefix, "target.instance")
+	retryKey := fmt.Sprintf("%s.%s", prefix, "target.retry")
+
+	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), c.m.GetString(instanceKey), int(c.m.GetInt64(retryKey))
+}
+
+// LoggingConfigForWebhook returns all the webhook settings needed to send events.
+func (c *Config) LoggingConfigForWebhook(loggerName string) (string, string, string, string, string, string, int) {
+	prefix := fmt.Sprintf("logging.%s", loggerName)
+	addressKey := fmt.Sprintf("%s.%s", prefix, "target.address")
+	usernameKey := fmt.Sprintf("%s.%s", prefix, "target.username")
+	passwordKey := fmt.Sprintf("%s.%s", prefix, "target.password")
+	caCertKey := fmt.Sprintf("%s.%s", prefix, "target.ca_cert")
+	secretKey := fmt.Sprintf("%s.%s", prefix, "target.secret")
+	instanceKey := fmt.Sprintf("%s.%s", prefix, "target.instance")
+	retryKey := fmt.Sprintf("%s.%s", prefix, "target.retry")
+
+	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), c.m.GetString(secretKey), c.m.GetString(instanceKey), int(c.m.GetInt64(retryKey))
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -520,6 +633,35 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Port and interface for HTTP server (used by HTTP-01)
 	"acme.http.port": {Default: ":80", Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=audit.enabled)
+	// When enabled, every request modifying the state of the server is recorded in the audit log available at `/1.0/audit`.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to record API mutations in the audit log
+	"audit.enabled": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=audit.expiry)
+	// Specify the number of days after which audit log entries are deleted.
+	// Set to `0` to keep entries forever.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `90`
+	//  shortdesc: When audit log entries are deleted
+	"audit.expiry": {Type: config.Int64, Default: "90", Validator: validate.Optional(validate.IsInRange(0, 36500))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.builtin)
+	// When enabled, authorization is based on the identities, groups and permissions managed through `/1.0/auth`.
+	// This is ignored when OpenFGA or an authorization scriptlet is configured.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use the built-in authorization driver
+	"authorization.builtin": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.scriptlet)
 	// When using scriptlet-based authorization, this option stores the scriptlet.
 	// ---
@@ -537,6 +679,47 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Compression algorithm to use for backups
 	"backups.compression_algorithm": {Default: "gzip", Validator: validate.IsCompressionAlgorithm},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.endpoint)
+	// URL of the S3-compatible endpoint instance and custom volume backups can be stored on
+	// (for example, `https://s3.example.net:9000`).
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: S3 endpoint for backups
+	"backups.s3.endpoint": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.bucket)
+	// The bucket must already exist on the endpoint.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: S3 bucket for backups
+	"backups.s3.bucket": {},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.access_key)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: S3 access key for backups
+	"backups.s3.access_key": {},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.secret_key)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: S3 secret key for backups
+	"backups.s3.secret_key": {},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.prefix)
+	// Instance backups are stored under `<prefix>/instances/` and custom volume backups under `<prefix>/custom/` in the bucket.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Object key prefix for backups
+	"backups.s3.prefix": {},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.offline_threshold)
 	// Specify the number of seconds after which an unresponsive member is considered offline.
 	// ---
@@ -777,6 +960,25 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Default architecture to use in a mixed-architecture cluster
 	"images.default_architecture": {Validator: validate.Optional(validate.IsArchitecture)},
 
+	// gendoc:generate(entity=server, group=images, key=images.download.bandwidth_limit)
+	// The limit is shared by all the image downloads of a server, including the automatic updates of cached images.
+	// It doesn't apply to images downloaded from OCI registries.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Maximum bandwidth (in bit/s) used by image downloads on each server (various suffixes supported, see {ref}`instances-limit-units`)
+	"images.download.bandwidth_limit": {Validator: validate.Optional(bandwidthLimitValidator)},
+
+	// gendoc:generate(entity=server, group=images, key=images.download.concurrency)
+	// Additional downloads wait for one of the running downloads to complete.
+	// Set this option to `0` to allow any number of concurrent downloads.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of concurrent image downloads on each server
+	"images.download.concurrency": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 1024))},
+
 	// gendoc:generate(entity=server, group=images, key=images.remote_cache_expiry)
 	// Specify the number of days after which the unused cached image expires.
 	// ---
@@ -892,6 +1094,93 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Events to send to the Loki server
 	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging", Deprecated: "Use 'logging.*.types' instead"},
 
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.type)
+	// Possible values are `prometheus` (Prometheus remote-write) and `otlp` (OpenTelemetry over HTTP).
+	// Metrics are only pushed when this option is set.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Protocol used to push metrics
+	"metrics.push.type": {Validator: validate.Optional(validate.IsOneOf("prometheus", "otlp"))},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.address)
+	// Specify the full URL of the endpoint, for example `https://prometheus.example.net/api/v1/write`
+	// or `https://otel.example.net:4318/v1/metrics`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL to push metrics to
+	"metrics.push.address": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.username)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: User name used for authentication on the metrics endpoint
+	"metrics.push.username": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.password)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Password used for authentication on the metrics endpoint
+	"metrics.push.password": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.token)
+	// When set, it takes precedence over the user name and password.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Bearer token used for authentication on the metrics endpoint
+	"metrics.push.token": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the metrics endpoint
+	"metrics.push.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.interval)
+	// Specify the number of seconds between two pushes.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `60`
+	//  shortdesc: Interval between metrics pushes
+	"metrics.push.interval": {Type: config.Int64, Default: "60", Validator: validate.IsInRange(10, 86400)},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.labels)
+	// Specify a comma-separated list of `key=value` pairs (for example, `site=lab,env=prod`).
+	// They are added as labels to all Prometheus samples, or as resource attributes for OpenTelemetry.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Extra labels for pushed metrics
+	"metrics.push.labels": {Validator: validate.Optional(validate.IsListOf(metricsPushLabelValidator))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=migration.bandwidth_limit)
+	// The limit is shared by all the instance and storage volume migrations sent by each server, including the ones of cluster evacuations.
+	// Use the `migration.bandwidth_limit` option of instances and storage pools to limit a single migration.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Maximum bandwidth (in bit/s) used by migrations sent by each server (various suffixes supported, see {ref}`instances-limit-units`)
+	"migration.bandwidth_limit": {Validator: validate.Optional(bandwidthLimitValidator)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=migration.compression)
+	// Possible values are `zstd`, `lz4` or `none`.
+	// If the target server doesn't support it, the other algorithm is used or the data isn't compressed.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `zstd`
+	//  shortdesc: Compression algorithm to use for the data sent by migrations
+	"migration.compression": {Default: "zstd", Validator: validate.IsOneOf("zstd", "lz4", "none")},
+
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
 	// ---
@@ -956,6 +1245,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=oidc, key=oidc.groups.claim)
+	// Name of the claim holding the list of groups of the user.
+	// Those groups are mapped to authorization groups when using the built-in authorization driver.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: OpenID Connect claim to use as the list of groups
+	"oidc.groups.claim": {},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
@@ -1045,6 +1343,19 @@ func expiryValidator(value string) error {
 	return nil
 }
 
+func bandwidthLimitValidator(value string) error {
+	limit, err := units.ParseBitSizeString(value)
+	if err != nil {
+		return err
+	}
+
+	if limit < 8 {
+		return fmt.Errorf("Value must be at least 8bit")
+	}
+
+	return nil
+}
+
 func offlineThresholdDefault() string {
 	return strconv.Itoa(db.DefaultOfflineThreshold)
 }
@@ -1117,3 +1428,16 @@ func rebalanceThresholdValidator(value string) error {
 
 	return nil
 }
+
+func metricsPushLabelValidator(value string) error {
+	key, _, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("Label %q must be in the key=value format", value)
+	}
+
+	if key == "" {
+		return fmt.Errorf("Label %q has an empty key", value)
+	}
+
+	return nil
+}
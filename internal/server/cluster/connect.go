@@ -69,6 +69,16 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 				req.Header.Add(request.HeaderForwardedProtocol, val)
 			}
 
+			groups, ok := ctx.Value(request.CtxOIDCGroups).([]string)
+			if ok && len(groups) > 0 {
+				value, err := request.EncodeForwardedOIDCGroups(groups)
+				if err != nil {
+					return nil, err
+				}
+
+				req.Header.Add(request.HeaderForwardedOIDCGroups, value)
+			}
+
 			req.Header.Add(request.HeaderForwardedAddress, r.RemoteAddr)
 
 			return proxy.FromEnvironment(req)
@@ -27,6 +27,8 @@ func GetLoggingRuleForKey(key string) (Key, error) {
 	case "target.address":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.address)
 		// Specify the protocol, name or IP and port. For example `tcp://syslog01.int.example.net:514`.
+		// For `otlp`, this is the base URL of the collector (for example `https://otel.example.net:4318`), to which `/v1/logs` is added.
+		// For `webhook`, this is the full URL the events are posted to.
 		// ---
 		//  type: string
 		//  scope: global
@@ -59,11 +61,12 @@ func GetLoggingRuleForKey(key string) (Key, error) {
 	case "target.instance":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.instance)
 		// This allows replacing the default instance value (server host name) by a more relevant value like a cluster identifier.
+		// For `otlp`, this is used as the `service.instance.id` resource attribute.
 		// ---
 		//  type: string
 		//  scope: global
 		//  defaultdesc: Local server host name or cluster member name
-		//  shortdesc: Name to use as the instance field in Loki events.
+		//  shortdesc: Name to use as the instance field in Loki, OTLP and webhook events.
 		return Key{}, nil
 	case "target.labels":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.labels)
@@ -73,6 +76,15 @@ func GetLoggingRuleForKey(key string) (Key, error) {
 		//  scope: global
 		//  shortdesc: Labels for a Loki log entry
 		return Key{}, nil
+	case "target.secret":
+		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.secret)
+		// When set, the `webhook` logger signs each request with an HMAC-SHA256 of its body using this secret.
+		// The signature is sent in the `X-Incus-Signature-256` header as `sha256=<hex digest>`.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Secret used to sign webhook requests
+		return Key{}, nil
 	case "target.facility":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.facility)
 		//
@@ -88,7 +100,7 @@ func GetLoggingRuleForKey(key string) (Key, error) {
 		//  type: string
 		//  scope: global
 		//  shortdesc: The type of the logger, e.g., syslog
-		return Key{Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("syslog", "loki")))}, nil
+		return Key{Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("syslog", "loki", "otlp", "webhook")))}, nil
 	case "target.retry":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.retry)
 		//
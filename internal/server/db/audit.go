@@ -0,0 +1,165 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CreateAuditEntry appends an entry to the audit log and returns its ID.
+func (c *ClusterTx) CreateAuditEntry(ctx context.Context, entry api.AuditEntry) (int64, error) {
+	q := `
+INSERT INTO audit_log (date, location, username, protocol, source_address, method, url, entity, status_code, error, operation_id, operation_status)
+     VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	result, err := c.tx.ExecContext(ctx, q, entry.Date.UTC(), entry.Location, entry.Username, entry.Protocol, entry.Address, entry.Method, entry.URL, entry.Entity, entry.StatusCode, entry.Error, entry.OperationID, entry.OperationStatus)
+	if err != nil {
+		return -1, fmt.Errorf("Failed adding audit log entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed getting audit log entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// AuditEntryFilter specifies which audit log entries to return.
+type AuditEntryFilter struct {
+	// Only return entries recorded at or after Since and before Until, if set.
+	Since time.Time
+	Until time.Time
+
+	// Additional SQL condition on the columns of the audit_log table and its arguments.
+	Where string
+	Args  []any
+
+	// Only return entries older than the one with this ID, if set.
+	BeforeID int64
+
+	// Maximum number of entries to return, 0 for no limit.
+	Limit int
+}
+
+// UpdateAuditEntryOperation records the final status of an operation in the audit log entries which started it
+// and returns how many entries were updated.
+func (c *ClusterTx) UpdateAuditEntryOperation(ctx context.Context, operationID string, status string, operationErr string) (int64, error) {
+	q := `
+UPDATE audit_log
+   SET operation_status=?, error=CASE WHEN error='' THEN ? ELSE error END
+ WHERE operation_id=?
+`
+	result, err := c.tx.ExecContext(ctx, q, status, operationErr, operationID)
+	if err != nil {
+		return -1, fmt.Errorf("Failed updating audit log entry: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return n, nil
+}
+
+// GetAuditEntries returns the entries of the audit log matching the filter, most recent first.
+func (c *ClusterTx) GetAuditEntries(ctx context.Context, filter AuditEntryFilter) ([]api.AuditEntry, error) {
+	q := `
+SELECT id, date, location, username, protocol, source_address, method, url, entity, status_code, error, operation_id, operation_status
+  FROM audit_log
+ WHERE 1=1`
+
+	args := []any{}
+
+	if !filter.Since.IsZero() {
+		q += " AND date>=?"
+		args = append(args, filter.Since.UTC())
+	}
+
+	if !filter.Until.IsZero() {
+		q += " AND date<?"
+		args = append(args, filter.Until.UTC())
+	}
+
+	if filter.BeforeID > 0 {
+		q += " AND id<?"
+		args = append(args, filter.BeforeID)
+	}
+
+	if filter.Where != "" {
+		q += " AND (" + filter.Where + ")"
+		args = append(args, filter.Args...)
+	}
+
+	q += " ORDER BY id DESC"
+
+	if filter.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	entries := []api.AuditEntry{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		entry := api.AuditEntry{}
+
+		err := scan(&entry.ID, &entry.Date, &entry.Location, &entry.Username, &entry.Protocol, &entry.Address, &entry.Method, &entry.URL, &entry.Entity, &entry.StatusCode, &entry.Error, &entry.OperationID, &entry.OperationStatus)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting audit log entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetAuditEntry returns the audit log entry with the given ID.
+func (c *ClusterTx) GetAuditEntry(ctx context.Context, id int64) (*api.AuditEntry, error) {
+	q := `
+SELECT id, date, location, username, protocol, source_address, method, url, entity, status_code, error, operation_id, operation_status
+  FROM audit_log
+ WHERE id=?
+`
+	entry := api.AuditEntry{}
+
+	err := c.tx.QueryRowContext(ctx, q, id).Scan(&entry.ID, &entry.Date, &entry.Location, &entry.Username, &entry.Protocol, &entry.Address, &entry.Method, &entry.URL, &entry.Entity, &entry.StatusCode, &entry.Error, &entry.OperationID, &entry.OperationStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Audit log entry not found")
+		}
+
+		return nil, fmt.Errorf("Failed getting audit log entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// DeleteAuditEntriesBefore deletes the audit log entries recorded before the given time and returns how many were removed.
+func (c *ClusterTx) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM audit_log WHERE date<?", before.UTC())
+	if err != nil {
+		return -1, fmt.Errorf("Failed deleting audit log entries: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return n, nil
+}
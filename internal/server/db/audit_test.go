@@ -0,0 +1,97 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestAuditEntries(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	entries := []api.AuditEntry{
+		{Date: start, Location: "none", Username: "alice", Protocol: "tls", Method: "POST", URL: "/1.0/instances", Entity: "/1.0/instances/c1?project=default", StatusCode: http.StatusAccepted, OperationID: "op1"},
+		{Date: start.Add(time.Hour), Location: "none", Username: "bob", Protocol: "oidc", Method: "DELETE", URL: "/1.0/instances/c2", Entity: "/1.0/instances/c2?project=default", StatusCode: http.StatusNotFound, Error: "Instance not found"},
+		{Date: start.Add(2 * time.Hour), Location: "none", Username: "alice", Protocol: "tls", Method: "PUT", URL: "/1.0", Entity: "/1.0", StatusCode: http.StatusOK},
+	}
+
+	for i := range entries {
+		id, err := tx.CreateAuditEntry(ctx, entries[i])
+		require.NoError(t, err)
+		entries[i].ID = id
+	}
+
+	// All entries, most recent first.
+	all, err := tx.GetAuditEntries(ctx, db.AuditEntryFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	for i := range all {
+		entry := entries[len(entries)-1-i]
+		assert.Equal(t, entry.ID, all[i].ID)
+		assert.Equal(t, entry.Username, all[i].Username)
+		assert.Equal(t, entry.StatusCode, all[i].StatusCode)
+		assert.True(t, entry.Date.Equal(all[i].Date))
+	}
+
+	// Range queries include since and exclude until.
+	ranged, err := tx.GetAuditEntries(ctx, db.AuditEntryFilter{Since: start.Add(time.Hour), Until: start.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, ranged, 1)
+	assert.Equal(t, "bob", ranged[0].Username)
+	assert.Equal(t, "Instance not found", ranged[0].Error)
+
+	// Conditions, limits and paging.
+	filtered, err := tx.GetAuditEntries(ctx, db.AuditEntryFilter{Where: "username=?", Args: []any{"alice"}, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "PUT", filtered[0].Method)
+
+	filtered, err = tx.GetAuditEntries(ctx, db.AuditEntryFilter{Where: "username=?", Args: []any{"alice"}, BeforeID: filtered[0].ID})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "POST", filtered[0].Method)
+
+	// Operation status.
+	n, err := tx.UpdateAuditEntryOperation(ctx, "op1", "Failure", "Disk full")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = tx.UpdateAuditEntryOperation(ctx, "op2", "Success", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	// Single entries.
+	entry, err := tx.GetAuditEntry(ctx, entries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "op1", entry.OperationID)
+	assert.Equal(t, "Failure", entry.OperationStatus)
+	assert.Equal(t, "Disk full", entry.Error)
+	assert.Equal(t, "/1.0/instances/c1?project=default", entry.Entity)
+
+	_, err = tx.GetAuditEntry(ctx, 1000)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	// Pruning.
+	n, err = tx.DeleteAuditEntriesBefore(ctx, start.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	all, err = tx.GetAuditEntries(ctx, db.AuditEntryFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "PUT", all[0].Method)
+}
@@ -0,0 +1,489 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthGroups returns all the authorization groups.
+func (c *ClusterTx) GetAuthGroups(ctx context.Context) ([]api.AuthGroup, error) {
+	groups := []api.AuthGroup{}
+	groupIndexes := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, name, description FROM auth_groups ORDER BY name", func(scan func(dest ...any) error) error {
+		var id int64
+		group := api.AuthGroup{
+			AuthGroupPut: api.AuthGroupPut{
+				Permissions: []api.AuthPermission{},
+				OIDCGroups:  []string{},
+			},
+			Identities: []string{},
+		}
+
+		err := scan(&id, &group.Name, &group.Description)
+		if err != nil {
+			return err
+		}
+
+		groupIndexes[id] = len(groups)
+		groups = append(groups, group)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading authorization groups: %w", err)
+	}
+
+	err = query.Scan(ctx, c.tx, "SELECT auth_group_id, entitlement, object FROM auth_groups_permissions ORDER BY object, entitlement", func(scan func(dest ...any) error) error {
+		var id int64
+		var permission api.AuthPermission
+
+		err := scan(&id, &permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndexes[id]
+		if ok {
+			groups[i].Permissions = append(groups[i].Permissions, permission)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading authorization group permissions: %w", err)
+	}
+
+	err = query.Scan(ctx, c.tx, "SELECT auth_group_id, name FROM auth_groups_oidc_groups ORDER BY name", func(scan func(dest ...any) error) error {
+		var id int64
+		var name string
+
+		err := scan(&id, &name)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndexes[id]
+		if ok {
+			groups[i].OIDCGroups = append(groups[i].OIDCGroups, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading authorization group OIDC groups: %w", err)
+	}
+
+	q := `
+SELECT identities_auth_groups.auth_group_id, identities.authentication_method, identities.identifier
+  FROM identities_auth_groups
+  JOIN identities ON identities.id=identities_auth_groups.identity_id
+ ORDER BY identities.authentication_method, identities.identifier
+`
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		var authenticationMethod string
+		var identifier string
+
+		err := scan(&id, &authenticationMethod, &identifier)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndexes[id]
+		if ok {
+			groups[i].Identities = append(groups[i].Identities, api.NewURL().Path(version.APIVersion, "auth", "identities", authenticationMethod, identifier).String())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading authorization group members: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns the authorization group with the given name.
+func (c *ClusterTx) GetAuthGroup(ctx context.Context, name string) (*api.AuthGroup, error) {
+	groups, err := c.GetAuthGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.Name == name {
+			return &group, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Authorization group not found")
+}
+
+// getAuthGroupID returns the ID of the authorization group with the given name.
+func (c *ClusterTx) getAuthGroupID(ctx context.Context, name string) (int64, error) {
+	var id int64
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM auth_groups WHERE name=?", name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Authorization group %q not found", name)
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (c *ClusterTx) CreateAuthGroup(ctx context.Context, group api.AuthGroupsPost) error {
+	_, err := c.getAuthGroupID(ctx, group.Name)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", group.Name)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_groups (name, description) VALUES (?, ?)", group.Name, group.Description)
+	if err != nil {
+		return fmt.Errorf("Failed creating authorization group: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return c.setAuthGroupEntries(ctx, id, group.AuthGroupPut)
+}
+
+// UpdateAuthGroup updates the description, permissions and OIDC groups of an authorization group.
+func (c *ClusterTx) UpdateAuthGroup(ctx context.Context, name string, group api.AuthGroupPut) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET description=? WHERE id=?", group.Description, id)
+	if err != nil {
+		return fmt.Errorf("Failed updating authorization group: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups_permissions WHERE auth_group_id=?", id)
+	if err != nil {
+		return fmt.Errorf("Failed clearing authorization group permissions: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups_oidc_groups WHERE auth_group_id=?", id)
+	if err != nil {
+		return fmt.Errorf("Failed clearing authorization group OIDC groups: %w", err)
+	}
+
+	return c.setAuthGroupEntries(ctx, id, group)
+}
+
+// setAuthGroupEntries adds the permissions and OIDC groups of an authorization group.
+func (c *ClusterTx) setAuthGroupEntries(ctx context.Context, id int64, group api.AuthGroupPut) error {
+	for _, permission := range group.Permissions {
+		_, err := c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO auth_groups_permissions (auth_group_id, entitlement, object) VALUES (?, ?, ?)", id, permission.Entitlement, permission.Object)
+		if err != nil {
+			return fmt.Errorf("Failed adding authorization group permission: %w", err)
+		}
+	}
+
+	for _, name := range group.OIDCGroups {
+		_, err := c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO auth_groups_oidc_groups (auth_group_id, name) VALUES (?, ?)", id, name)
+		if err != nil {
+			return fmt.Errorf("Failed adding authorization group OIDC group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames an authorization group.
+func (c *ClusterTx) RenameAuthGroup(ctx context.Context, name string, newName string) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.getAuthGroupID(ctx, newName)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", newName)
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET name=? WHERE id=?", newName, id)
+	if err != nil {
+		return fmt.Errorf("Failed renaming authorization group: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAuthGroup deletes an authorization group.
+func (c *ClusterTx) DeleteAuthGroup(ctx context.Context, name string) error {
+	id, err := c.getAuthGroupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.DeleteObject(c.tx, "auth_groups", id)
+	if err != nil {
+		return fmt.Errorf("Failed deleting authorization group: %w", err)
+	}
+
+	return nil
+}
+
+// GetIdentities returns all the identities known to the built-in authorization driver.
+func (c *ClusterTx) GetIdentities(ctx context.Context) ([]api.Identity, error) {
+	identities := []api.Identity{}
+	identityIndexes := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, authentication_method, identifier, name FROM identities ORDER BY authentication_method, identifier", func(scan func(dest ...any) error) error {
+		var id int64
+		identity := api.Identity{IdentityPut: api.IdentityPut{Groups: []string{}}}
+
+		err := scan(&id, &identity.AuthenticationMethod, &identity.Identifier, &identity.Name)
+		if err != nil {
+			return err
+		}
+
+		identityIndexes[id] = len(identities)
+		identities = append(identities, identity)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading identities: %w", err)
+	}
+
+	q := `
+SELECT identities_auth_groups.identity_id, auth_groups.name
+  FROM identities_auth_groups
+  JOIN auth_groups ON auth_groups.id=identities_auth_groups.auth_group_id
+ ORDER BY auth_groups.name
+`
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		var name string
+
+		err := scan(&id, &name)
+		if err != nil {
+			return err
+		}
+
+		i, ok := identityIndexes[id]
+		if ok {
+			identities[i].Groups = append(identities[i].Groups, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading identity groups: %w", err)
+	}
+
+	return identities, nil
+}
+
+// GetIdentity returns the identity with the given authentication method and identifier.
+func (c *ClusterTx) GetIdentity(ctx context.Context, authenticationMethod string, identifier string) (*api.Identity, error) {
+	identities, err := c.GetIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range identities {
+		if identity.AuthenticationMethod == authenticationMethod && identity.Identifier == identifier {
+			return &identity, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+}
+
+// getIdentityID returns the ID of the identity with the given authentication method and identifier.
+func (c *ClusterTx) getIdentityID(ctx context.Context, authenticationMethod string, identifier string) (int64, error) {
+	var id int64
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM identities WHERE authentication_method=? AND identifier=?", authenticationMethod, identifier).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// CreateIdentity creates a new identity.
+func (c *ClusterTx) CreateIdentity(ctx context.Context, identity api.IdentitiesPost) error {
+	_, err := c.getIdentityID(ctx, identity.AuthenticationMethod, identity.Identifier)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "Identity already exists")
+	} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO identities (authentication_method, identifier, name) VALUES (?, ?, ?)", identity.AuthenticationMethod, identity.Identifier, identity.Name)
+	if err != nil {
+		return fmt.Errorf("Failed creating identity: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return c.setIdentityGroups(ctx, id, identity.Groups)
+}
+
+// UpdateIdentity updates the name and groups of an identity.
+func (c *ClusterTx) UpdateIdentity(ctx context.Context, authenticationMethod string, identifier string, identity api.IdentityPut) error {
+	id, err := c.getIdentityID(ctx, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE identities SET name=? WHERE id=?", identity.Name, id)
+	if err != nil {
+		return fmt.Errorf("Failed updating identity: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM identities_auth_groups WHERE identity_id=?", id)
+	if err != nil {
+		return fmt.Errorf("Failed clearing identity groups: %w", err)
+	}
+
+	return c.setIdentityGroups(ctx, id, identity.Groups)
+}
+
+// setIdentityGroups adds an identity to the given authorization groups.
+func (c *ClusterTx) setIdentityGroups(ctx context.Context, id int64, groups []string) error {
+	for _, group := range groups {
+		groupID, err := c.getAuthGroupID(ctx, group)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.tx.ExecContext(ctx, "INSERT OR IGNORE INTO identities_auth_groups (identity_id, auth_group_id) VALUES (?, ?)", id, groupID)
+		if err != nil {
+			return fmt.Errorf("Failed adding identity to group %q: %w", group, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteIdentity deletes an identity.
+func (c *ClusterTx) DeleteIdentity(ctx context.Context, authenticationMethod string, identifier string) error {
+	id, err := c.getIdentityID(ctx, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.DeleteObject(c.tx, "identities", id)
+	if err != nil {
+		return fmt.Errorf("Failed deleting identity: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuthPermissions returns the permissions granted to an identity through the groups it is a member of, either
+// directly or through the given OIDC groups. It also returns whether the identity is a member of any group.
+func (c *ClusterTx) GetAuthPermissions(ctx context.Context, authenticationMethod string, identifier string, oidcGroups []string) ([]api.AuthPermission, bool, error) {
+	groupIDs := []int64{}
+
+	q := `
+SELECT identities_auth_groups.auth_group_id
+  FROM identities_auth_groups
+  JOIN identities ON identities.id=identities_auth_groups.identity_id
+ WHERE identities.authentication_method=? AND identities.identifier=?
+`
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+
+		err := scan(&id)
+		if err != nil {
+			return err
+		}
+
+		groupIDs = append(groupIDs, id)
+
+		return nil
+	}, authenticationMethod, identifier)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed loading identity groups: %w", err)
+	}
+
+	if len(oidcGroups) > 0 {
+		args := make([]any, 0, len(oidcGroups))
+		for _, name := range oidcGroups {
+			args = append(args, name)
+		}
+
+		err = query.Scan(ctx, c.tx, "SELECT auth_group_id FROM auth_groups_oidc_groups WHERE name IN "+query.Params(len(args)), func(scan func(dest ...any) error) error {
+			var id int64
+
+			err := scan(&id)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(groupIDs, id) {
+				groupIDs = append(groupIDs, id)
+			}
+
+			return nil
+		}, args...)
+		if err != nil {
+			return nil, false, fmt.Errorf("Failed loading OIDC groups: %w", err)
+		}
+	}
+
+	if len(groupIDs) == 0 {
+		return nil, false, nil
+	}
+
+	args := make([]any, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		args = append(args, id)
+	}
+
+	permissions := []api.AuthPermission{}
+	err = query.Scan(ctx, c.tx, "SELECT DISTINCT entitlement, object FROM auth_groups_permissions WHERE auth_group_id IN "+query.Params(len(args)), func(scan func(dest ...any) error) error {
+		var permission api.AuthPermission
+
+		err := scan(&permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed loading permissions: %w", err)
+	}
+
+	return permissions, true, nil
+}
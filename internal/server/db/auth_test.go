@@ -0,0 +1,135 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestAuthGroups(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := tx.CreateAuthGroup(ctx, api.AuthGroupsPost{
+		Name: "operators",
+		AuthGroupPut: api.AuthGroupPut{
+			Description: "Operators",
+			Permissions: []api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}},
+			OIDCGroups:  []string{"incus-operators"},
+		},
+	})
+	require.NoError(t, err)
+
+	err = tx.CreateAuthGroup(ctx, api.AuthGroupsPost{Name: "operators"})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	err = tx.CreateIdentity(ctx, api.IdentitiesPost{
+		AuthenticationMethod: api.AuthenticationMethodOIDC,
+		Identifier:           "jane@example.com",
+		IdentityPut:          api.IdentityPut{Name: "Jane", Groups: []string{"operators"}},
+	})
+	require.NoError(t, err)
+
+	// Identities can't be added to missing groups.
+	err = tx.CreateIdentity(ctx, api.IdentitiesPost{
+		AuthenticationMethod: api.AuthenticationMethodOIDC,
+		Identifier:           "joe@example.com",
+		IdentityPut:          api.IdentityPut{Groups: []string{"missing"}},
+	})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	group, err := tx.GetAuthGroup(ctx, "operators")
+	require.NoError(t, err)
+	assert.Equal(t, "Operators", group.Description)
+	assert.Equal(t, []api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}}, group.Permissions)
+	assert.Equal(t, []string{"incus-operators"}, group.OIDCGroups)
+	assert.Equal(t, []string{"/1.0/auth/identities/oidc/jane@example.com"}, group.Identities)
+
+	// Updating replaces the permissions and OIDC groups.
+	err = tx.UpdateAuthGroup(ctx, "operators", api.AuthGroupPut{
+		Permissions: []api.AuthPermission{{Entitlement: "can_view", Object: "server:incus"}},
+	})
+	require.NoError(t, err)
+
+	group, err = tx.GetAuthGroup(ctx, "operators")
+	require.NoError(t, err)
+	assert.Equal(t, []api.AuthPermission{{Entitlement: "can_view", Object: "server:incus"}}, group.Permissions)
+	assert.Empty(t, group.OIDCGroups)
+
+	err = tx.RenameAuthGroup(ctx, "operators", "viewers")
+	require.NoError(t, err)
+
+	identity, err := tx.GetIdentity(ctx, api.AuthenticationMethodOIDC, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viewers"}, identity.Groups)
+
+	err = tx.DeleteAuthGroup(ctx, "viewers")
+	require.NoError(t, err)
+
+	identity, err = tx.GetIdentity(ctx, api.AuthenticationMethodOIDC, "jane@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, identity.Groups)
+
+	err = tx.DeleteIdentity(ctx, api.AuthenticationMethodOIDC, "jane@example.com")
+	require.NoError(t, err)
+
+	_, err = tx.GetIdentity(ctx, api.AuthenticationMethodOIDC, "jane@example.com")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+}
+
+func TestGetAuthPermissions(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := tx.CreateAuthGroup(ctx, api.AuthGroupsPost{
+		Name:         "admins",
+		AuthGroupPut: api.AuthGroupPut{Permissions: []api.AuthPermission{{Entitlement: "can_edit", Object: "server:incus"}}},
+	})
+	require.NoError(t, err)
+
+	err = tx.CreateAuthGroup(ctx, api.AuthGroupsPost{
+		Name: "operators",
+		AuthGroupPut: api.AuthGroupPut{
+			Permissions: []api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}},
+			OIDCGroups:  []string{"incus-operators"},
+		},
+	})
+	require.NoError(t, err)
+
+	err = tx.CreateIdentity(ctx, api.IdentitiesPost{
+		AuthenticationMethod: api.AuthenticationMethodTLS,
+		Identifier:           "abcdef",
+		IdentityPut:          api.IdentityPut{Groups: []string{"admins"}},
+	})
+	require.NoError(t, err)
+
+	// Direct membership.
+	permissions, found, err := tx.GetAuthPermissions(ctx, api.AuthenticationMethodTLS, "abcdef", nil)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []api.AuthPermission{{Entitlement: "can_edit", Object: "server:incus"}}, permissions)
+
+	// Membership through OIDC groups.
+	permissions, found, err = tx.GetAuthPermissions(ctx, api.AuthenticationMethodOIDC, "jane@example.com", []string{"other", "incus-operators"})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []api.AuthPermission{{Entitlement: "can_exec", Object: "project:default"}}, permissions)
+
+	// Unknown identity.
+	permissions, found, err = tx.GetAuthPermissions(ctx, api.AuthenticationMethodOIDC, "joe@example.com", []string{"other"})
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, permissions)
+}
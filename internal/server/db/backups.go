@@ -24,6 +24,8 @@ type InstanceBackup struct {
 	InstanceOnly         bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Target               string
+	Scheduled            bool
 }
 
 // StoragePoolVolumeBackup is a value object holding all db-related details about a storage volume backup.
@@ -36,6 +38,8 @@ type StoragePoolVolumeBackup struct {
 	VolumeOnly           bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Scheduled            bool
+	Target               string
 }
 
 // StoragePoolBucketBackup is a value object holding all db-related details about a storage bucket backup.
@@ -73,7 +77,8 @@ func (c *ClusterTx) GetInstanceBackup(ctx context.Context, projectName string, n
 	q := `
 SELECT instances_backups.id, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.target, instances_backups.scheduled
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -83,6 +88,7 @@ SELECT instances_backups.id, instances_backups.instance_id,
 	arg2 := []any{
 		&args.ID, &args.InstanceID, &args.CreationDate,
 		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt,
+		&args.Target, &args.Scheduled,
 	}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, arg2)
@@ -115,7 +121,8 @@ func (c *ClusterTx) GetInstanceBackupWithID(ctx context.Context, backupID int) (
 	q := `
 SELECT instances_backups.name, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.target, instances_backups.scheduled
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -125,6 +132,7 @@ SELECT instances_backups.name, instances_backups.instance_id,
 	arg2 := []any{
 		&args.Name, &args.InstanceID, &args.CreationDate,
 		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt,
+		&args.Target, &args.Scheduled,
 	}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, arg2)
@@ -188,7 +196,7 @@ func (c *ClusterTx) CreateInstanceBackup(ctx context.Context, args InstanceBacku
 		optimizedStorageInt = 1
 	}
 
-	str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage) VALUES (?, ?, ?, ?, ?, ?)"
+	str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage, target, scheduled) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
 	stmt, err := c.tx.Prepare(str)
 	if err != nil {
 		return err
@@ -197,7 +205,7 @@ func (c *ClusterTx) CreateInstanceBackup(ctx context.Context, args InstanceBacku
 	defer func() { _ = stmt.Close() }()
 	result, err := stmt.Exec(args.InstanceID, args.Name,
 		args.CreationDate.Unix(), args.ExpiryDate.Unix(), instanceOnlyInt,
-		optimizedStorageInt)
+		optimizedStorageInt, args.Target, args.Scheduled)
 	if err != nil {
 		return err
 	}
@@ -299,13 +307,13 @@ func (c *ClusterTx) GetExpiredInstanceBackups(ctx context.Context) ([]InstanceBa
 func (c *ClusterTx) GetExpiredStorageVolumeBackups(ctx context.Context) ([]StoragePoolVolumeBackup, error) {
 	var backups []StoragePoolVolumeBackup
 
-	q := `SELECT storage_volumes_backups.name, storage_volumes_backups.expiry_date, storage_volumes_backups.storage_volume_id FROM storage_volumes_backups`
+	q := `SELECT storage_volumes_backups.id, storage_volumes_backups.name, storage_volumes_backups.expiry_date, storage_volumes_backups.storage_volume_id, storage_volumes_backups.target FROM storage_volumes_backups`
 
 	err := query.Scan(ctx, c.Tx(), q, func(scan func(dest ...any) error) error {
 		var b StoragePoolVolumeBackup
 		var expiryTime sql.NullTime
 
-		err := scan(&b.Name, &expiryTime, &b.VolumeID)
+		err := scan(&b.ID, &b.Name, &expiryTime, &b.VolumeID, &b.Target)
 		if err != nil {
 			return err
 		}
@@ -343,7 +351,9 @@ func (c *ClusterTx) GetStoragePoolVolumeBackups(ctx context.Context, projectName
 		backups.creation_date,
 		backups.expiry_date,
 		backups.volume_only,
-		backups.optimized_storage
+		backups.optimized_storage,
+		backups.scheduled,
+		backups.target
 	FROM storage_volumes_backups AS backups
 	JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 	JOIN projects ON projects.id=storage_volumes.project_id
@@ -357,7 +367,7 @@ func (c *ClusterTx) GetStoragePoolVolumeBackups(ctx context.Context, projectName
 		var b StoragePoolVolumeBackup
 		var expiryTime sql.NullTime
 
-		err := scan(&b.ID, &b.VolumeID, &b.Name, &b.CreationDate, &expiryTime, &b.VolumeOnly, &b.OptimizedStorage)
+		err := scan(&b.ID, &b.VolumeID, &b.Name, &b.CreationDate, &expiryTime, &b.VolumeOnly, &b.OptimizedStorage, &b.Scheduled, &b.Target)
 		if err != nil {
 			return err
 		}
@@ -416,7 +426,7 @@ func (c *ClusterTx) CreateStoragePoolVolumeBackup(ctx context.Context, args Stor
 		optimizedStorageInt = 1
 	}
 
-	str := "INSERT INTO storage_volumes_backups (storage_volume_id, name, creation_date, expiry_date, volume_only, optimized_storage) VALUES (?, ?, ?, ?, ?, ?)"
+	str := "INSERT INTO storage_volumes_backups (storage_volume_id, name, creation_date, expiry_date, volume_only, optimized_storage, scheduled, target) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
 	stmt, err := c.tx.Prepare(str)
 	if err != nil {
 		return err
@@ -425,7 +435,7 @@ func (c *ClusterTx) CreateStoragePoolVolumeBackup(ctx context.Context, args Stor
 	defer func() { _ = stmt.Close() }()
 	result, err := stmt.Exec(args.VolumeID, args.Name,
 		args.CreationDate.Unix(), args.ExpiryDate.Unix(), volumeOnlyInt,
-		optimizedStorageInt)
+		optimizedStorageInt, args.Scheduled, args.Target)
 	if err != nil {
 		return err
 	}
@@ -479,14 +489,16 @@ SELECT
 	backups.creation_date,
 	backups.expiry_date,
 	backups.volume_only,
-	backups.optimized_storage
+	backups.optimized_storage,
+	backups.scheduled,
+	backups.target
 FROM storage_volumes_backups AS backups
 JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 JOIN projects ON projects.id=storage_volumes.project_id
 WHERE projects.name=? AND backups.name=?
 `
 	arg1 := []any{projectName, backupName}
-	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage}
+	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage, &args.Scheduled, &args.Target}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, outfmt)
 	if err != nil {
@@ -511,14 +523,16 @@ SELECT
 	backups.creation_date,
 	backups.expiry_date,
 	backups.volume_only,
-	backups.optimized_storage
+	backups.optimized_storage,
+	backups.scheduled,
+	backups.target
 FROM storage_volumes_backups AS backups
 JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 JOIN projects ON projects.id=storage_volumes.project_id
 WHERE backups.id=?
 `
 	arg1 := []any{backupID}
-	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage}
+	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage, &args.Scheduled, &args.Target}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, outfmt)
 	if err != nil {
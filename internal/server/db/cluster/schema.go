@@ -6,6 +6,44 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE "audit_log" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    date DATETIME NOT NULL,
+    location TEXT NOT NULL,
+    username TEXT NOT NULL,
+    protocol TEXT NOT NULL,
+    source_address TEXT NOT NULL,
+    method TEXT NOT NULL,
+    url TEXT NOT NULL,
+    entity TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    operation_id TEXT NOT NULL,
+    operation_status TEXT NOT NULL DEFAULT ""
+);
+CREATE INDEX audit_log_date_idx ON audit_log (date);
+CREATE INDEX audit_log_operation_id_idx ON audit_log (operation_id);
+CREATE TABLE "auth_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE "auth_groups_oidc_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+CREATE TABLE "auth_groups_permissions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -43,6 +81,20 @@ CREATE TABLE config (
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE "identities" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (authentication_method, identifier)
+);
+CREATE TABLE "identities_auth_groups" (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (identity_id, auth_group_id),
+    FOREIGN KEY (identity_id) REFERENCES "identities" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
 CREATE TABLE "images" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -103,6 +155,7 @@ CREATE TABLE "images_source" (
     protocol INTEGER NOT NULL,
     certificate TEXT NOT NULL,
     alias TEXT NOT NULL,
+    trusted_keys TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
 );
 CREATE TABLE "instances" (
@@ -130,6 +183,8 @@ CREATE TABLE "instances_backups" (
     expiry_date DATETIME,
     container_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    target TEXT NOT NULL DEFAULT "",
+    scheduled INTEGER NOT NULL DEFAULT 0,
     FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
     UNIQUE (instance_id, name)
 );
@@ -210,6 +265,23 @@ CREATE TABLE "instances_snapshots_devices_config" (
     FOREIGN KEY (instance_snapshot_device_id) REFERENCES "instances_snapshots_devices" (id) ON DELETE CASCADE,
     UNIQUE (instance_snapshot_device_id, key)
 );
+CREATE TABLE "instances_usage" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    instance_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    instance_type INTEGER NOT NULL DEFAULT 0,
+    period DATETIME NOT NULL,
+    cpu_seconds REAL NOT NULL DEFAULT 0,
+    memory_byte_seconds REAL NOT NULL DEFAULT 0,
+    disk_read_bytes INTEGER NOT NULL DEFAULT 0,
+    disk_written_bytes INTEGER NOT NULL DEFAULT 0,
+    network_received_bytes INTEGER NOT NULL DEFAULT 0,
+    network_sent_bytes INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (instance_id, period),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE INDEX instances_usage_project_id_period_idx ON instances_usage (project_id, period);
 CREATE TABLE "networks" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     project_id INTEGER NOT NULL,
@@ -369,6 +441,25 @@ CREATE TABLE "networks_zones_config" (
     UNIQUE (network_zone_id, key),
     FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    algorithm INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_zones_pushed_rrsets" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    UNIQUE (network_zone_id, name, type),
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_zones_records" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_zone_id INTEGER NOT NULL,
@@ -437,6 +528,22 @@ CREATE TABLE "operations" (
     FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
+CREATE TABLE "operations_checkpoints" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    node_id INTEGER NOT NULL,
+    project_id INTEGER,
+    type INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    resources TEXT NOT NULL,
+    metadata TEXT NOT NULL,
+    requestor TEXT NOT NULL,
+    checkpoint TEXT NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
 CREATE TABLE "profiles" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -609,6 +716,8 @@ CREATE TABLE "storage_volumes_backups" (
     expiry_date DATETIME,
     volume_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    scheduled INTEGER NOT NULL DEFAULT 0,
+    target TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (storage_volume_id) REFERENCES "storage_volumes" (id) ON DELETE CASCADE,
     UNIQUE (storage_volume_id, name)
 );
@@ -674,5 +783,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (76, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (87, strftime("%s"))
 `
@@ -113,6 +113,265 @@ var updates = map[int]schema.Update{
 	74: updateFromV73,
 	75: updateFromV74,
 	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+	84: updateFromV83,
+	85: updateFromV84,
+	86: updateFromV85,
+	87: updateFromV86,
+}
+
+// updateFromV85 adds a table recording the record sets of network zones sent to their primary DNS server.
+func updateFromV85(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_zones_pushed_rrsets" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    UNIQUE (network_zone_id, name, type),
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating network zone pushed record sets table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV86 adds a column recording the final status of the operation started by the audited requests.
+func updateFromV86(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE audit_log ADD COLUMN operation_status TEXT NOT NULL DEFAULT "";
+CREATE INDEX audit_log_operation_id_idx ON audit_log (operation_id);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding operation status column to audit log: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV84 adds a target column to storage volume backups.
+func updateFromV84(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE storage_volumes_backups ADD COLUMN target TEXT NOT NULL DEFAULT "";
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding target column to storage volume backups: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV83 adds a column recording whether instance and volume backups were created by the backup scheduler.
+func updateFromV83(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE instances_backups ADD COLUMN scheduled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE storage_volumes_backups ADD COLUMN scheduled INTEGER NOT NULL DEFAULT 0;
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding scheduled column to backups: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV82 adds a table holding the state of durable operations so they can be restored after a restart.
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "operations_checkpoints" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    node_id INTEGER NOT NULL,
+    project_id INTEGER,
+    type INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    resources TEXT NOT NULL,
+    metadata TEXT NOT NULL,
+    requestor TEXT NOT NULL,
+    checkpoint TEXT NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating operation checkpoints table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV81 adds a table holding the audit log of API mutations.
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "audit_log" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    date DATETIME NOT NULL,
+    location TEXT NOT NULL,
+    username TEXT NOT NULL,
+    protocol TEXT NOT NULL,
+    source_address TEXT NOT NULL,
+    method TEXT NOT NULL,
+    url TEXT NOT NULL,
+    entity TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    operation_id TEXT NOT NULL
+);
+
+CREATE INDEX audit_log_date_idx ON audit_log (date);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating audit log table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV80 adds the tables holding the identities, groups and permissions of the built-in authorization driver.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "auth_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+
+CREATE TABLE "auth_groups_permissions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+
+CREATE TABLE "auth_groups_oidc_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+
+CREATE TABLE "identities" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (authentication_method, identifier)
+);
+
+CREATE TABLE "identities_auth_groups" (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (identity_id, auth_group_id),
+    FOREIGN KEY (identity_id) REFERENCES "identities" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES "auth_groups" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating built-in authorization tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds a column holding the keys trusted to sign the index of image sources.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE images_source ADD COLUMN trusted_keys TEXT NOT NULL DEFAULT "";
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding trusted keys column to image sources: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds a table holding the DNSSEC keys of network zones.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    algorithm INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating network zone keys table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds a table holding the hourly resource usage of instances.
+// Usage is kept by instance ID and outlives the instance so that it can still be reported on.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "instances_usage" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    instance_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    instance_type INTEGER NOT NULL DEFAULT 0,
+    period DATETIME NOT NULL,
+    cpu_seconds REAL NOT NULL DEFAULT 0,
+    memory_byte_seconds REAL NOT NULL DEFAULT 0,
+    disk_read_bytes INTEGER NOT NULL DEFAULT 0,
+    disk_written_bytes INTEGER NOT NULL DEFAULT 0,
+    network_received_bytes INTEGER NOT NULL DEFAULT 0,
+    network_sent_bytes INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (instance_id, period),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+
+CREATE INDEX instances_usage_project_id_period_idx ON instances_usage (project_id, period);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating instances usage table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds a target column to instance backups.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE instances_backups ADD COLUMN target TEXT NOT NULL DEFAULT "";
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding target column to instance backups: %w", err)
+	}
+
+	return nil
 }
 
 func updateFromV75(ctx context.Context, tx *sql.Tx) error {
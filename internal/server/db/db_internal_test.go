@@ -279,7 +279,7 @@ func (s *dbTestSuite) Test_GetCachedImageSourceFingerprint() {
 		imageID, _, err := tx.GetImage(ctx, "fingerprint", cluster.ImageFilter{Project: &project})
 		s.Nil(err)
 
-		err = tx.CreateImageSource(ctx, imageID, "server.remote", "simplestreams", "", "test")
+		err = tx.CreateImageSource(ctx, imageID, "server.remote", "simplestreams", "", "test", "")
 		s.Nil(err)
 
 		fingerprint, err := tx.GetCachedImageSourceFingerprint(ctx, "server.remote", "simplestreams", "test", "container", 0)
@@ -296,7 +296,7 @@ func (s *dbTestSuite) Test_GetCachedImageSourceFingerprint_no_match() {
 		imageID, _, err := tx.GetImage(ctx, "fingerprint", cluster.ImageFilter{Project: &project})
 		s.Nil(err)
 
-		err = tx.CreateImageSource(ctx, imageID, "server.remote", "simplestreams", "", "test")
+		err = tx.CreateImageSource(ctx, imageID, "server.remote", "simplestreams", "", "test", "")
 		s.Nil(err)
 
 		_, err = tx.GetCachedImageSourceFingerprint(ctx, "server.remote", "incus", "test", "container", 0)
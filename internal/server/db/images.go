@@ -39,20 +39,21 @@ SELECT images.fingerprint
 
 // GetImageSource returns the image source with the given ID.
 func (c *ClusterTx) GetImageSource(ctx context.Context, imageID int) (int, api.ImageSource, error) {
-	q := `SELECT id, server, protocol, certificate, alias FROM images_source WHERE image_id=?`
+	q := `SELECT id, server, protocol, certificate, alias, trusted_keys FROM images_source WHERE image_id=?`
 	type imagesSource struct {
 		ID          int
 		Server      string
 		Protocol    int
 		Certificate string
 		Alias       string
+		TrustedKeys string
 	}
 
 	sources := []imagesSource{}
 	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
 		s := imagesSource{}
 
-		err := scan(&s.ID, &s.Server, &s.Protocol, &s.Certificate, &s.Alias)
+		err := scan(&s.ID, &s.Server, &s.Protocol, &s.Certificate, &s.Alias, &s.TrustedKeys)
 		if err != nil {
 			return err
 		}
@@ -81,6 +82,7 @@ func (c *ClusterTx) GetImageSource(ctx context.Context, imageID int) (int, api.I
 		Protocol:    protocol,
 		Certificate: source.Certificate,
 		Alias:       source.Alias,
+		TrustedKeys: source.TrustedKeys,
 	}
 
 	return source.ID, result, nil
@@ -211,7 +213,7 @@ SELECT fingerprint
 }
 
 // CreateImageSource inserts a new image source.
-func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string, protocol string, certificate string, alias string) error {
+func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string, protocol string, certificate string, alias string, trustedKeys string) error {
 	protocolInt := -1
 	for protoInt, protoString := range ImageSourceProtocol {
 		if protoString == protocol {
@@ -229,12 +231,14 @@ func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string
 		"protocol",
 		"certificate",
 		"alias",
+		"trusted_keys",
 	}, []any{
 		id,
 		server,
 		protocolInt,
 		certificate,
 		alias,
+		trustedKeys,
 	})
 
 	return err
@@ -0,0 +1,127 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+)
+
+// InstanceUsagePeriod is the granularity at which instance resource usage is stored.
+const InstanceUsagePeriod = time.Hour
+
+// InstanceUsage is a value object holding the resource usage of an instance.
+type InstanceUsage struct {
+	Project              string
+	InstanceID           int
+	Instance             string
+	Type                 instancetype.Type
+	CPUSeconds           float64
+	MemoryByteSeconds    float64
+	DiskReadBytes        int64
+	DiskWrittenBytes     int64
+	NetworkReceivedBytes int64
+	NetworkSentBytes     int64
+}
+
+// AddInstanceUsage adds the given resource usage to the totals of the instances for the period containing the given time.
+// Usage is recorded against the instance ID so that it follows the instance when renamed.
+func (c *ClusterTx) AddInstanceUsage(ctx context.Context, timestamp time.Time, usages []InstanceUsage) error {
+	period := timestamp.UTC().Truncate(InstanceUsagePeriod)
+	projectIDs := map[string]int64{}
+
+	for _, usage := range usages {
+		projectID, ok := projectIDs[usage.Project]
+		if !ok {
+			var err error
+
+			projectID, err = cluster.GetProjectID(ctx, c.tx, usage.Project)
+			if err != nil {
+				return fmt.Errorf("Failed getting project ID of %q: %w", usage.Project, err)
+			}
+
+			projectIDs[usage.Project] = projectID
+		}
+
+		q := `
+UPDATE instances_usage
+   SET instance_name=?,
+       instance_type=?,
+       cpu_seconds=cpu_seconds+?,
+       memory_byte_seconds=memory_byte_seconds+?,
+       disk_read_bytes=disk_read_bytes+?,
+       disk_written_bytes=disk_written_bytes+?,
+       network_received_bytes=network_received_bytes+?,
+       network_sent_bytes=network_sent_bytes+?
+ WHERE instance_id=? AND period=?
+`
+		result, err := c.tx.ExecContext(ctx, q, usage.Instance, usage.Type, usage.CPUSeconds, usage.MemoryByteSeconds, usage.DiskReadBytes, usage.DiskWrittenBytes, usage.NetworkReceivedBytes, usage.NetworkSentBytes, usage.InstanceID, period)
+		if err != nil {
+			return fmt.Errorf("Failed updating usage of instance %q in project %q: %w", usage.Instance, usage.Project, err)
+		}
+
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n > 0 {
+			continue
+		}
+
+		q = `
+INSERT INTO instances_usage (project_id, instance_id, instance_name, instance_type, period, cpu_seconds, memory_byte_seconds, disk_read_bytes, disk_written_bytes, network_received_bytes, network_sent_bytes)
+     VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+		_, err = c.tx.ExecContext(ctx, q, projectID, usage.InstanceID, usage.Instance, usage.Type, period, usage.CPUSeconds, usage.MemoryByteSeconds, usage.DiskReadBytes, usage.DiskWrittenBytes, usage.NetworkReceivedBytes, usage.NetworkSentBytes)
+		if err != nil {
+			return fmt.Errorf("Failed adding usage of instance %q in project %q: %w", usage.Instance, usage.Project, err)
+		}
+	}
+
+	return nil
+}
+
+// GetProjectInstancesUsage returns the resource usage of each instance of a project between since and until.
+// Usage is stored per period, so only the periods starting within the range are accounted for.
+// Instances are reported under their current name, or their last known name once deleted.
+func (c *ClusterTx) GetProjectInstancesUsage(ctx context.Context, projectName string, since time.Time, until time.Time) ([]InstanceUsage, error) {
+	q := `
+SELECT instances_usage.instance_id,
+       COALESCE(MAX(instances.name), (SELECT last.instance_name FROM instances_usage AS last WHERE last.instance_id=instances_usage.instance_id ORDER BY last.period DESC LIMIT 1)),
+       MAX(instances_usage.instance_type),
+       SUM(instances_usage.cpu_seconds), SUM(instances_usage.memory_byte_seconds),
+       SUM(instances_usage.disk_read_bytes), SUM(instances_usage.disk_written_bytes),
+       SUM(instances_usage.network_received_bytes), SUM(instances_usage.network_sent_bytes)
+  FROM instances_usage
+  JOIN projects ON projects.id=instances_usage.project_id
+  LEFT JOIN instances ON instances.id=instances_usage.instance_id
+ WHERE projects.name=? AND instances_usage.period>=? AND instances_usage.period<?
+ GROUP BY instances_usage.instance_id
+ ORDER BY 2, instances_usage.instance_id
+`
+	usages := []InstanceUsage{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		usage := InstanceUsage{Project: projectName}
+
+		err := scan(&usage.InstanceID, &usage.Instance, &usage.Type, &usage.CPUSeconds, &usage.MemoryByteSeconds, &usage.DiskReadBytes, &usage.DiskWrittenBytes, &usage.NetworkReceivedBytes, &usage.NetworkSentBytes)
+		if err != nil {
+			return err
+		}
+
+		usages = append(usages, usage)
+
+		return nil
+	}, projectName, since.UTC(), until.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting usage of project %q: %w", projectName, err)
+	}
+
+	return usages, nil
+}
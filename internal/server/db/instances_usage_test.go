@@ -0,0 +1,119 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+)
+
+func TestInstanceUsage(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Two samples within the same period are summed.
+	err := tx.AddInstanceUsage(ctx, start.Add(5*time.Minute), []db.InstanceUsage{
+		{Project: "default", InstanceID: 1, Instance: "c1", Type: instancetype.Container, CPUSeconds: 1.5, DiskReadBytes: 100},
+		{Project: "default", InstanceID: 2, Instance: "v1", Type: instancetype.VM, NetworkSentBytes: 10},
+	})
+	require.NoError(t, err)
+
+	err = tx.AddInstanceUsage(ctx, start.Add(10*time.Minute), []db.InstanceUsage{
+		{Project: "default", InstanceID: 1, Instance: "c1", Type: instancetype.Container, CPUSeconds: 2, MemoryByteSeconds: 300},
+	})
+	require.NoError(t, err)
+
+	// A sample in the next period is stored separately.
+	err = tx.AddInstanceUsage(ctx, start.Add(70*time.Minute), []db.InstanceUsage{
+		{Project: "default", InstanceID: 1, Instance: "c1", Type: instancetype.Container, CPUSeconds: 4},
+	})
+	require.NoError(t, err)
+
+	usages, err := tx.GetProjectInstancesUsage(ctx, "default", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+
+	assert.Equal(t, db.InstanceUsage{Project: "default", InstanceID: 1, Instance: "c1", Type: instancetype.Container, CPUSeconds: 3.5, MemoryByteSeconds: 300, DiskReadBytes: 100}, usages[0])
+	assert.Equal(t, db.InstanceUsage{Project: "default", InstanceID: 2, Instance: "v1", Type: instancetype.VM, NetworkSentBytes: 10}, usages[1])
+
+	usages, err = tx.GetProjectInstancesUsage(ctx, "default", start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+	assert.Equal(t, 7.5, usages[0].CPUSeconds)
+
+	// Periods outside of the range are left out.
+	usages, err = tx.GetProjectInstancesUsage(ctx, "default", start.Add(2*time.Hour), start.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+
+	// Other projects don't see the usage.
+	usages, err = tx.GetProjectInstancesUsage(ctx, "missing", start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+
+	// Unknown projects fail when adding usage.
+	err = tx.AddInstanceUsage(ctx, start, []db.InstanceUsage{{Project: "missing", InstanceID: 3, Instance: "c1"}})
+	require.Error(t, err)
+}
+
+func TestInstanceUsage_InstanceID(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	addContainer(t, tx, 1, "c1")
+	id := int(getContainerID(t, tx, "c1"))
+
+	err := tx.AddInstanceUsage(ctx, start, []db.InstanceUsage{
+		{Project: "default", InstanceID: id, Instance: "c1", Type: instancetype.Container, CPUSeconds: 1},
+	})
+	require.NoError(t, err)
+
+	// Usage follows the instance when renamed, including the usage recorded before.
+	_, err = tx.Tx().Exec("UPDATE instances SET name='c2' WHERE id=?", id)
+	require.NoError(t, err)
+
+	err = tx.AddInstanceUsage(ctx, start.Add(time.Minute), []db.InstanceUsage{
+		{Project: "default", InstanceID: id, Instance: "c2", Type: instancetype.Container, CPUSeconds: 2},
+	})
+	require.NoError(t, err)
+
+	usages, err := tx.GetProjectInstancesUsage(ctx, "default", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, "c2", usages[0].Instance)
+	assert.Equal(t, 3.0, usages[0].CPUSeconds)
+
+	// A new instance reusing the old name is accounted for separately.
+	addContainer(t, tx, 1, "c1")
+	newID := int(getContainerID(t, tx, "c1"))
+	require.NotEqual(t, id, newID)
+
+	err = tx.AddInstanceUsage(ctx, start.Add(2*time.Minute), []db.InstanceUsage{
+		{Project: "default", InstanceID: newID, Instance: "c1", Type: instancetype.Container, CPUSeconds: 5},
+	})
+	require.NoError(t, err)
+
+	// Usage of deleted instances is kept under their last known name.
+	_, err = tx.Tx().Exec("DELETE FROM instances WHERE id=?", id)
+	require.NoError(t, err)
+
+	usages, err = tx.GetProjectInstancesUsage(ctx, "default", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+
+	assert.Equal(t, db.InstanceUsage{Project: "default", InstanceID: newID, Instance: "c1", Type: instancetype.Container, CPUSeconds: 5}, usages[0])
+	assert.Equal(t, db.InstanceUsage{Project: "default", InstanceID: id, Instance: "c2", Type: instancetype.Container, CPUSeconds: 3}, usages[1])
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/db/query"
 	"github.com/lxc/incus/v6/internal/version"
@@ -486,6 +487,135 @@ func (c *ClusterTx) DeleteNetworkZoneRecord(ctx context.Context, id int64) error
 	return err
 }
 
+// NetworkZoneKey is a value object holding a DNSSEC key of a network zone.
+type NetworkZoneKey struct {
+	ID         int64
+	Flags      uint16
+	Algorithm  uint8
+	PublicKey  string
+	PrivateKey string
+	CreatedAt  time.Time
+}
+
+// GetNetworkZoneKeysByZone returns the DNSSEC keys of the network zone, oldest first.
+func (c *ClusterTx) GetNetworkZoneKeysByZone(ctx context.Context, zone int64) ([]NetworkZoneKey, error) {
+	q := `SELECT id, flags, algorithm, public_key, private_key, created_at FROM networks_zones_keys
+		WHERE network_zone_id=?
+		ORDER BY created_at, id
+	`
+
+	keys := []NetworkZoneKey{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		key := NetworkZoneKey{}
+
+		err := scan(&key.ID, &key.Flags, &key.Algorithm, &key.PublicKey, &key.PrivateKey, &key.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+
+		return nil
+	}, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateNetworkZoneKey adds a DNSSEC key to the network zone.
+func (c *ClusterTx) CreateNetworkZoneKey(ctx context.Context, zone int64, key NetworkZoneKey) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, `
+		INSERT INTO networks_zones_keys (network_zone_id, flags, algorithm, public_key, private_key, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, zone, key.Flags, key.Algorithm, key.PublicKey, key.PrivateKey, key.CreatedAt)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// DeleteNetworkZoneKey deletes a DNSSEC key of a network zone.
+func (c *ClusterTx) DeleteNetworkZoneKey(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_keys WHERE id=?", id)
+
+	return err
+}
+
+// DeleteNetworkZoneKeysByZone deletes all the DNSSEC keys of the network zone.
+func (c *ClusterTx) DeleteNetworkZoneKeysByZone(ctx context.Context, zone int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_keys WHERE network_zone_id=?", zone)
+
+	return err
+}
+
+// NetworkZonePushedRRSet is a value object identifying a record set of a network zone sent to its primary DNS server.
+type NetworkZonePushedRRSet struct {
+	Address string
+	Name    string
+	Type    string
+}
+
+// GetNetworkZonePushedRRSets returns the record sets of the network zone sent to its primary DNS server.
+func (c *ClusterTx) GetNetworkZonePushedRRSets(ctx context.Context, zone int64) ([]NetworkZonePushedRRSet, error) {
+	q := `SELECT address, name, type FROM networks_zones_pushed_rrsets
+		WHERE network_zone_id=?
+		ORDER BY id
+	`
+
+	rrsets := []NetworkZonePushedRRSet{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		rrset := NetworkZonePushedRRSet{}
+
+		err := scan(&rrset.Address, &rrset.Name, &rrset.Type)
+		if err != nil {
+			return err
+		}
+
+		rrsets = append(rrsets, rrset)
+
+		return nil
+	}, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return rrsets, nil
+}
+
+// CreateNetworkZonePushedRRSet records a record set of the network zone sent to its primary DNS server.
+func (c *ClusterTx) CreateNetworkZonePushedRRSet(ctx context.Context, zone int64, rrset NetworkZonePushedRRSet) error {
+	_, err := c.tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO networks_zones_pushed_rrsets (network_zone_id, address, name, type)
+		VALUES (?, ?, ?, ?)
+	`, zone, rrset.Address, rrset.Name, rrset.Type)
+
+	return err
+}
+
+// DeleteNetworkZonePushedRRSet forgets a record set of the network zone removed from its primary DNS server.
+func (c *ClusterTx) DeleteNetworkZonePushedRRSet(ctx context.Context, zone int64, name string, rrtype string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_pushed_rrsets WHERE network_zone_id=? AND name=? AND type=?", zone, name, rrtype)
+
+	return err
+}
+
+// DeleteNetworkZonePushedRRSetsByZone forgets all the record sets of the network zone sent to its primary DNS server.
+func (c *ClusterTx) DeleteNetworkZonePushedRRSetsByZone(ctx context.Context, zone int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_pushed_rrsets WHERE network_zone_id=?", zone)
+
+	return err
+}
+
 // GetNetworkZoneURIs returns the URIs for the network ACLs with the given project.
 func (c *ClusterTx) GetNetworkZoneURIs(ctx context.Context, projectID int, project string) ([]string, error) {
 	q := `SELECT networks_zones.name from networks_zones WHERE networks_zones.project_id = ?`
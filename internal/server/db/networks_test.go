@@ -81,6 +81,30 @@ func TestCreatePendingNetwork(t *testing.T) {
 	assert.Equal(t, map[string]string{"bridge.external_interfaces": "egg,if1/eth0/1001"}, configs["none"])
 }
 
+func TestGetNetworkMembersConfig(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	nodeID, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	err = tx.CreatePendingNetwork(context.Background(), "buzz", api.ProjectDefaultName, "network1", "", db.NetworkTypeWireguard, map[string]string{"wireguard.ipv4.address": "10.0.1.1/24"})
+	require.NoError(t, err)
+
+	err = tx.CreatePendingNetwork(context.Background(), "none", api.ProjectDefaultName, "network1", "", db.NetworkTypeWireguard, map[string]string{"wireguard.ipv4.address": "10.0.2.1/24"})
+	require.NoError(t, err)
+
+	networkID, err := tx.GetNetworkID(context.Background(), api.ProjectDefaultName, "network1")
+	require.NoError(t, err)
+
+	configs, err := tx.GetNetworkMembersConfig(context.Background(), networkID)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]map[string]string{
+		1:      {"wireguard.ipv4.address": "10.0.2.1/24"},
+		nodeID: {"wireguard.ipv4.address": "10.0.1.1/24"},
+	}, configs)
+}
+
 // If an entry for the given network and node already exists, an error is
 // returned.
 func TestNetworksCreatePending_AlreadyDefined(t *testing.T) {
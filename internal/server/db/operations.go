@@ -4,6 +4,9 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
@@ -66,3 +69,103 @@ WHERE (projects.name = ? OR operations.project_id IS NULL) and operations.type =
 
 	return ops, nil
 }
+
+// OperationCheckpoint holds the persisted state of a durable operation running on this member.
+// Resources, metadata, requestor and checkpoint are JSON encoded.
+type OperationCheckpoint struct {
+	UUID       string
+	Project    string
+	Type       operationtype.Type
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Resources  string
+	Metadata   string
+	Requestor  string
+	Checkpoint string
+}
+
+// UpsertOperationCheckpoint records the state of a durable operation running on this member, replacing any previous one.
+func (c *ClusterTx) UpsertOperationCheckpoint(ctx context.Context, checkpoint OperationCheckpoint) error {
+	var projectID sql.NullInt64
+	if checkpoint.Project != "" {
+		id, err := cluster.GetProjectID(ctx, c.tx, checkpoint.Project)
+		if err != nil {
+			return fmt.Errorf("Failed getting project ID of %q: %w", checkpoint.Project, err)
+		}
+
+		projectID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	q := `
+UPDATE operations_checkpoints
+   SET node_id=?, project_id=?, type=?, created_at=?, updated_at=?, resources=?, metadata=?, requestor=?, checkpoint=?
+ WHERE uuid=?
+`
+	result, err := c.tx.ExecContext(ctx, q, c.nodeID, projectID, checkpoint.Type, checkpoint.CreatedAt.UTC(), checkpoint.UpdatedAt.UTC(), checkpoint.Resources, checkpoint.Metadata, checkpoint.Requestor, checkpoint.Checkpoint, checkpoint.UUID)
+	if err != nil {
+		return fmt.Errorf("Failed updating checkpoint of operation %q: %w", checkpoint.UUID, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return nil
+	}
+
+	q = `
+INSERT INTO operations_checkpoints (uuid, node_id, project_id, type, created_at, updated_at, resources, metadata, requestor, checkpoint)
+     VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	_, err = c.tx.ExecContext(ctx, q, checkpoint.UUID, c.nodeID, projectID, checkpoint.Type, checkpoint.CreatedAt.UTC(), checkpoint.UpdatedAt.UTC(), checkpoint.Resources, checkpoint.Metadata, checkpoint.Requestor, checkpoint.Checkpoint)
+	if err != nil {
+		return fmt.Errorf("Failed adding checkpoint of operation %q: %w", checkpoint.UUID, err)
+	}
+
+	return nil
+}
+
+// GetOperationCheckpoints returns the state of the durable operations which were running on this member.
+func (c *ClusterTx) GetOperationCheckpoints(ctx context.Context) ([]OperationCheckpoint, error) {
+	q := `
+SELECT operations_checkpoints.uuid, IFNULL(projects.name, ""), operations_checkpoints.type,
+       operations_checkpoints.created_at, operations_checkpoints.updated_at,
+       operations_checkpoints.resources, operations_checkpoints.metadata,
+       operations_checkpoints.requestor, operations_checkpoints.checkpoint
+  FROM operations_checkpoints
+  LEFT JOIN projects ON projects.id=operations_checkpoints.project_id
+ WHERE operations_checkpoints.node_id=?
+ ORDER BY operations_checkpoints.created_at
+`
+	checkpoints := []OperationCheckpoint{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		checkpoint := OperationCheckpoint{}
+
+		err := scan(&checkpoint.UUID, &checkpoint.Project, &checkpoint.Type, &checkpoint.CreatedAt, &checkpoint.UpdatedAt, &checkpoint.Resources, &checkpoint.Metadata, &checkpoint.Requestor, &checkpoint.Checkpoint)
+		if err != nil {
+			return err
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+
+		return nil
+	}, c.nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting operation checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// DeleteOperationCheckpoint deletes the recorded state of a durable operation.
+func (c *ClusterTx) DeleteOperationCheckpoint(ctx context.Context, uuid string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM operations_checkpoints WHERE uuid=?", uuid)
+	if err != nil {
+		return fmt.Errorf("Failed deleting checkpoint of operation %q: %w", uuid, err)
+	}
+
+	return nil
+}
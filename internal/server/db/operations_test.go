@@ -5,6 +5,7 @@ package db_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -109,3 +110,63 @@ func TestOperationNoProject(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, len(ops), 0)
 }
+
+// Record, update, list and remove the checkpoints of durable operations.
+func TestOperationCheckpoint(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	checkpoint := db.OperationCheckpoint{
+		UUID:       "abcd",
+		Project:    "default",
+		Type:       operationtype.ImageDownload,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+		Resources:  "{}",
+		Metadata:   "{}",
+		Requestor:  "null",
+		Checkpoint: "null",
+	}
+
+	err := tx.UpsertOperationCheckpoint(ctx, checkpoint)
+	require.NoError(t, err)
+
+	err = tx.UpsertOperationCheckpoint(ctx, db.OperationCheckpoint{UUID: "efgh", Type: operationtype.ClusterMemberEvacuate, CreatedAt: createdAt.Add(time.Minute), UpdatedAt: createdAt.Add(time.Minute)})
+	require.NoError(t, err)
+
+	// Updating replaces the previous state.
+	checkpoint.UpdatedAt = createdAt.Add(time.Hour)
+	checkpoint.Metadata = `{"progress":"50%"}`
+	checkpoint.Checkpoint = `{"source":"images:alpine/edge"}`
+	err = tx.UpsertOperationCheckpoint(ctx, checkpoint)
+	require.NoError(t, err)
+
+	checkpoints, err := tx.GetOperationCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+
+	assert.Equal(t, "abcd", checkpoints[0].UUID)
+	assert.Equal(t, "default", checkpoints[0].Project)
+	assert.Equal(t, operationtype.ImageDownload, checkpoints[0].Type)
+	assert.True(t, checkpoints[0].UpdatedAt.Equal(createdAt.Add(time.Hour)))
+	assert.Equal(t, `{"progress":"50%"}`, checkpoints[0].Metadata)
+	assert.Equal(t, `{"source":"images:alpine/edge"}`, checkpoints[0].Checkpoint)
+
+	assert.Equal(t, "efgh", checkpoints[1].UUID)
+	assert.Equal(t, "", checkpoints[1].Project)
+
+	// Unknown projects are rejected.
+	err = tx.UpsertOperationCheckpoint(ctx, db.OperationCheckpoint{UUID: "ijkl", Project: "missing"})
+	require.Error(t, err)
+
+	err = tx.DeleteOperationCheckpoint(ctx, "abcd")
+	require.NoError(t, err)
+
+	checkpoints, err = tx.GetOperationCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	assert.Equal(t, "efgh", checkpoints[0].UUID)
+}
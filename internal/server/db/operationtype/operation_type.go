@@ -304,3 +304,27 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 
 	return "", ""
 }
+
+// Durable returns whether operations of this type are long-running ones whose state is kept across restarts.
+// Those are resumed when a resume function is registered for their type (only image downloads at the moment)
+// and are otherwise marked as failed when the daemon starts again.
+func (t Type) Durable() bool {
+	switch t {
+	case BackupCreate, BackupRestore:
+		return true
+	case InstanceCreate, InstanceMigrate, InstanceRebuild:
+		return true
+	case ImageDownload:
+		return true
+	case VolumeCopy, VolumeMigrate, VolumeMove:
+		return true
+	case CustomVolumeBackupCreate, CustomVolumeBackupRestore:
+		return true
+	case BucketBackupCreate, BucketBackupRestore:
+		return true
+	case ClusterMemberEvacuate, ClusterMemberRestore:
+		return true
+	}
+
+	return false
+}
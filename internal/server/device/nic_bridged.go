@@ -324,11 +324,11 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 			return fmt.Errorf("Specified network is not fully created")
 		}
 
-		if n.Type() != "bridge" {
-			return fmt.Errorf("Specified network must be of type bridge")
+		if !slices.Contains([]string{"bridge", "wireguard"}, n.Type()) {
+			return fmt.Errorf("Specified network must be of type bridge or wireguard")
 		}
 
-		netConfig := n.Config()
+		netConfig := network.BridgeConfig(n)
 
 		if d.config["ipv4.address"] != "" {
 			dhcpv4Subnet := n.DHCPv4Subnet()
@@ -450,7 +450,7 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		}
 
 		// Apply network settings to NIC.
-		netConfig := d.network.Config()
+		netConfig := network.BridgeConfig(d.network)
 
 		// Link device to network bridge.
 		d.config["parent"] = d.config["network"]
@@ -1417,7 +1417,7 @@ func (d *nicBridged) setFilters() (err error) {
 	var ipv6DNS []string
 
 	if d.network != nil {
-		netConfig := d.network.Config()
+		netConfig := network.BridgeConfig(d.network)
 
 		ipv4DNS = []string{}
 		ipv6DNS = []string{}
@@ -1951,7 +1951,7 @@ func (d *nicBridged) State() (*api.InstanceStateNetwork, error) {
 
 	if d.network != nil {
 		// Extract subnet sizes from bridge addresses if available.
-		netConfig := d.network.Config()
+		netConfig := network.BridgeConfig(d.network)
 		_, v4subnet, _ := net.ParseCIDR(netConfig["ipv4.address"])
 		_, v6subnet, _ := net.ParseCIDR(netConfig["ipv6.address"])
 
@@ -0,0 +1,222 @@
+package dns
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// dnssecKeyTTL is the TTL of the DNSKEY records.
+const dnssecKeyTTL = 3600
+
+// dnssecSignatureValidity is how long the generated signatures are valid for.
+// Cached signatures are renewed halfway through, so this needs to cover twice the SOA expiry.
+const dnssecSignatureValidity = 14 * 24 * time.Hour
+
+// signedZone is a signed zone cached by the server.
+type signedZone struct {
+	fingerprint string
+	signedAt    time.Time
+	records     []dns.RR
+}
+
+// signZone returns the records of a zone signed with its active keys, along with its DNSKEY records and NSEC chain.
+// The signed zone is cached and only signed again when its records or keys change, or when the signatures get
+// halfway to expiry. The SOA serial isn't taken into account as it changes whenever the zone is rendered.
+// The returned records are shared and mustn't be modified. This must be called with the server lock held.
+func (s *Server) signZone(zoneName string, records []dns.RR, keys []Key) ([]dns.RR, error) {
+	fingerprint := zoneFingerprint(records, keys)
+
+	cached, ok := s.signedZones[zoneName]
+	if ok && cached.fingerprint == fingerprint && time.Since(cached.signedAt) < dnssecSignatureValidity/2 {
+		return cached.records, nil
+	}
+
+	signed, err := signRecords(zoneName, records, keys, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.signedZones == nil {
+		s.signedZones = map[string]*signedZone{}
+	}
+
+	s.signedZones[zoneName] = &signedZone{fingerprint: fingerprint, signedAt: time.Now(), records: signed}
+
+	return signed, nil
+}
+
+// zoneFingerprint returns a hash of the records of a zone, ignoring the SOA serial, and of its keys.
+func zoneFingerprint(records []dns.RR, keys []Key) string {
+	hash := sha256.New()
+
+	for _, rr := range records {
+		soa, ok := rr.(*dns.SOA)
+		if ok {
+			entry := *soa
+			entry.Serial = 0
+			rr = &entry
+		}
+
+		_, _ = fmt.Fprintln(hash, rr.String())
+	}
+
+	for _, key := range keys {
+		_, _ = fmt.Fprintln(hash, key.DNSKEY.String(), key.Active)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// signRecords signs the records of a zone with its active keys.
+// When full is true, the DNSKEY records and the NSEC chain are also added to the zone.
+func signRecords(zoneName string, records []dns.RR, keys []Key, full bool) ([]dns.RR, error) {
+	origin := dns.Fqdn(zoneName)
+
+	// Look for the SOA record to get the negative caching TTL.
+	var soa *dns.SOA
+	for _, rr := range records {
+		entry, ok := rr.(*dns.SOA)
+		if ok {
+			soa = entry
+			break
+		}
+	}
+
+	if soa == nil {
+		return nil, fmt.Errorf("Zone %q doesn't have a SOA record", zoneName)
+	}
+
+	signed := slices.Clone(records)
+
+	if full {
+		// Publish all the keys.
+		for _, key := range keys {
+			dnskey := *key.DNSKEY
+			dnskey.Hdr = dns.RR_Header{Name: origin, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: dnssecKeyTTL}
+			signed = append(signed, &dnskey)
+		}
+
+		// Build the NSEC chain.
+		signed = append(signed, nsecChain(signed, min(soa.Hdr.Ttl, soa.Minttl))...)
+	}
+
+	// Group the records in sets.
+	type rrsetKey struct {
+		name   string
+		rrtype uint16
+	}
+
+	rrsetKeys := []rrsetKey{}
+	rrsets := map[rrsetKey][]dns.RR{}
+	for _, rr := range signed {
+		k := rrsetKey{name: dns.CanonicalName(rr.Header().Name), rrtype: rr.Header().Rrtype}
+
+		_, ok := rrsets[k]
+		if !ok {
+			rrsetKeys = append(rrsetKeys, k)
+		}
+
+		rrsets[k] = append(rrsets[k], rr)
+	}
+
+	// Sign each set, using the key signing keys for the DNSKEY set and the zone signing keys for the rest.
+	now := time.Now()
+	for _, k := range rrsetKeys {
+		rrset := rrsets[k]
+
+		for _, key := range keys {
+			if !key.Active {
+				continue
+			}
+
+			isKSK := key.DNSKEY.Flags&dns.SEP != 0
+			if isKSK != (k.rrtype == dns.TypeDNSKEY) {
+				continue
+			}
+
+			sig := &dns.RRSIG{
+				Hdr:        dns.RR_Header{Ttl: rrset[0].Header().Ttl},
+				Algorithm:  key.DNSKEY.Algorithm,
+				KeyTag:     key.DNSKEY.KeyTag(),
+				SignerName: origin,
+				Inception:  uint32(now.Add(-time.Hour).Unix()),
+				Expiration: uint32(now.Add(dnssecSignatureValidity).Unix()),
+			}
+
+			err := sig.Sign(key.Signer, rrset)
+			if err != nil {
+				return nil, fmt.Errorf("Failed signing %q records of %q: %w", dns.TypeToString[k.rrtype], k.name, err)
+			}
+
+			signed = append(signed, sig)
+		}
+	}
+
+	return signed, nil
+}
+
+// nsecChain returns the NSEC records linking all the names of the zone.
+func nsecChain(records []dns.RR, ttl uint32) []dns.RR {
+	names := []string{}
+	types := map[string][]uint16{}
+
+	for _, rr := range records {
+		name := dns.CanonicalName(rr.Header().Name)
+
+		_, ok := types[name]
+		if !ok {
+			names = append(names, name)
+		}
+
+		if !slices.Contains(types[name], rr.Header().Rrtype) {
+			types[name] = append(types[name], rr.Header().Rrtype)
+		}
+	}
+
+	slices.SortFunc(names, canonicalCompare)
+
+	nsecs := make([]dns.RR, 0, len(names))
+	for i, name := range names {
+		bitmap := append(slices.Clone(types[name]), dns.TypeRRSIG, dns.TypeNSEC)
+		slices.Sort(bitmap)
+
+		nsecs = append(nsecs, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: ttl},
+			NextDomain: names[(i+1)%len(names)],
+			TypeBitMap: bitmap,
+		})
+	}
+
+	return nsecs
+}
+
+// nsecCovers returns whether the NSEC record proves that the name doesn't exist.
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	// The last record of the chain points back to the zone apex.
+	if canonicalCompare(nsec.Hdr.Name, name) >= 0 {
+		return false
+	}
+
+	return canonicalCompare(name, nsec.NextDomain) < 0 || canonicalCompare(nsec.NextDomain, nsec.Hdr.Name) <= 0
+}
+
+// canonicalCompare compares two domain names using the canonical DNS name order (RFC 4034).
+func canonicalCompare(a string, b string) int {
+	labelsA := dns.SplitDomainName(strings.ToLower(a))
+	labelsB := dns.SplitDomainName(strings.ToLower(b))
+
+	// Compare labels starting from the right.
+	for i := 1; i <= len(labelsA) && i <= len(labelsB); i++ {
+		c := strings.Compare(labelsA[len(labelsA)-i], labelsB[len(labelsB)-i])
+		if c != 0 {
+			return c
+		}
+	}
+
+	return len(labelsA) - len(labelsB)
+}
@@ -0,0 +1,317 @@
+package dns
+
+import (
+	"crypto"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKey returns a new active DNSSEC key for the zone.
+func newTestKey(t *testing.T, zoneName string, ksk bool) Key {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(zoneName), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+
+	if ksk {
+		dnskey.Flags |= dns.SEP
+	}
+
+	privateKey, err := dnskey.Generate(256)
+	require.NoError(t, err)
+
+	return Key{DNSKEY: dnskey, Signer: privateKey.(crypto.Signer), Active: true}
+}
+
+// testRecords returns the records of the test zone.
+func testRecords(t *testing.T) []dns.RR {
+	zone := &Zone{Content: testZoneContent}
+
+	records, err := zone.records()
+	require.NoError(t, err)
+
+	return records
+}
+
+// rrsets groups the records by name and type, leaving out the signatures.
+func rrsets(records []dns.RR) map[string][]dns.RR {
+	sets := map[string][]dns.RR{}
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeRRSIG {
+			continue
+		}
+
+		k := dns.CanonicalName(rr.Header().Name) + "/" + dns.TypeToString[rr.Header().Rrtype]
+		sets[k] = append(sets[k], rr)
+	}
+
+	return sets
+}
+
+func TestSignRecords(t *testing.T) {
+	ksk := newTestKey(t, "incus.example.net", true)
+	zsk := newTestKey(t, "incus.example.net", false)
+
+	records := testRecords(t)
+	signed, err := signRecords("incus.example.net", records, []Key{ksk, zsk}, true)
+	require.NoError(t, err)
+
+	sets := rrsets(signed)
+
+	// The keys and the NSEC chain are added.
+	assert.Len(t, sets["incus.example.net./DNSKEY"], 2)
+	assert.Len(t, sets["incus.example.net./NSEC"], 1)
+	assert.Len(t, sets["c1.incus.example.net./NSEC"], 1)
+
+	// Every record set is signed, the DNSKEY set by the key signing key and the others by the zone signing key.
+	sigs := map[string]*dns.RRSIG{}
+	for _, rr := range signed {
+		sig, ok := rr.(*dns.RRSIG)
+		if !ok {
+			continue
+		}
+
+		k := dns.CanonicalName(sig.Hdr.Name) + "/" + dns.TypeToString[sig.TypeCovered]
+		assert.NotContains(t, sigs, k)
+		sigs[k] = sig
+	}
+
+	assert.Len(t, sigs, len(sets))
+
+	for k, rrset := range sets {
+		sig := sigs[k]
+		require.NotNil(t, sig, k)
+
+		key := zsk.DNSKEY
+		if rrset[0].Header().Rrtype == dns.TypeDNSKEY {
+			key = ksk.DNSKEY
+		}
+
+		assert.Equal(t, key.KeyTag(), sig.KeyTag, k)
+		assert.Equal(t, "incus.example.net.", sig.SignerName)
+		assert.True(t, sig.ValidityPeriod(time.Now()), k)
+		assert.NoError(t, sig.Verify(key, rrset), k)
+	}
+}
+
+func TestSignRecordsPartial(t *testing.T) {
+	zsk := newTestKey(t, "incus.example.net", false)
+
+	// Only the existing records are signed when not signing the full zone.
+	signed, err := signRecords("incus.example.net", testRecords(t)[:1], []Key{zsk}, false)
+	require.NoError(t, err)
+	require.Len(t, signed, 2)
+
+	sig, ok := signed[1].(*dns.RRSIG)
+	require.True(t, ok)
+	assert.Equal(t, dns.TypeSOA, sig.TypeCovered)
+	assert.NoError(t, sig.Verify(zsk.DNSKEY, signed[:1]))
+}
+
+func TestSignRecordsInactiveKey(t *testing.T) {
+	zsk := newTestKey(t, "incus.example.net", false)
+	newZSK := newTestKey(t, "incus.example.net", false)
+	newZSK.Active = false
+
+	signed, err := signRecords("incus.example.net", testRecords(t), []Key{zsk, newZSK}, true)
+	require.NoError(t, err)
+
+	// Inactive keys are published but not used for signing.
+	assert.Len(t, rrsets(signed)["incus.example.net./DNSKEY"], 2)
+
+	for _, rr := range signed {
+		sig, ok := rr.(*dns.RRSIG)
+		if ok {
+			assert.Equal(t, zsk.DNSKEY.KeyTag(), sig.KeyTag)
+		}
+	}
+}
+
+func TestSignRecordsMissingSOA(t *testing.T) {
+	zsk := newTestKey(t, "incus.example.net", false)
+	rr, err := dns.NewRR("c1.incus.example.net. 300 IN A 192.0.2.10")
+	require.NoError(t, err)
+
+	_, err = signRecords("incus.example.net", []dns.RR{rr}, []Key{zsk}, true)
+	assert.ErrorContains(t, err, "doesn't have a SOA record")
+}
+
+func TestSignZoneCache(t *testing.T) {
+	s := &Server{}
+	ksk := newTestKey(t, "incus.example.net", true)
+	zsk := newTestKey(t, "incus.example.net", false)
+	keys := []Key{ksk, zsk}
+
+	signed, err := s.signZone("incus.example.net", testRecords(t), keys)
+	require.NoError(t, err)
+
+	// The signed zone is reused when only the SOA serial changes.
+	records := testRecords(t)
+	records[0].(*dns.SOA).Serial = 2
+
+	cached, err := s.signZone("incus.example.net", records, keys)
+	require.NoError(t, err)
+	assert.Same(t, signed[len(signed)-1], cached[len(cached)-1])
+
+	// The zone is signed again when its records change.
+	records = append(testRecords(t), &dns.A{Hdr: dns.RR_Header{Name: "c4.incus.example.net.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300}, A: net.ParseIP("192.0.2.40")})
+
+	resigned, err := s.signZone("incus.example.net", records, keys)
+	require.NoError(t, err)
+	assert.NotSame(t, signed[len(signed)-1], resigned[len(resigned)-1])
+	assert.Len(t, rrsets(resigned)["c4.incus.example.net./A"], 1)
+
+	// Or when its keys change.
+	zsk.Active = false
+	newZSK := newTestKey(t, "incus.example.net", false)
+
+	rekeyed, err := s.signZone("incus.example.net", records, []Key{ksk, zsk, newZSK})
+	require.NoError(t, err)
+	assert.Len(t, rrsets(rekeyed)["incus.example.net./DNSKEY"], 3)
+
+	for _, rr := range rekeyed {
+		sig, ok := rr.(*dns.RRSIG)
+		if ok && sig.TypeCovered != dns.TypeDNSKEY {
+			assert.Equal(t, newZSK.DNSKEY.KeyTag(), sig.KeyTag)
+		}
+	}
+
+	// Or when the signatures get old.
+	s.signedZones["incus.example.net"].signedAt = time.Now().Add(-dnssecSignatureValidity)
+
+	renewed, err := s.signZone("incus.example.net", records, []Key{ksk, zsk, newZSK})
+	require.NoError(t, err)
+	assert.NotSame(t, rekeyed[len(rekeyed)-1], renewed[len(renewed)-1])
+
+	// Deleted zones are dropped from the cache.
+	s.RemoveZone("incus.example.net")
+	assert.Empty(t, s.signedZones)
+}
+
+func TestNSECChain(t *testing.T) {
+	nsecs := nsecChain(testRecords(t), 30)
+
+	next := map[string]string{}
+	for _, rr := range nsecs {
+		nsec := rr.(*dns.NSEC)
+		assert.Equal(t, uint32(30), nsec.Hdr.Ttl)
+		next[nsec.Hdr.Name] = nsec.NextDomain
+	}
+
+	// Names are linked in canonical order, the last one pointing back to the apex.
+	assert.Equal(t, map[string]string{
+		"incus.example.net.":        "c1.incus.example.net.",
+		"c1.incus.example.net.":     "ext.incus.example.net.",
+		"ext.incus.example.net.":    "c2.lab.incus.example.net.",
+		"c2.lab.incus.example.net.": "www.incus.example.net.",
+		"www.incus.example.net.":    "incus.example.net.",
+	}, next)
+
+	// The type bitmap lists the types of the name.
+	assert.Equal(t, []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeRRSIG, dns.TypeNSEC}, nsecs[1].(*dns.NSEC).TypeBitMap)
+}
+
+func TestNSECCovers(t *testing.T) {
+	nsec := &dns.NSEC{Hdr: dns.RR_Header{Name: "c1.incus.example.net."}, NextDomain: "ext.incus.example.net."}
+	assert.True(t, nsecCovers(nsec, "d.incus.example.net."))
+	assert.True(t, nsecCovers(nsec, "a.c1.incus.example.net."))
+	assert.False(t, nsecCovers(nsec, "c1.incus.example.net."))
+	assert.False(t, nsecCovers(nsec, "ext.incus.example.net."))
+	assert.False(t, nsecCovers(nsec, "www.incus.example.net."))
+
+	// The last record covers the names after it.
+	last := &dns.NSEC{Hdr: dns.RR_Header{Name: "www.incus.example.net."}, NextDomain: "incus.example.net."}
+	assert.True(t, nsecCovers(last, "zzz.incus.example.net."))
+	assert.False(t, nsecCovers(last, "c1.incus.example.net."))
+}
+
+func TestCanonicalCompare(t *testing.T) {
+	// Example from RFC 4034 section 6.1.
+	names := []string{
+		"example.",
+		"a.example.",
+		"yljkjljk.a.example.",
+		"Z.a.example.",
+		"zABC.a.EXAMPLE.",
+		"z.example.",
+		"*.z.example.",
+	}
+
+	for i := 1; i < len(names); i++ {
+		assert.Negative(t, canonicalCompare(names[i-1], names[i]), names[i])
+		assert.Positive(t, canonicalCompare(names[i], names[i-1]), names[i])
+	}
+
+	assert.Zero(t, canonicalCompare("Example.", "example."))
+}
+
+func TestServeQueryDNSSEC(t *testing.T) {
+	h := newTestHandler()
+
+	ksk := newTestKey(t, "incus.example.net", true)
+	zsk := newTestKey(t, "incus.example.net", false)
+	zone, err := h.server.zoneRetriever("incus.example.net", true)
+	require.NoError(t, err)
+
+	zone.Keys = []Key{ksk, zsk}
+
+	dnssecQuery := func(name string, qtype uint16, do bool) *dns.Msg {
+		r := &dns.Msg{}
+		r.SetQuestion(dns.Fqdn(name), qtype)
+		r.SetEdns0(4096, do)
+
+		w := &testResponseWriter{remote: &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 12345}}
+		h.ServeDNS(w, r)
+
+		return w.msg
+	}
+
+	countType := func(records []dns.RR, rrtype uint16) int {
+		count := 0
+		for _, rr := range records {
+			if rr.Header().Rrtype == rrtype {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	// Signatures are only included when requested.
+	m := dnssecQuery("c1.incus.example.net", dns.TypeA, false)
+	assert.Len(t, m.Answer, 1)
+
+	m = dnssecQuery("c1.incus.example.net", dns.TypeA, true)
+	require.Len(t, m.Answer, 2)
+	assert.Equal(t, dns.TypeRRSIG, m.Answer[1].Header().Rrtype)
+	assert.NoError(t, m.Answer[1].(*dns.RRSIG).Verify(zsk.DNSKEY, m.Answer[:1]))
+
+	// The keys can be queried.
+	m = dnssecQuery("incus.example.net", dns.TypeDNSKEY, true)
+	assert.Equal(t, 2, countType(m.Answer, dns.TypeDNSKEY))
+	assert.Equal(t, 1, countType(m.Answer, dns.TypeRRSIG))
+
+	// Missing types are proven by the NSEC record of the name.
+	m = dnssecQuery("c1.incus.example.net", dns.TypeMX, true)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Empty(t, m.Answer)
+	assert.Equal(t, 1, countType(m.Ns, dns.TypeNSEC))
+	assert.Equal(t, "c1.incus.example.net.", m.Ns[2].Header().Name)
+
+	// Missing names are proven by the NSEC records covering the name and the wildcard.
+	m = dnssecQuery("d.incus.example.net", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+	assert.Equal(t, 2, countType(m.Ns, dns.TypeNSEC))
+	assert.Equal(t, 3, countType(m.Ns, dns.TypeRRSIG))
+
+	// Responses advertise DNSSEC support.
+	require.NotNil(t, m.IsEdns0())
+	assert.True(t, m.IsEdns0().Do())
+}
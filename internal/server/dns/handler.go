@@ -3,6 +3,7 @@ package dns
 import (
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
@@ -45,18 +46,6 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	// Check that it's a supported request type.
-	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
-		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeNotImplemented)
-		err := w.WriteMsg(m)
-		if err != nil {
-			logger.Error("Unable to write message", logger.Ctx{"err": err})
-		}
-
-		return
-	}
-
 	// Extract the request information.
 	name := strings.TrimSuffix(r.Question[0].Name, ".")
 	ip, _, err := net.SplitHostPort(w.RemoteAddr().String())
@@ -71,6 +60,12 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	// Answer regular queries from the records of the zone.
+	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
+		d.serveQuery(w, r, ip)
+		return
+	}
+
 	// Prepare the response.
 	m := &dns.Msg{}
 	m.SetReply(r)
@@ -103,28 +98,286 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	zoneRR := dns.NewZoneParser(strings.NewReader(zone.Content), "", "")
-	for {
-		rr, ok := zoneRR.Next()
-		if !ok {
-			err := zoneRR.Err()
+	records, err := zone.records()
+	if err != nil {
+		logger.Errorf("Bad DNS record in zone %q: %v", name, err)
+
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeFormatError)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	// Sign the zone.
+	if len(zone.Keys) > 0 {
+		if r.Question[0].Qtype == dns.TypeSOA {
+			records, err = signRecords(name, records, zone.Keys, false)
+		} else {
+			records, err = d.server.signZone(zone.Info.Name, records, zone.Keys)
+		}
+
+		if err != nil {
+			logger.Errorf("Failed signing DNS zone %q: %v", name, err)
+
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			err := w.WriteMsg(m)
 			if err != nil {
-				logger.Errorf("Bad DNS record in zone %q: %v", name, err)
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+	}
+
+	for _, rr := range records {
+		// Only include signatures in SOA responses when requested.
+		if r.Question[0].Qtype == dns.TypeSOA && rr.Header().Rrtype == dns.TypeRRSIG && (r.IsEdns0() == nil || !r.IsEdns0().Do()) {
+			continue
+		}
+
+		m.Answer = append(m.Answer, rr)
+	}
+
+	// Zone transfers end with the SOA record.
+	if r.Question[0].Qtype != dns.TypeSOA && len(records) > 0 {
+		m.Answer = append(m.Answer, records[0])
+	}
+
+	tsig := r.IsTsig()
+	if tsig != nil && w.TsigStatus() == nil {
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+
+	err = w.WriteMsg(m)
+	if err != nil {
+		logger.Error("Unable to write message", logger.Ctx{"err": err})
+	}
+}
+
+// serveQuery answers a regular query using the records of the most specific zone containing the name.
+func (d dnsHandler) serveQuery(w dns.ResponseWriter, r *dns.Msg, ip string) {
+	question := r.Question[0]
+
+	// Only answer Internet class queries.
+	if question.Qclass != dns.ClassINET {
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeNotImplemented)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	// Find the zone, walking up from the queried name (names are case insensitive).
+	var zone *Zone
+	labels := dns.SplitDomainName(strings.ToLower(question.Name))
+	for i := range labels {
+		candidate, err := d.server.zoneRetriever(strings.Join(labels[i:], "."), true)
+		if err == nil {
+			zone = candidate
+			break
+		}
+	}
+
+	// Refuse queries for names we're not authoritative for or from untrusted clients.
+	if zone == nil || !d.isAllowed(zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) {
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeRefused)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
 
-				m := &dns.Msg{}
-				m.SetRcode(r, dns.RcodeFormatError)
-				err := w.WriteMsg(m)
-				if err != nil {
-					logger.Error("Unable to write message", logger.Ctx{"err": err})
+	// Parse and sign the zone content.
+	records, err := zone.records()
+	if err == nil && len(zone.Keys) > 0 {
+		records, err = d.server.signZone(zone.Info.Name, records, zone.Keys)
+	}
+
+	if err != nil {
+		logger.Errorf("Failed loading DNS zone %q: %v", zone.Info.Name, err)
+
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeServerFailure)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	var soa *dns.SOA
+	nsecs := []*dns.NSEC{}
+	for _, rr := range records {
+		switch entry := rr.(type) {
+		case *dns.SOA:
+			soa = entry
+		case *dns.NSEC:
+			nsecs = append(nsecs, entry)
+		}
+	}
+
+	// Check whether the client wants DNSSEC records.
+	opt := r.IsEdns0()
+	dnssecOK := opt != nil && opt.Do()
+
+	// signatures returns the signatures of a record set when requested by the client.
+	signatures := func(name string, rrtype uint16) []dns.RR {
+		sigs := []dns.RR{}
+		if !dnssecOK {
+			return sigs
+		}
+
+		for _, rr := range records {
+			sig, ok := rr.(*dns.RRSIG)
+			if ok && sig.TypeCovered == rrtype && strings.EqualFold(sig.Hdr.Name, name) {
+				sigs = append(sigs, sig)
+			}
+		}
+
+		return sigs
+	}
+
+	// nameExists returns whether the name has records or names having records below it.
+	nameExists := func(name string) bool {
+		for _, rr := range records {
+			if dns.IsSubDomain(name, rr.Header().Name) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// denial returns the NSEC record proving the name or type doesn't exist, with its signatures.
+	denial := func(name string) []dns.RR {
+		for _, nsec := range nsecs {
+			if strings.EqualFold(nsec.Hdr.Name, name) || nsecCovers(nsec, name) {
+				return append([]dns.RR{nsec}, signatures(nsec.Hdr.Name, dns.TypeNSEC)...)
+			}
+		}
+
+		return nil
+	}
+
+	// Prepare the response.
+	m := &dns.Msg{}
+	m.SetReply(r)
+	m.Authoritative = true
+
+	if opt != nil {
+		m.SetEdns0(dns.DefaultMsgSize, dnssecOK)
+	}
+
+	// Look for matching records, following CNAME records within the zone.
+	target := question.Name
+	for range 8 {
+		var cname *dns.CNAME
+		answers := []dns.RR{}
+		answerTypes := []uint16{}
+
+		for _, rr := range records {
+			if !strings.EqualFold(rr.Header().Name, target) {
+				continue
+			}
+
+			if question.Qtype == dns.TypeANY || rr.Header().Rrtype == question.Qtype {
+				answers = append(answers, rr)
+
+				if !slices.Contains(answerTypes, rr.Header().Rrtype) {
+					answerTypes = append(answerTypes, rr.Header().Rrtype)
 				}
+			} else if rr.Header().Rrtype == dns.TypeCNAME {
+				cname, _ = rr.(*dns.CNAME)
+			}
+		}
+
+		if len(answers) > 0 {
+			m.Answer = append(m.Answer, answers...)
 
-				return
+			// Signatures are already part of the answer for ANY queries.
+			if question.Qtype != dns.TypeANY {
+				for _, rrtype := range answerTypes {
+					m.Answer = append(m.Answer, signatures(target, rrtype)...)
+				}
 			}
 
 			break
 		}
 
-		m.Answer = append(m.Answer, rr)
+		if cname != nil {
+			m.Answer = append(m.Answer, cname)
+			m.Answer = append(m.Answer, signatures(target, dns.TypeCNAME)...)
+			target = cname.Target
+
+			// Let the client resolve targets outside of the zone.
+			if !dns.IsSubDomain(dns.Fqdn(zone.Info.Name), target) {
+				break
+			}
+
+			continue
+		}
+
+		// No matching record, include the SOA record for negative caching.
+		if soa != nil {
+			m.Ns = append(m.Ns, soa)
+			m.Ns = append(m.Ns, signatures(soa.Hdr.Name, dns.TypeSOA)...)
+		}
+
+		if nameExists(target) {
+			// No record of the requested type.
+			if dnssecOK {
+				m.Ns = append(m.Ns, denial(target)...)
+			}
+
+			break
+		}
+
+		m.Rcode = dns.RcodeNameError
+
+		if dnssecOK {
+			// Prove that neither the name nor a matching wildcard exist.
+			m.Ns = append(m.Ns, denial(target)...)
+
+			labels := dns.SplitDomainName(target)
+			for i := 1; i < len(labels); i++ {
+				encloser := dns.Fqdn(strings.Join(labels[i:], "."))
+				if !nameExists(encloser) {
+					continue
+				}
+
+				for _, rr := range denial("*." + encloser) {
+					if !slices.Contains(m.Ns, rr) {
+						m.Ns = append(m.Ns, rr)
+					}
+				}
+
+				break
+			}
+		}
+
+		break
+	}
+
+	// Truncate UDP responses to the size supported by the client.
+	if w.RemoteAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+		if opt != nil {
+			size = int(opt.UDPSize())
+		}
+
+		m.Truncate(size)
 	}
 
 	tsig := r.IsTsig()
@@ -0,0 +1,215 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// testResponseWriter records the message written in response to a query.
+type testResponseWriter struct {
+	remote net.Addr
+	msg    *dns.Msg
+}
+
+func (w *testResponseWriter) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 53}
+}
+
+func (w *testResponseWriter) RemoteAddr() net.Addr {
+	return w.remote
+}
+
+func (w *testResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+func (w *testResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *testResponseWriter) Close() error {
+	return nil
+}
+
+func (w *testResponseWriter) TsigStatus() error {
+	return nil
+}
+
+func (w *testResponseWriter) TsigTimersOnly(bool) {}
+
+func (w *testResponseWriter) Hijack() {}
+
+const testZoneContent = `incus.example.net. 300 IN SOA incus.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+incus.example.net. 300 IN NS ns1.incus.example.net.
+c1.incus.example.net. 300 IN A 192.0.2.10
+c1.incus.example.net. 300 IN AAAA 2001:db8::10
+www.incus.example.net. 300 IN CNAME c1.incus.example.net.
+ext.incus.example.net. 300 IN CNAME www.example.com.
+c2.lab.incus.example.net. 300 IN A 192.0.2.20
+incus.example.net. 300 IN SOA incus.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+`
+
+const testSubZoneContent = `lab.incus.example.net. 300 IN SOA lab.incus.example.net. hostmaster.lab.incus.example.net. 1 120 60 86400 30
+c3.lab.incus.example.net. 300 IN A 192.0.2.30
+`
+
+// newTestHandler returns a handler serving the test zones to clients from 192.0.2.1.
+func newTestHandler() dnsHandler {
+	zones := map[string]*Zone{
+		"incus.example.net":     {Content: testZoneContent},
+		"lab.incus.example.net": {Content: testSubZoneContent},
+	}
+
+	for name, zone := range zones {
+		zone.Info = api.NetworkZone{Name: name, NetworkZonePut: api.NetworkZonePut{Config: map[string]string{"peers.test.address": "192.0.2.1"}}}
+	}
+
+	retriever := func(name string, full bool) (*Zone, error) {
+		zone, ok := zones[name]
+		if !ok {
+			return nil, fmt.Errorf("Zone %q not found", name)
+		}
+
+		return zone, nil
+	}
+
+	return dnsHandler{server: &Server{zoneRetriever: retriever}}
+}
+
+// query sends a query to the handler from the given client address and returns the response.
+func query(h dnsHandler, client string, name string, qtype uint16) *dns.Msg {
+	r := &dns.Msg{}
+	r.SetQuestion(dns.Fqdn(name), qtype)
+
+	w := &testResponseWriter{remote: &net.UDPAddr{IP: net.ParseIP(client), Port: 12345}}
+	h.ServeDNS(w, r)
+
+	return w.msg
+}
+
+// answerStrings returns the answer section of a response as strings.
+func answerStrings(m *dns.Msg) []string {
+	answers := []string{}
+	for _, rr := range m.Answer {
+		answers = append(answers, rr.String())
+	}
+
+	return answers
+}
+
+func TestServeQuery(t *testing.T) {
+	h := newTestHandler()
+
+	m := query(h, "192.0.2.1", "c1.incus.example.net", dns.TypeA)
+	require.NotNil(t, m)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.True(t, m.Authoritative)
+	assert.Equal(t, []string{"c1.incus.example.net.\t300\tIN\tA\t192.0.2.10"}, answerStrings(m))
+
+	// Names are case insensitive.
+	m = query(h, "192.0.2.1", "C1.Incus.Example.Net", dns.TypeAAAA)
+	assert.Equal(t, []string{"c1.incus.example.net.\t300\tIN\tAAAA\t2001:db8::10"}, answerStrings(m))
+
+	// All the records of the name are returned for ANY queries.
+	m = query(h, "192.0.2.1", "c1.incus.example.net", dns.TypeANY)
+	assert.Len(t, m.Answer, 2)
+}
+
+func TestServeQueryCNAME(t *testing.T) {
+	h := newTestHandler()
+
+	// CNAME records are followed within the zone.
+	m := query(h, "192.0.2.1", "www.incus.example.net", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Equal(t, []string{
+		"www.incus.example.net.\t300\tIN\tCNAME\tc1.incus.example.net.",
+		"c1.incus.example.net.\t300\tIN\tA\t192.0.2.10",
+	}, answerStrings(m))
+
+	// Targets outside of the zone are left to the client.
+	m = query(h, "192.0.2.1", "ext.incus.example.net", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Equal(t, []string{"ext.incus.example.net.\t300\tIN\tCNAME\twww.example.com."}, answerStrings(m))
+
+	// The CNAME record itself can be queried.
+	m = query(h, "192.0.2.1", "www.incus.example.net", dns.TypeCNAME)
+	assert.Equal(t, []string{"www.incus.example.net.\t300\tIN\tCNAME\tc1.incus.example.net."}, answerStrings(m))
+}
+
+func TestServeQueryNegative(t *testing.T) {
+	h := newTestHandler()
+
+	// Missing types return an empty answer with the SOA record.
+	m := query(h, "192.0.2.1", "c1.incus.example.net", dns.TypeMX)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Empty(t, m.Answer)
+	require.Len(t, m.Ns, 1)
+	assert.Equal(t, dns.TypeSOA, m.Ns[0].Header().Rrtype)
+
+	// Missing names return NXDOMAIN with the SOA record.
+	m = query(h, "192.0.2.1", "missing.incus.example.net", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+	assert.Empty(t, m.Answer)
+	require.Len(t, m.Ns, 1)
+	assert.Equal(t, dns.TypeSOA, m.Ns[0].Header().Rrtype)
+}
+
+func TestServeQueryZoneSelection(t *testing.T) {
+	h := newTestHandler()
+
+	// Names are answered from the most specific zone.
+	m := query(h, "192.0.2.1", "c3.lab.incus.example.net", dns.TypeA)
+	assert.Equal(t, []string{"c3.lab.incus.example.net.\t300\tIN\tA\t192.0.2.30"}, answerStrings(m))
+
+	m = query(h, "192.0.2.1", "c2.lab.incus.example.net", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+	assert.Equal(t, "lab.incus.example.net.", m.Ns[0].Header().Name)
+}
+
+func TestServeQueryRefused(t *testing.T) {
+	h := newTestHandler()
+
+	// Names outside of the zones are refused.
+	m := query(h, "192.0.2.1", "www.example.com", dns.TypeA)
+	assert.Equal(t, dns.RcodeRefused, m.Rcode)
+
+	// Clients which aren't peers of the zone are refused.
+	m = query(h, "192.0.2.2", "c1.incus.example.net", dns.TypeA)
+	assert.Equal(t, dns.RcodeRefused, m.Rcode)
+	assert.Empty(t, m.Answer)
+
+	// Only Internet class queries are answered.
+	r := &dns.Msg{}
+	r.SetQuestion("c1.incus.example.net.", dns.TypeA)
+	r.Question[0].Qclass = dns.ClassCHAOS
+
+	w := &testResponseWriter{remote: &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 12345}}
+	h.ServeDNS(w, r)
+	assert.Equal(t, dns.RcodeNotImplemented, w.msg.Rcode)
+}
+
+func TestServeTransfer(t *testing.T) {
+	h := newTestHandler()
+
+	// Zone transfers start and end with the SOA record, which is only included once in the content.
+	m := query(h, "192.0.2.1", "incus.example.net", dns.TypeAXFR)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	require.Len(t, m.Answer, 8)
+	assert.Equal(t, dns.TypeSOA, m.Answer[0].Header().Rrtype)
+	assert.Equal(t, dns.TypeSOA, m.Answer[7].Header().Rrtype)
+
+	// Zone transfers of unknown zones or to other clients fail.
+	m = query(h, "192.0.2.1", "example.com", dns.TypeAXFR)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+
+	m = query(h, "192.0.2.2", "incus.example.net", dns.TypeAXFR)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+}
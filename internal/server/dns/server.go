@@ -28,6 +28,9 @@ type Server struct {
 	// Internal state (to handle reconfiguration).
 	address string
 
+	// Signed zones, by zone name.
+	signedZones map[string]*signedZone
+
 	mu sync.Mutex
 }
 
@@ -149,6 +152,15 @@ func (s *Server) reconfigure(address string) error {
 	return nil
 }
 
+// RemoveZone drops the cached data of a deleted zone.
+func (s *Server) RemoveZone(name string) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.signedZones, name)
+}
+
 // UpdateTSIG fetches all TSIG keys and loads them into the DNS server.
 func (s *Server) UpdateTSIG() error {
 	// Locking.
@@ -1,6 +1,11 @@
 package dns
 
 import (
+	"crypto"
+	"strings"
+
+	"github.com/miekg/dns"
+
 	"github.com/lxc/incus/v6/shared/api"
 )
 
@@ -8,4 +13,52 @@ import (
 type Zone struct {
 	Info    api.NetworkZone
 	Content string
+
+	// DNSSEC keys used to sign the zone (empty for unsigned zones).
+	Keys []Key
+}
+
+// Key represents a DNSSEC key of a zone.
+type Key struct {
+	// Public key record (key signing keys have the SEP flag set).
+	DNSKEY *dns.DNSKEY
+
+	// Private key.
+	Signer crypto.Signer
+
+	// Whether the key is used to generate signatures or only published.
+	Active bool
+}
+
+// records parses the zone content into a list of records, only keeping the first SOA record.
+func (z *Zone) records() ([]dns.RR, error) {
+	var soa *dns.SOA
+	records := []dns.RR{}
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(z.Content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		// The SOA record is repeated at the end of full zone content.
+		entry, ok := rr.(*dns.SOA)
+		if ok {
+			if soa != nil {
+				continue
+			}
+
+			soa = entry
+		}
+
+		records = append(records, rr)
+	}
+
+	return records, nil
 }
@@ -0,0 +1,197 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// Dependencies returns the names of the instances listed in boot.depends_on.
+func Dependencies(config map[string]string) []string {
+	return util.SplitNTrimSpace(config["boot.depends_on"], ",", -1, true)
+}
+
+// ValidDependencies checks that the dependencies of the instance configuration don't introduce a dependency cycle
+// between the instances of the project.
+func ValidDependencies(s *state.State, projectName string, instanceName string, config map[string]string) error {
+	deps := Dependencies(config)
+	if len(deps) == 0 {
+		return nil
+	}
+
+	if slices.Contains(deps, instanceName) {
+		return fmt.Errorf("Instance %q cannot depend on itself", instanceName)
+	}
+
+	graph := map[string][]string{}
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			if inst.Snapshot {
+				return nil
+			}
+
+			graph[inst.Name] = Dependencies(db.ExpandInstanceConfig(inst.Config, inst.Profiles))
+
+			return nil
+		}, cluster.InstanceFilter{Project: &projectName})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading instance dependencies: %w", err)
+	}
+
+	graph[instanceName] = deps
+
+	cycle := dependencyCycle(graph, instanceName)
+	if cycle != nil {
+		return fmt.Errorf("Dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// dependencyCycle returns the path of a dependency cycle going through the instance, or nil if there is none.
+// The graph maps each instance name to the names of the instances it depends on.
+func dependencyCycle(graph map[string][]string, instanceName string) []string {
+	visited := map[string]bool{}
+
+	var findCycle func(name string, path []string) []string
+	findCycle = func(name string, path []string) []string {
+		if name == instanceName && len(path) > 0 {
+			return append(path, name)
+		}
+
+		if visited[name] {
+			return nil
+		}
+
+		visited[name] = true
+
+		for _, dep := range graph[name] {
+			cycle := findCycle(dep, append(path, name))
+			if cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return findCycle(instanceName, nil)
+}
+
+// instanceKey returns a key identifying an instance across projects.
+func instanceKey(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// dependencyOrder returns the instances with each instance placed after (or before if reverse is set) the instances
+// of the list it depends on. The existing order is kept otherwise.
+func dependencyOrder(instances []Instance, reverse bool) []Instance {
+	byKey := make(map[string]Instance, len(instances))
+	for _, inst := range instances {
+		byKey[instanceKey(inst.Project().Name, inst.Name())] = inst
+	}
+
+	// Build the edges to follow before placing each instance.
+	edges := make(map[string][]string, len(instances))
+	for _, inst := range instances {
+		key := instanceKey(inst.Project().Name, inst.Name())
+
+		for _, dep := range Dependencies(inst.ExpandedConfig()) {
+			depKey := instanceKey(inst.Project().Name, dep)
+			if byKey[depKey] == nil {
+				continue
+			}
+
+			if reverse {
+				edges[depKey] = append(edges[depKey], key)
+			} else {
+				edges[key] = append(edges[key], depKey)
+			}
+		}
+	}
+
+	ordered := make([]Instance, 0, len(instances))
+	visited := make(map[string]bool, len(instances))
+
+	var visit func(key string)
+	visit = func(key string) {
+		if visited[key] {
+			return
+		}
+
+		visited[key] = true
+
+		for _, edge := range edges[key] {
+			visit(edge)
+		}
+
+		ordered = append(ordered, byKey[key])
+	}
+
+	for _, inst := range instances {
+		visit(instanceKey(inst.Project().Name, inst.Name()))
+	}
+
+	return ordered
+}
+
+// StartOrder returns the instances ordered so that each instance comes after its dependencies.
+func StartOrder(instances []Instance) []Instance {
+	return dependencyOrder(instances, false)
+}
+
+// StopOrder returns the instances ordered so that each instance comes before its dependencies.
+func StopOrder(instances []Instance) []Instance {
+	return dependencyOrder(instances, true)
+}
+
+// AutostartOrder returns the instances to start automatically, in start order. This includes the instances for which
+// autoStart returns true as well as the instances of the list they (recursively) depend on.
+func AutostartOrder(instances []Instance, autoStart func(inst Instance) bool) []Instance {
+	byKey := make(map[string]Instance, len(instances))
+	for _, inst := range instances {
+		byKey[instanceKey(inst.Project().Name, inst.Name())] = inst
+	}
+
+	selected := make(map[string]bool, len(instances))
+
+	var selectInstance func(inst Instance)
+	selectInstance = func(inst Instance) {
+		key := instanceKey(inst.Project().Name, inst.Name())
+		if selected[key] {
+			return
+		}
+
+		selected[key] = true
+
+		for _, dep := range Dependencies(inst.ExpandedConfig()) {
+			depInst := byKey[instanceKey(inst.Project().Name, dep)]
+			if depInst != nil {
+				selectInstance(depInst)
+			}
+		}
+	}
+
+	for _, inst := range instances {
+		if autoStart(inst) {
+			selectInstance(inst)
+		}
+	}
+
+	autostart := make([]Instance, 0, len(selected))
+	for _, inst := range StartOrder(instances) {
+		if selected[instanceKey(inst.Project().Name, inst.Name())] {
+			autostart = append(autostart, inst)
+		}
+	}
+
+	return autostart
+}
@@ -0,0 +1,181 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// fakeInstance implements the parts of Instance used by the dependency ordering.
+type fakeInstance struct {
+	Instance
+
+	name   string
+	config map[string]string
+}
+
+func (f *fakeInstance) Name() string {
+	return f.name
+}
+
+func (f *fakeInstance) Project() api.Project {
+	return api.Project{Name: "default"}
+}
+
+func (f *fakeInstance) ExpandedConfig() map[string]string {
+	return f.config
+}
+
+func newFakeInstance(name string, dependsOn string, autostart string) Instance {
+	return &fakeInstance{name: name, config: map[string]string{"boot.depends_on": dependsOn, "boot.autostart": autostart}}
+}
+
+func instanceNames(instances []Instance) []string {
+	names := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		names = append(names, inst.Name())
+	}
+
+	return names
+}
+
+func TestValidDependencies_SelfDependency(t *testing.T) {
+	err := ValidDependencies(nil, "default", "c1", map[string]string{"boot.depends_on": "c2, c1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot depend on itself")
+}
+
+func Test_dependencyCycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    map[string][]string
+		instance string
+		want     []string
+	}{
+		{
+			"No dependencies",
+			map[string][]string{"c1": nil},
+			"c1",
+			nil,
+		},
+		{
+			"Two nodes cycle",
+			map[string][]string{"c1": {"c2"}, "c2": {"c1"}},
+			"c1",
+			[]string{"c1", "c2", "c1"},
+		},
+		{
+			"Three nodes cycle",
+			map[string][]string{"c1": {"c2"}, "c2": {"c3"}, "c3": {"c1"}},
+			"c1",
+			[]string{"c1", "c2", "c3", "c1"},
+		},
+		{
+			"Missing dependency",
+			map[string][]string{"c1": {"c2"}, "c2": {"missing"}},
+			"c1",
+			nil,
+		},
+		{
+			"Diamond",
+			map[string][]string{"app": {"db", "cache"}, "db": {"storage"}, "cache": {"storage"}},
+			"app",
+			nil,
+		},
+		{
+			"Cycle not going through the instance",
+			map[string][]string{"c1": {"c2"}, "c2": {"c3"}, "c3": {"c2"}},
+			"c1",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dependencyCycle(tt.graph, tt.instance))
+		})
+	}
+}
+
+func TestStartStopOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []Instance
+		wantStart []string
+		wantStop  []string
+	}{
+		{
+			"No dependencies",
+			[]Instance{newFakeInstance("c1", "", ""), newFakeInstance("c2", "", "")},
+			[]string{"c1", "c2"},
+			[]string{"c1", "c2"},
+		},
+		{
+			"Chain",
+			[]Instance{newFakeInstance("app", "db", ""), newFakeInstance("db", "storage", ""), newFakeInstance("storage", "", "")},
+			[]string{"storage", "db", "app"},
+			[]string{"app", "db", "storage"},
+		},
+		{
+			"Diamond",
+			[]Instance{newFakeInstance("app", "db,cache", ""), newFakeInstance("db", "storage", ""), newFakeInstance("cache", "storage", ""), newFakeInstance("storage", "", "")},
+			[]string{"storage", "db", "cache", "app"},
+			[]string{"app", "db", "cache", "storage"},
+		},
+		{
+			"Missing dependency",
+			[]Instance{newFakeInstance("app", "missing", ""), newFakeInstance("db", "", "")},
+			[]string{"app", "db"},
+			[]string{"app", "db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStart, instanceNames(StartOrder(tt.instances)))
+			assert.Equal(t, tt.wantStop, instanceNames(StopOrder(tt.instances)))
+		})
+	}
+}
+
+func TestAutostartOrder(t *testing.T) {
+	autoStart := func(inst Instance) bool {
+		return inst.ExpandedConfig()["boot.autostart"] == "true"
+	}
+
+	tests := []struct {
+		name      string
+		instances []Instance
+		want      []string
+	}{
+		{
+			"Only autostart instances",
+			[]Instance{newFakeInstance("c1", "", "true"), newFakeInstance("c2", "", "false")},
+			[]string{"c1"},
+		},
+		{
+			"Dependency not set to autostart",
+			[]Instance{newFakeInstance("app", "db", "true"), newFakeInstance("db", "", "false"), newFakeInstance("other", "", "false")},
+			[]string{"db", "app"},
+		},
+		{
+			"Indirect dependency not set to autostart",
+			[]Instance{newFakeInstance("app", "db", "true"), newFakeInstance("db", "storage", "false"), newFakeInstance("storage", "", "")},
+			[]string{"storage", "db", "app"},
+		},
+		{
+			"Dependency of an instance not started",
+			[]Instance{newFakeInstance("app", "db", "false"), newFakeInstance("db", "", "false")},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, instanceNames(AutostartOrder(tt.instances, autoStart)))
+		})
+	}
+}
@@ -235,6 +235,11 @@ func lxcCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operatio
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = instance.ValidDependencies(s, d.project.Name, d.name, d.expandedConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid dependencies: %w", err)
+		}
 	}
 
 	_, rootDiskDevice, err := d.getRootDiskDevice()
@@ -3293,6 +3298,11 @@ func (d *lxc) onStopNS(args map[string]string) error {
 		return err
 	}
 
+	// Record the resource usage of the container while its cgroup and devices are still around.
+	if instance.RecordUsage != nil {
+		instance.RecordUsage(d)
+	}
+
 	// Clean up devices.
 	d.cleanupDevices(false, netns)
 
@@ -4767,6 +4777,12 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded devices: %w", err)
 		}
 
+		// Check that the dependencies don't introduce a cycle.
+		err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+		if err != nil {
+			return fmt.Errorf("Invalid dependencies: %w", err)
+		}
+
 		// Validate root device
 		_, oldRootDev, oldErr := internalInstance.GetRootDiskDevice(oldExpandedDevices.CloneNative())
 		_, newRootDev, newErr := internalInstance.GetRootDiskDevice(d.expandedDevices.CloneNative())
@@ -5691,6 +5707,9 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 		}
 	}
 
+	// Offer to compress the migration streams.
+	offerHeader.Compression = localMigration.CompressionTypes(d.state.GlobalConfig.MigrationCompression())
+
 	// Send offer to target.
 	d.logger.Debug("Sending migration offer to target")
 	err = args.ControlSend(offerHeader)
@@ -5720,6 +5739,24 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 		return err
 	}
 
+	// Compress and rate limit the migration streams as negotiated with the target.
+	compression := localMigration.MatchCompression(respHeader.GetCompression())
+	bandwidthLimit := localMigration.BandwidthLimit(d.ExpandedConfig()["migration.bandwidth_limit"], pool.Driver().Config()["migration.bandwidth_limit"])
+
+	filesystemConn, err = localMigration.NewStreamConn(filesystemConn, compression, bandwidthLimit)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	if stateConn != nil {
+		stateConn, err = localMigration.NewStreamConn(stateConn, compression, bandwidthLimit)
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	volSourceArgs := &localMigration.VolumeSourceArgs{
 		IndexHeaderVersion: respHeader.GetIndexHeaderVersion(), // Enable index header frame if supported.
 		Name:               d.Name(),
@@ -6298,6 +6335,12 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	// Add CRIU info to response.
 	respHeader.Criu = criuType
 
+	// Pick the compression of the migration streams.
+	compression := localMigration.MatchCompression(offerHeader.GetCompression())
+	if compression != "" {
+		respHeader.Compression = []string{compression}
+	}
+
 	if args.Refresh {
 		// Get the remote snapshots on the source.
 		sourceSnapshots := offerHeader.GetSnapshots()
@@ -6370,6 +6413,18 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 
 	d.logger.Debug("Sent migration response to source")
 
+	filesystemConn, err = localMigration.NewStreamConn(filesystemConn, compression, 0)
+	if err != nil {
+		return err
+	}
+
+	if stateConn != nil {
+		stateConn, err = localMigration.NewStreamConn(stateConn, compression, 0)
+		if err != nil {
+			return err
+		}
+	}
+
 	srcIdmap := &idmap.Set{}
 	for _, idmapSet := range offerHeader.Idmap {
 		e := idmap.Entry{
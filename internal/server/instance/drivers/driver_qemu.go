@@ -120,6 +120,48 @@ const qemuSparseUSBPorts = 8
 
 var errQemuAgentOffline = fmt.Errorf("VM agent isn't currently running")
 
+// qemuCrashDumpHeadroom is the free space required on top of the guest memory size to write a crash dump.
+const qemuCrashDumpHeadroom = 64 * 1024 * 1024
+
+// qemuCrashFreeSpace returns the space available to write a crash dump at path, can be replaced in tests.
+var qemuCrashFreeSpace = func(path string) (uint64, error) {
+	var statfs unix.Statfs_t
+
+	err := unix.Statfs(path, &statfs)
+	if err != nil {
+		return 0, err
+	}
+
+	return statfs.Bavail * uint64(statfs.Bsize), nil
+}
+
+// qemuCrashDump writes the guest memory of a crashed instance and qemuCrashAction resets or powers it off
+// through its monitor, both can be replaced in tests.
+var (
+	qemuCrashDump   func(d *qemu, w *os.File) error
+	qemuCrashAction func(d *qemu, action string) error
+)
+
+func init() {
+	// Set here as they lead back to the crash handling through the monitor event handler.
+	qemuCrashDump = func(d *qemu, w *os.File) error {
+		return d.DumpGuestMemory(w, "elf")
+	}
+
+	qemuCrashAction = func(d *qemu, action string) error {
+		monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+		if err != nil {
+			return err
+		}
+
+		if action == "poweroff" {
+			return monitor.Quit()
+		}
+
+		return monitor.Reset()
+	}
+}
+
 type monitorHook func(m *qmp.Monitor) error
 
 // qemuLoad creates a Qemu instance from the supplied InstanceArgs.
@@ -266,6 +308,11 @@ func qemuCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operati
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = instance.ValidDependencies(s, d.project.Name, d.name, d.expandedConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid dependencies: %w", err)
+		}
 	}
 
 	// Retrieve the instance's storage pool.
@@ -403,7 +450,7 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 	state := d.state
 
 	return func(event string, data map[string]any) {
-		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted}, event) {
+		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted, qmp.EventWatchdog, qmp.EventGuestPanicked}, event) {
 			return // Don't bother loading the instance from DB if we aren't going to handle the event.
 		}
 
@@ -438,8 +485,9 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 			}
 		} else if event == qmp.EventVMShutdown {
 			target := "stop"
+			// Resets requested by the guest or by onCrash lead to a shutdown as reboots are handled by us.
 			entry, ok := data["reason"]
-			if ok && entry == "guest-reset" {
+			if ok && (entry == "guest-reset" || entry == "host-qmp-system-reset") {
 				target = "reboot"
 			}
 
@@ -454,6 +502,28 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				d.logger.Error("Failed to cleanly stop instance", logger.Ctx{"err": err})
 				return
 			}
+		} else if event == qmp.EventWatchdog || event == qmp.EventGuestPanicked {
+			// Only handle the events for which QEMU paused the guest.
+			if data["action"] != "pause" {
+				return
+			}
+
+			trigger := "watchdog"
+			if event == qmp.EventGuestPanicked {
+				trigger = "pvpanic"
+			}
+
+			// Keep the guest paused for investigation if the panic handling wasn't requested.
+			if !util.IsTrue(d.expandedConfig["security."+trigger]) {
+				d.logger.Warn("Instance paused following a crash", logger.Ctx{"trigger": trigger})
+				return
+			}
+
+			err = d.onCrash(trigger)
+			if err != nil {
+				d.logger.Error("Failed to handle instance crash", logger.Ctx{"trigger": trigger, "err": err})
+				return
+			}
 		}
 	}
 }
@@ -590,6 +660,97 @@ func (d *qemu) pidWait(timeout time.Duration) bool {
 	return true
 }
 
+// onCrash is run when QEMU paused the instance following a watchdog expiry or a guest panic.
+// It applies the action configured for the trigger, either "watchdog" or "pvpanic".
+func (d *qemu) onCrash(trigger string) error {
+	action := d.expandedConfig["security."+trigger+".action"]
+	if action == "" {
+		action = "reset"
+	}
+
+	// Avoid resetting a guest crashing in a loop, power it off instead.
+	if action != "poweroff" && util.IsTrue(d.expandedConfig["boot.autorestart"]) && !d.shouldAutoRestart() {
+		d.logger.Warn("Instance crashed too many times, powering it off", logger.Ctx{"trigger": trigger})
+		action = "poweroff"
+	}
+
+	d.logger.Warn("Instance crashed", logger.Ctx{"trigger": trigger, "action": action})
+
+	ctx := map[string]any{
+		"trigger": trigger,
+		"action":  action,
+	}
+
+	if action == "dump" {
+		dumpName, err := d.dumpCrash()
+		if err != nil {
+			// Still reset the instance so that it doesn't remain unavailable.
+			d.logger.Error("Failed dumping guest memory", logger.Ctx{"err": err})
+		} else {
+			ctx["dump"] = dumpName
+		}
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceCrashed.Event(d, ctx))
+
+	// Both lead to QEMU exiting, the event handler then either restarts the instance (reset) or applies the
+	// usual handling of an instance initiated shutdown (poweroff), including boot.autorestart.
+	return qemuCrashAction(d, action)
+}
+
+// dumpCrash writes the guest memory to a log file, replacing any previous dump, and returns the file name.
+// The dump is only written when the log directory has room for the whole guest memory.
+func (d *qemu) dumpCrash() (string, error) {
+	oldDumps, err := filepath.Glob(filepath.Join(d.LogPath(), "crash_*.dump"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, oldDump := range oldDumps {
+		err = os.Remove(oldDump)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	memSize := d.expandedConfig["limits.memory"]
+	if memSize == "" {
+		memSize = qemudefault.MemSize
+	}
+
+	memSizeBytes, err := ParseMemoryStr(memSize)
+	if err != nil {
+		return "", fmt.Errorf("limits.memory invalid: %w", err)
+	}
+
+	freeSpace, err := qemuCrashFreeSpace(d.LogPath())
+	if err != nil {
+		return "", fmt.Errorf("Failed checking free space in %q: %w", d.LogPath(), err)
+	}
+
+	if freeSpace < uint64(memSizeBytes)+qemuCrashDumpHeadroom {
+		return "", fmt.Errorf("Not enough free space in %q for a %s memory dump (%s available)", d.LogPath(), units.GetByteSizeStringIEC(memSizeBytes, 2), units.GetByteSizeStringIEC(int64(freeSpace), 2))
+	}
+
+	dumpName := fmt.Sprintf("crash_%s.dump", time.Now().UTC().Format("20060102150405"))
+	dumpPath := filepath.Join(d.LogPath(), dumpName)
+	dumpFile, err := os.OpenFile(dumpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = dumpFile.Close() }()
+
+	err = qemuCrashDump(d, dumpFile)
+	if err != nil {
+		// Don't leave a partial dump behind.
+		_ = os.Remove(dumpPath)
+		return "", err
+	}
+
+	return dumpName, nil
+}
+
 // onStop is run when the instance stops.
 func (d *qemu) onStop(target string) error {
 	d.logger.Debug("onStop hook started", logger.Ctx{"target": target})
@@ -717,6 +878,11 @@ func (d *qemu) Shutdown(timeout time.Duration) error {
 		return err
 	}
 
+	// Record the resource usage of the instance while it can still be queried.
+	if instance.RecordUsage != nil {
+		instance.RecordUsage(d)
+	}
+
 	// Setup a new operation.
 	// Allow inheriting of ongoing restart operation (we are called from restartCommon).
 	// Allow reuse when creating a new stop operation. This allows the Stop() function to inherit operation.
@@ -1958,6 +2124,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"shutdown": "poweroff",
 		"reboot":   "shutdown", // Don't reset on reboot. Let us handle reboots.
 		"panic":    "pause",    // Pause on panics to allow investigation.
+		"watchdog": "pause",    // Pause on watchdog expiry and let us apply the configured action.
 	}
 
 	err = monitor.SetAction(actions)
@@ -2179,6 +2346,20 @@ func (d *qemu) advertiseVsockAddress() error {
 	return nil
 }
 
+// AgentRunning returns whether the VM agent is currently running.
+func (d *qemu) AgentRunning() bool {
+	if !d.IsRunning() {
+		return false
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return false
+	}
+
+	return monitor.AgenStarted()
+}
+
 // AgentCertificate returns the server certificate of the agent.
 func (d *qemu) AgentCertificate() *x509.Certificate {
 	agentCert := filepath.Join(d.Path(), "config", "agent.crt")
@@ -3497,6 +3678,33 @@ func (d *qemu) generateQemuConfig(machineDefinition string, cpuInfo *cpuTopology
 		conf = append(conf, qemuIOMMU(&iommuOpts, isWindows)...)
 	}
 
+	// Add watchdog.
+	if util.IsTrue(d.expandedConfig["security.watchdog"]) {
+		devBus, devAddr, multi := bus.allocateDirect()
+		watchdogOpts := qemuDevOpts{
+			busName:       bus.name,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		}
+
+		conf = append(conf, qemuWatchdog(&watchdogOpts)...)
+	}
+
+	// Add panic notification device (on x86_64 it's an ISA device and doesn't need a PCI address).
+	if util.IsTrue(d.expandedConfig["security.pvpanic"]) {
+		pvpanicOpts := qemuPVPanicOpts{
+			dev:          qemuDevOpts{busName: bus.name},
+			architecture: d.architecture,
+		}
+
+		if d.architecture != osarch.ARCH_64BIT_INTEL_X86 {
+			pvpanicOpts.dev.devBus, pvpanicOpts.dev.devAddr, pvpanicOpts.dev.multifunction = bus.allocateDirect()
+		}
+
+		conf = append(conf, qemuPVPanic(&pvpanicOpts)...)
+	}
+
 	// Now add the fixed set of devices. The multi-function groups used for these fixed internal devices are
 	// specifically chosen to ensure that we consume exactly 4 PCI bus ports (on PCIe bus). This ensures that
 	// the first user device NIC added will use the 5th PCI bus port and will be consistently named enp5s0
@@ -5107,6 +5315,11 @@ func (d *qemu) Stop(stateful bool) error {
 	// Attempt to save the console log from ring buffer before the instance is stopped. Must be run prior to creating the operation lock.
 	_, _ = d.ConsoleLog()
 
+	// Record the resource usage of the instance while it can still be queried.
+	if instance.RecordUsage != nil && statusCode != api.Error {
+		instance.RecordUsage(d)
+	}
+
 	// Setup a new operation.
 	// Allow inheriting of ongoing restart or restore operation (we are called from restartCommon and Restore).
 	// Don't allow reuse when creating a new stop operation. This prevents other operations from interfering.
@@ -5251,6 +5464,83 @@ func (d *qemu) IsPrivileged() bool {
 	return false
 }
 
+// Quiesce asks the agent to flush and freeze the guest filesystems when snapshots.quiesce is enabled.
+// The guest filesystems are automatically thawed after the timeout.
+// The returned function thaws them again and must always be called. It fails if they were thawed early.
+// Nothing is frozen if the instance isn't running or if the agent can't be reached.
+func (d *qemu) Quiesce(timeout time.Duration) (func() error, error) {
+	if !d.IsRunning() || util.IsFalseOrEmpty(d.expandedConfig["snapshots.quiesce"]) {
+		return func() error { return nil }, nil
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		if errors.Is(err, errQemuAgentOffline) {
+			d.logger.Warn("Skipping guest filesystem freeze as the agent isn't running")
+			return func() error { return nil }, nil
+		}
+
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		d.logger.Error("Failed to connect to the agent", logger.Ctx{"err": err})
+		return nil, fmt.Errorf("Failed to connect to the agent")
+	}
+
+	thaw, err := qemuAgentFreeze(agent, timeout)
+	if err != nil {
+		agent.Disconnect()
+
+		// Older agents don't support freezing the filesystems.
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			d.logger.Warn("Skipping guest filesystem freeze as the agent doesn't support it")
+			return func() error { return nil }, nil
+		}
+
+		return nil, err
+	}
+
+	d.logger.Debug("Froze guest filesystems")
+
+	return func() error {
+		defer agent.Disconnect()
+
+		err := thaw()
+		if err != nil {
+			return err
+		}
+
+		d.logger.Debug("Thawed guest filesystems")
+
+		return nil
+	}, nil
+}
+
+// qemuAgentFreeze asks the agent to freeze the guest filesystems for at most the given timeout (rounded up to the
+// second) and returns a function thawing them.
+func qemuAgentFreeze(agent incus.InstanceServer, timeout time.Duration) (func() error, error) {
+	req := agentAPI.FreezePost{Timeout: int((timeout + time.Second - 1) / time.Second)}
+	if req.Timeout < 1 {
+		req.Timeout = 1
+	}
+
+	_, _, err := agent.RawQuery("POST", "/1.0/freeze", req, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed freezing guest filesystems: %w", err)
+	}
+
+	return func() error {
+		_, _, err := agent.RawQuery("DELETE", "/1.0/freeze", nil, "")
+		if err != nil {
+			return fmt.Errorf("Failed thawing guest filesystems: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
 // snapshot creates a snapshot of the instance.
 func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 	var err error
@@ -5853,6 +6143,12 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded devices: %w", err)
 		}
 
+		// Check that the dependencies don't introduce a cycle.
+		err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+		if err != nil {
+			return fmt.Errorf("Invalid dependencies: %w", err)
+		}
+
 		// Validate root device
 		_, oldRootDev, oldErr := internalInstance.GetRootDiskDevice(oldExpandedDevices.CloneNative())
 		_, newRootDev, newErr := internalInstance.GetRootDiskDevice(d.expandedDevices.CloneNative())
@@ -6888,6 +7184,9 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 		offerHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
 	}
 
+	// Offer to compress the migration streams.
+	offerHeader.Compression = localMigration.CompressionTypes(d.state.GlobalConfig.MigrationCompression())
+
 	// Send offer to target.
 	d.logger.Debug("Sending migration offer to target")
 	err = args.ControlSend(offerHeader)
@@ -6917,6 +7216,16 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 		return err
 	}
 
+	// Compress and rate limit the migration streams as negotiated with the target.
+	compression := localMigration.MatchCompression(respHeader.GetCompression())
+	bandwidthLimit := localMigration.BandwidthLimit(d.expandedConfig["migration.bandwidth_limit"], pool.Driver().Config()["migration.bandwidth_limit"])
+
+	filesystemConn, err = localMigration.NewStreamConn(filesystemConn, compression, bandwidthLimit)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	volSourceArgs := &localMigration.VolumeSourceArgs{
 		IndexHeaderVersion: respHeader.GetIndexHeaderVersion(), // Enable index header frame if supported.
 		Name:               d.Name(),
@@ -6954,6 +7263,12 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 			op.Done(err)
 			return err
 		}
+
+		stateConn, err = localMigration.NewStreamConn(stateConn, compression, bandwidthLimit)
+		if err != nil {
+			op.Done(err)
+			return err
+		}
 	}
 
 	g, ctx := errgroup.WithContext(context.Background())
@@ -7559,6 +7874,12 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		useStateConn = true
 	}
 
+	// Pick the compression of the migration streams.
+	compression := localMigration.MatchCompression(offerHeader.GetCompression())
+	if compression != "" {
+		respHeader.Compression = []string{compression}
+	}
+
 	// Send response to source.
 	d.logger.Debug("Sending migration response to source")
 	err = args.ControlSend(respHeader)
@@ -7568,6 +7889,11 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 
 	d.logger.Debug("Sent migration response to source")
 
+	filesystemConn, err = localMigration.NewStreamConn(filesystemConn, compression, 0)
+	if err != nil {
+		return err
+	}
+
 	// Establish state transfer connection if needed.
 	var stateConn io.ReadWriteCloser
 	if args.Live && useStateConn {
@@ -7575,6 +7901,11 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		if err != nil {
 			return err
 		}
+
+		stateConn, err = localMigration.NewStreamConn(stateConn, compression, 0)
+		if err != nil {
+			return err
+		}
 	}
 
 	reverter := revert.New()
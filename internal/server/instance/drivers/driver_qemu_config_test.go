@@ -286,6 +286,66 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_watchdog", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuDevOpts
+			expected string
+		}{{
+			qemuDevOpts{"pcie", "pcie.0", "2.0", false},
+			`# Watchdog
+			[device "qemu_watchdog"]
+			driver = "i6300esb"
+			bus = "pcie.0"
+			addr = "2.0"
+			`,
+		}, {
+			qemuDevOpts{"ccw", "", "", false},
+			`# Watchdog
+			[device "qemu_watchdog"]
+			driver = "diag288"
+			`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuWatchdog(&tc.opts))
+		}
+	})
+
+	t.Run("qemu_pvpanic", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuPVPanicOpts
+			expected string
+		}{{
+			qemuPVPanicOpts{
+				dev:          qemuDevOpts{busName: "pcie"},
+				architecture: osarch.ARCH_64BIT_INTEL_X86,
+			},
+			`# Panic notification
+			[device "qemu_pvpanic"]
+			driver = "pvpanic"
+			`,
+		}, {
+			qemuPVPanicOpts{
+				dev:          qemuDevOpts{"pcie", "pcie.0", "3.0", false},
+				architecture: osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN,
+			},
+			`# Panic notification
+			[device "qemu_pvpanic"]
+			driver = "pvpanic-pci"
+			bus = "pcie.0"
+			addr = "3.0"
+			`,
+		}, {
+			qemuPVPanicOpts{
+				dev:          qemuDevOpts{busName: "ccw"},
+				architecture: osarch.ARCH_64BIT_S390_BIG_ENDIAN,
+			},
+			``,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuPVPanic(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_vsock", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuVsockOpts
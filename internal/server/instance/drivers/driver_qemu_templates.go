@@ -353,6 +353,48 @@ func qemuIOMMU(opts *qemuDevOpts, isWindows bool) []cfg.Section {
 	}}
 }
 
+func qemuWatchdog(opts *qemuDevOpts) []cfg.Section {
+	entriesOpts := qemuDevEntriesOpts{
+		dev:     *opts,
+		pciName: "i6300esb",
+		ccwName: "diag288",
+	}
+
+	return []cfg.Section{{
+		Name:    `device "qemu_watchdog"`,
+		Comment: "Watchdog",
+		Entries: qemuDeviceEntries(&entriesOpts),
+	}}
+}
+
+type qemuPVPanicOpts struct {
+	dev          qemuDevOpts
+	architecture int
+}
+
+func qemuPVPanic(opts *qemuPVPanicOpts) []cfg.Section {
+	// s390x guests report panics without needing an extra device.
+	if opts.dev.busName == "ccw" {
+		return nil
+	}
+
+	var entries []cfg.Entry
+	if opts.architecture == osarch.ARCH_64BIT_INTEL_X86 {
+		entries = []cfg.Entry{{Key: "driver", Value: "pvpanic"}}
+	} else {
+		entries = qemuDeviceEntries(&qemuDevEntriesOpts{
+			dev:     opts.dev,
+			pciName: "pvpanic-pci",
+		})
+	}
+
+	return []cfg.Section{{
+		Name:    `device "qemu_pvpanic"`,
+		Comment: "Panic notification",
+		Entries: entries,
+	}}
+}
+
 type qemuSevOpts struct {
 	cbitpos         int
 	reducedPhysBits int
@@ -0,0 +1,210 @@
+package drivers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/events"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// fakeFreezeAgent serves the freeze endpoint of the agent.
+type fakeFreezeAgent struct {
+	timeouts   []int
+	postStatus int
+	thawStatus int
+}
+
+func (a *fakeFreezeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/1.0/freeze" {
+		_ = response.NotFound(nil).Render(w)
+		return
+	}
+
+	status := a.thawStatus
+	if r.Method == http.MethodPost {
+		req := agentAPI.FreezePost{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		a.timeouts = append(a.timeouts, req.Timeout)
+		status = a.postStatus
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		_ = response.NotFound(nil).Render(w)
+	case http.StatusConflict:
+		_ = response.Conflict(errors.New("Filesystems were thawed after the freeze timeout")).Render(w)
+	default:
+		_ = response.EmptySyncResponse.Render(w)
+	}
+}
+
+func connectFakeFreezeAgent(t *testing.T, agent *fakeFreezeAgent) incus.InstanceServer {
+	server := httptest.NewTLSServer(agent)
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+				return net.Dial("tcp", server.Listener.Addr().String())
+			},
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	conn, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	require.NoError(t, err)
+
+	return conn
+}
+
+func TestQemuAgentFreeze(t *testing.T) {
+	agent := &fakeFreezeAgent{}
+	conn := connectFakeFreezeAgent(t, agent)
+
+	// The timeout is rounded up to the second.
+	for _, timeout := range []time.Duration{time.Minute, 1500 * time.Millisecond, 0} {
+		thaw, err := qemuAgentFreeze(conn, timeout)
+		require.NoError(t, err)
+		require.NoError(t, thaw())
+	}
+
+	assert.Equal(t, []int{60, 2, 1}, agent.timeouts)
+}
+
+func TestQemuAgentFreezeExpired(t *testing.T) {
+	agent := &fakeFreezeAgent{thawStatus: http.StatusConflict}
+	conn := connectFakeFreezeAgent(t, agent)
+
+	thaw, err := qemuAgentFreeze(conn, time.Second)
+	require.NoError(t, err)
+
+	// The filesystems were thawed by the agent before the end of the copy.
+	err = thaw()
+	require.Error(t, err)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+}
+
+func TestQemuAgentFreezeUnsupported(t *testing.T) {
+	agent := &fakeFreezeAgent{postStatus: http.StatusNotFound}
+	conn := connectFakeFreezeAgent(t, agent)
+
+	// Older agents are detected so that the freeze can be skipped.
+	_, err := qemuAgentFreeze(conn, time.Second)
+	require.Error(t, err)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+}
+
+func TestQemuOnCrash(t *testing.T) {
+	t.Setenv("INCUS_DIR", t.TempDir())
+
+	// Record what would be done to QEMU.
+	actions := []string{}
+	dumps := 0
+	freeSpace := uint64(8 * 1024 * 1024 * 1024)
+
+	oldAction, oldDump, oldFreeSpace := qemuCrashAction, qemuCrashDump, qemuCrashFreeSpace
+	t.Cleanup(func() { qemuCrashAction, qemuCrashDump, qemuCrashFreeSpace = oldAction, oldDump, oldFreeSpace })
+
+	qemuCrashAction = func(d *qemu, action string) error {
+		actions = append(actions, action)
+		return nil
+	}
+
+	qemuCrashDump = func(d *qemu, w *os.File) error {
+		dumps++
+		_, err := w.WriteString("ELF")
+		return err
+	}
+
+	qemuCrashFreeSpace = func(path string) (uint64, error) {
+		return freeSpace, nil
+	}
+
+	crashes := []map[string]any{}
+	eventServer := events.NewServer(false, false, func(event api.Event) {
+		lifecycleEvent := api.EventLifecycle{}
+		require.NoError(t, json.Unmarshal(event.Metadata, &lifecycleEvent))
+		assert.Equal(t, api.EventLifecycleInstanceCrashed, lifecycleEvent.Action)
+		crashes = append(crashes, lifecycleEvent.Context)
+	})
+
+	newVM := func(id int, config map[string]string) *qemu {
+		d := &qemu{}
+		d.id = id
+		d.name = fmt.Sprintf("vm%d", id)
+		d.project = api.Project{Name: "default"}
+		d.expandedConfig = config
+		d.logger = logger.AddContext(logger.Ctx{"instance": d.name})
+		d.state = &state.State{Events: eventServer}
+		require.NoError(t, os.MkdirAll(d.LogPath(), 0o700))
+
+		return d
+	}
+
+	// The configured action is applied, resetting the instance by default.
+	d := newVM(1, map[string]string{"security.pvpanic.action": "poweroff"})
+	require.NoError(t, d.onCrash("watchdog"))
+	require.NoError(t, d.onCrash("pvpanic"))
+	assert.Equal(t, []string{"reset", "poweroff"}, actions)
+	require.Len(t, crashes, 2)
+	assert.Equal(t, map[string]any{"trigger": "watchdog", "action": "reset"}, crashes[0])
+	assert.Equal(t, map[string]any{"trigger": "pvpanic", "action": "poweroff"}, crashes[1])
+
+	// Dumps replace the previous one and are reported in the event.
+	actions = []string{}
+	crashes = []map[string]any{}
+	d = newVM(2, map[string]string{"security.watchdog.action": "dump", "limits.memory": "1GiB"})
+	require.NoError(t, os.WriteFile(filepath.Join(d.LogPath(), "crash_20240101000000.dump"), []byte("ELF"), 0o600))
+
+	require.NoError(t, d.onCrash("watchdog"))
+	assert.Equal(t, []string{"dump"}, actions)
+	assert.Equal(t, 1, dumps)
+	require.Len(t, crashes, 1)
+
+	dumpFiles, err := filepath.Glob(filepath.Join(d.LogPath(), "crash_*.dump"))
+	require.NoError(t, err)
+	require.Len(t, dumpFiles, 1)
+	assert.Equal(t, filepath.Base(dumpFiles[0]), crashes[0]["dump"])
+
+	// Without enough space for the guest memory, the instance is reset without a dump.
+	freeSpace = 1024 * 1024 * 1024
+	crashes = []map[string]any{}
+
+	require.NoError(t, d.onCrash("watchdog"))
+	assert.Equal(t, 1, dumps)
+	require.Len(t, crashes, 1)
+	assert.NotContains(t, crashes[0], "dump")
+
+	dumpFiles, err = filepath.Glob(filepath.Join(d.LogPath(), "crash_*.dump"))
+	require.NoError(t, err)
+	assert.Empty(t, dumpFiles)
+
+	// Instances crashing in a loop get powered off.
+	actions = []string{}
+	d = newVM(3, map[string]string{"boot.autorestart": "true"})
+	for range 11 {
+		require.NoError(t, d.onCrash("pvpanic"))
+	}
+
+	assert.Equal(t, "reset", actions[9])
+	assert.Equal(t, "poweroff", actions[10])
+}
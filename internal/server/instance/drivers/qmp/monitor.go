@@ -37,6 +37,12 @@ var EventVMShutdownReasonDisconnect = "disconnect"
 // EventDiskEjected is used to indicate that a disk device was ejected by the guest.
 var EventDiskEjected = "DEVICE_TRAY_MOVED"
 
+// EventWatchdog is the event sent when the guest watchdog expires.
+var EventWatchdog = "WATCHDOG"
+
+// EventGuestPanicked is the event sent when the guest reports a panic.
+var EventGuestPanicked = "GUEST_PANICKED"
+
 // ExcludedCommands is used to filter verbose commands from the QMP logs.
 var ExcludedCommands = []string{"ringbuf-read"}
 
@@ -195,9 +195,11 @@ type VM interface {
 	Instance
 
 	AgentCertificate() *x509.Certificate
+	AgentRunning() bool
 	ConsoleLog() (string, error)
 	ConsoleScreenshot(screenshotFile *os.File) error
 	DumpGuestMemory(w *os.File, format string) error
+	Quiesce(timeout time.Duration) (func() error, error)
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
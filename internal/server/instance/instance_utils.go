@@ -34,6 +34,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/seccomp"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/internal/server/sys"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
@@ -58,6 +59,10 @@ var Load func(s *state.State, args db.InstanceArgs, p api.Project) (Instance, er
 // Returns a revert fail function that can be used to undo this function if a subsequent step fails.
 var Create func(s *state.State, args db.InstanceArgs, p api.Project, op *operations.Operation) (Instance, revert.Hook, error)
 
+// RecordUsage is linked from the daemon's usage accounting to record the resource usage of an instance
+// which is about to stop, while its usage counters are still available.
+var RecordUsage func(inst Instance)
+
 func exclusiveConfigKeys(key1 string, key2 string, config map[string]string) (val string, ok bool, err error) {
 	if config[key1] != "" && config[key2] != "" {
 		return "", false, fmt.Errorf("Mutually exclusive keys %s and %s are set", key1, key2)
@@ -503,7 +508,25 @@ func BackupLoadByName(s *state.State, project, name string) (*backup.InstanceBac
 		return nil, err
 	}
 
-	return backup.NewInstanceBackup(s, instance, args.ID, name, args.CreationDate, args.ExpiryDate, args.InstanceOnly, args.OptimizedStorage), nil
+	// Load the target the backup is stored on
+	target, err := BackupTargetLoad(s, args.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return backup.NewInstanceBackup(s, instance, args.ID, name, args.CreationDate, args.ExpiryDate, args.InstanceOnly, args.OptimizedStorage, target), nil
+}
+
+// BackupTargetLoad returns the backup target with the given name.
+func BackupTargetLoad(s *state.State, name string) (backup.Target, error) {
+	switch name {
+	case backup.TargetLocal:
+		return backup.NewLocalTarget(), nil
+	case backup.TargetS3:
+		return s3.NewBackupTarget(s.GlobalConfig.BackupsS3()), nil
+	}
+
+	return nil, fmt.Errorf("Unknown backup target %q", name)
 }
 
 // ResolveImage takes an instance source and returns a hash suitable for instance creation or download.
@@ -647,6 +670,7 @@ func SuitableArchitectures(ctx context.Context, s *state.State, tx *db.ClusterTx
 					Proxy:         s.Proxy,
 					CachePath:     s.OS.CacheDir,
 					CacheExpiry:   time.Hour,
+					TrustedKeys:   req.Source.TrustedKeys,
 				})
 				if err != nil {
 					return nil, err
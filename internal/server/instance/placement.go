@@ -0,0 +1,209 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Placement policies for the instances sharing a placement group.
+const (
+	PlacementPolicyAffinity         = "affinity"
+	PlacementPolicyAntiAffinity     = "anti-affinity"
+	PlacementPolicySoftAffinity     = "soft-affinity"
+	PlacementPolicySoftAntiAffinity = "soft-anti-affinity"
+)
+
+// placementGroupMembers returns the number of instances of the placement group on each cluster member, not
+// counting the instance itself.
+func placementGroupMembers(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, group string) (map[string]int, error) {
+	members := map[string]int{}
+
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+		if inst.Snapshot || inst.Name == instanceName {
+			return nil
+		}
+
+		if db.ExpandInstanceConfig(inst.Config, inst.Profiles)["placement.group"] != group {
+			return nil
+		}
+
+		members[inst.Node]++
+
+		return nil
+	}, cluster.InstanceFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instances of placement group %q: %w", group, err)
+	}
+
+	return members, nil
+}
+
+// placementScore returns how much the placement policy favors a cluster member hosting the given number of
+// instances of the placement group, higher being better.
+func placementScore(policy string, count int) int {
+	if policy == PlacementPolicyAffinity || policy == PlacementPolicySoftAffinity {
+		return count
+	}
+
+	return -count
+}
+
+// placementAllowed returns whether a hard placement policy allows a cluster member hosting the given number of
+// instances of the placement group, with total instances of the group across the cluster.
+func placementAllowed(policy string, count int, total int) bool {
+	switch policy {
+	case PlacementPolicyAffinity:
+		return total == 0 || count > 0
+	case PlacementPolicySoftAffinity, PlacementPolicySoftAntiAffinity:
+		return true
+	default:
+		return count == 0
+	}
+}
+
+// PlacementPending tracks the instances of placement groups which are being moved to cluster members but aren't
+// recorded on them in the database yet, so that concurrent placements take each other into account.
+type PlacementPending struct {
+	mu      sync.Mutex
+	members map[string]map[string]int
+}
+
+// NewPlacementPending returns a new tracker of pending placements.
+func NewPlacementPending() *PlacementPending {
+	return &PlacementPending{members: map[string]map[string]int{}}
+}
+
+// Add records an instance with the given configuration as being placed on a cluster member.
+// The returned function must be called once the instance is recorded on the member in the database.
+func (p *PlacementPending) Add(projectName string, config map[string]string, member string) func() {
+	group := config["placement.group"]
+	if p == nil || group == "" {
+		return func() {}
+	}
+
+	key := projectName + "/" + group
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.members[key] == nil {
+		p.members[key] = map[string]int{}
+	}
+
+	p.members[key][member]++
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.members[key][member]--
+		if p.members[key][member] <= 0 {
+			delete(p.members[key], member)
+		}
+
+		if len(p.members[key]) == 0 {
+			delete(p.members, key)
+		}
+	}
+}
+
+// addTo adds the pending placements of the placement group to the given per member counts.
+func (p *PlacementPending) addTo(counts map[string]int, projectName string, group string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for member, count := range p.members[projectName+"/"+group] {
+		counts[member] += count
+	}
+}
+
+// placementFilter returns the candidate cluster members allowed by the placement policy given the number of instances
+// of the placement group on each member, sorted by preference (keeping the existing order otherwise).
+func placementFilter(policy string, counts map[string]int, candidates []db.NodeInfo) []db.NodeInfo {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	allowed := make([]db.NodeInfo, 0, len(candidates))
+	for _, candidate := range candidates {
+		if placementAllowed(policy, counts[candidate.Name], total) {
+			allowed = append(allowed, candidate)
+		}
+	}
+
+	slices.SortStableFunc(allowed, func(a db.NodeInfo, b db.NodeInfo) int {
+		return placementScore(policy, counts[b.Name]) - placementScore(policy, counts[a.Name])
+	})
+
+	return allowed
+}
+
+// PlacementCandidates filters and sorts the candidate cluster members according to the placement group and policy
+// of the instance configuration. Members not allowed by a hard policy are removed, returning a conflict error if
+// none is left, and the remaining members are sorted by preference (keeping the existing order otherwise).
+// The pending placements, if any, are counted along with the instances recorded in the database.
+func PlacementCandidates(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, config map[string]string, candidates []db.NodeInfo, pending *PlacementPending) ([]db.NodeInfo, error) {
+	group := config["placement.group"]
+	if group == "" || len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	policy := config["placement.policy"]
+
+	counts, err := placementGroupMembers(ctx, tx, projectName, instanceName, group)
+	if err != nil {
+		return nil, err
+	}
+
+	pending.addTo(counts, projectName, group)
+
+	allowed := placementFilter(policy, counts, candidates)
+	if len(allowed) == 0 {
+		if policy == "" {
+			policy = PlacementPolicyAntiAffinity
+		}
+
+		return nil, api.StatusErrorf(http.StatusConflict, "No suitable cluster member satisfies the %s policy of placement group %q", policy, group)
+	}
+
+	return allowed, nil
+}
+
+// PlacementAllowsMove returns whether the placement group and policy of the instance configuration allow moving it
+// from the source to the target cluster member without making its placement worse.
+func PlacementAllowsMove(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, config map[string]string, source string, target string) (bool, error) {
+	group := config["placement.group"]
+	if group == "" {
+		return true, nil
+	}
+
+	policy := config["placement.policy"]
+
+	counts, err := placementGroupMembers(ctx, tx, projectName, instanceName, group)
+	if err != nil {
+		return false, err
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	if !placementAllowed(policy, counts[target], total) {
+		return false, nil
+	}
+
+	return placementScore(policy, counts[target]) >= placementScore(policy, counts[source]), nil
+}
@@ -0,0 +1,116 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+)
+
+func TestPlacementScore(t *testing.T) {
+	tests := []struct {
+		policy string
+		count  int
+		score  int
+	}{
+		{PlacementPolicyAffinity, 3, 3},
+		{PlacementPolicySoftAffinity, 3, 3},
+		{PlacementPolicyAntiAffinity, 3, -3},
+		{PlacementPolicySoftAntiAffinity, 3, -3},
+		{"", 2, -2},
+		{PlacementPolicyAffinity, 0, 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.score, placementScore(test.policy, test.count), "policy %q with %d instances", test.policy, test.count)
+	}
+}
+
+func TestPlacementAllowed(t *testing.T) {
+	tests := []struct {
+		policy  string
+		count   int
+		total   int
+		allowed bool
+	}{
+		{PlacementPolicyAffinity, 0, 0, true},
+		{PlacementPolicyAffinity, 1, 1, true},
+		{PlacementPolicyAffinity, 0, 1, false},
+		{PlacementPolicyAntiAffinity, 0, 1, true},
+		{PlacementPolicyAntiAffinity, 1, 1, false},
+		{"", 1, 1, false},
+		{PlacementPolicySoftAffinity, 0, 1, true},
+		{PlacementPolicySoftAntiAffinity, 2, 2, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, placementAllowed(test.policy, test.count, test.total), "policy %q with %d of %d instances", test.policy, test.count, test.total)
+	}
+}
+
+func TestPlacementFilter(t *testing.T) {
+	candidates := []db.NodeInfo{{Name: "m1"}, {Name: "m2"}, {Name: "m3"}}
+
+	names := func(members []db.NodeInfo) []string {
+		result := []string{}
+		for _, member := range members {
+			result = append(result, member.Name)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		policy string
+		counts map[string]int
+		result []string
+	}{
+		{PlacementPolicyAntiAffinity, map[string]int{}, []string{"m1", "m2", "m3"}},
+		{PlacementPolicyAntiAffinity, map[string]int{"m2": 1}, []string{"m1", "m3"}},
+		{PlacementPolicyAntiAffinity, map[string]int{"m1": 1, "m2": 1, "m3": 1}, []string{}},
+		{PlacementPolicyAffinity, map[string]int{"m3": 2}, []string{"m3"}},
+		{PlacementPolicySoftAntiAffinity, map[string]int{"m1": 2, "m2": 1}, []string{"m3", "m2", "m1"}},
+		{PlacementPolicySoftAffinity, map[string]int{"m2": 1, "m3": 2}, []string{"m3", "m2", "m1"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.result, names(placementFilter(test.policy, test.counts, candidates)), "policy %q with %v", test.policy, test.counts)
+	}
+}
+
+func TestPlacementPending(t *testing.T) {
+	pending := NewPlacementPending()
+	config := map[string]string{"placement.group": "web"}
+
+	release1 := pending.Add("default", config, "m1")
+	release2 := pending.Add("default", config, "m1")
+	release3 := pending.Add("default", config, "m2")
+
+	// Instances outside of a placement group aren't tracked.
+	pending.Add("default", map[string]string{}, "m3")
+
+	counts := map[string]int{"m2": 1}
+	pending.addTo(counts, "default", "web")
+	assert.Equal(t, map[string]int{"m1": 2, "m2": 2}, counts)
+
+	// Placement groups are per project.
+	counts = map[string]int{}
+	pending.addTo(counts, "other", "web")
+	assert.Empty(t, counts)
+
+	// A concurrent anti-affinity placement avoids the members which instances are being moved to.
+	counts = map[string]int{}
+	pending.addTo(counts, "default", "web")
+	assert.Equal(t, []db.NodeInfo{{Name: "m3"}}, placementFilter(PlacementPolicyAntiAffinity, counts, []db.NodeInfo{{Name: "m1"}, {Name: "m2"}, {Name: "m3"}}))
+
+	release1()
+	release2()
+	release3()
+	assert.Empty(t, pending.members)
+
+	// A nil tracker is a no-op.
+	var none *PlacementPending
+	none.Add("default", config, "m1")()
+	none.addTo(counts, "default", "web")
+}
@@ -0,0 +1,90 @@
+package ip
+
+import (
+	"bytes"
+	"net"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// FDB represents arguments for bridge forwarding database entry manipulation.
+type FDB struct {
+	DevName string
+	MAC     net.HardwareAddr
+	Dst     net.IP
+}
+
+// Show lists the forwarding database entries with a destination, filtered by DevName and optionally MAC address.
+func (f *FDB) Show() ([]FDB, error) {
+	out, err := subprocess.RunCommand("bridge", "fdb", "show", "dev", f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFDB(out, f.DevName, f.MAC), nil
+}
+
+// Append adds a forwarding database entry, keeping any existing entry for the same MAC address.
+func (f *FDB) Append() error {
+	_, err := subprocess.RunCommand("bridge", "fdb", "append", f.MAC.String(), "dev", f.DevName, "dst", f.Dst.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a forwarding database entry.
+func (f *FDB) Delete() error {
+	_, err := subprocess.RunCommand("bridge", "fdb", "del", f.MAC.String(), "dev", f.DevName, "dst", f.Dst.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseFDB parses the output of "bridge fdb show", returning the entries with a destination.
+func parseFDB(out string, devName string, mac net.HardwareAddr) []FDB {
+	entries := []FDB{}
+
+	for _, line := range util.SplitNTrimSpace(out, "\n", -1, true) {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		entryMAC, err := net.ParseMAC(fields[0])
+		if err != nil {
+			continue
+		}
+
+		// Check entry matches desired MAC address if specified.
+		if mac != nil && !bytes.Equal(mac, entryMAC) {
+			continue
+		}
+
+		for i := 1; i < len(fields)-1; i++ {
+			if fields[i] != "dst" {
+				continue
+			}
+
+			dst := net.ParseIP(fields[i+1])
+			if dst == nil {
+				break
+			}
+
+			entries = append(entries, FDB{
+				DevName: devName,
+				MAC:     entryMAC,
+				Dst:     dst,
+			})
+
+			break
+		}
+	}
+
+	return entries
+}
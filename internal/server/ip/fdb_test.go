@@ -0,0 +1,37 @@
+package ip
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFDB(t *testing.T) {
+	out := `33:33:00:00:00:01 self permanent
+00:00:00:00:00:00 dst 192.0.2.20 self permanent
+00:00:00:00:00:00 dst 2001:db8::30 self permanent
+0a:1b:2c:3d:4e:5f dst 192.0.2.40 self
+0a:1b:2c:3d:4e:60 master br0
+`
+
+	flood, _ := net.ParseMAC("00:00:00:00:00:00")
+	other, _ := net.ParseMAC("0a:1b:2c:3d:4e:5f")
+
+	// Entries without a destination are skipped.
+	entries := parseFDB(out, "br0-vx", nil)
+	assert.Equal(t, []FDB{
+		{DevName: "br0-vx", MAC: flood, Dst: net.ParseIP("192.0.2.20")},
+		{DevName: "br0-vx", MAC: flood, Dst: net.ParseIP("2001:db8::30")},
+		{DevName: "br0-vx", MAC: other, Dst: net.ParseIP("192.0.2.40")},
+	}, entries)
+
+	// Entries are filtered by MAC address.
+	entries = parseFDB(out, "br0-vx", flood)
+	assert.Equal(t, []FDB{
+		{DevName: "br0-vx", MAC: flood, Dst: net.ParseIP("192.0.2.20")},
+		{DevName: "br0-vx", MAC: flood, Dst: net.ParseIP("2001:db8::30")},
+	}, entries)
+
+	assert.Empty(t, parseFDB("", "br0-vx", flood))
+}
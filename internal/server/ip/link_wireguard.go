@@ -0,0 +1,71 @@
+package ip
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// Wireguard represents arguments for link of type wireguard.
+type Wireguard struct {
+	Link
+	ListenPort uint64
+	Peers      []WireguardPeer
+}
+
+// WireguardPeer represents a peer of a wireguard link.
+type WireguardPeer struct {
+	PublicKey           string
+	Endpoint            string
+	AllowedIPs          []string
+	PersistentKeepalive uint64
+}
+
+// Add adds new virtual link.
+func (w *Wireguard) Add() error {
+	return w.Link.add("wireguard", nil)
+}
+
+// config generates the wireguard configuration of the link.
+func (w *Wireguard) config(privateKey string) string {
+	var sb strings.Builder
+
+	sb.WriteString("[Interface]\n")
+	sb.WriteString(fmt.Sprintf("PrivateKey = %s\n", privateKey))
+
+	if w.ListenPort > 0 {
+		sb.WriteString(fmt.Sprintf("ListenPort = %d\n", w.ListenPort))
+	}
+
+	for _, peer := range w.Peers {
+		sb.WriteString("\n[Peer]\n")
+		sb.WriteString(fmt.Sprintf("PublicKey = %s\n", peer.PublicKey))
+
+		if peer.Endpoint != "" {
+			sb.WriteString(fmt.Sprintf("Endpoint = %s\n", peer.Endpoint))
+		}
+
+		if len(peer.AllowedIPs) > 0 {
+			sb.WriteString(fmt.Sprintf("AllowedIPs = %s\n", strings.Join(peer.AllowedIPs, ", ")))
+		}
+
+		if peer.PersistentKeepalive > 0 {
+			sb.WriteString(fmt.Sprintf("PersistentKeepalive = %d\n", peer.PersistentKeepalive))
+		}
+	}
+
+	return sb.String()
+}
+
+// Configure applies the private key, listen port and peers to the link.
+// Peers which aren't in the list are removed from the link.
+func (w *Wireguard) Configure(privateKey string) error {
+	err := subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(w.config(privateKey)), nil, "wg", "syncconf", w.Name, "/dev/stdin")
+	if err != nil {
+		return fmt.Errorf("Failed configuring wireguard link %q: %w", w.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package ip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWireguardConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		wireguard Wireguard
+		want      string
+	}{
+		{
+			name:      "no peers",
+			wireguard: Wireguard{},
+			want:      "[Interface]\nPrivateKey = private\n",
+		},
+		{
+			name: "peers",
+			wireguard: Wireguard{
+				ListenPort: 51820,
+				Peers: []WireguardPeer{
+					{
+						PublicKey:           "peer1",
+						Endpoint:            "203.0.113.1:51820",
+						AllowedIPs:          []string{"10.0.1.0/24", "fd42:1::/64"},
+						PersistentKeepalive: 25,
+					},
+					{
+						PublicKey: "peer2",
+					},
+				},
+			},
+			want: `[Interface]
+PrivateKey = private
+ListenPort = 51820
+
+[Peer]
+PublicKey = peer1
+Endpoint = 203.0.113.1:51820
+AllowedIPs = 10.0.1.0/24, fd42:1::/64
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = peer2
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.wireguard.config("private"))
+		})
+	}
+}
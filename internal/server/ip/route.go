@@ -16,6 +16,7 @@ type Route struct {
 	Family  string
 	Via     string
 	VRF     string
+	Metric  string
 }
 
 // Add adds new route.
@@ -29,7 +30,11 @@ func (r *Route) Add() error {
 		cmd = append(cmd, "via", r.Via)
 	}
 
-	cmd = append(cmd, r.Route, "dev", r.DevName)
+	cmd = append(cmd, r.Route)
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
 	if r.Src != "" {
 		cmd = append(cmd, "src", r.Src)
 	}
@@ -38,6 +43,10 @@ func (r *Route) Add() error {
 		cmd = append(cmd, "proto", r.Proto)
 	}
 
+	if r.Metric != "" {
+		cmd = append(cmd, "metric", r.Metric)
+	}
+
 	if r.VRF != "" {
 		cmd = append(cmd, "vrf", r.VRF)
 	}
@@ -52,7 +61,18 @@ func (r *Route) Add() error {
 
 // Delete deletes routing table.
 func (r *Route) Delete() error {
-	cmd := []string{r.Family, "route", "delete", r.Route, "dev", r.DevName}
+	cmd := []string{r.Family, "route", "delete", r.Route}
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	if r.Proto != "" {
+		cmd = append(cmd, "proto", r.Proto)
+	}
+
+	if r.Metric != "" {
+		cmd = append(cmd, "metric", r.Metric)
+	}
 
 	if r.VRF != "" {
 		cmd = append(cmd, "vrf", r.VRF)
@@ -103,7 +123,15 @@ func (r *Route) Flush() error {
 
 // Replace changes or adds new route.
 func (r *Route) Replace(routes []string) error {
-	cmd := []string{r.Family, "route", "replace", "dev", r.DevName, "proto", r.Proto}
+	cmd := []string{r.Family, "route", "replace"}
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	cmd = append(cmd, "proto", r.Proto)
+	if r.Metric != "" {
+		cmd = append(cmd, "metric", r.Metric)
+	}
 
 	if r.VRF != "" {
 		cmd = append(cmd, "vrf", r.VRF)
@@ -122,7 +150,12 @@ func (r *Route) Replace(routes []string) error {
 func (r *Route) Show() ([]string, error) {
 	routes := []string{}
 
-	cmd := []string{r.Family, "route", "show", "dev", r.DevName, "proto", r.Proto}
+	cmd := []string{r.Family, "route", "show"}
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	cmd = append(cmd, "proto", r.Proto)
 
 	if r.VRF != "" {
 		cmd = append(cmd, "vrf", r.VRF)
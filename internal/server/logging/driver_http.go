@@ -0,0 +1,265 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// maxErrMsgLen is the maximum length of the response body included in delivery errors.
+const maxErrMsgLen = 1024
+
+// httpBatchLogger implements the batching and delivery logic shared by the HTTP based loggers.
+// Events are accumulated until the batch grows too big or too old, and then handed over to the
+// driver for encoding before being sent in a single request.
+type httpBatchLogger struct {
+	common
+
+	address    string
+	username   string
+	password   string
+	batchSize  int
+	batchWait  time.Duration
+	retry      int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	timeout    time.Duration
+
+	// eventSize returns the size an event adds to a batch, its metadata size if unset.
+	eventSize func(event api.Event) int
+
+	// encode converts a batch of events into the request body, skipping events it can't convert.
+	// A nil body means there's nothing left to send.
+	encode func(events []api.Event) ([]byte, error)
+
+	// setHeaders adds driver specific headers to a request for the given body.
+	setHeaders func(req *http.Request, body []byte)
+
+	client *http.Client
+	ctx    context.Context
+	events chan api.Event
+	quit   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// newHTTPBatchLogger returns a new httpBatchLogger sending to the given address.
+func newHTTPBatchLogger(ctx context.Context, common common, address string, username string, password string, caCert string, retry int) (*httpBatchLogger, error) {
+	l := &httpBatchLogger{
+		common:     common,
+		address:    address,
+		username:   username,
+		password:   password,
+		batchSize:  1024 * 1024,
+		batchWait:  1 * time.Second,
+		retry:      retry,
+		backoff:    1 * time.Second,
+		maxBackoff: time.Minute,
+		timeout:    10 * time.Second,
+		client:     http.DefaultClient,
+		ctx:        ctx,
+		events:     make(chan api.Event),
+		quit:       make(chan struct{}),
+	}
+
+	if caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		l.client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		}
+	}
+
+	return l, nil
+}
+
+func (l *httpBatchLogger) run() {
+	var batch []api.Event
+	var batchBytes int
+	var batchCreated time.Time
+
+	maxWaitCheck := time.NewTicker(max(l.batchWait/10, 10*time.Millisecond))
+	defer maxWaitCheck.Stop()
+
+	defer func() {
+		// Send all pending events.
+		l.sendBatch(batch)
+		l.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+
+		case <-l.quit:
+			return
+
+		case event := <-l.events:
+			size := len(event.Metadata)
+			if l.eventSize != nil {
+				size = l.eventSize(event)
+			}
+
+			// Send the current batch first if the event would make it too big.
+			if len(batch) > 0 && batchBytes+size > l.batchSize {
+				l.sendBatch(batch)
+				batch = nil
+				batchBytes = 0
+			}
+
+			if len(batch) == 0 {
+				batchCreated = time.Now()
+			}
+
+			batch = append(batch, event)
+			batchBytes += size
+
+		case <-maxWaitCheck.C:
+			// Send batch if max wait time has been reached.
+			if len(batch) == 0 || time.Since(batchCreated) < l.batchWait {
+				break
+			}
+
+			l.sendBatch(batch)
+			batch = nil
+			batchBytes = 0
+		}
+	}
+}
+
+func (l *httpBatchLogger) sendBatch(batch []api.Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := l.encode(batch)
+	if err != nil {
+		logger.Warn("Failed encoding log events", logger.Ctx{"logger": l.name, "err": err})
+		return
+	}
+
+	if body == nil {
+		return
+	}
+
+	backoff := l.backoff
+	attempts := max(l.retry, 1)
+
+	for i := 1; ; i++ {
+		status, err := l.send(body)
+		if err == nil {
+			return
+		}
+
+		// Only retry 429s, 500s and connection-level errors.
+		if i >= attempts || (status > 0 && status != http.StatusTooManyRequests && status/100 != 5) {
+			logger.Warn("Failed sending log events", logger.Ctx{"logger": l.name, "events": len(batch), "attempts": i, "err": err})
+			return
+		}
+
+		// Retry with an exponential backoff.
+		select {
+		case <-l.quit:
+			logger.Warn("Dropping log events on shutdown", logger.Ctx{"logger": l.name, "events": len(batch), "err": err})
+			return
+		case <-l.ctx.Done():
+			logger.Warn("Dropping log events on shutdown", logger.Ctx{"logger": l.name, "events": len(batch), "err": err})
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, l.maxBackoff)
+	}
+}
+
+func (l *httpBatchLogger) send(body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(l.ctx, l.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.address, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	if l.username != "" && l.password != "" {
+		req.SetBasicAuth(l.username, l.password)
+	}
+
+	if l.setHeaders != nil {
+		l.setHeaders(req, body)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+		line := ""
+
+		if scanner.Scan() {
+			line = scanner.Text()
+		}
+
+		return resp.StatusCode, fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Start starts the logger.
+func (l *httpBatchLogger) Start() error {
+	l.wg.Add(1)
+	go l.run()
+
+	return nil
+}
+
+// Stop stops the logger, sending any pending events.
+func (l *httpBatchLogger) Stop() {
+	l.once.Do(func() { close(l.quit) })
+	l.wg.Wait()
+}
+
+// Validate checks whether the logger configuration is correct.
+func (l *httpBatchLogger) Validate() error {
+	if l.address == "" {
+		return fmt.Errorf("%s: URL cannot be empty", l.name)
+	}
+
+	return nil
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (l *httpBatchLogger) HandleEvent(event api.Event) {
+	if !l.processEvent(event) {
+		return
+	}
+
+	select {
+	case l.events <- event:
+	case <-l.quit:
+	}
+}
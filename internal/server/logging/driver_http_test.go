@@ -0,0 +1,226 @@
+package logging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// fakeLogServer records the requests it receives and replies with the queued status codes.
+type fakeLogServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (f *fakeLogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r)
+	f.bodies = append(f.bodies, body)
+
+	status := http.StatusNoContent
+	if len(f.statuses) > 0 {
+		status = f.statuses[0]
+		f.statuses = f.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (f *fakeLogServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.requests)
+}
+
+func newFakeLogServer(t *testing.T, statuses ...int) (*fakeLogServer, string) {
+	f := &fakeLogServer{statuses: statuses}
+
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	return f, server.URL
+}
+
+func testCommonLogger() common {
+	return common{
+		name:         "test",
+		loggingLevel: "info",
+		types:        []string{"lifecycle", "logging"},
+	}
+}
+
+func testLifecycleEvent(t *testing.T) api.Event {
+	metadata, err := json.Marshal(api.EventLifecycle{
+		Action:  "instance-started",
+		Source:  "/1.0/instances/c1",
+		Name:    "c1",
+		Project: "default",
+	})
+	require.NoError(t, err)
+
+	return api.Event{
+		Type:      api.EventTypeLifecycle,
+		Timestamp: time.Unix(1700000000, 0),
+		Location:  "node1",
+		Metadata:  metadata,
+	}
+}
+
+func testLoggingEvent(t *testing.T, level string) api.Event {
+	metadata, err := json.Marshal(api.EventLogging{
+		Level:   level,
+		Message: "Something happened",
+		Context: map[string]string{"instance": "c1"},
+	})
+	require.NoError(t, err)
+
+	return api.Event{
+		Type:      api.EventTypeLogging,
+		Timestamp: time.Unix(1700000001, 0),
+		Location:  "node1",
+		Metadata:  metadata,
+	}
+}
+
+func TestWebhookLogger(t *testing.T) {
+	server, address := newFakeLogServer(t)
+
+	l, err := newWebhookLogger(context.Background(), testCommonLogger(), address, "user", "pass", "", "secret", "server1", 3)
+	require.NoError(t, err)
+	require.NoError(t, l.Validate())
+	require.NoError(t, l.Start())
+
+	l.HandleEvent(testLifecycleEvent(t))
+	l.HandleEvent(testLoggingEvent(t, "error"))
+
+	// Events below the configured level are filtered out.
+	l.HandleEvent(testLoggingEvent(t, "debug"))
+
+	// The pending batch is sent when stopping.
+	l.Stop()
+	require.Equal(t, 1, server.count())
+
+	req := server.requests[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(server.bodies[0])
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get(webhookSignatureHeader))
+
+	payload := webhookPayload{}
+	require.NoError(t, json.Unmarshal(server.bodies[0], &payload))
+	assert.Equal(t, "server1", payload.Instance)
+	require.Len(t, payload.Events, 2)
+	assert.Equal(t, api.EventTypeLifecycle, payload.Events[0].Type)
+	assert.Equal(t, api.EventTypeLogging, payload.Events[1].Type)
+}
+
+func TestWebhookLoggerBatchSize(t *testing.T) {
+	server, address := newFakeLogServer(t)
+
+	l, err := newWebhookLogger(context.Background(), testCommonLogger(), address, "", "", "", "", "server1", 3)
+	require.NoError(t, err)
+
+	// Only a single event fits in a batch.
+	event := testLifecycleEvent(t)
+	l.batchSize = len(event.Metadata)
+
+	require.NoError(t, l.Start())
+	l.HandleEvent(event)
+	l.HandleEvent(event)
+	l.HandleEvent(event)
+	l.Stop()
+
+	assert.Equal(t, 3, server.count())
+
+	// Without a secret, requests aren't signed.
+	assert.Empty(t, server.requests[0].Header.Get(webhookSignatureHeader))
+}
+
+func TestHTTPBatchLoggerRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+	}{
+		{
+			name:     "Success after server errors",
+			statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests},
+			requests: 3,
+		},
+		{
+			name:     "Too many server errors",
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			requests: 3,
+		},
+		{
+			name:     "Client errors aren't retried",
+			statuses: []int{http.StatusBadRequest},
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, address := newFakeLogServer(t, tt.statuses...)
+
+			l, err := newWebhookLogger(context.Background(), testCommonLogger(), address, "", "", "", "", "server1", 3)
+			require.NoError(t, err)
+
+			l.backoff = 100 * time.Millisecond
+
+			start := time.Now()
+			l.sendBatch([]api.Event{testLifecycleEvent(t)})
+
+			assert.Equal(t, tt.requests, server.count())
+
+			// There's no wait after the last attempt (100ms and 200ms between the three attempts).
+			assert.Less(t, time.Since(start), 600*time.Millisecond)
+		})
+	}
+}
+
+func TestHTTPBatchLoggerStop(t *testing.T) {
+	server, address := newFakeLogServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	l, err := newWebhookLogger(context.Background(), testCommonLogger(), address, "", "", "", "", "server1", 3)
+	require.NoError(t, err)
+
+	l.backoff = time.Minute
+
+	require.NoError(t, l.Start())
+	l.HandleEvent(testLifecycleEvent(t))
+
+	// Stopping the logger doesn't wait for the retries of the pending batch.
+	start := time.Now()
+	l.Stop()
+
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, 1, server.count())
+}
@@ -1,13 +1,9 @@
 package logging
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -15,37 +11,15 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
-	localtls "github.com/lxc/incus/v6/shared/tls"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 // This is a modified version of https://github.com/grafana/loki/blob/v1.6.1/pkg/promtail/client/.
 
-const (
-	contentType  = "application/json"
-	maxErrMsgLen = 1024
-)
-
-type config struct {
-	batchSize int
-	batchWait time.Duration
-
-	caCert   string
-	username string
-	password string
-	labels   []string
-	instance string
-	location string
-	retry    int
-
-	timeout time.Duration
-	url     *url.URL
-}
-
 type entry struct {
 	labels LabelSet
 	Entry
@@ -53,14 +27,11 @@ type entry struct {
 
 // LokiLogger represents a Loki client.
 type LokiLogger struct {
-	common
-	cfg     config
-	client  *http.Client
-	ctx     context.Context
-	quit    chan struct{}
-	once    sync.Once
-	entries chan entry
-	wg      sync.WaitGroup
+	*httpBatchLogger
+
+	instance string
+	location string
+	labels   []string
 }
 
 // NewLokiLogger returns a logger of loki type.
@@ -89,196 +60,82 @@ func NewLokiLogger(s *state.State, name string) (*LokiLogger, error) {
 		instance = s.ServerName
 	}
 
-	loggerClient := LokiLogger{
-		common: newCommonLogger(name, s.GlobalConfig),
-		cfg: config{
-			batchSize: 10 * 1024,
-			batchWait: 1 * time.Second,
-			caCert:    caCert,
-			username:  username,
-			password:  password,
-			instance:  instance,
-			location:  location,
-			labels:    sliceFromString(labels),
-			retry:     retry,
-			timeout:   10 * time.Second,
-			url:       u,
-		},
-		client:  &http.Client{},
-		ctx:     s.ShutdownCtx,
-		entries: make(chan entry),
-		quit:    make(chan struct{}),
-	}
-
-	if caCert != "" {
-		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
-		if err != nil {
-			return nil, err
-		}
-
-		loggerClient.client.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
-		}
-	} else {
-		loggerClient.client = http.DefaultClient
-	}
-
-	return &loggerClient, nil
+	return newLokiLogger(s.ShutdownCtx, newCommonLogger(name, s.GlobalConfig), u, username, password, caCert, instance, location, sliceFromString(labels), retry)
 }
 
-func (l *LokiLogger) run() {
-	batch := newBatch()
-
-	minWaitCheckFrequency := 10 * time.Millisecond
-	maxWaitCheckFrequency := l.cfg.batchWait / 10
-
-	if maxWaitCheckFrequency < minWaitCheckFrequency {
-		maxWaitCheckFrequency = minWaitCheckFrequency
+// newLokiLogger returns a logger pushing to the Loki server at the given URL.
+func newLokiLogger(ctx context.Context, common common, u *url.URL, username string, password string, caCert string, instance string, location string, labels []string, retry int) (*LokiLogger, error) {
+	var address string
+	if u.String() != "" {
+		address = fmt.Sprintf("%s/loki/api/v1/push", u.String())
 	}
 
-	maxWaitCheck := time.NewTicker(maxWaitCheckFrequency)
-
-	defer func() {
-		// Send all pending batches
-		l.sendBatch(batch)
-		l.wg.Done()
-	}()
-
-	for {
-		select {
-		case <-l.ctx.Done():
-			return
-
-		case <-l.quit:
-			return
-
-		case e := <-l.entries:
-			// If adding the entry to the batch will increase the size over the max
-			// size allowed, we do send the current batch and then create a new one
-			if batch.sizeBytesAfter(e) > l.cfg.batchSize {
-				l.sendBatch(batch)
-
-				batch = newBatch(e)
-				break
-			}
-
-			// The max size of the batch isn't reached, so we can add the entry
-			batch.add(e)
-
-		case <-maxWaitCheck.C:
-			// Send batch if max wait time has been reached
-			if batch.age() < l.cfg.batchWait {
-				break
-			}
-
-			l.sendBatch(batch)
-			batch = newBatch()
-		}
+	httpLogger, err := newHTTPBatchLogger(ctx, common, address, username, password, caCert, retry)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (l *LokiLogger) sendBatch(batch *batch) {
-	if batch.empty() {
-		return
-	}
+	// Keep the batching and retry behavior of the original Loki client.
+	httpLogger.batchSize = 10 * 1024
+	httpLogger.backoff = 10 * time.Second
+	httpLogger.maxBackoff = 10 * time.Second
 
-	buf, _, err := batch.encode()
-	if err != nil {
-		return
+	l := &LokiLogger{
+		httpBatchLogger: httpLogger,
+		instance:        instance,
+		location:        location,
+		labels:          labels,
 	}
 
-	var status int
-
-	for i := 0; i < l.cfg.retry; i++ {
-		select {
-		case <-l.quit:
-			return
-		default:
-			// Try to send the message.
-			status, err = l.send(l.ctx, buf)
-			if err == nil {
-				return
-			}
+	l.encode = l.encodeEvents
+	l.eventSize = l.eventLineSize
 
-			// Only retry 429s, 500s and connection-level errors.
-			if status > 0 && status != 429 && status/100 != 5 {
-				return
-			}
-
-			// Retry every 10s.
-			time.Sleep(10 * time.Second)
-		}
-	}
+	return l, nil
 }
 
-func (l *LokiLogger) send(ctx context.Context, buf []byte) (int, error) {
-	ctx, cancel := context.WithTimeout(ctx, l.cfg.timeout)
-	defer cancel()
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/loki/api/v1/push", l.cfg.url.String()), bytes.NewReader(buf))
+// eventLineSize returns the size of the log line of an event, which is what Loki batches are limited by.
+func (l *LokiLogger) eventLineSize(event api.Event) int {
+	entry, err := l.entryFromEvent(event)
 	if err != nil {
-		return -1, err
+		return 0
 	}
 
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", contentType)
-
-	if l.cfg.username != "" && l.cfg.password != "" {
-		req.SetBasicAuth(l.cfg.username, l.cfg.password)
-	}
-
-	resp, err := l.client.Do(req)
-	if err != nil {
-		return -1, err
-	}
+	return len(entry.Line)
+}
 
-	if resp.StatusCode/100 != 2 {
-		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
-		line := ""
+// encodeEvents converts events into a Loki push request.
+// Events which can't be converted are skipped.
+func (l *LokiLogger) encodeEvents(events []api.Event) ([]byte, error) {
+	entries := make([]entry, 0, len(events))
 
-		if scanner.Scan() {
-			line = scanner.Text()
+	for _, event := range events {
+		entry, err := l.entryFromEvent(event)
+		if err != nil {
+			logger.Warn("Skipping log event", logger.Ctx{"logger": l.name, "type": event.Type, "err": err})
+			continue
 		}
 
-		err = fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+		entries = append(entries, *entry)
 	}
 
-	return resp.StatusCode, err
-}
-
-// Start starts the loki logger.
-func (l *LokiLogger) Start() error {
-	l.wg.Add(1)
-	go l.run()
-
-	return nil
-}
-
-// Stop stops the client.
-func (l *LokiLogger) Stop() {
-	l.once.Do(func() { close(l.quit) })
-	l.wg.Wait()
-}
+	if len(entries) == 0 {
+		return nil, nil
+	}
 
-// Validate checks whether the logger configuration is correct.
-func (l *LokiLogger) Validate() error {
-	if l.cfg.url.String() == "" {
-		return fmt.Errorf("%s: URL cannot be empty", l.name)
+	buf, _, err := newBatch(entries...).encode()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return buf, nil
 }
 
-// HandleEvent handles the event received from the internal event listener.
-func (l *LokiLogger) HandleEvent(event api.Event) {
-	if !l.processEvent(event) {
-		return
-	}
-
+// entryFromEvent converts a lifecycle, logging or network ACL event into a Loki entry.
+func (l *LokiLogger) entryFromEvent(event api.Event) (*entry, error) {
 	// Support overriding the location field (used on standalone systems).
 	location := event.Location
-	if l.cfg.location != "" {
-		location = l.cfg.location
+	if l.location != "" {
+		location = l.location
 	}
 
 	entry := entry{
@@ -286,7 +143,7 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 			"app":      "incus",
 			"type":     event.Type,
 			"location": location,
-			"instance": l.cfg.instance,
+			"instance": l.instance,
 		},
 		Entry: Entry{
 			Timestamp: event.Timestamp,
@@ -301,7 +158,7 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 
 		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
 		if err != nil {
-			return
+			return nil, err
 		}
 
 		if lifecycleEvent.Name != "" {
@@ -340,7 +197,7 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 		for _, k := range keys {
 			v := ctx[k]
 
-			if slices.Contains(l.cfg.labels, k) {
+			if slices.Contains(l.labels, k) {
 				_, ok := entry.labels[k]
 				if !ok {
 					// Label names may not contain any hyphens.
@@ -363,7 +220,7 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 
 		err := json.Unmarshal(event.Metadata, &logEvent)
 		if err != nil {
-			return
+			return nil, err
 		}
 
 		tmpContext := map[string]any{}
@@ -383,7 +240,7 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 
 		// Add key-value pairs as labels but don't override any labels.
 		for k, v := range ctx {
-			if slices.Contains(l.cfg.labels, k) {
+			if slices.Contains(l.labels, k) {
 				_, ok := entry.labels[k]
 				if !ok {
 					entry.labels[k] = v
@@ -410,9 +267,11 @@ func (l *LokiLogger) HandleEvent(event api.Event) {
 		message.WriteString(logEvent.Message)
 
 		entry.Line = message.String()
+	default:
+		return nil, fmt.Errorf("Unsupported event type %q", event.Type)
 	}
 
-	l.entries <- entry
+	return &entry, nil
 }
 
 func buildNestedContext(prefix string, m map[string]any) map[string]string {
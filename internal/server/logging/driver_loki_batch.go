@@ -2,23 +2,17 @@ package logging
 
 import (
 	"encoding/json"
-	"time"
 )
 
-// batch holds pending log streams waiting to be sent to Loki, and it's used
-// to reduce the number of push requests to Loki aggregating multiple log streams
-// and entries in a single batch request.
+// batch groups log entries by stream, and it's used to send multiple log
+// streams and entries to Loki in a single push request.
 type batch struct {
-	streams   map[string]*Stream
-	bytes     int
-	createdAt time.Time
+	streams map[string]*Stream
 }
 
 func newBatch(entries ...entry) *batch {
 	b := &batch{
-		streams:   map[string]*Stream{},
-		bytes:     0,
-		createdAt: time.Now(),
+		streams: map[string]*Stream{},
 	}
 
 	// Add entries to the batch
@@ -31,8 +25,6 @@ func newBatch(entries ...entry) *batch {
 
 // add an entry to the batch.
 func (b *batch) add(entry entry) {
-	b.bytes += len(entry.Line)
-
 	// Append the entry to an already existing stream (if any)
 	labels := entry.labels.String()
 
@@ -49,17 +41,6 @@ func (b *batch) add(entry entry) {
 	}
 }
 
-// sizeBytesAfter returns the size of the batch after the input entry
-// will be added to the batch itself.
-func (b *batch) sizeBytesAfter(entry entry) int {
-	return b.bytes + len(entry.Line)
-}
-
-// age of the batch since its creation.
-func (b *batch) age() time.Duration {
-	return time.Since(b.createdAt)
-}
-
 // encode the batch as push request, and returns the encoded bytes and the number of encoded
 // entries.
 func (b *batch) encode() ([]byte, int, error) {
@@ -88,8 +69,3 @@ func (b *batch) createPushRequest() (*PushRequest, int) {
 
 	return &req, entriesCount
 }
-
-// empty returns true if streams is empty.
-func (b *batch) empty() bool {
-	return len(b.streams) == 0
-}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestLokiLogger(t *testing.T) {
+	server, address := newFakeLogServer(t)
+
+	u, err := url.Parse(address)
+	require.NoError(t, err)
+
+	l, err := newLokiLogger(context.Background(), testCommonLogger(), u, "", "", "", "server1", "", []string{"level", "source"}, 3)
+	require.NoError(t, err)
+	require.NoError(t, l.Start())
+
+	l.HandleEvent(testLifecycleEvent(t))
+	l.HandleEvent(testLoggingEvent(t, "error"))
+	l.Stop()
+
+	require.Equal(t, 1, server.count())
+	assert.Equal(t, "/loki/api/v1/push", server.requests[0].URL.Path)
+
+	req := struct {
+		Streams []struct {
+			Labels LabelSet    `json:"stream"`
+			Values [][2]string `json:"values"`
+		} `json:"streams"`
+	}{}
+
+	require.NoError(t, json.Unmarshal(server.bodies[0], &req))
+	require.Len(t, req.Streams, 2)
+
+	streams := map[string][2]string{}
+	labels := map[string]LabelSet{}
+	for _, stream := range req.Streams {
+		require.Len(t, stream.Values, 1)
+		assert.Equal(t, "server1", stream.Labels["instance"])
+		streams[stream.Labels["type"]] = stream.Values[0]
+		labels[stream.Labels["type"]] = stream.Labels
+	}
+
+	// The configured labels are taken out of the message.
+	assert.Equal(t, [2]string{"1700000000000000000", `action="instance-started" instance-started`}, streams["lifecycle"])
+	assert.Equal(t, [2]string{"1700000001000000000", `context-instance="c1" Something happened`}, streams["logging"])
+	assert.Equal(t, "/1.0/instances/c1", labels["lifecycle"]["source"])
+	assert.Equal(t, "error", labels["logging"]["level"])
+}
+
+func TestLokiLoggerInvalidEvent(t *testing.T) {
+	u, err := url.Parse("http://loki")
+	require.NoError(t, err)
+
+	l, err := newLokiLogger(context.Background(), testCommonLogger(), u, "", "", "", "server1", "", nil, 3)
+	require.NoError(t, err)
+
+	invalid := testLifecycleEvent(t)
+	invalid.Type = "unknown"
+
+	// Events which can't be converted are skipped without dropping the others.
+	body, err := l.encodeEvents([]api.Event{invalid, testLoggingEvent(t, "error")})
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Something happened")
+	assert.NotContains(t, string(body), "unknown")
+
+	// Nothing is sent when no event can be converted.
+	body, err = l.encodeEvents([]api.Event{invalid})
+	require.NoError(t, err)
+	assert.Nil(t, body)
+}
+
+func TestLokiLoggerBatching(t *testing.T) {
+	server, address := newFakeLogServer(t)
+
+	u, err := url.Parse(address)
+	require.NoError(t, err)
+
+	l, err := newLokiLogger(context.Background(), testCommonLogger(), u, "", "", "", "server1", "", nil, 3)
+	require.NoError(t, err)
+
+	// Batches are limited by the size of the log lines, as with the original Loki client.
+	assert.Equal(t, 10*1024, l.batchSize)
+	assert.Equal(t, 10*time.Second, l.backoff)
+	assert.Equal(t, 10*time.Second, l.maxBackoff)
+
+	event := testLoggingEvent(t, "error")
+	event.Metadata, err = json.Marshal(api.EventLogging{Level: "error", Message: strings.Repeat("a", 4*1024)})
+	require.NoError(t, err)
+
+	require.NoError(t, l.Start())
+	for i := 0; i < 5; i++ {
+		l.HandleEvent(event)
+	}
+
+	l.Stop()
+
+	// Two lines fit in a batch.
+	assert.Equal(t, 3, server.count())
+}
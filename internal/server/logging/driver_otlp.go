@@ -0,0 +1,234 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// otlpSeverityNumbers maps log levels to OpenTelemetry severity numbers.
+var otlpSeverityNumbers = map[string]int{
+	"trace":   1,
+	"debug":   5,
+	"info":    9,
+	"warn":    13,
+	"warning": 13,
+	"error":   17,
+	"fatal":   21,
+	"panic":   21,
+}
+
+// otlpKeyValue is an OTLP attribute with a string value.
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+// otlpLogRecord is an OTLP log record.
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+// otlpAnyValue is an OTLP string value.
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// OTLPLogger represents an OpenTelemetry logs exporter using OTLP over HTTP.
+type OTLPLogger struct {
+	*httpBatchLogger
+
+	instance string
+}
+
+// NewOTLPLogger returns a logger of otlp type.
+func NewOTLPLogger(s *state.State, name string) (*OTLPLogger, error) {
+	address, username, password, caCert, instance, retry := s.GlobalConfig.LoggingConfigForOTLP(name)
+
+	instance, err := loggerInstance(s, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOTLPLogger(s.ShutdownCtx, newCommonLogger(name, s.GlobalConfig), address, username, password, caCert, instance, retry)
+}
+
+// newOTLPLogger returns a logger exporting to the OTLP collector at the given address.
+func newOTLPLogger(ctx context.Context, common common, address string, username string, password string, caCert string, instance string, retry int) (*OTLPLogger, error) {
+	// Like other OTLP exporters, the address is the base URL of the collector.
+	if address != "" {
+		address = strings.TrimSuffix(address, "/") + "/v1/logs"
+	}
+
+	httpLogger, err := newHTTPBatchLogger(ctx, common, address, username, password, caCert, retry)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &OTLPLogger{
+		httpBatchLogger: httpLogger,
+		instance:        instance,
+	}
+
+	l.encode = l.encodeEvents
+
+	return l, nil
+}
+
+// encodeEvents converts events into an OTLP ExportLogsServiceRequest using the JSON encoding.
+// Events which can't be converted are skipped.
+func (l *OTLPLogger) encodeEvents(events []api.Event) ([]byte, error) {
+	records := make([]otlpLogRecord, 0, len(events))
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	for _, event := range events {
+		record, err := otlpRecordFromEvent(event)
+		if err != nil {
+			logger.Warn("Skipping log event", logger.Ctx{"logger": l.name, "type": event.Type, "err": err})
+			continue
+		}
+
+		record.ObservedTimeUnixNano = observed
+		records = append(records, *record)
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	req := map[string]any{
+		"resourceLogs": []map[string]any{{
+			"resource": map[string]any{
+				"attributes": otlpAttributes(map[string]string{
+					"service.name":        "incus",
+					"service.instance.id": l.instance,
+				}),
+			},
+			"scopeLogs": []map[string]any{{
+				"scope": map[string]string{
+					"name":    "incus",
+					"version": version.Version,
+				},
+				"logRecords": records,
+			}},
+		}},
+	}
+
+	return json.Marshal(req)
+}
+
+// otlpRecordFromEvent converts a lifecycle, logging or network ACL event into an OTLP log record.
+func otlpRecordFromEvent(event api.Event) (*otlpLogRecord, error) {
+	attributes := map[string]string{
+		"incus.type": event.Type,
+	}
+
+	if event.Location != "" {
+		attributes["incus.location"] = event.Location
+	}
+
+	if event.Project != "" {
+		attributes["incus.project"] = event.Project
+	}
+
+	level := "info"
+	var message string
+
+	switch event.Type {
+	case api.EventTypeLifecycle:
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return nil, err
+		}
+
+		message = lifecycleEvent.Action
+		attributes["incus.action"] = lifecycleEvent.Action
+		attributes["incus.source"] = lifecycleEvent.Source
+
+		if lifecycleEvent.Name != "" {
+			attributes["incus.name"] = lifecycleEvent.Name
+		}
+
+		if lifecycleEvent.Project != "" {
+			attributes["incus.project"] = lifecycleEvent.Project
+		}
+
+		for k, v := range buildNestedContext("context", lifecycleEvent.Context) {
+			attributes["incus."+k] = v
+		}
+
+		if lifecycleEvent.Requestor != nil {
+			attributes["incus.requester-address"] = lifecycleEvent.Requestor.Address
+			attributes["incus.requester-protocol"] = lifecycleEvent.Requestor.Protocol
+			attributes["incus.requester-username"] = lifecycleEvent.Requestor.Username
+		}
+
+	case api.EventTypeLogging, api.EventTypeNetworkACL:
+		logEvent := api.EventLogging{}
+
+		err := json.Unmarshal(event.Metadata, &logEvent)
+		if err != nil {
+			return nil, err
+		}
+
+		message = logEvent.Message
+		level = strings.ToLower(logEvent.Level)
+
+		for k, v := range logEvent.Context {
+			attributes["incus.context-"+k] = v
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported event type %q", event.Type)
+	}
+
+	severityNumber, ok := otlpSeverityNumbers[level]
+	if !ok {
+		severityNumber = otlpSeverityNumbers["info"]
+	}
+
+	return &otlpLogRecord{
+		TimeUnixNano:   strconv.FormatInt(event.Timestamp.UnixNano(), 10),
+		SeverityNumber: severityNumber,
+		SeverityText:   strings.ToUpper(level),
+		Body:           otlpAnyValue{StringValue: message},
+		Attributes:     otlpAttributes(attributes),
+	}, nil
+}
+
+// otlpAttributes converts a map into sorted OTLP attributes.
+func otlpAttributes(values map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		attribute := otlpKeyValue{Key: k}
+		attribute.Value.StringValue = values[k]
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// otlpTestRequest is the subset of an OTLP ExportLogsServiceRequest checked by the tests.
+type otlpTestRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []otlpLogRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// otlpTestAttributes converts OTLP attributes back into a map.
+func otlpTestAttributes(attributes []otlpKeyValue) map[string]string {
+	values := map[string]string{}
+	for _, attribute := range attributes {
+		values[attribute.Key] = attribute.Value.StringValue
+	}
+
+	return values
+}
+
+func TestOTLPLogger(t *testing.T) {
+	server, address := newFakeLogServer(t)
+
+	// The logs endpoint is appended to the collector address.
+	l, err := newOTLPLogger(context.Background(), testCommonLogger(), address+"/", "", "", "", "server1", 3)
+	require.NoError(t, err)
+	require.NoError(t, l.Start())
+
+	l.HandleEvent(testLifecycleEvent(t))
+	l.HandleEvent(testLoggingEvent(t, "warn"))
+	l.Stop()
+
+	require.Equal(t, 1, server.count())
+	assert.Equal(t, "/v1/logs", server.requests[0].URL.Path)
+
+	req := otlpTestRequest{}
+	require.NoError(t, json.Unmarshal(server.bodies[0], &req))
+	require.Len(t, req.ResourceLogs, 1)
+	require.Len(t, req.ResourceLogs[0].ScopeLogs, 1)
+
+	resource := otlpTestAttributes(req.ResourceLogs[0].Resource.Attributes)
+	assert.Equal(t, map[string]string{"service.name": "incus", "service.instance.id": "server1"}, resource)
+
+	scopeLogs := req.ResourceLogs[0].ScopeLogs[0]
+	assert.Equal(t, "incus", scopeLogs.Scope.Name)
+	require.Len(t, scopeLogs.LogRecords, 2)
+
+	lifecycle := scopeLogs.LogRecords[0]
+	assert.Equal(t, "1700000000000000000", lifecycle.TimeUnixNano)
+	assert.NotEmpty(t, lifecycle.ObservedTimeUnixNano)
+	assert.Equal(t, 9, lifecycle.SeverityNumber)
+	assert.Equal(t, "INFO", lifecycle.SeverityText)
+	assert.Equal(t, "instance-started", lifecycle.Body.StringValue)
+
+	attributes := otlpTestAttributes(lifecycle.Attributes)
+	assert.Equal(t, "lifecycle", attributes["incus.type"])
+	assert.Equal(t, "node1", attributes["incus.location"])
+	assert.Equal(t, "c1", attributes["incus.name"])
+	assert.Equal(t, "default", attributes["incus.project"])
+	assert.Equal(t, "/1.0/instances/c1", attributes["incus.source"])
+
+	logging := scopeLogs.LogRecords[1]
+	assert.Equal(t, 13, logging.SeverityNumber)
+	assert.Equal(t, "WARN", logging.SeverityText)
+	assert.Equal(t, "Something happened", logging.Body.StringValue)
+	assert.Equal(t, "c1", otlpTestAttributes(logging.Attributes)["incus.context-instance"])
+}
+
+func TestOTLPLoggerValidate(t *testing.T) {
+	l, err := newOTLPLogger(context.Background(), testCommonLogger(), "", "", "", "", "server1", 3)
+	require.NoError(t, err)
+	assert.Error(t, l.Validate())
+}
+
+func TestOTLPLoggerInvalidEvent(t *testing.T) {
+	l, err := newOTLPLogger(context.Background(), testCommonLogger(), "http://collector", "", "", "", "server1", 3)
+	require.NoError(t, err)
+
+	invalid := testLoggingEvent(t, "error")
+	invalid.Metadata = []byte(`"invalid"`)
+
+	// Events which can't be converted are skipped without dropping the others.
+	body, err := l.encodeEvents([]api.Event{invalid, testLifecycleEvent(t)})
+	require.NoError(t, err)
+
+	req := otlpTestRequest{}
+	require.NoError(t, json.Unmarshal(body, &req))
+	require.Len(t, req.ResourceLogs[0].ScopeLogs[0].LogRecords, 1)
+	assert.Equal(t, "instance-started", req.ResourceLogs[0].ScopeLogs[0].LogRecords[0].Body.StringValue)
+
+	// Nothing is sent when no event can be converted.
+	body, err = l.encodeEvents([]api.Event{invalid})
+	require.NoError(t, err)
+	assert.Nil(t, body)
+}
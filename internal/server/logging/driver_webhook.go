@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// webhookSignatureHeader is the header holding the HMAC-SHA256 signature of the request body.
+const webhookSignatureHeader = "X-Incus-Signature-256"
+
+// webhookPayload is the body sent to the webhook.
+type webhookPayload struct {
+	Instance string      `json:"instance"`
+	Events   []api.Event `json:"events"`
+}
+
+// WebhookLogger represents a logger sending batches of events to a generic HTTP webhook.
+type WebhookLogger struct {
+	*httpBatchLogger
+
+	instance string
+	secret   string
+}
+
+// NewWebhookLogger returns a logger of webhook type.
+func NewWebhookLogger(s *state.State, name string) (*WebhookLogger, error) {
+	address, username, password, caCert, secret, instance, retry := s.GlobalConfig.LoggingConfigForWebhook(name)
+
+	instance, err := loggerInstance(s, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebhookLogger(s.ShutdownCtx, newCommonLogger(name, s.GlobalConfig), address, username, password, caCert, secret, instance, retry)
+}
+
+// newWebhookLogger returns a logger sending to the webhook at the given address.
+func newWebhookLogger(ctx context.Context, common common, address string, username string, password string, caCert string, secret string, instance string, retry int) (*WebhookLogger, error) {
+	httpLogger, err := newHTTPBatchLogger(ctx, common, address, username, password, caCert, retry)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &WebhookLogger{
+		httpBatchLogger: httpLogger,
+		instance:        instance,
+		secret:          secret,
+	}
+
+	l.encode = l.encodeEvents
+	l.setHeaders = l.signRequest
+
+	return l, nil
+}
+
+// encodeEvents converts events into the JSON payload of the webhook.
+func (l *WebhookLogger) encodeEvents(events []api.Event) ([]byte, error) {
+	return json.Marshal(webhookPayload{
+		Instance: l.instance,
+		Events:   events,
+	})
+}
+
+// signRequest adds the HMAC-SHA256 signature of the body to the request when a secret is configured.
+func (l *WebhookLogger) signRequest(req *http.Request, body []byte) {
+	if l.secret == "" {
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(l.secret))
+	_, _ = mac.Write(body)
+
+	req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+}
@@ -1,7 +1,10 @@
 package logging
 
 import (
+	"os"
 	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/state"
 )
 
 // sliceFromString converts a comma-separated string into a slice of strings.
@@ -37,3 +40,17 @@ func hasAnyPrefix(prefixes []string, s string) bool {
 	}
 	return false
 }
+
+// loggerInstance returns the name identifying this server in log entries. It defaults to the
+// cluster member name, or to the host name on standalone systems.
+func loggerInstance(s *state.State, instance string) (string, error) {
+	if instance != "" {
+		return instance, nil
+	}
+
+	if !s.ServerClustered {
+		return os.Hostname()
+	}
+
+	return s.ServerName, nil
+}
@@ -88,37 +88,17 @@ func (m *MetricSet) Merge(metricSet *MetricSet) {
 
 func (m *MetricSet) String() string {
 	var out strings.Builder
-	metricTypes := []MetricType{}
 
 	// Sort output by metric type name
-	for metricType := range m.set {
-		metricTypes = append(metricTypes, metricType)
-	}
-
-	sort.SliceStable(metricTypes, func(i, j int) bool {
-		return int(metricTypes[i]) < int(metricTypes[j])
-	})
-
-	for _, metricType := range metricTypes {
+	for _, metricType := range m.sortedMetricTypes() {
 		// Add HELP message as specified by OpenMetrics
 		_, err := out.WriteString(MetricHeaders[metricType] + "\n")
 		if err != nil {
 			return ""
 		}
 
-		metricTypeName := ""
-
-		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
-		if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
-			metricTypeName = "gauge"
-		} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
-			metricTypeName = "counter"
-		} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
-			metricTypeName = "gauge"
-		}
-
 		// Add TYPE message as specified by OpenMetrics
-		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricTypeName))
+		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricTypeName(metricType)))
 		if err != nil {
 			return ""
 		}
@@ -166,6 +146,34 @@ func (m *MetricSet) String() string {
 	return out.String()
 }
 
+// sortedMetricTypes returns the metric types of the set in a stable order.
+func (m *MetricSet) sortedMetricTypes() []MetricType {
+	metricTypes := make([]MetricType, 0, len(m.set))
+	for metricType := range m.set {
+		metricTypes = append(metricTypes, metricType)
+	}
+
+	sort.Slice(metricTypes, func(i, j int) bool {
+		return int(metricTypes[i]) < int(metricTypes[j])
+	})
+
+	return metricTypes
+}
+
+// metricTypeName returns the OpenMetrics type (counter or gauge) of the given metric type.
+func metricTypeName(metricType MetricType) string {
+	// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
+	if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
+		return "gauge"
+	} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
+		return "counter"
+	} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
+		return "gauge"
+	}
+
+	return ""
+}
+
 // MetricSetFromAPI converts api.Metrics to a MetricSet, and returns it.
 func MetricSetFromAPI(metrics *Metrics, labels map[string]string) (*MetricSet, error) {
 	set := NewMetricSet(labels)
@@ -0,0 +1,304 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/lxc/incus/v6/internal/version"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// PushTypePrometheus pushes metrics using the Prometheus remote-write protocol.
+const PushTypePrometheus = "prometheus"
+
+// PushTypeOTLP pushes metrics using the OpenTelemetry protocol over HTTP.
+const PushTypeOTLP = "otlp"
+
+// pushTimeout is the maximum time a single push may take.
+const pushTimeout = 30 * time.Second
+
+// maxErrMsgLen is the maximum length of the error returned by the remote endpoint.
+const maxErrMsgLen = 1024
+
+// Pusher sends metrics to a remote endpoint.
+type Pusher struct {
+	pushType string
+	address  string
+	username string
+	password string
+	token    string
+	labels   map[string]string
+	client   *http.Client
+}
+
+// NewPusher returns a new Pusher for the given endpoint.
+// The labels are added to every pushed sample (Prometheus) or to the resource attributes (OTLP).
+func NewPusher(pushType string, address string, username string, password string, token string, caCert string, labels map[string]string) (*Pusher, error) {
+	if pushType != PushTypePrometheus && pushType != PushTypeOTLP {
+		return nil, fmt.Errorf("Unsupported metrics push type %q", pushType)
+	}
+
+	if address == "" {
+		return nil, fmt.Errorf("A metrics push address is required")
+	}
+
+	p := &Pusher{
+		pushType: pushType,
+		address:  address,
+		username: username,
+		password: password,
+		token:    token,
+		labels:   labels,
+		client:   http.DefaultClient,
+	}
+
+	if caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		p.client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	return p, nil
+}
+
+// Push sends all samples of the metric set, recorded at the given time, to the remote endpoint.
+func (p *Pusher) Push(ctx context.Context, metricSet *MetricSet, timestamp time.Time) error {
+	var body []byte
+	var err error
+
+	headers := map[string]string{}
+
+	switch p.pushType {
+	case PushTypePrometheus:
+		body = s2.EncodeSnappy(nil, metricSet.remoteWriteRequest(p.labels, timestamp))
+		headers["Content-Type"] = "application/x-protobuf"
+		headers["Content-Encoding"] = "snappy"
+		headers["X-Prometheus-Remote-Write-Version"] = "0.1.0"
+	case PushTypeOTLP:
+		body, err = metricSet.otlpRequest(p.labels, timestamp)
+		if err != nil {
+			return err
+		}
+
+		headers["Content-Type"] = "application/json"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	} else if p.username != "" && p.password != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+		line := ""
+
+		if scanner.Scan() {
+			line = scanner.Text()
+		}
+
+		return fmt.Errorf("Metrics endpoint returned HTTP status %s: %s", resp.Status, line)
+	}
+
+	return nil
+}
+
+// remoteWriteRequest encodes the metric set as a Prometheus remote-write WriteRequest protobuf message.
+func (m *MetricSet) remoteWriteRequest(extraLabels map[string]string, timestamp time.Time) []byte {
+	var out []byte
+
+	for _, metricType := range m.sortedMetricTypes() {
+		for _, sample := range m.set[metricType] {
+			labels := map[string]string{}
+			for k, v := range extraLabels {
+				labels[k] = v
+			}
+
+			// Sample labels take precedence over the extra labels.
+			for k, v := range sample.Labels {
+				labels[k] = v
+			}
+
+			labels["__name__"] = MetricNames[metricType]
+
+			labelNames := make([]string, 0, len(labels))
+			for k := range labels {
+				labelNames = append(labelNames, k)
+			}
+
+			// Remote-write requires the labels to be sorted by name.
+			sort.Strings(labelNames)
+
+			var series []byte
+			for _, k := range labelNames {
+				var label []byte
+				label = protowire.AppendTag(label, 1, protowire.BytesType)
+				label = protowire.AppendString(label, k)
+				label = protowire.AppendTag(label, 2, protowire.BytesType)
+				label = protowire.AppendString(label, labels[k])
+
+				series = protowire.AppendTag(series, 1, protowire.BytesType)
+				series = protowire.AppendBytes(series, label)
+			}
+
+			var value []byte
+			value = protowire.AppendTag(value, 1, protowire.Fixed64Type)
+			value = protowire.AppendFixed64(value, math.Float64bits(sample.Value))
+			value = protowire.AppendTag(value, 2, protowire.VarintType)
+			value = protowire.AppendVarint(value, uint64(timestamp.UnixMilli()))
+
+			series = protowire.AppendTag(series, 2, protowire.BytesType)
+			series = protowire.AppendBytes(series, value)
+
+			out = protowire.AppendTag(out, 1, protowire.BytesType)
+			out = protowire.AppendBytes(out, series)
+		}
+	}
+
+	return out
+}
+
+// otlpKeyValue is an OTLP attribute with a string value.
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+// otlpDataPoint is an OTLP number data point.
+type otlpDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	AsDouble     float64        `json:"asDouble"`
+}
+
+// otlpGauge is an OTLP gauge.
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+// otlpSum is an OTLP sum.
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+// otlpMetric is an OTLP metric, either a gauge or a cumulative monotonic sum.
+type otlpMetric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Gauge       *otlpGauge `json:"gauge,omitempty"`
+	Sum         *otlpSum   `json:"sum,omitempty"`
+}
+
+// otlpAggregationTemporalityCumulative marks sums as being cumulative since the start of the process.
+const otlpAggregationTemporalityCumulative = 2
+
+// otlpAttributes converts a label map into sorted OTLP attributes.
+func otlpAttributes(labels map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		attribute := otlpKeyValue{Key: k}
+		attribute.Value.StringValue = labels[k]
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes
+}
+
+// otlpRequest encodes the metric set as an OTLP ExportMetricsServiceRequest using the JSON encoding.
+func (m *MetricSet) otlpRequest(resourceLabels map[string]string, timestamp time.Time) ([]byte, error) {
+	timeUnixNano := strconv.FormatInt(timestamp.UnixNano(), 10)
+
+	metrics := []otlpMetric{}
+	for _, metricType := range m.sortedMetricTypes() {
+		dataPoints := make([]otlpDataPoint, 0, len(m.set[metricType]))
+		for _, sample := range m.set[metricType] {
+			dataPoints = append(dataPoints, otlpDataPoint{
+				Attributes:   otlpAttributes(sample.Labels),
+				TimeUnixNano: timeUnixNano,
+				AsDouble:     sample.Value,
+			})
+		}
+
+		metric := otlpMetric{
+			Name:        MetricNames[metricType],
+			Description: strings.TrimPrefix(MetricHeaders[metricType], "# HELP "+MetricNames[metricType]+" "),
+		}
+
+		if metricTypeName(metricType) == "counter" {
+			metric.Sum = &otlpSum{
+				DataPoints:             dataPoints,
+				AggregationTemporality: otlpAggregationTemporalityCumulative,
+				IsMonotonic:            true,
+			}
+		} else {
+			metric.Gauge = &otlpGauge{DataPoints: dataPoints}
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	req := map[string]any{
+		"resourceMetrics": []map[string]any{{
+			"resource": map[string]any{
+				"attributes": otlpAttributes(resourceLabels),
+			},
+			"scopeMetrics": []map[string]any{{
+				"scope": map[string]string{
+					"name":    "incus",
+					"version": version.Version,
+				},
+				"metrics": metrics,
+			}},
+		}},
+	}
+
+	return json.Marshal(req)
+}
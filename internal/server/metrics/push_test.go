@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// consumeMessage returns the length-delimited fields of a protobuf message grouped by field number.
+func consumeMessage(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	fields := map[protowire.Number][][]byte{}
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], v)
+			b = b[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], protowire.AppendFixed64(nil, v))
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], protowire.AppendVarint(nil, v))
+			b = b[n:]
+		default:
+			t.Fatalf("Unexpected wire type %d", typ)
+		}
+	}
+
+	return fields
+}
+
+func TestPusher_Prometheus(t *testing.T) {
+	var body []byte
+	var headers http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		body, err = s2.Decode(nil, compressed)
+		require.NoError(t, err)
+	}))
+
+	defer server.Close()
+
+	m := NewMetricSet(map[string]string{"project": "default", "name": "c1"})
+	m.AddSamples(CPUs, Sample{Value: 4})
+
+	p, err := NewPusher(PushTypePrometheus, server.URL, "user", "pass", "", "", map[string]string{"site": "lab"})
+	require.NoError(t, err)
+
+	timestamp := time.Unix(1700000000, 0)
+	require.NoError(t, p.Push(context.Background(), m, timestamp))
+
+	require.Equal(t, "snappy", headers.Get("Content-Encoding"))
+	require.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+
+	username, password, ok := (&http.Request{Header: headers}).BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "pass", password)
+
+	// Decode the WriteRequest.
+	series := consumeMessage(t, body)[1]
+	require.Len(t, series, 1)
+
+	timeseries := consumeMessage(t, series[0])
+
+	labels := [][2]string{}
+	for _, label := range timeseries[1] {
+		fields := consumeMessage(t, label)
+		labels = append(labels, [2]string{string(fields[1][0]), string(fields[2][0])})
+	}
+
+	require.Equal(t, [][2]string{
+		{"__name__", "incus_cpu_effective_total"},
+		{"name", "c1"},
+		{"project", "default"},
+		{"site", "lab"},
+	}, labels)
+
+	sample := consumeMessage(t, timeseries[2][0])
+	value, _ := protowire.ConsumeFixed64(sample[1][0])
+	require.Equal(t, float64(4), math.Float64frombits(value))
+
+	ts, _ := protowire.ConsumeVarint(sample[2][0])
+	require.Equal(t, timestamp.UnixMilli(), int64(ts))
+}
+
+func TestPusher_OTLP(t *testing.T) {
+	var req struct {
+		ResourceMetrics []struct {
+			Resource struct {
+				Attributes []otlpKeyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeMetrics []struct {
+				Metrics []otlpMetric `json:"metrics"`
+			} `json:"scopeMetrics"`
+		} `json:"resourceMetrics"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	}))
+
+	defer server.Close()
+
+	m := NewMetricSet(nil)
+	m.AddSamples(CPUs, Sample{Value: 4})
+	m.AddSamples(CPUSecondsTotal, Sample{Value: 10, Labels: map[string]string{"mode": "user"}})
+
+	p, err := NewPusher(PushTypeOTLP, server.URL, "", "", "secret", "", map[string]string{"service.name": "incus"})
+	require.NoError(t, err)
+	require.NoError(t, p.Push(context.Background(), m, time.Unix(1700000000, 0)))
+
+	require.Len(t, req.ResourceMetrics, 1)
+	require.Equal(t, "service.name", req.ResourceMetrics[0].Resource.Attributes[0].Key)
+	require.Equal(t, "incus", req.ResourceMetrics[0].Resource.Attributes[0].Value.StringValue)
+
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	// Counters are exported as cumulative monotonic sums.
+	require.Equal(t, "incus_cpu_seconds_total", metrics[0].Name)
+	require.NotNil(t, metrics[0].Sum)
+	require.True(t, metrics[0].Sum.IsMonotonic)
+	require.Equal(t, "mode", metrics[0].Sum.DataPoints[0].Attributes[0].Key)
+	require.Equal(t, "1700000000000000000", metrics[0].Sum.DataPoints[0].TimeUnixNano)
+
+	// Other metrics are exported as gauges.
+	require.Equal(t, "incus_cpu_effective_total", metrics[1].Name)
+	require.NotNil(t, metrics[1].Gauge)
+	require.Equal(t, float64(4), metrics[1].Gauge.DataPoints[0].AsDouble)
+}
+
+func TestPusher_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+
+	defer server.Close()
+
+	p, err := NewPusher(PushTypePrometheus, server.URL, "", "", "", "", nil)
+	require.NoError(t, err)
+
+	err = p.Push(context.Background(), NewMetricSet(nil), time.Now())
+	require.ErrorContains(t, err, "out of order sample")
+
+	_, err = NewPusher("graphite", server.URL, "", "", "", "", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+// Usage represents the resource usage counters of an instance.
+type Usage struct {
+	// CPUSeconds is the CPU time spent running the instance.
+	CPUSeconds float64
+
+	// MemoryBytes is the memory currently in use by the instance.
+	MemoryBytes float64
+
+	// DiskReadBytes is the amount of data read from disks.
+	DiskReadBytes float64
+
+	// DiskWrittenBytes is the amount of data written to disks.
+	DiskWrittenBytes float64
+
+	// NetworkReceivedBytes is the amount of data received on network interfaces.
+	NetworkReceivedBytes float64
+
+	// NetworkSentBytes is the amount of data sent on network interfaces.
+	NetworkSentBytes float64
+}
+
+// usageIdleCPUModes are the CPU modes not accounted as CPU usage.
+var usageIdleCPUModes = map[string]bool{
+	"idle":   true,
+	"iowait": true,
+	"steal":  true,
+}
+
+// Usage returns the resource usage counters of an instance from its metric set.
+// All values but the memory usage are cumulative since the instance was started.
+func (m *MetricSet) Usage() Usage {
+	sum := func(metricType MetricType, skip func(labels map[string]string) bool) float64 {
+		var total float64
+
+		for _, sample := range m.set[metricType] {
+			if skip != nil && skip(sample.Labels) {
+				continue
+			}
+
+			total += sample.Value
+		}
+
+		return total
+	}
+
+	skipIdle := func(labels map[string]string) bool {
+		return usageIdleCPUModes[labels["mode"]]
+	}
+
+	skipLoopback := func(labels map[string]string) bool {
+		return labels["device"] == "lo"
+	}
+
+	usage := Usage{
+		CPUSeconds:           sum(CPUSecondsTotal, skipIdle),
+		DiskReadBytes:        sum(DiskReadBytesTotal, nil),
+		DiskWrittenBytes:     sum(DiskWrittenBytesTotal, nil),
+		NetworkReceivedBytes: sum(NetworkReceiveBytesTotal, skipLoopback),
+		NetworkSentBytes:     sum(NetworkTransmitBytesTotal, skipLoopback),
+	}
+
+	// Memory in use excludes the page cache. Fall back to the resident set size when no limit is known.
+	if len(m.set[MemoryMemTotalBytes]) > 0 {
+		usage.MemoryBytes = max(sum(MemoryMemTotalBytes, nil)-sum(MemoryMemAvailableBytes, nil), 0)
+	} else {
+		usage.MemoryBytes = sum(MemoryRSSBytes, nil)
+	}
+
+	return usage
+}
+
+// Sub returns the usage accumulated since prev. Counters lower than in prev are assumed to have
+// been reset by an instance restart and are returned as is. The memory usage is left unchanged.
+func (u Usage) Sub(prev Usage) Usage {
+	delta := func(current float64, previous float64) float64 {
+		if current < previous {
+			return current
+		}
+
+		return current - previous
+	}
+
+	return Usage{
+		CPUSeconds:           delta(u.CPUSeconds, prev.CPUSeconds),
+		MemoryBytes:          u.MemoryBytes,
+		DiskReadBytes:        delta(u.DiskReadBytes, prev.DiskReadBytes),
+		DiskWrittenBytes:     delta(u.DiskWrittenBytes, prev.DiskWrittenBytes),
+		NetworkReceivedBytes: delta(u.NetworkReceivedBytes, prev.NetworkReceivedBytes),
+		NetworkSentBytes:     delta(u.NetworkSentBytes, prev.NetworkSentBytes),
+	}
+}
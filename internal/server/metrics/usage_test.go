@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricSet_Usage(t *testing.T) {
+	m := NewMetricSet(map[string]string{"project": "default", "name": "c1"})
+	m.AddSamples(CPUSecondsTotal,
+		Sample{Value: 10, Labels: map[string]string{"mode": "user", "cpu": "0"}},
+		Sample{Value: 5, Labels: map[string]string{"mode": "system", "cpu": "0"}},
+		Sample{Value: 100, Labels: map[string]string{"mode": "idle", "cpu": "0"}},
+		Sample{Value: 2, Labels: map[string]string{"mode": "user", "cpu": "1"}},
+		Sample{Value: 3, Labels: map[string]string{"mode": "iowait", "cpu": "1"}},
+	)
+
+	m.AddSamples(DiskReadBytesTotal, Sample{Value: 1000, Labels: map[string]string{"device": "sda"}}, Sample{Value: 500, Labels: map[string]string{"device": "sdb"}})
+	m.AddSamples(DiskWrittenBytesTotal, Sample{Value: 200, Labels: map[string]string{"device": "sda"}})
+	m.AddSamples(NetworkReceiveBytesTotal, Sample{Value: 300, Labels: map[string]string{"device": "eth0"}}, Sample{Value: 9000, Labels: map[string]string{"device": "lo"}})
+	m.AddSamples(NetworkTransmitBytesTotal, Sample{Value: 400, Labels: map[string]string{"device": "eth0"}}, Sample{Value: 9000, Labels: map[string]string{"device": "lo"}})
+	m.AddSamples(MemoryMemTotalBytes, Sample{Value: 4096})
+	m.AddSamples(MemoryMemAvailableBytes, Sample{Value: 1024})
+	m.AddSamples(MemoryRSSBytes, Sample{Value: 512})
+
+	require.Equal(t, Usage{
+		CPUSeconds:           17,
+		MemoryBytes:          3072,
+		DiskReadBytes:        1500,
+		DiskWrittenBytes:     200,
+		NetworkReceivedBytes: 300,
+		NetworkSentBytes:     400,
+	}, m.Usage())
+
+	// Without a memory limit, the resident set size is used.
+	m = NewMetricSet(nil)
+	m.AddSamples(MemoryRSSBytes, Sample{Value: 512})
+
+	require.Equal(t, Usage{MemoryBytes: 512}, m.Usage())
+}
+
+func TestUsage_Sub(t *testing.T) {
+	prev := Usage{
+		CPUSeconds:           10,
+		MemoryBytes:          2048,
+		DiskReadBytes:        1000,
+		DiskWrittenBytes:     1000,
+		NetworkReceivedBytes: 500,
+		NetworkSentBytes:     500,
+	}
+
+	current := Usage{
+		CPUSeconds:           15,
+		MemoryBytes:          1024,
+		DiskReadBytes:        1500,
+		DiskWrittenBytes:     100,
+		NetworkReceivedBytes: 500,
+		NetworkSentBytes:     800,
+	}
+
+	require.Equal(t, Usage{
+		CPUSeconds:           5,
+		MemoryBytes:          1024,
+		DiskReadBytes:        500,
+		DiskWrittenBytes:     100,
+		NetworkReceivedBytes: 0,
+		NetworkSentBytes:     300,
+	}, current.Sub(prev))
+}
+
+func TestUsage_SubReset(t *testing.T) {
+	prev := Usage{
+		CPUSeconds:           100,
+		MemoryBytes:          4096,
+		DiskReadBytes:        1000,
+		DiskWrittenBytes:     1000,
+		NetworkReceivedBytes: 1000,
+		NetworkSentBytes:     1000,
+	}
+
+	// The instance was restarted, so its counters started over from zero.
+	current := Usage{
+		CPUSeconds:           2,
+		MemoryBytes:          512,
+		DiskReadBytes:        10,
+		DiskWrittenBytes:     20,
+		NetworkReceivedBytes: 30,
+		NetworkSentBytes:     40,
+	}
+
+	require.Equal(t, current, current.Sub(prev))
+
+	// Counters starting from zero are kept as is.
+	require.Equal(t, current, current.Sub(Usage{}))
+
+	// Unchanged counters give no usage.
+	require.Equal(t, Usage{MemoryBytes: 4096}, prev.Sub(prev))
+}
@@ -0,0 +1,235 @@
+package migration
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
+	internalIO "github.com/lxc/incus/v6/internal/io"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// Stream compression algorithms which can be negotiated for the migration filesystem connection.
+const (
+	CompressionZstd = "zstd"
+	CompressionLZ4  = "lz4"
+	CompressionNone = "none"
+)
+
+// serverLimiter limits the combined bandwidth of all the migrations sent by the server.
+var serverLimiter = internalIO.NewRateLimiter(0)
+
+// SetServerBandwidthLimit sets the bandwidth limit (in bytes per second) shared by all the migrations sent by the server.
+func SetServerBandwidthLimit(limit int64) {
+	serverLimiter.SetLimit(limit)
+}
+
+// BandwidthLimit returns the bandwidth limit (in bytes per second) of a single migration from the first of the given
+// `migration.bandwidth_limit` values which is set, ordered from the most specific to the least specific one.
+// Zero is returned when none is set.
+func BandwidthLimit(values ...string) int64 {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		bits, err := units.ParseBitSizeString(value)
+		if err != nil {
+			continue
+		}
+
+		return bits / 8
+	}
+
+	return 0
+}
+
+// CompressionTypes returns the stream compression algorithms to offer in order of preference, starting with the
+// preferred one. No algorithm is offered when the preferred one is "none".
+func CompressionTypes(preferred string) []string {
+	switch preferred {
+	case CompressionNone:
+		return nil
+	case CompressionLZ4:
+		return []string{CompressionLZ4, CompressionZstd}
+	default:
+		return []string{CompressionZstd, CompressionLZ4}
+	}
+}
+
+// MatchCompression returns the first of the offered stream compression algorithms which is supported.
+// An empty string is returned if none of them are, in which case the stream isn't compressed.
+func MatchCompression(offer []string) string {
+	for _, compression := range offer {
+		if slices.Contains([]string{CompressionZstd, CompressionLZ4}, compression) {
+			return compression
+		}
+	}
+
+	return ""
+}
+
+// streamConn is a migration connection whose data is compressed and rate limited.
+//
+// The underlying connection carries a sequence of streams, each terminated by a call to Close. Every stream is
+// compressed as a separate frame so that the other side sees the end of each of them.
+type streamConn struct {
+	conn        io.ReadWriteCloser
+	out         io.Writer
+	compression string
+
+	muw     sync.Mutex
+	encoder streamEncoder
+
+	mur     sync.Mutex
+	reader  io.Reader
+	segment *streamSegment
+}
+
+// streamSegment reads a single stream from the underlying connection.
+type streamSegment struct {
+	conn io.Reader
+	done bool
+}
+
+// Read implements the Reader interface.
+func (s *streamSegment) Read(p []byte) (int, error) {
+	if s.done {
+		return 0, io.EOF
+	}
+
+	n, err := s.conn.Read(p)
+	if err != nil {
+		s.done = true
+	}
+
+	return n, err
+}
+
+// streamEncoder is the compressor of a stream.
+type streamEncoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// NewStreamConn wraps a migration connection so that the data sent over it is compressed with the given algorithm
+// and written at most at limit bytes per second. The data sent by all the connections is also subject to the server
+// wide bandwidth limit. No compression is used when the algorithm is empty and no per-connection limit is applied
+// when the limit is zero.
+func NewStreamConn(conn io.ReadWriteCloser, compression string, limit int64) (io.ReadWriteCloser, error) {
+	if !slices.Contains([]string{"", CompressionZstd, CompressionLZ4}, compression) {
+		return nil, fmt.Errorf("Unsupported migration stream compression %q", compression)
+	}
+
+	var out io.Writer = internalIO.NewRateLimitedWriter(conn, serverLimiter)
+	if limit > 0 {
+		out = internalIO.NewRateLimitedWriter(out, internalIO.NewRateLimiter(limit))
+	}
+
+	return &streamConn{
+		conn:        conn,
+		out:         out,
+		compression: compression,
+	}, nil
+}
+
+// Read implements the Reader interface.
+// It returns io.EOF at the end of each stream, after which the next stream can be read.
+func (s *streamConn) Read(p []byte) (int, error) {
+	s.mur.Lock()
+	defer s.mur.Unlock()
+
+	if s.reader == nil {
+		s.segment = &streamSegment{conn: s.conn}
+
+		switch s.compression {
+		case CompressionZstd:
+			decoder, err := zstd.NewReader(s.segment, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return 0, fmt.Errorf("Failed creating zstd decoder: %w", err)
+			}
+
+			s.reader = decoder.IOReadCloser()
+		case CompressionLZ4:
+			s.reader = lz4.NewReader(s.segment)
+		default:
+			return s.conn.Read(p)
+		}
+	}
+
+	n, err := s.reader.Read(p)
+	if err != nil {
+		closer, ok := s.reader.(io.Closer)
+		if ok {
+			_ = closer.Close()
+		}
+
+		// Skip to the end of the stream in case the decoder stopped at the end of the frame.
+		if err == io.EOF {
+			_, err = io.Copy(io.Discard, s.segment)
+			if err == nil {
+				err = io.EOF
+			}
+		}
+
+		// Start a new decoder for the next stream.
+		s.reader = nil
+	}
+
+	return n, err
+}
+
+// Write implements the Writer interface.
+// The compressed data is flushed after each write as the other side may be waiting for it before replying.
+func (s *streamConn) Write(p []byte) (int, error) {
+	s.muw.Lock()
+	defer s.muw.Unlock()
+
+	if s.encoder == nil {
+		switch s.compression {
+		case CompressionZstd:
+			encoder, err := zstd.NewWriter(s.out, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+			if err != nil {
+				return 0, fmt.Errorf("Failed creating zstd encoder: %w", err)
+			}
+
+			s.encoder = encoder
+		case CompressionLZ4:
+			s.encoder = lz4.NewWriter(s.out)
+		default:
+			return s.out.Write(p)
+		}
+	}
+
+	n, err := s.encoder.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	err = s.encoder.Flush()
+	if err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// Close terminates the current stream.
+func (s *streamConn) Close() error {
+	s.muw.Lock()
+	defer s.muw.Unlock()
+
+	if s.encoder != nil {
+		err := s.encoder.Close()
+		s.encoder = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.conn.Close()
+}
@@ -0,0 +1,154 @@
+package migration
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentedConn mimics the websocket wrapper, where Close sends a barrier ending the current stream.
+type segmentedConn struct {
+	in  chan []byte
+	out chan []byte
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+func newSegmentedConnPair() (*segmentedConn, *segmentedConn) {
+	a := make(chan []byte, 1024)
+	b := make(chan []byte, 1024)
+
+	return &segmentedConn{in: a, out: b}, &segmentedConn{in: b, out: a}
+}
+
+func (c *segmentedConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) == 0 {
+		msg := <-c.in
+		if msg == nil {
+			return 0, io.EOF
+		}
+
+		c.pending = msg
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+func (c *segmentedConn) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		c.out <- bytes.Clone(p)
+	}
+
+	return len(p), nil
+}
+
+func (c *segmentedConn) Close() error {
+	c.out <- nil
+	return nil
+}
+
+func TestBandwidthLimit(t *testing.T) {
+	assert.Equal(t, int64(0), BandwidthLimit())
+	assert.Equal(t, int64(0), BandwidthLimit("", ""))
+	assert.Equal(t, int64(25000000), BandwidthLimit("200Mbit", "1Gbit"))
+	assert.Equal(t, int64(125000000), BandwidthLimit("", "1Gbit"))
+}
+
+func TestMatchCompression(t *testing.T) {
+	assert.Equal(t, CompressionZstd, MatchCompression(CompressionTypes("")))
+	assert.Equal(t, CompressionLZ4, MatchCompression(CompressionTypes(CompressionLZ4)))
+	assert.Equal(t, CompressionLZ4, MatchCompression([]string{"xz", CompressionLZ4}))
+	assert.Equal(t, "", MatchCompression(CompressionTypes(CompressionNone)))
+	assert.Equal(t, "", MatchCompression([]string{"xz"}))
+}
+
+func TestStreamConn(t *testing.T) {
+	for _, compression := range []string{"", CompressionZstd, CompressionLZ4} {
+		t.Run(compression, func(t *testing.T) {
+			left, right := newSegmentedConnPair()
+
+			sender, err := NewStreamConn(left, compression, 0)
+			require.NoError(t, err)
+
+			receiver, err := NewStreamConn(right, compression, 0)
+			require.NoError(t, err)
+
+			// Send multiple streams, including an empty one.
+			streams := [][]byte{
+				bytes.Repeat([]byte("incus"), 100000),
+				{},
+				[]byte("last"),
+			}
+
+			go func() {
+				for _, stream := range streams {
+					for chunk := range slices.Chunk(stream, 4096) {
+						_, _ = sender.Write(chunk)
+					}
+
+					_ = sender.Close()
+				}
+			}()
+
+			for _, stream := range streams {
+				data, err := io.ReadAll(receiver)
+				require.NoError(t, err)
+				assert.Equal(t, stream, data)
+			}
+		})
+	}
+}
+
+func TestStreamConnInteractive(t *testing.T) {
+	left, right := newSegmentedConnPair()
+
+	sender, err := NewStreamConn(left, CompressionZstd, 0)
+	require.NoError(t, err)
+
+	receiver, err := NewStreamConn(right, CompressionZstd, 0)
+	require.NoError(t, err)
+
+	// Each message must be readable by the other side before the stream is closed.
+	buf := make([]byte, 4)
+	for _, msg := range []string{"ping", "pong"} {
+		_, err = sender.Write([]byte(msg))
+		require.NoError(t, err)
+
+		_, err = io.ReadFull(receiver, buf)
+		require.NoError(t, err)
+		assert.Equal(t, msg, string(buf))
+
+		sender, receiver = receiver, sender
+	}
+}
+
+func TestStreamConnLimit(t *testing.T) {
+	left, right := newSegmentedConnPair()
+
+	sender, err := NewStreamConn(left, "", 64*1024)
+	require.NoError(t, err)
+
+	go func() { _, _ = io.Copy(io.Discard, right) }()
+
+	start := time.Now()
+	for range 4 {
+		_, err = sender.Write(make([]byte, 32*1024))
+		require.NoError(t, err)
+	}
+
+	// The first write goes through immediately, the following ones wait for their share of the bandwidth.
+	assert.GreaterOrEqual(t, time.Since(start), 1400*time.Millisecond)
+}
@@ -18,6 +18,7 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/daemon"
@@ -46,6 +47,23 @@ import (
 // Default MTU for bridge interface.
 const bridgeMTUDefault = 1500
 
+// Default MTU for bridge interface when the automatic VXLAN tunnel is in use.
+const bridgeVXLANMTUDefault = 1450
+
+// Default UDP port of the automatic VXLAN tunnel.
+const bridgeVXLANPortDefault = 4789
+
+// Routing protocol ID of the host routes imported from BGP peers.
+// It is distinct from those used by routing daemons (such as "bgp" for FRR and "bird") so only the routes added
+// by Incus are ever updated or removed.
+const bridgeBGPRouteProto = "250"
+
+// Metric of the host routes imported from BGP peers (same as the eBGP distance of common routing daemons).
+const bridgeBGPRouteMetric = "20"
+
+// MAC address used for the flood entries of the automatic VXLAN tunnel.
+var bridgeVXLANFloodMAC = net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
 // bridge represents a bridge network.
 type bridge struct {
 	common
@@ -82,6 +100,45 @@ func (n *bridge) checkClusterWideMACSafe(config map[string]string) error {
 	return nil
 }
 
+// checkBGPImportSubnets checks the BGP import subnets don't overlap with those of other bridge networks.
+// The imported routes of all bridge networks share the host routing table, so each network must own its subnets.
+func (n *bridge) checkBGPImportSubnets(config map[string]string) error {
+	subnets, err := n.bgpImportSubnets(config)
+	if err != nil {
+		return err
+	}
+
+	var projectNetworks map[string]map[int64]api.Network
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load all networks: %w", err)
+	}
+
+	for netProject, networks := range n.bridgeProjectNetworks(projectNetworks) {
+		for _, network := range networks {
+			if netProject == n.project && network.Name == n.name {
+				continue
+			}
+
+			otherSubnets, err := n.bgpImportSubnets(network.Config)
+			if err != nil {
+				return err
+			}
+
+			subnet, otherSubnet := bgpSubnetsOverlap(subnets, otherSubnets)
+			if subnet != nil {
+				return fmt.Errorf("BGP import subnet %q overlaps with %q of network %q in project %q", subnet.String(), otherSubnet.String(), network.Name, netProject)
+			}
+		}
+	}
+
+	return nil
+}
+
 // FillConfig fills requested config with any default values.
 func (n *bridge) FillConfig(config map[string]string) error {
 	// Set some default values where needed.
@@ -177,6 +234,22 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  default: local address
 		//  shortdesc: Override the next-hop for advertised prefixes
 		"bgp.ipv6.nexthop": validate.Optional(validate.IsNetworkAddressV6),
+		// gendoc:generate(entity=network_bridge, group=common, key=bgp.ipv4.import)
+		//
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  default: -
+		//  shortdesc: Comma-separated list of IPv4 subnets to import from BGP peers
+		"bgp.ipv4.import": validate.Optional(validate.IsListOf(validate.IsNetworkV4)),
+		// gendoc:generate(entity=network_bridge, group=common, key=bgp.ipv6.import)
+		//
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  default: -
+		//  shortdesc: Comma-separated list of IPv6 subnets to import from BGP peers
+		"bgp.ipv6.import": validate.Optional(validate.IsListOf(validate.IsNetworkV6)),
 
 		// gendoc:generate(entity=network_bridge, group=common, key=bridge.driver)
 		//
@@ -531,6 +604,47 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  default: `false`
 		//  shortdesc: Whether to log egress traffic that doesn't match any ACL rule
 		"security.acls.default.egress.logged": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=vxlan.mode)
+		//
+		// ---
+		//  type: string
+		//  condition: -
+		//  default: -
+		//  shortdesc: Automatic VXLAN tunnel between cluster members: `static` (flood list of all cluster members) or `evpn` (flood list learned through BGP EVPN)
+		"vxlan.mode": validate.Optional(validate.IsOneOf("static", "evpn")),
+		// gendoc:generate(entity=network_bridge, group=common, key=vxlan.id)
+		//
+		// ---
+		//  type: integer
+		//  condition: `vxlan.mode`
+		//  default: network ID
+		//  shortdesc: VXLAN network identifier (VNI) of the automatic tunnel
+		"vxlan.id": validate.Optional(validate.IsInRange(1, 16777215)),
+		// gendoc:generate(entity=network_bridge, group=common, key=vxlan.port)
+		//
+		// ---
+		//  type: integer
+		//  condition: `vxlan.mode`
+		//  default: `4789`
+		//  shortdesc: UDP port of the automatic VXLAN tunnel
+		"vxlan.port": networkValidPort,
+		// gendoc:generate(entity=network_bridge, group=common, key=vxlan.interface)
+		//
+		// ---
+		//  type: string
+		//  condition: `vxlan.mode`
+		//  default: -
+		//  shortdesc: Specific host interface to use for the automatic VXLAN tunnel
+		"vxlan.interface": validate.Optional(validate.IsInterfaceName),
+		// gendoc:generate(entity=network_bridge, group=common, key=vxlan.local)
+		//
+		// ---
+		//  type: string
+		//  condition: `vxlan.mode`
+		//  default: cluster address
+		//  shortdesc: Local address of the automatic VXLAN tunnel (member-specific)
+		"vxlan.local": validate.Optional(validate.IsNetworkAddress),
 	}
 
 	// Add dynamic validation rules.
@@ -680,6 +794,17 @@ func (n *bridge) Validate(config map[string]string) error {
 		}
 	}
 
+	// Check the automatic VXLAN tunnel interface name.
+	if config["vxlan.mode"] != "" {
+		if len(n.name) > 12 {
+			return fmt.Errorf("Network name too long for VXLAN interface: %s-vx", n.name)
+		}
+
+		if config["tunnel.vx.protocol"] != "" {
+			return fmt.Errorf(`Tunnel name "vx" is reserved when "vxlan.mode" is set`)
+		}
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -688,6 +813,14 @@ func (n *bridge) Validate(config map[string]string) error {
 		}
 	}
 
+	// Check the BGP import subnets aren't used by other networks.
+	if config["bgp.ipv4.import"] != "" || config["bgp.ipv6.import"] != "" {
+		err = n.checkBGPImportSubnets(config)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check IPv4 OVN ranges.
 	if config["ipv4.ovn.ranges"] != "" && util.IsTrueOrEmpty(config["ipv4.dhcp"]) {
 		dhcpSubnet := n.DHCPv4Subnet()
@@ -918,6 +1051,8 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		bridge.MTU = uint32(mtuInt)
 	} else if len(tunnels) > 0 {
 		bridge.MTU = 1400
+	} else if n.config["vxlan.mode"] != "" {
+		bridge.MTU = bridgeVXLANMTUDefault
 	}
 
 	// Decide the MAC address of bridge interface.
@@ -1712,6 +1847,67 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Configure the automatic VXLAN tunnel.
+	err = n.state.BGP.RemoveEVPNRoutesByOwner(n.vxlanEVPNOwner())
+	if err != nil {
+		return err
+	}
+
+	if n.config["vxlan.mode"] != "" {
+		vxlanLocal, err := n.vxlanLocalAddress()
+		if err != nil {
+			return err
+		}
+
+		vxlanPort := n.config["vxlan.port"]
+		if vxlanPort == "" {
+			vxlanPort = strconv.Itoa(bridgeVXLANPortDefault)
+		}
+
+		vxlan := &ip.Vxlan{
+			Link:    ip.Link{Name: n.vxlanName()},
+			VxlanID: strconv.FormatUint(uint64(n.vxlanID()), 10),
+			DevName: n.config["vxlan.interface"],
+			Local:   vxlanLocal.String(),
+			DstPort: vxlanPort,
+		}
+
+		err = vxlan.Add()
+		if err != nil {
+			return err
+		}
+
+		// Bridge it and bring up.
+		err = AttachInterface(n.state, n.name, vxlan.Name)
+		if err != nil {
+			return err
+		}
+
+		err = vxlan.SetMTU(bridge.MTU)
+		if err != nil {
+			return err
+		}
+
+		err = vxlan.SetUp()
+		if err != nil {
+			return err
+		}
+
+		// Advertise the local VTEP to the other members.
+		if n.config["vxlan.mode"] == "evpn" {
+			err = n.state.BGP.AddEVPNRoute(n.vxlanID(), vxlanLocal, n.vxlanEVPNOwner())
+			if err != nil {
+				return err
+			}
+		}
+
+		// Populate the flood list.
+		err = n.vxlanSync()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Generate and load apparmor profiles.
 	err = apparmor.NetworkLoad(n.state.OS, n)
 	if err != nil {
@@ -1900,6 +2096,12 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup the routes imported from BGP peers.
+	err = n.bgpImportSetup(oldConfig)
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 
 	return nil
@@ -1919,6 +2121,23 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Remove the routes imported from BGP peers.
+	subnets, err := n.bgpImportSubnets(n.config)
+	if err != nil {
+		return err
+	}
+
+	err = n.bgpImportApply(nil, subnets)
+	if err != nil {
+		return err
+	}
+
+	// Withdraw the local VTEP.
+	err = n.state.BGP.RemoveEVPNRoutesByOwner(n.vxlanEVPNOwner())
+	if err != nil {
+		return err
+	}
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
@@ -2103,6 +2322,313 @@ func (n *bridge) getTunnels() []string {
 	return tunnels
 }
 
+// vxlanName returns the name of the automatic VXLAN tunnel interface.
+func (n *bridge) vxlanName() string {
+	return fmt.Sprintf("%s-vx", n.name)
+}
+
+// vxlanID returns the VXLAN network identifier of the automatic VXLAN tunnel.
+func (n *bridge) vxlanID() uint32 {
+	if n.config["vxlan.id"] != "" {
+		vni, _ := strconv.ParseUint(n.config["vxlan.id"], 10, 32)
+		return uint32(vni)
+	}
+
+	return uint32(n.id)
+}
+
+// vxlanEVPNOwner returns the owner of the EVPN routes advertised for the network.
+func (n *bridge) vxlanEVPNOwner() string {
+	return fmt.Sprintf("network_%d_evpn", n.id)
+}
+
+// vxlanMemberAddresses returns the VXLAN tunnel address of each cluster member, indexed by member ID.
+// The address is taken from the member's "vxlan.local" setting, falling back to its cluster address.
+func (n *bridge) vxlanMemberAddresses() (map[int64]net.IP, error) {
+	var members []db.NodeInfo
+	var membersConfig map[int64]map[string]string
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		membersConfig, err = tx.GetNetworkMembersConfig(ctx, n.id)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster members: %w", err)
+	}
+
+	addresses := make(map[int64]net.IP, len(members))
+
+	for _, member := range members {
+		address := membersConfig[member.ID]["vxlan.local"]
+		if address == "" {
+			host, _, err := net.SplitHostPort(member.Address)
+			if err != nil {
+				continue
+			}
+
+			address = host
+		}
+
+		addr := net.ParseIP(address)
+		if addr == nil || addr.IsUnspecified() {
+			continue
+		}
+
+		addresses[member.ID] = addr
+	}
+
+	return addresses, nil
+}
+
+// vxlanLocalAddress returns the local address of the automatic VXLAN tunnel.
+func (n *bridge) vxlanLocalAddress() (net.IP, error) {
+	if n.config["vxlan.local"] != "" {
+		return net.ParseIP(n.config["vxlan.local"]), nil
+	}
+
+	addresses, err := n.vxlanMemberAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, ok := addresses[n.state.DB.Cluster.GetNodeID()]
+	if !ok {
+		return nil, fmt.Errorf(`Failed determining the local VXLAN address, "vxlan.local" must be set`)
+	}
+
+	return addr, nil
+}
+
+// vxlanRemotes returns the addresses of the remote ends of the automatic VXLAN tunnel.
+func (n *bridge) vxlanRemotes() ([]net.IP, error) {
+	if n.config["vxlan.mode"] == "evpn" {
+		remotes, err := n.state.BGP.EVPNRemoteVTEPs(n.vxlanID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting EVPN remote VTEPs: %w", err)
+		}
+
+		return remotes, nil
+	}
+
+	addresses, err := n.vxlanMemberAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := []net.IP{}
+	localMemberID := n.state.DB.Cluster.GetNodeID()
+
+	for memberID, addr := range addresses {
+		if memberID == localMemberID {
+			continue
+		}
+
+		remotes = append(remotes, addr)
+	}
+
+	return remotes, nil
+}
+
+// vxlanSync updates the flood list of the automatic VXLAN tunnel to match its current remote ends.
+func (n *bridge) vxlanSync() error {
+	remotes, err := n.vxlanRemotes()
+	if err != nil {
+		return err
+	}
+
+	fdb := &ip.FDB{DevName: n.vxlanName(), MAC: bridgeVXLANFloodMAC}
+	entries, err := fdb.Show()
+	if err != nil {
+		return fmt.Errorf("Failed listing VXLAN flood entries: %w", err)
+	}
+
+	hasAddress := func(list []net.IP, addr net.IP) bool {
+		return slices.ContainsFunc(list, func(entry net.IP) bool { return entry.Equal(addr) })
+	}
+
+	existing := make([]net.IP, 0, len(entries))
+
+	// Remove the entries of remotes which are gone.
+	for _, entry := range entries {
+		if hasAddress(remotes, entry.Dst) {
+			existing = append(existing, entry.Dst)
+			continue
+		}
+
+		err = entry.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed removing VXLAN flood entry for %q: %w", entry.Dst, err)
+		}
+	}
+
+	// Add the entries of new remotes.
+	for _, remote := range remotes {
+		if hasAddress(existing, remote) {
+			continue
+		}
+
+		entry := &ip.FDB{DevName: n.vxlanName(), MAC: bridgeVXLANFloodMAC, Dst: remote}
+		err = entry.Append()
+		if err != nil {
+			return fmt.Errorf("Failed adding VXLAN flood entry for %q: %w", remote, err)
+		}
+	}
+
+	return nil
+}
+
+// HandleBGPRoutes refreshes the host routes imported from the BGP peers of the network.
+func (n *bridge) HandleBGPRoutes() error {
+	if !n.isRunning() {
+		return nil
+	}
+
+	return n.bgpImportSetup(nil)
+}
+
+// bgpImportSetup adds the routes received from the BGP peers that match the import subnets to the host routing table.
+// Routes previously imported within the current or old import subnets that are no longer received are removed.
+func (n *bridge) bgpImportSetup(oldConfig map[string]string) error {
+	subnets, err := n.bgpImportSubnets(n.config)
+	if err != nil {
+		return err
+	}
+
+	oldSubnets, err := n.bgpImportSubnets(oldConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(subnets) == 0 && len(oldSubnets) == 0 {
+		return nil
+	}
+
+	routes, err := n.bgpImportRoutes(n.config)
+	if err != nil {
+		return err
+	}
+
+	return n.bgpImportApply(routes, append(subnets, oldSubnets...))
+}
+
+// bgpImportApply syncs the BGP routes of the host routing table within the given subnets with the provided routes.
+func (n *bridge) bgpImportApply(routes []bgp.Route, subnets []*net.IPNet) error {
+	if len(subnets) == 0 {
+		return nil
+	}
+
+	// Index the wanted routes.
+	wanted := map[string]string{}
+	for _, route := range routes {
+		wanted[route.Prefix.String()] = route.Nexthop.String()
+	}
+
+	// Remove the existing routes which are no longer wanted.
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		r := &ip.Route{
+			Proto:  bridgeBGPRouteProto,
+			Family: family,
+		}
+
+		existing, err := r.Show()
+		if err != nil {
+			return fmt.Errorf("Failed listing BGP routes: %w", err)
+		}
+
+		for _, line := range existing {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+
+			// Extract the next-hop.
+			var nexthop string
+			for i := 1; i < len(fields)-1; i++ {
+				if fields[i] == "via" {
+					nexthop = fields[i+1]
+				}
+			}
+
+			prefix := fields[0]
+			if prefix == "default" {
+				prefix = "0.0.0.0/0"
+				if family == ip.FamilyV6 {
+					prefix = "::/0"
+				}
+			}
+
+			if !strings.Contains(prefix, "/") {
+				prefixIP := IPToNet(net.ParseIP(prefix))
+				prefix = prefixIP.String()
+			}
+
+			_, prefixNet, err := net.ParseCIDR(prefix)
+			if err != nil {
+				continue
+			}
+
+			if !slices.ContainsFunc(subnets, func(subnet *net.IPNet) bool { return SubnetContains(subnet, prefixNet) }) {
+				continue // Outside of the subnets managed by the network.
+			}
+
+			if wanted[prefixNet.String()] == nexthop {
+				continue
+			}
+
+			r := &ip.Route{
+				Route:  fields[0],
+				Proto:  bridgeBGPRouteProto,
+				Metric: bridgeBGPRouteMetric,
+				Family: family,
+			}
+
+			err = r.Delete()
+			if err != nil {
+				return fmt.Errorf("Failed removing BGP route %q: %w", fields[0], err)
+			}
+		}
+	}
+
+	// Add or update the wanted routes.
+	for _, route := range routes {
+		family := ip.FamilyV4
+		if route.Prefix.IP.To4() == nil {
+			family = ip.FamilyV6
+		}
+
+		r := &ip.Route{
+			Proto:  bridgeBGPRouteProto,
+			Metric: bridgeBGPRouteMetric,
+			Family: family,
+		}
+
+		err := r.Replace([]string{route.Prefix.String(), "via", route.Nexthop.String()})
+		if err != nil {
+			// The next-hop may not be reachable from this host, skip the route.
+			n.logger.Warn("Failed adding BGP route", logger.Ctx{"prefix": route.Prefix.String(), "nexthop": route.Nexthop.String(), "err": err})
+		}
+	}
+
+	return nil
+}
+
+// HandleHeartbeat refreshes the flood list of the automatic VXLAN tunnel.
+func (n *bridge) HandleHeartbeat(heartbeatData *cluster.APIHeartbeat) error {
+	if n.config["vxlan.mode"] == "" || !InterfaceExists(n.vxlanName()) {
+		return nil
+	}
+
+	return n.vxlanSync()
+}
+
 // bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
 func (n *bridge) bootRoutesV4() ([]string, error) {
 	r := &ip.Route{
@@ -547,6 +547,11 @@ func (n *common) HandleHeartbeat(heartbeatData *cluster.APIHeartbeat) error {
 	return nil
 }
 
+// HandleBGPRoutes is a no-op.
+func (n *common) HandleBGPRoutes() error {
+	return nil
+}
+
 // notifyDependentNetworks allows any dependent networks to apply changes to themselves when this network changes.
 func (n *common) notifyDependentNetworks(changedKeys []string) {
 	if n.Project() != api.ProjectDefaultName {
@@ -861,6 +866,93 @@ func (n *common) bgpGetPeers(config map[string]string) []string {
 	return peers
 }
 
+// bgpImportSubnets returns the subnets from bgp.ipv4.import and bgp.ipv6.import.
+func (n *common) bgpImportSubnets(config map[string]string) ([]*net.IPNet, error) {
+	subnets := []*net.IPNet{}
+	for _, ipVersion := range []uint{4, 6} {
+		importKey := fmt.Sprintf("bgp.ipv%d.import", ipVersion)
+		for _, value := range util.SplitNTrimSpace(config[importKey], ",", -1, true) {
+			_, subnet, err := net.ParseCIDR(value)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing %q: %w", importKey, err)
+			}
+
+			subnets = append(subnets, subnet)
+		}
+	}
+
+	return subnets, nil
+}
+
+// bgpImportRoutes returns the routes received from the BGP peers in the given configuration which are allowed by
+// its import subnets. A route is allowed if its prefix is fully contained in one of the import subnets.
+// Returns nil if no import subnets are configured.
+func (n *common) bgpImportRoutes(config map[string]string) ([]bgp.Route, error) {
+	subnets, err := n.bgpImportSubnets(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(subnets) == 0 {
+		return nil, nil
+	}
+
+	peers := []net.IP{}
+	for _, peer := range n.bgpGetPeers(config) {
+		fields := strings.Split(peer, ",")
+		peerAddress := net.ParseIP(fields[0])
+		if peerAddress != nil {
+			peers = append(peers, peerAddress)
+		}
+	}
+
+	received, err := n.state.BGP.ReceivedRoutes(peers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting routes received from BGP peers: %w", err)
+	}
+
+	return bgpFilterRoutes(received, subnets), nil
+}
+
+// bgpFilterRoutes returns the routes whose prefix is fully contained in one of the subnets.
+func bgpFilterRoutes(routes []bgp.Route, subnets []*net.IPNet) []bgp.Route {
+	filtered := []bgp.Route{}
+	for _, route := range routes {
+		if slices.ContainsFunc(subnets, func(subnet *net.IPNet) bool { return SubnetContains(subnet, &route.Prefix) }) {
+			filtered = append(filtered, route)
+		}
+	}
+
+	return filtered
+}
+
+// bgpSubnetsOverlap returns the first pair of overlapping subnets between the two lists, or nil if none overlap.
+func bgpSubnetsOverlap(subnets []*net.IPNet, otherSubnets []*net.IPNet) (*net.IPNet, *net.IPNet) {
+	for _, subnet := range subnets {
+		for _, otherSubnet := range otherSubnets {
+			if SubnetContains(subnet, otherSubnet) || SubnetContains(otherSubnet, subnet) {
+				return subnet, otherSubnet
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// bgpImportState returns the network state representation of the imported routes.
+func (n *common) bgpImportState(routes []bgp.Route) *api.NetworkStateBGP {
+	state := &api.NetworkStateBGP{Routes: []api.NetworkStateBGPRoute{}}
+	for _, route := range routes {
+		state.Routes = append(state.Routes, api.NetworkStateBGPRoute{
+			Prefix:  route.Prefix.String(),
+			Nexthop: route.Nexthop.String(),
+			Peer:    route.Peer.String(),
+		})
+	}
+
+	return state
+}
+
 // forwardValidate validates the forward request.
 func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwardPut) ([]*forwardPortMap, error) {
 	if listenAddress == nil {
@@ -1569,7 +1661,22 @@ func (n *common) peerUsedBy(peerName string, firstOnly bool) ([]string, error) {
 }
 
 func (n *common) State() (*api.NetworkState, error) {
-	return resources.GetNetworkState(n.name)
+	state, err := resources.GetNetworkState(n.name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the routes imported from BGP peers.
+	routes, err := n.bgpImportRoutes(n.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if routes != nil {
+		state.BGP = n.bgpImportState(routes)
+	}
+
+	return state, nil
 }
 
 func (n *common) setUnavailable() {
@@ -0,0 +1,129 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/bgp"
+)
+
+func TestBGPImportSubnets(t *testing.T) {
+	n := &common{}
+
+	subnets, err := n.bgpImportSubnets(map[string]string{
+		"bgp.ipv4.import": "198.51.100.0/24, 203.0.113.0/25",
+		"bgp.ipv6.import": "2001:db8:1::/48",
+	})
+	require.NoError(t, err)
+
+	result := []string{}
+	for _, subnet := range subnets {
+		result = append(result, subnet.String())
+	}
+
+	assert.Equal(t, []string{"198.51.100.0/24", "203.0.113.0/25", "2001:db8:1::/48"}, result)
+
+	subnets, err = n.bgpImportSubnets(map[string]string{})
+	require.NoError(t, err)
+	assert.Empty(t, subnets)
+
+	_, err = n.bgpImportSubnets(map[string]string{"bgp.ipv4.import": "198.51.100.0"})
+	assert.ErrorContains(t, err, `Failed parsing "bgp.ipv4.import"`)
+}
+
+func TestBGPFilterRoutes(t *testing.T) {
+	route := func(prefix string) bgp.Route {
+		_, subnet, _ := net.ParseCIDR(prefix)
+		return bgp.Route{Prefix: *subnet, Nexthop: net.ParseIP("192.0.2.10"), Peer: net.ParseIP("192.0.2.1")}
+	}
+
+	_, subnetV4, _ := net.ParseCIDR("198.51.100.0/24")
+	_, subnetV6, _ := net.ParseCIDR("2001:db8:1::/48")
+
+	routes := []bgp.Route{
+		route("198.51.100.0/24"),
+		route("198.51.100.128/25"),
+		route("198.51.0.0/16"),
+		route("203.0.113.0/24"),
+		route("0.0.0.0/0"),
+		route("2001:db8:1:2::/64"),
+		route("2001:db8::/32"),
+	}
+
+	// Only the routes fully contained in one of the subnets are kept.
+	filtered := bgpFilterRoutes(routes, []*net.IPNet{subnetV4, subnetV6})
+	assert.Equal(t, []bgp.Route{routes[0], routes[1], routes[5]}, filtered)
+
+	assert.Empty(t, bgpFilterRoutes(routes, nil))
+	assert.Empty(t, bgpFilterRoutes(nil, []*net.IPNet{subnetV4}))
+}
+
+func TestBGPSubnetsOverlap(t *testing.T) {
+	subnets := func(values ...string) []*net.IPNet {
+		result := []*net.IPNet{}
+		for _, value := range values {
+			_, subnet, _ := net.ParseCIDR(value)
+			result = append(result, subnet)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name         string
+		subnets      []*net.IPNet
+		otherSubnets []*net.IPNet
+		want         []string
+	}{
+		{
+			name:         "disjoint",
+			subnets:      subnets("198.51.100.0/24", "2001:db8:1::/48"),
+			otherSubnets: subnets("203.0.113.0/24", "2001:db8:2::/48"),
+		},
+		{
+			name:         "different families",
+			subnets:      subnets("0.0.0.0/0"),
+			otherSubnets: subnets("::/0"),
+		},
+		{
+			name:         "same subnet",
+			subnets:      subnets("198.51.100.0/24"),
+			otherSubnets: subnets("203.0.113.0/24", "198.51.100.0/24"),
+			want:         []string{"198.51.100.0/24", "198.51.100.0/24"},
+		},
+		{
+			name:         "contained",
+			subnets:      subnets("2001:db8:1:2::/64"),
+			otherSubnets: subnets("2001:db8::/32"),
+			want:         []string{"2001:db8:1:2::/64", "2001:db8::/32"},
+		},
+		{
+			name:         "containing",
+			subnets:      subnets("198.51.0.0/16"),
+			otherSubnets: subnets("198.51.100.128/25"),
+			want:         []string{"198.51.0.0/16", "198.51.100.128/25"},
+		},
+		{
+			name:    "no other subnets",
+			subnets: subnets("198.51.100.0/24"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subnet, otherSubnet := bgpSubnetsOverlap(tt.subnets, tt.otherSubnets)
+			if tt.want == nil {
+				assert.Nil(t, subnet)
+				assert.Nil(t, otherSubnet)
+				return
+			}
+
+			require.NotNil(t, subnet)
+			require.NotNil(t, otherSubnet)
+			assert.Equal(t, tt.want, []string{subnet.String(), otherSubnet.String()})
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/iprange"
+	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -205,6 +206,30 @@ func (n *ovn) State() (*api.NetworkState, error) {
 		}
 	}
 
+	// Get the routes imported from the BGP peers of the uplink network.
+	var bgpState *api.NetworkStateBGP
+	if !slices.Contains([]string{"", "none"}, n.config["network"]) {
+		var uplink *api.Network
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, uplink, _, err = tx.GetNetworkInAnyState(ctx, api.ProjectDefaultName, n.config["network"])
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load uplink network %q: %w", n.config["network"], err)
+		}
+
+		routes, err := n.bgpUplinkImportRoutes(uplink.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		if routes != nil {
+			bgpState = n.bgpImportState(routes)
+		}
+	}
+
 	// Get the switch MTU.
 	mtu := int(n.getBridgeMTU())
 	if mtu == 0 {
@@ -224,6 +249,7 @@ func (n *ovn) State() (*api.NetworkState, error) {
 			UplinkIPv4:    uplinkIPv4,
 			UplinkIPv6:    uplinkIPv6,
 		},
+		BGP: bgpState,
 	}, nil
 }
 
@@ -2409,6 +2435,12 @@ func (n *ovn) setup(update bool) error {
 				return fmt.Errorf("Failed adding default routes: %w", err)
 			}
 		}
+
+		// Add the routes imported from the BGP peers of the uplink network.
+		err = n.bgpImportSetup(uplinkNetwork)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Gather internal router port IPs (in CIDR format).
@@ -4933,6 +4965,123 @@ func (n *ovn) uplinkHasIngressRoutedAnycastIPv6(uplink *api.Network) bool {
 	return util.IsTrue(uplink.Config["ipv6.routes.anycast"]) && uplink.Config["ovn.ingress_mode"] == "routed"
 }
 
+// HandleBGPRoutes refreshes the logical router routes imported from the BGP peers of the uplink network.
+func (n *ovn) HandleBGPRoutes() error {
+	if n.state.OS.MockMode || n.config["network"] == "" || n.config["network"] == "none" {
+		return nil
+	}
+
+	return n.bgpImportSetup(n.config["network"])
+}
+
+// bgpUplinkImportRoutes returns the routes received from the BGP peers of the uplink network which can be added
+// to the logical router. Default routes are skipped (the router already has them) as well as routes whose
+// next-hop isn't on the uplink network.
+func (n *ovn) bgpUplinkImportRoutes(uplinkConfig map[string]string) ([]bgp.Route, error) {
+	received, err := n.bgpImportRoutes(uplinkConfig)
+	if err != nil || received == nil {
+		return received, err
+	}
+
+	uplinkSubnets := []*net.IPNet{}
+	for _, key := range []string{"ipv4.address", "ipv4.gateway", "ipv6.address", "ipv6.gateway"} {
+		_, subnet, err := net.ParseCIDR(uplinkConfig[key])
+		if err == nil {
+			uplinkSubnets = append(uplinkSubnets, subnet)
+		}
+	}
+
+	routes := []bgp.Route{}
+	for _, route := range received {
+		ones, _ := route.Prefix.Mask.Size()
+		if ones == 0 {
+			continue
+		}
+
+		if !slices.ContainsFunc(uplinkSubnets, func(subnet *net.IPNet) bool { return subnet.Contains(route.Nexthop) }) {
+			continue
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// bgpImportSetup adds the routes received from the BGP peers of the uplink network to the logical router and
+// removes the previously imported routes which are no longer received.
+func (n *ovn) bgpImportSetup(uplinkName string) error {
+	// Only servers running the BGP server know about the received routes.
+	if !n.state.BGP.Debug().Server.Running {
+		return nil
+	}
+
+	var uplink *api.Network
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		_, uplink, _, err = tx.GetNetworkInAnyState(ctx, api.ProjectDefaultName, uplinkName)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load uplink network %q: %w", uplinkName, err)
+	}
+
+	routes, err := n.bgpUplinkImportRoutes(uplink.Config)
+	if err != nil {
+		return err
+	}
+
+	wantedRoutes := make([]networkOVN.OVNRouterRoute, 0, len(routes))
+	for _, route := range routes {
+		wantedRoutes = append(wantedRoutes, networkOVN.OVNRouterRoute{
+			Prefix:  route.Prefix,
+			NextHop: route.Nexthop,
+			Port:    n.getRouterExtPortName(),
+		})
+	}
+
+	existingRoutes, err := n.ovnnb.GetLogicalRouterRoutes(context.TODO(), n.getRouterName())
+	if err != nil {
+		return fmt.Errorf("Failed getting router routes: %w", err)
+	}
+
+	// Besides the default routes, all the routes through the uplink port are imported routes.
+	staleRoutes := []net.IPNet{}
+	for _, existing := range existingRoutes {
+		ones, _ := existing.Prefix.Mask.Size()
+		if existing.Port != n.getRouterExtPortName() || ones == 0 {
+			continue
+		}
+
+		if slices.ContainsFunc(wantedRoutes, func(route networkOVN.OVNRouterRoute) bool {
+			return route.Prefix.String() == existing.Prefix.String() && route.NextHop.Equal(existing.NextHop)
+		}) {
+			continue
+		}
+
+		staleRoutes = append(staleRoutes, existing.Prefix)
+	}
+
+	if len(staleRoutes) > 0 {
+		err = n.ovnnb.DeleteLogicalRouterRoute(context.TODO(), n.getRouterName(), staleRoutes...)
+		if err != nil {
+			return fmt.Errorf("Failed removing BGP routes: %w", err)
+		}
+	}
+
+	if len(wantedRoutes) > 0 {
+		err = n.ovnnb.CreateLogicalRouterRoute(context.TODO(), n.getRouterName(), true, wantedRoutes...)
+		if err != nil {
+			return fmt.Errorf("Failed adding BGP routes: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // handleDependencyChange applies changes from uplink network if specific watched keys have changed.
 func (n *ovn) handleDependencyChange(uplinkName string, uplinkConfig map[string]string, changedKeys []string) error {
 	// Detect changes that need to be applied to the network.
@@ -4950,6 +5099,16 @@ func (n *ovn) handleDependencyChange(uplinkName string, uplinkConfig map[string]
 		}
 	}
 
+	// Refresh the imported routes if the uplink's BGP configuration has changed.
+	if slices.ContainsFunc(changedKeys, func(k string) bool { return strings.HasPrefix(k, "bgp.") }) {
+		n.logger.Debug("Applying BGP changes from uplink network", logger.Ctx{"uplink": uplinkName})
+
+		err := n.bgpImportSetup(uplinkName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Add or remove the instance NIC l2proxy DNAT_AND_SNAT rules if uplink's ovn.ingress_mode has changed.
 	if slices.Contains(changedKeys, "ovn.ingress_mode") {
 		n.logger.Debug("Applying ingress mode changes from uplink network to instance NICs", logger.Ctx{"uplink": uplinkName})
@@ -45,6 +45,8 @@ func (n *physical) Validate(config map[string]string) error {
 		"ipv6.routes.anycast":         validate.Optional(validate.IsBool),
 		"dns.nameservers":             validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
 		"ovn.ingress_mode":            validate.Optional(validate.IsOneOf("l2proxy", "routed")),
+		"bgp.ipv4.import":             validate.Optional(validate.IsListOf(validate.IsNetworkV4)),
+		"bgp.ipv6.import":             validate.Optional(validate.IsListOf(validate.IsNetworkV6)),
 		"volatile.last_state.created": validate.Optional(validate.IsBool),
 	}
 
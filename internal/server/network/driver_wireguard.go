@@ -0,0 +1,741 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// Default MTU for wireguard networks (leaves room for the WireGuard encapsulation over IPv6).
+const wireguardMTUDefault = 1420
+
+// Default UDP port used by wireguard networks.
+const wireguardPortDefault = 51820
+
+// wireguardPeersApplied tracks the last peer configuration applied to each wireguard network.
+var (
+	wireguardPeersApplied   = map[int64]string{}
+	wireguardPeersAppliedMu sync.Mutex
+)
+
+// wireguard represents a WireGuard overlay network.
+//
+// Each cluster member has a local bridge, using the member-specific subnet, that instances connect to.
+// Traffic to the subnets of the other members and of the explicit peers is routed through a WireGuard
+// interface which is configured with every other member of the network as a peer.
+type wireguard struct {
+	common
+}
+
+// DBType returns the network type DB ID.
+func (n *wireguard) DBType() db.NetworkType {
+	return db.NetworkTypeWireguard
+}
+
+// ValidateName validates network name.
+func (n *wireguard) ValidateName(name string) error {
+	err := validate.IsInterfaceName(name)
+	if err != nil {
+		return err
+	}
+
+	// The WireGuard interface name is derived from the network name.
+	if len(name) > 12 {
+		return fmt.Errorf("Network name too long for WireGuard interface (max 12 characters)")
+	}
+
+	// Apply common name validation that applies to all network types.
+	return n.common.ValidateName(name)
+}
+
+// Validate network config.
+func (n *wireguard) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"bridge.mtu": validate.Optional(validate.IsNetworkMTU),
+
+		"ipv4.dhcp":        validate.Optional(validate.IsBool),
+		"ipv4.dhcp.expiry": validate.IsAny,
+		"ipv4.firewall":    validate.Optional(validate.IsBool),
+
+		"ipv6.dhcp":          validate.Optional(validate.IsBool),
+		"ipv6.dhcp.expiry":   validate.IsAny,
+		"ipv6.dhcp.stateful": validate.Optional(validate.IsBool),
+		"ipv6.firewall":      validate.Optional(validate.IsBool),
+
+		"dns.domain":      validate.IsAny,
+		"dns.mode":        validate.Optional(validate.IsOneOf("dynamic", "managed", "none")),
+		"dns.nameservers": validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+		"dns.search":      validate.IsAny,
+
+		"wireguard.ipv4.address": validate.Optional(func(value string) error {
+			if value == "none" {
+				return nil
+			}
+
+			return validate.IsNetworkAddressCIDRV4(value)
+		}),
+		"wireguard.ipv6.address": validate.Optional(func(value string) error {
+			if value == "none" {
+				return nil
+			}
+
+			return validate.IsNetworkAddressCIDRV6(value)
+		}),
+		"wireguard.endpoint":             validate.Optional(validate.IsListenAddress(false, false, false)),
+		"wireguard.port":                 networkValidPort,
+		"wireguard.persistent_keepalive": validate.Optional(validate.IsUint32),
+
+		"volatile.wireguard.public_key": validate.Optional(validateWireguardKey),
+	}
+
+	// Add dynamic validation rules.
+	for k := range config {
+		// Peer keys have the peer name in their name, extract the suffix.
+		if !strings.HasPrefix(k, "wireguard.peers.") {
+			continue
+		}
+
+		fields := strings.Split(k, ".")
+		if len(fields) != 4 {
+			return fmt.Errorf("Invalid network configuration key: %s", k)
+		}
+
+		switch fields[3] {
+		case "public_key":
+			rules[k] = validate.Required(validateWireguardKey)
+		case "endpoint":
+			rules[k] = validate.Optional(validate.IsListenAddress(false, false, true))
+		case "allowed_ips":
+			rules[k] = validate.Optional(validate.IsListOf(validate.IsNetwork))
+		}
+
+		// Every peer requires a public key.
+		peerKey := fmt.Sprintf("wireguard.peers.%s.public_key", fields[2])
+		_, found := rules[peerKey]
+		if !found {
+			rules[peerKey] = validate.Required(validateWireguardKey)
+		}
+	}
+
+	// Validate the configuration.
+	err := n.validate(config, rules)
+	if err != nil {
+		return err
+	}
+
+	// Check the MTU is large enough for the configured addresses.
+	if config["bridge.mtu"] != "" {
+		mtu, err := strconv.ParseInt(config["bridge.mtu"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid value for an integer: %s", config["bridge.mtu"])
+		}
+
+		if !util.IsNoneOrEmpty(config["wireguard.ipv6.address"]) && mtu < 1280 {
+			return fmt.Errorf("The minimum MTU for an IPv6 network is 1280")
+		}
+	}
+
+	return nil
+}
+
+// wireguardBridgeConfig returns the config of the local bridge of a wireguard network from the network config.
+func wireguardBridgeConfig(config map[string]string) map[string]string {
+	bridgeConfig := map[string]string{
+		"bridge.mtu":   strconv.Itoa(wireguardMTUDefault),
+		"ipv4.address": "none",
+		"ipv6.address": "none",
+	}
+
+	for k, v := range config {
+		if strings.HasPrefix(k, "wireguard.") || strings.HasPrefix(k, "volatile.") {
+			continue
+		}
+
+		bridgeConfig[k] = v
+	}
+
+	if config["wireguard.ipv4.address"] != "" {
+		bridgeConfig["ipv4.address"] = config["wireguard.ipv4.address"]
+	}
+
+	if config["wireguard.ipv6.address"] != "" {
+		bridgeConfig["ipv6.address"] = config["wireguard.ipv6.address"]
+	}
+
+	return bridgeConfig
+}
+
+// BridgeConfig returns the config of the local bridge interface that the instances connected to the network
+// are attached to. For networks other than wireguard, this is the network config itself.
+func BridgeConfig(n Network) map[string]string {
+	wg, ok := n.(*wireguard)
+	if ok {
+		return wireguardBridgeConfig(wg.config)
+	}
+
+	return n.Config()
+}
+
+// localBridge returns the bridge driver managing the local bridge of the network.
+func (n *wireguard) localBridge(config map[string]string) *bridge {
+	b := &bridge{}
+
+	_ = b.init(n.state, n.id, n.project, &api.Network{
+		Name:        n.name,
+		Description: n.description,
+		Type:        "bridge",
+		Config:      wireguardBridgeConfig(config),
+		Status:      n.status,
+		Managed:     n.managed,
+	}, n.nodes)
+
+	return b
+}
+
+// wireguardName returns the name of the WireGuard interface of the network.
+func (n *wireguard) wireguardName() string {
+	return fmt.Sprintf("%s-wg", n.name)
+}
+
+// isRunning returns whether the network is up.
+func (n *wireguard) isRunning() bool {
+	return InterfaceExists(n.name)
+}
+
+// Create checks whether the bridge and WireGuard interface names are used already.
+func (n *wireguard) Create(clientType request.ClientType) error {
+	n.logger.Debug("Create", logger.Ctx{"clientType": clientType, "config": n.config})
+
+	for _, name := range []string{n.name, n.wireguardName()} {
+		if InterfaceExists(name) {
+			return fmt.Errorf("Network interface %q already exists", name)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes a network.
+func (n *wireguard) Delete(clientType request.ClientType) error {
+	n.logger.Debug("Delete", logger.Ctx{"clientType": clientType})
+
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return n.localBridge(n.config).Delete(clientType)
+}
+
+// Rename renames a network.
+func (n *wireguard) Rename(newName string) error {
+	n.logger.Debug("Rename", logger.Ctx{"newName": newName})
+
+	if InterfaceExists(newName) {
+		return fmt.Errorf("Network interface %q already exists", newName)
+	}
+
+	// Bring the network down.
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Rename common steps.
+	err := n.common.rename(newName)
+	if err != nil {
+		return err
+	}
+
+	// Bring the network up.
+	err = n.Start()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Start starts the network.
+func (n *wireguard) Start() error {
+	n.logger.Debug("Start")
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { n.setUnavailable() })
+
+	err := n.setup(nil)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	// Ensure network is marked as available now its started.
+	n.setAvailable()
+
+	return nil
+}
+
+// setup sets up the local bridge and the WireGuard interface.
+func (n *wireguard) setup(oldConfig map[string]string) error {
+	// If we are in mock mode, just no-op.
+	if n.state.OS.MockMode {
+		return nil
+	}
+
+	n.logger.Debug("Setting up network")
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Set up the local bridge.
+	var oldBridgeConfig map[string]string
+	if oldConfig != nil {
+		oldBridgeConfig = wireguardBridgeConfig(oldConfig)
+	}
+
+	err := n.localBridge(n.config).setup(oldBridgeConfig)
+	if err != nil {
+		return err
+	}
+
+	// Load or generate the private key of the local member.
+	privateKey, err := n.privateKey()
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := wireguardPublicKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	// Publish the public key so that the other members can add the local member as a peer.
+	if n.config["volatile.wireguard.public_key"] != publicKey {
+		newConfig := util.CloneMap(n.config)
+		newConfig["volatile.wireguard.public_key"] = publicKey
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetwork(ctx, n.project, n.name, n.description, newConfig)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed saving WireGuard public key: %w", err)
+		}
+
+		n.config = newConfig
+	}
+
+	// Create the WireGuard interface if needed.
+	mtu, err := strconv.ParseUint(wireguardBridgeConfig(n.config)["bridge.mtu"], 10, 32)
+	if err != nil {
+		return fmt.Errorf("Invalid MTU: %w", err)
+	}
+
+	link := &ip.Wireguard{
+		Link: ip.Link{
+			Name: n.wireguardName(),
+			MTU:  uint32(mtu),
+		},
+	}
+
+	if !InterfaceExists(link.Name) {
+		err = link.Add()
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = link.Delete() })
+	} else {
+		err = link.SetMTU(link.MTU)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = link.SetUp()
+	if err != nil {
+		return err
+	}
+
+	// Configure the peers.
+	err = n.setupPeers(privateKey, true)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// privateKey returns the WireGuard private key of the local member, generating it if missing.
+// The private key never leaves the local member.
+func (n *wireguard) privateKey() (string, error) {
+	keyPath := internalUtil.VarPath("networks", n.name, "wireguard.key")
+
+	content, err := os.ReadFile(keyPath)
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("Failed reading WireGuard private key: %w", err)
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating WireGuard private key: %w", err)
+	}
+
+	privateKey := base64.StdEncoding.EncodeToString(key.Bytes())
+
+	err = os.WriteFile(keyPath, []byte(privateKey+"\n"), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// wireguardPublicKey returns the public key matching a WireGuard private key.
+func wireguardPublicKey(privateKey string) (string, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("Invalid WireGuard private key: %w", err)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("Invalid WireGuard private key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// validateWireguardKey validates a base64 encoded WireGuard key.
+func validateWireguardKey(value string) error {
+	keyBytes, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(keyBytes) != 32 {
+		return fmt.Errorf("Invalid WireGuard key")
+	}
+
+	return nil
+}
+
+// wireguardSubnets returns the subnets of the given member-specific config.
+func wireguardSubnets(config map[string]string) []string {
+	subnets := []string{}
+
+	for _, key := range []string{"wireguard.ipv4.address", "wireguard.ipv6.address"} {
+		_, subnet, err := net.ParseCIDR(config[key])
+		if err != nil {
+			continue
+		}
+
+		subnets = append(subnets, subnet.String())
+	}
+
+	return subnets
+}
+
+// peers returns the WireGuard peers of the local member. These are the other cluster members the network is
+// defined on which have published their public key, followed by the explicitly configured peers.
+func (n *wireguard) peers() ([]ip.WireguardPeer, error) {
+	var members []db.NodeInfo
+	var membersConfig map[int64]map[string]string
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		membersConfig, err = tx.GetNetworkMembersConfig(ctx, n.id)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading WireGuard peers: %w", err)
+	}
+
+	return wireguardPeers(n.config, members, membersConfig, n.state.DB.Cluster.GetNodeID()), nil
+}
+
+// wireguardPeers returns the WireGuard peers of the local member from the network config and the
+// member-specific config of the network on each cluster member.
+func wireguardPeers(config map[string]string, members []db.NodeInfo, membersConfig map[int64]map[string]string, localMemberID int64) []ip.WireguardPeer {
+	port := config["wireguard.port"]
+	if port == "" {
+		port = strconv.Itoa(wireguardPortDefault)
+	}
+
+	keepalive, _ := strconv.ParseUint(config["wireguard.persistent_keepalive"], 10, 32)
+
+	peers := []ip.WireguardPeer{}
+
+	for _, member := range members {
+		memberConfig := membersConfig[member.ID]
+
+		if member.ID == localMemberID || memberConfig["volatile.wireguard.public_key"] == "" {
+			continue
+		}
+
+		endpoint := memberConfig["wireguard.endpoint"]
+		if endpoint == "" {
+			host, _, err := net.SplitHostPort(member.Address)
+			if err != nil {
+				continue
+			}
+
+			endpoint = host
+		}
+
+		_, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			endpoint = net.JoinHostPort(strings.Trim(endpoint, "[]"), port)
+		}
+
+		peers = append(peers, ip.WireguardPeer{
+			PublicKey:           memberConfig["volatile.wireguard.public_key"],
+			Endpoint:            endpoint,
+			AllowedIPs:          wireguardSubnets(memberConfig),
+			PersistentKeepalive: keepalive,
+		})
+	}
+
+	// Add the explicitly configured peers, sorted by name.
+	peerNames := []string{}
+	for k := range config {
+		fields := strings.Split(k, ".")
+		if len(fields) == 4 && fields[0] == "wireguard" && fields[1] == "peers" && !slices.Contains(peerNames, fields[2]) {
+			peerNames = append(peerNames, fields[2])
+		}
+	}
+
+	slices.Sort(peerNames)
+
+	for _, peerName := range peerNames {
+		prefix := fmt.Sprintf("wireguard.peers.%s.", peerName)
+
+		peers = append(peers, ip.WireguardPeer{
+			PublicKey:           config[prefix+"public_key"],
+			Endpoint:            config[prefix+"endpoint"],
+			AllowedIPs:          util.SplitNTrimSpace(config[prefix+"allowed_ips"], ",", -1, true),
+			PersistentKeepalive: keepalive,
+		})
+	}
+
+	return peers
+}
+
+// setupPeers configures the WireGuard peers and the routes to their subnets.
+// Unless force is true, nothing is done when the peers haven't changed since they were last applied.
+func (n *wireguard) setupPeers(privateKey string, force bool) error {
+	peers, err := n.peers()
+	if err != nil {
+		return err
+	}
+
+	port, _ := strconv.ParseUint(n.config["wireguard.port"], 10, 16)
+	if port == 0 {
+		port = wireguardPortDefault
+	}
+
+	link := &ip.Wireguard{
+		Link:       ip.Link{Name: n.wireguardName()},
+		ListenPort: port,
+		Peers:      peers,
+	}
+
+	state := fmt.Sprintf("%d %+v", link.ListenPort, link.Peers)
+
+	wireguardPeersAppliedMu.Lock()
+	defer wireguardPeersAppliedMu.Unlock()
+
+	if !force && wireguardPeersApplied[n.id] == state {
+		return nil
+	}
+
+	err = link.Configure(privateKey)
+	if err != nil {
+		return err
+	}
+
+	// Route the subnets of the peers through the WireGuard interface.
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		r := &ip.Route{
+			DevName: link.Name,
+			Proto:   "static",
+			Family:  family,
+		}
+
+		err = r.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, peer := range peers {
+		for _, allowedIP := range peer.AllowedIPs {
+			_, subnet, err := net.ParseCIDR(allowedIP)
+			if err != nil {
+				return fmt.Errorf("Invalid allowed IPs %q: %w", allowedIP, err)
+			}
+
+			family := ip.FamilyV4
+			if subnet.IP.To4() == nil {
+				family = ip.FamilyV6
+			}
+
+			r := &ip.Route{
+				DevName: link.Name,
+				Proto:   "static",
+				Family:  family,
+			}
+
+			err = r.Replace([]string{subnet.String()})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	wireguardPeersApplied[n.id] = state
+
+	return nil
+}
+
+// HandleHeartbeat refreshes the WireGuard peers from the cluster database.
+func (n *wireguard) HandleHeartbeat(heartbeatData *cluster.APIHeartbeat) error {
+	if n.state.OS.MockMode || !InterfaceExists(n.wireguardName()) {
+		return nil
+	}
+
+	privateKey, err := n.privateKey()
+	if err != nil {
+		return err
+	}
+
+	return n.setupPeers(privateKey, false)
+}
+
+// Stop stops the network.
+func (n *wireguard) Stop() error {
+	n.logger.Debug("Stop")
+
+	// Delete the WireGuard interface (along with its routes).
+	if InterfaceExists(n.wireguardName()) {
+		link := &ip.Link{Name: n.wireguardName()}
+		err := link.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	wireguardPeersAppliedMu.Lock()
+	delete(wireguardPeersApplied, n.id)
+	wireguardPeersAppliedMu.Unlock()
+
+	return n.localBridge(n.config).Stop()
+}
+
+// Update updates the network. Accepts notification boolean indicating if this update request is coming from a
+// cluster notification, in which case do not update the database, just apply local changes needed.
+func (n *wireguard) Update(newNetwork api.NetworkPut, targetNode string, clientType request.ClientType) error {
+	n.logger.Debug("Update", logger.Ctx{"clientType": clientType, "newNetwork": newNetwork})
+
+	dbUpdateNeeded, changedKeys, oldNetwork, err := n.common.configChanged(newNetwork)
+	if err != nil {
+		return err
+	}
+
+	if !dbUpdateNeeded {
+		return nil // Nothing changed.
+	}
+
+	// If the network as a whole has not had any previous creation attempts, or the node itself is still
+	// pending, then don't apply the new settings to the node, just to the database record (ready for the
+	// actual global create request to be initiated).
+	if n.Status() == api.NetworkStatusPending || n.LocalStatus() == api.NetworkStatusPending {
+		return n.common.update(newNetwork, targetNode, clientType)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Define a function which reverts everything.
+	reverter.Add(func() {
+		// Reset changes to all nodes and database.
+		_ = n.common.update(oldNetwork, targetNode, clientType)
+
+		// Reset any change that was made to the local interfaces.
+		if len(changedKeys) > 0 {
+			_ = n.setup(newNetwork.Config)
+		}
+	})
+
+	// Apply changes to all nodes and database.
+	err = n.common.update(newNetwork, targetNode, clientType)
+	if err != nil {
+		return err
+	}
+
+	// Restart the network if needed.
+	if len(changedKeys) > 0 {
+		err = n.setup(oldNetwork.Config)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// DHCPv4Subnet returns the DHCPv4 subnet of the local bridge (if DHCP is enabled on network).
+func (n *wireguard) DHCPv4Subnet() *net.IPNet {
+	return n.localBridge(n.config).DHCPv4Subnet()
+}
+
+// DHCPv6Subnet returns the DHCPv6 subnet of the local bridge (if DHCP or SLAAC is enabled on network).
+func (n *wireguard) DHCPv6Subnet() *net.IPNet {
+	return n.localBridge(n.config).DHCPv6Subnet()
+}
+
+// UsesDNSMasq indicates if network's config indicates if it needs to use dnsmasq.
+func (n *wireguard) UsesDNSMasq() bool {
+	return n.localBridge(n.config).UsesDNSMasq()
+}
+
+// Leases returns a list of leases for the network.
+func (n *wireguard) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
+	return n.localBridge(n.config).Leases(projectName, clientType)
+}
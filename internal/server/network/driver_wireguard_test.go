@@ -0,0 +1,262 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/ip"
+)
+
+// Key pair from the X25519 test vectors of RFC 7748.
+const (
+	testWireguardPrivateKey = "dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo="
+	testWireguardPublicKey  = "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo="
+	testWireguardPeerKey    = "3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08="
+)
+
+func TestValidateWireguardKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid", value: testWireguardPublicKey},
+		{name: "empty", value: "", wantErr: true},
+		{name: "invalid base64", value: "not-a-key!", wantErr: true},
+		{name: "too short", value: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==", wantErr: true},
+		{name: "too long", value: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWireguardKey(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWireguardPublicKey(t *testing.T) {
+	publicKey, err := wireguardPublicKey(testWireguardPrivateKey)
+	require.NoError(t, err)
+	assert.Equal(t, testWireguardPublicKey, publicKey)
+
+	_, err = wireguardPublicKey("not-a-key!")
+	assert.Error(t, err)
+
+	_, err = wireguardPublicKey("AAAA")
+	assert.Error(t, err)
+}
+
+func TestWireguardValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			config: map[string]string{},
+		},
+		{
+			name: "valid",
+			config: map[string]string{
+				"wireguard.ipv4.address":               "10.0.0.1/24",
+				"wireguard.ipv6.address":               "fd42::1/64",
+				"wireguard.port":                       "51821",
+				"wireguard.persistent_keepalive":       "25",
+				"wireguard.peers.remote.public_key":    testWireguardPeerKey,
+				"wireguard.peers.remote.endpoint":      "203.0.113.1:51820",
+				"wireguard.peers.remote.allowed_ips":   "10.1.0.0/24, fd43::/64",
+				"volatile.wireguard.public_key":        testWireguardPublicKey,
+				"user.comment":                         "test",
+				"bridge.mtu":                           "1420",
+				"wireguard.endpoint":                   "198.51.100.1:51821",
+				"ipv4.dhcp":                            "true",
+				"dns.mode":                             "managed",
+				"wireguard.peers.remote-2.public_key":  testWireguardPublicKey,
+				"wireguard.peers.remote-2.allowed_ips": "10.2.0.0/24",
+				"wireguard.peers.remote-2.endpoint":    "[2001:db8::1]:51820",
+				"ipv6.dhcp.stateful":                   "false",
+				"ipv4.firewall":                        "false",
+				"ipv6.firewall":                        "false",
+				"dns.domain":                           "wg",
+				"dns.search":                           "wg",
+				"ipv6.dhcp":                            "false",
+				"ipv4.dhcp.expiry":                     "1h",
+				"ipv6.dhcp.expiry":                     "1h",
+				"dns.nameservers":                      "10.0.0.1",
+			},
+		},
+		{
+			name:   "no addresses",
+			config: map[string]string{"wireguard.ipv4.address": "none", "wireguard.ipv6.address": "none"},
+		},
+		{
+			name:    "unknown key",
+			config:  map[string]string{"wireguard.foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid IPv4 address",
+			config:  map[string]string{"wireguard.ipv4.address": "fd42::1/64"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			config:  map[string]string{"wireguard.port": "70000"},
+			wantErr: true,
+		},
+		{
+			name:    "endpoint with DNS name",
+			config:  map[string]string{"wireguard.endpoint": "wg.example.net"},
+			wantErr: true,
+		},
+		{
+			name:    "wildcard endpoint",
+			config:  map[string]string{"wireguard.endpoint": "0.0.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid public key",
+			config:  map[string]string{"volatile.wireguard.public_key": "AAAA"},
+			wantErr: true,
+		},
+		{
+			name:    "peer without public key",
+			config:  map[string]string{"wireguard.peers.remote.allowed_ips": "10.1.0.0/24"},
+			wantErr: true,
+		},
+		{
+			name:    "peer with invalid public key",
+			config:  map[string]string{"wireguard.peers.remote.public_key": "AAAA"},
+			wantErr: true,
+		},
+		{
+			name:    "peer with unknown key",
+			config:  map[string]string{"wireguard.peers.remote.public_key": testWireguardPeerKey, "wireguard.peers.remote.foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "peer key without name",
+			config:  map[string]string{"wireguard.peers.public_key": testWireguardPeerKey},
+			wantErr: true,
+		},
+		{
+			name:    "peer endpoint without port",
+			config:  map[string]string{"wireguard.peers.remote.public_key": testWireguardPeerKey, "wireguard.peers.remote.endpoint": "203.0.113.1"},
+			wantErr: true,
+		},
+		{
+			name:    "peer with invalid allowed IPs",
+			config:  map[string]string{"wireguard.peers.remote.public_key": testWireguardPeerKey, "wireguard.peers.remote.allowed_ips": "10.1.0.1"},
+			wantErr: true,
+		},
+		{
+			name:    "MTU too small for IPv6",
+			config:  map[string]string{"bridge.mtu": "1200", "wireguard.ipv6.address": "fd42::1/64"},
+			wantErr: true,
+		},
+		{
+			name:    "MTU out of range",
+			config:  map[string]string{"bridge.mtu": "1200", "wireguard.ipv6.address": "none"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &wireguard{}
+			n.name = "wg0"
+
+			err := n.Validate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWireguardPeers(t *testing.T) {
+	members := []db.NodeInfo{
+		{ID: 1, Name: "member1", Address: "10.10.0.1:8443"},
+		{ID: 2, Name: "member2", Address: "10.10.0.2:8443"},
+		{ID: 3, Name: "member3", Address: "[2001:db8::3]:8443"},
+		{ID: 4, Name: "member4", Address: "10.10.0.4:8443"},
+		{ID: 5, Name: "member5", Address: "10.10.0.5:8443"},
+	}
+
+	membersConfig := map[int64]map[string]string{
+		1: {
+			"volatile.wireguard.public_key": testWireguardPublicKey,
+			"wireguard.ipv4.address":        "10.0.1.1/24",
+		},
+		2: {
+			"volatile.wireguard.public_key": "key2",
+			"wireguard.ipv4.address":        "10.0.2.1/24",
+			"wireguard.ipv6.address":        "fd42:2::1/64",
+		},
+		3: {
+			"volatile.wireguard.public_key": "key3",
+		},
+		4: {
+			"volatile.wireguard.public_key": "key4",
+			"wireguard.endpoint":            "198.51.100.4",
+			"wireguard.ipv4.address":        "none",
+		},
+		// Member 5 hasn't published its public key yet.
+		5: {
+			"wireguard.ipv4.address": "10.0.5.1/24",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   []ip.WireguardPeer
+	}{
+		{
+			name:   "members",
+			config: map[string]string{},
+			want: []ip.WireguardPeer{
+				{PublicKey: "key2", Endpoint: "10.10.0.2:51820", AllowedIPs: []string{"10.0.2.0/24", "fd42:2::/64"}},
+				{PublicKey: "key3", Endpoint: "[2001:db8::3]:51820", AllowedIPs: []string{}},
+				{PublicKey: "key4", Endpoint: "198.51.100.4:51820", AllowedIPs: []string{}},
+			},
+		},
+		{
+			name: "explicit peers",
+			config: map[string]string{
+				"wireguard.port":                    "51821",
+				"wireguard.persistent_keepalive":    "25",
+				"wireguard.peers.zeta.public_key":   "zeta",
+				"wireguard.peers.alpha.public_key":  "alpha",
+				"wireguard.peers.alpha.endpoint":    "203.0.113.1:51820",
+				"wireguard.peers.alpha.allowed_ips": "10.1.0.0/24, fd43::/64",
+				"wireguard.peers.zeta.allowed_ips":  "",
+			},
+			want: []ip.WireguardPeer{
+				{PublicKey: "key2", Endpoint: "10.10.0.2:51821", AllowedIPs: []string{"10.0.2.0/24", "fd42:2::/64"}, PersistentKeepalive: 25},
+				{PublicKey: "key3", Endpoint: "[2001:db8::3]:51821", AllowedIPs: []string{}, PersistentKeepalive: 25},
+				{PublicKey: "key4", Endpoint: "198.51.100.4:51821", AllowedIPs: []string{}, PersistentKeepalive: 25},
+				{PublicKey: "alpha", Endpoint: "203.0.113.1:51820", AllowedIPs: []string{"10.1.0.0/24", "fd43::/64"}, PersistentKeepalive: 25},
+				{PublicKey: "zeta", PersistentKeepalive: 25},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers := wireguardPeers(tt.config, members, membersConfig, 1)
+			assert.Equal(t, tt.want, peers)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package zone
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	incusDNS "github.com/lxc/incus/v6/internal/server/dns"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// dnssecAlgorithmDefault is the algorithm used for new DNSSEC keys unless configured otherwise.
+const dnssecAlgorithmDefault = "ECDSAP256SHA256"
+
+// dnssecAlgorithmBits is the key size used for each of the supported DNSSEC algorithms.
+var dnssecAlgorithmBits = map[string]int{
+	"ECDSAP256SHA256": 256,
+	"ECDSAP384SHA384": 384,
+	"ED25519":         256,
+	"RSASHA256":       2048,
+}
+
+// dnssecKeyPublishDelay is how long a new key is published before being used for signing.
+// This lets resolvers with a cached DNSKEY set pick up the new key first.
+const dnssecKeyPublishDelay = 2 * time.Hour
+
+// dnssecZSKRetireDelay is how long a replaced zone signing key remains published.
+const dnssecZSKRetireDelay = 2 * 24 * time.Hour
+
+// dnssecKSKRetireDelay is how long a replaced key signing key remains published.
+// The DS record of the new key must be published in the parent zone within that time.
+const dnssecKSKRetireDelay = 30 * 24 * time.Hour
+
+// dnssecKeyLifetime returns the configured lifetime of the key signing or zone signing keys.
+func (d *zone) dnssecKeyLifetime(ksk bool) time.Duration {
+	key := "dnssec.zsk.lifetime"
+	days := "30"
+	if ksk {
+		key = "dnssec.ksk.lifetime"
+		days = "0"
+	}
+
+	if d.info.Config[key] != "" {
+		days = d.info.Config[key]
+	}
+
+	value, err := strconv.Atoi(days)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(value) * 24 * time.Hour
+}
+
+// dnssecAlgorithm returns the configured DNSSEC algorithm.
+func (d *zone) dnssecAlgorithm() uint8 {
+	name := d.info.Config["dnssec.algorithm"]
+	if name == "" {
+		name = dnssecAlgorithmDefault
+	}
+
+	return dns.StringToAlgorithm[name]
+}
+
+// dnssecGenerateKey generates a new DNSSEC key for the zone.
+func (d *zone) dnssecGenerateKey(ksk bool) (*db.NetworkZoneKey, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(d.info.Name), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE,
+		Protocol:  3,
+		Algorithm: d.dnssecAlgorithm(),
+	}
+
+	if ksk {
+		dnskey.Flags |= dns.SEP
+	}
+
+	privateKey, err := dnskey.Generate(dnssecAlgorithmBits[dns.AlgorithmToString[dnskey.Algorithm]])
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating DNSSEC key: %w", err)
+	}
+
+	return &db.NetworkZoneKey{
+		Flags:      dnskey.Flags,
+		Algorithm:  dnskey.Algorithm,
+		PublicKey:  dnskey.String(),
+		PrivateKey: dnskey.PrivateKeyString(privateKey),
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// RotateDNSSECKeys generates the missing DNSSEC keys of the zone, replaces the expired ones and
+// removes the ones that were replaced long enough ago. All keys are removed when DNSSEC is disabled.
+func (d *zone) RotateDNSSECKeys() error {
+	return d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+			return tx.DeleteNetworkZoneKeysByZone(ctx, d.id)
+		}
+
+		keys, err := tx.GetNetworkZoneKeysByZone(ctx, d.id)
+		if err != nil {
+			return fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+		}
+
+		algorithm := d.dnssecAlgorithm()
+		now := time.Now()
+
+		for _, ksk := range []bool{true, false} {
+			retireDelay := dnssecZSKRetireDelay
+			if ksk {
+				retireDelay = dnssecKSKRetireDelay
+			}
+
+			// Find the most recent key of the configured algorithm (keys are sorted by creation date).
+			var current *db.NetworkZoneKey
+			for i, key := range keys {
+				if (key.Flags&dns.SEP != 0) == ksk && key.Algorithm == algorithm {
+					current = &keys[i]
+				}
+			}
+
+			// Generate a new key if missing or expired.
+			lifetime := d.dnssecKeyLifetime(ksk)
+			if current == nil || (lifetime > 0 && now.Sub(current.CreatedAt) >= lifetime) {
+				current, err = d.dnssecGenerateKey(ksk)
+				if err != nil {
+					return err
+				}
+
+				current.ID, err = tx.CreateNetworkZoneKey(ctx, d.id, *current)
+				if err != nil {
+					return fmt.Errorf("Failed storing DNSSEC key: %w", err)
+				}
+
+				d.logger.Info("Generated new DNSSEC key", logger.Ctx{"ksk": ksk})
+			}
+
+			if now.Sub(current.CreatedAt) < retireDelay {
+				continue
+			}
+
+			// Remove the keys that were replaced long enough ago.
+			for _, key := range keys {
+				if (key.Flags&dns.SEP != 0) != ksk || key.ID == current.ID {
+					continue
+				}
+
+				err = tx.DeleteNetworkZoneKey(ctx, key.ID)
+				if err != nil {
+					return fmt.Errorf("Failed removing DNSSEC key: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// DNSSECKeys returns the DNSSEC keys of the zone.
+func (d *zone) DNSSECKeys() ([]incusDNS.Key, error) {
+	if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return nil, nil
+	}
+
+	var dbKeys []db.NetworkZoneKey
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbKeys, err = tx.GetNetworkZoneKeysByZone(ctx, d.id)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	keys := make([]incusDNS.Key, 0, len(dbKeys))
+	for _, dbKey := range dbKeys {
+		rr, err := dns.NewRR(dbKey.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing DNSSEC key %d: %w", dbKey.ID, err)
+		}
+
+		dnskey, ok := rr.(*dns.DNSKEY)
+		if !ok {
+			return nil, fmt.Errorf("Invalid DNSSEC key %d", dbKey.ID)
+		}
+
+		privateKey, err := dnskey.NewPrivateKey(dbKey.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing DNSSEC private key %d: %w", dbKey.ID, err)
+		}
+
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("Invalid DNSSEC private key %d", dbKey.ID)
+		}
+
+		keys = append(keys, incusDNS.Key{DNSKEY: dnskey, Signer: signer})
+	}
+
+	// Pick the keys used for signing.
+	dnssecActivateKeys(keys, dbKeys, d.dnssecAlgorithm(), time.Now())
+
+	return keys, nil
+}
+
+// dnssecActivateKeys marks the keys used for signing, for each type and algorithm.
+// The keys must be sorted by creation date and match the database keys.
+func dnssecActivateKeys(keys []incusDNS.Key, dbKeys []db.NetworkZoneKey, algorithm uint8, now time.Time) {
+	for i, key := range keys {
+		active := true
+		for j, other := range keys {
+			if i == j || other.DNSKEY.Flags != key.DNSKEY.Flags || other.DNSKEY.Algorithm != key.DNSKEY.Algorithm {
+				continue
+			}
+
+			// Keys of other algorithms keep signing until removed, as required during algorithm rollovers.
+			// Otherwise, the most recent key is used once it's been published for long enough.
+			if j > i && (key.DNSKEY.Algorithm != algorithm || now.Sub(dbKeys[j].CreatedAt) >= dnssecKeyPublishDelay) {
+				active = false
+				break
+			}
+
+			if j < i && key.DNSKEY.Algorithm == algorithm && now.Sub(dbKeys[i].CreatedAt) < dnssecKeyPublishDelay {
+				active = false
+				break
+			}
+		}
+
+		keys[i].Active = active
+	}
+}
+
+// DSRecords returns the DS records to publish in the parent zone.
+func (d *zone) DSRecords() ([]string, error) {
+	keys, err := d.DNSSECKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []string{}
+	for _, key := range keys {
+		if key.DNSKEY.Flags&dns.SEP == 0 {
+			continue
+		}
+
+		records = append(records, key.DNSKEY.ToDS(dns.SHA256).String())
+	}
+
+	return records, nil
+}
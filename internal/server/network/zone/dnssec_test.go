@@ -0,0 +1,153 @@
+package zone
+
+import (
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	incusDNS "github.com/lxc/incus/v6/internal/server/dns"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestDNSSECGenerateKey(t *testing.T) {
+	for _, algorithm := range []string{"ECDSAP256SHA256", "ECDSAP384SHA384", "ED25519", "RSASHA256"} {
+		t.Run(algorithm, func(t *testing.T) {
+			d := &zone{info: &api.NetworkZone{Name: "incus.example.net", NetworkZonePut: api.NetworkZonePut{Config: map[string]string{"dnssec.algorithm": algorithm}}}}
+
+			for _, ksk := range []bool{true, false} {
+				key, err := d.dnssecGenerateKey(ksk)
+				require.NoError(t, err)
+				assert.Equal(t, dns.StringToAlgorithm[algorithm], key.Algorithm)
+				assert.Equal(t, ksk, key.Flags&dns.SEP != 0)
+
+				// The stored keys can be loaded back.
+				rr, err := dns.NewRR(key.PublicKey)
+				require.NoError(t, err)
+
+				dnskey, ok := rr.(*dns.DNSKEY)
+				require.True(t, ok)
+				assert.Equal(t, "incus.example.net.", dnskey.Hdr.Name)
+				assert.Equal(t, key.Flags, dnskey.Flags)
+
+				privateKey, err := dnskey.NewPrivateKey(key.PrivateKey)
+				require.NoError(t, err)
+
+				_, ok = privateKey.(crypto.Signer)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestDNSSECKeyLifetime(t *testing.T) {
+	d := &zone{info: &api.NetworkZone{}}
+
+	// Zone signing keys are replaced monthly and key signing keys never by default.
+	assert.Equal(t, 30*24*time.Hour, d.dnssecKeyLifetime(false))
+	assert.Equal(t, time.Duration(0), d.dnssecKeyLifetime(true))
+	assert.Equal(t, dns.ECDSAP256SHA256, d.dnssecAlgorithm())
+
+	d.info.Config = map[string]string{
+		"dnssec.zsk.lifetime": "7",
+		"dnssec.ksk.lifetime": "365",
+		"dnssec.algorithm":    "ED25519",
+	}
+
+	assert.Equal(t, 7*24*time.Hour, d.dnssecKeyLifetime(false))
+	assert.Equal(t, 365*24*time.Hour, d.dnssecKeyLifetime(true))
+	assert.Equal(t, dns.ED25519, d.dnssecAlgorithm())
+}
+
+func TestDNSSECActivateKeys(t *testing.T) {
+	now := time.Now()
+
+	type testKey struct {
+		ksk       bool
+		algorithm uint8
+		age       time.Duration
+	}
+
+	tests := []struct {
+		name   string
+		keys   []testKey
+		active []bool
+	}{
+		{
+			name: "Single keys",
+			keys: []testKey{
+				{ksk: true, algorithm: dns.ECDSAP256SHA256, age: 100 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 10 * 24 * time.Hour},
+			},
+			active: []bool{true, true},
+		},
+		{
+			name: "New key being published",
+			keys: []testKey{
+				{ksk: true, algorithm: dns.ECDSAP256SHA256, age: 100 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 30 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: time.Hour},
+			},
+			active: []bool{true, true, false},
+		},
+		{
+			name: "New key published",
+			keys: []testKey{
+				{ksk: true, algorithm: dns.ECDSAP256SHA256, age: 100 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 30 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 3 * time.Hour},
+			},
+			active: []bool{true, false, true},
+		},
+		{
+			name: "Algorithm rollover",
+			keys: []testKey{
+				{ksk: true, algorithm: dns.ECDSAP256SHA256, age: 100 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 10 * 24 * time.Hour},
+				{ksk: true, algorithm: dns.ECDSAP384SHA384, age: time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP384SHA384, age: time.Hour},
+			},
+			active: []bool{true, true, true, true},
+		},
+		{
+			name: "Algorithm rollover with replaced keys",
+			keys: []testKey{
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 40 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP256SHA256, age: 10 * 24 * time.Hour},
+				{ksk: false, algorithm: dns.ECDSAP384SHA384, age: time.Hour},
+			},
+			active: []bool{false, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := []incusDNS.Key{}
+			dbKeys := []db.NetworkZoneKey{}
+
+			for _, key := range tt.keys {
+				flags := uint16(dns.ZONE)
+				if key.ksk {
+					flags |= dns.SEP
+				}
+
+				keys = append(keys, incusDNS.Key{DNSKEY: &dns.DNSKEY{Flags: flags, Algorithm: key.algorithm}})
+				dbKeys = append(dbKeys, db.NetworkZoneKey{Flags: flags, Algorithm: key.algorithm, CreatedAt: now.Add(-key.age)})
+			}
+
+			algorithm := tt.keys[len(tt.keys)-1].algorithm
+			dnssecActivateKeys(keys, dbKeys, algorithm, now)
+
+			active := []bool{}
+			for _, key := range keys {
+				active = append(active, key.Active)
+			}
+
+			assert.Equal(t, tt.active, active)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	incusDNS "github.com/lxc/incus/v6/internal/server/dns"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -22,6 +23,14 @@ type NetworkZone interface {
 	Content() (*strings.Builder, error)
 	SOA() (*strings.Builder, error)
 
+	// DNSSEC.
+	DNSSECKeys() ([]incusDNS.Key, error)
+	DSRecords() ([]string, error)
+	RotateDNSSECKeys() error
+
+	// Dynamic updates.
+	Push() error
+
 	// Records.
 	AddRecord(req api.NetworkZoneRecordsPost) error
 	GetRecords() ([]api.NetworkZoneRecord, error)
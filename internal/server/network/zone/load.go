@@ -106,9 +106,10 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 		}
 	}
 
+	var id int64
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Insert DB record.
-		_, err = tx.CreateNetworkZone(ctx, projectName, zoneInfo)
+		id, err = tx.CreateNetworkZone(ctx, projectName, zoneInfo)
 
 		return err
 	})
@@ -116,6 +117,16 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 		return err
 	}
 
+	// Generate the DNSSEC keys.
+	if util.IsTrue(zoneInfo.Config["dnssec.enabled"]) {
+		zone.init(s, id, projectName, &api.NetworkZone{Name: zoneInfo.Name, NetworkZonePut: zoneInfo.NetworkZonePut})
+
+		err = zone.RotateDNSSECKeys()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Trigger a refresh of the TSIG entries.
 	err = s.DNS.UpdateTSIG()
 	if err != nil {
@@ -0,0 +1,339 @@
+package zone
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+)
+
+// pushFullInterval is how often all the records of a zone are sent to the primary DNS server,
+// regardless of what was previously sent.
+const pushFullInterval = time.Hour
+
+// pushBatchSize is the maximum number of record sets changed in a single update message.
+const pushBatchSize = 100
+
+// pushState records what was last sent to the primary DNS server of a zone.
+type pushState struct {
+	address  string
+	rrsets   map[string][]dns.RR
+	lastFull time.Time
+}
+
+var pushStates = map[string]*pushState{}
+var pushStatesMu sync.Mutex
+
+// Persistence of the record sets sent to the primary DNS server of a zone, so that the record sets removed while
+// the leader restarts or changes can still be removed from the primary. Replaced in tests.
+var (
+	pushStateLoad = func(d *zone) ([]db.NetworkZonePushedRRSet, error) {
+		var rrsets []db.NetworkZonePushedRRSet
+
+		err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			rrsets, err = tx.GetNetworkZonePushedRRSets(ctx, d.id)
+
+			return err
+		})
+
+		return rrsets, err
+	}
+
+	pushStateSave = func(d *zone, sent []db.NetworkZonePushedRRSet, removed []db.NetworkZonePushedRRSet) error {
+		return d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			for _, rrset := range sent {
+				err := tx.CreateNetworkZonePushedRRSet(ctx, d.id, rrset)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, rrset := range removed {
+				err := tx.DeleteNetworkZonePushedRRSet(ctx, d.id, rrset.Name, rrset.Type)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	pushStateClear = func(d *zone) error {
+		return d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkZonePushedRRSetsByZone(ctx, d.id)
+		})
+	}
+)
+
+// pushRRSetKey returns the key identifying the record set of the record.
+func pushRRSetKey(rr dns.RR) string {
+	return dns.CanonicalName(rr.Header().Name) + " " + dns.TypeToString[rr.Header().Rrtype]
+}
+
+// pushRRSetFromKey returns the key fields of a record set as stored in the database.
+func pushRRSetFromKey(address string, key string) db.NetworkZonePushedRRSet {
+	name, rrtype, _ := strings.Cut(key, " ")
+
+	return db.NetworkZonePushedRRSet{Address: address, Name: name, Type: rrtype}
+}
+
+// pushRRSetEqual returns whether both record sets hold the same records.
+func pushRRSetEqual(a []dns.RR, b []dns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, rr := range a {
+		if !slices.ContainsFunc(b, func(other dns.RR) bool { return dns.IsDuplicate(rr, other) && rr.Header().Ttl == other.Header().Ttl }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pushRecords returns the record sets of the zone content to send to the primary DNS server.
+// The SOA and NS records of the zone apex are left to the primary DNS server.
+func pushRecords(zoneName string, content string) (map[string][]dns.RR, error) {
+	origin := dns.Fqdn(zoneName)
+	rrsets := map[string][]dns.RR{}
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, fmt.Errorf("Bad DNS record in zone %q: %w", zoneName, err)
+			}
+
+			break
+		}
+
+		if rr.Header().Rrtype == dns.TypeSOA || (rr.Header().Rrtype == dns.TypeNS && strings.EqualFold(rr.Header().Name, origin)) {
+			continue
+		}
+
+		key := pushRRSetKey(rr)
+		if slices.ContainsFunc(rrsets[key], func(other dns.RR) bool { return dns.IsDuplicate(rr, other) }) {
+			continue
+		}
+
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	return rrsets, nil
+}
+
+// Push sends the changes to the records of the zone to the configured primary DNS server
+// using dynamic updates (RFC 2136). All the records are periodically sent again to recover
+// from changes made on the primary DNS server or from failed updates.
+func (d *zone) Push() error {
+	address := d.info.Config["push.address"]
+	if address == "" {
+		pushReset(d.info.Name)
+		return pushStateClear(d)
+	}
+
+	address = internalUtil.CanonicalNetworkAddress(address, 53)
+
+	content, err := d.Content()
+	if err != nil {
+		return err
+	}
+
+	rrsets, err := pushRecords(d.info.Name, content.String())
+	if err != nil {
+		return err
+	}
+
+	return d.push(address, rrsets)
+}
+
+// push sends the changes to the record sets since the last update to the primary DNS server.
+func (d *zone) push(address string, rrsets map[string][]dns.RR) error {
+	pushStatesMu.Lock()
+	defer pushStatesMu.Unlock()
+
+	// Recover what was sent before a restart or by the previous leader.
+	pushed := pushStates[d.info.Name]
+	if pushed == nil {
+		var err error
+
+		pushed, err = d.pushLoad()
+		if err != nil {
+			return fmt.Errorf("Failed loading the record sets sent to the primary DNS server of zone %q: %w", d.info.Name, err)
+		}
+	}
+
+	// Send everything again when the primary changed or after the full update interval.
+	full := pushed.address != address || time.Since(pushed.lastFull) >= pushFullInterval
+	if pushed.address != address {
+		if len(pushed.rrsets) > 0 {
+			err := pushStateClear(d)
+			if err != nil {
+				return fmt.Errorf("Failed resetting the record sets sent to the primary DNS server of zone %q: %w", d.info.Name, err)
+			}
+		}
+
+		pushed = &pushState{address: address, rrsets: map[string][]dns.RR{}}
+	}
+
+	// Build the list of changes.
+	type change struct {
+		key    string
+		remove []dns.RR
+		insert []dns.RR
+	}
+
+	changes := []change{}
+	for key, rrset := range rrsets {
+		if !full && pushRRSetEqual(pushed.rrsets[key], rrset) {
+			continue
+		}
+
+		changes = append(changes, change{key: key, remove: rrset[:1], insert: rrset})
+	}
+
+	for key, rrset := range pushed.rrsets {
+		_, ok := rrsets[key]
+		if !ok {
+			changes = append(changes, change{key: key, remove: rrset[:1]})
+		}
+	}
+
+	slices.SortFunc(changes, func(a change, b change) int { return strings.Compare(a.key, b.key) })
+
+	// Send the changes.
+	for start := 0; start < len(changes); start += pushBatchSize {
+		batch := changes[start:min(start+pushBatchSize, len(changes))]
+
+		m := &dns.Msg{}
+		m.SetUpdate(dns.Fqdn(d.info.Name))
+		for _, entry := range batch {
+			m.RemoveRRset(entry.remove)
+
+			if len(entry.insert) > 0 {
+				m.Insert(entry.insert)
+			}
+		}
+
+		err := d.pushSend(address, m)
+		if err != nil {
+			return err
+		}
+
+		// Record what was sent.
+		sent := []db.NetworkZonePushedRRSet{}
+		removed := []db.NetworkZonePushedRRSet{}
+		for _, entry := range batch {
+			if len(entry.insert) > 0 {
+				pushed.rrsets[entry.key] = entry.insert
+				sent = append(sent, pushRRSetFromKey(address, entry.key))
+			} else {
+				delete(pushed.rrsets, entry.key)
+				removed = append(removed, pushRRSetFromKey(address, entry.key))
+			}
+		}
+
+		pushStates[d.info.Name] = pushed
+
+		err = pushStateSave(d, sent, removed)
+		if err != nil {
+			return fmt.Errorf("Failed recording the record sets sent to the primary DNS server of zone %q: %w", d.info.Name, err)
+		}
+	}
+
+	if full {
+		pushed.lastFull = time.Now()
+		pushStates[d.info.Name] = pushed
+	}
+
+	return nil
+}
+
+// pushLoad returns the record sets previously sent to the primary DNS server from the database.
+// As their content isn't known, they are all sent again on the next update.
+func (d *zone) pushLoad() (*pushState, error) {
+	rrsets, err := pushStateLoad(d)
+	if err != nil {
+		return nil, err
+	}
+
+	pushed := &pushState{rrsets: map[string][]dns.RR{}}
+	for _, rrset := range rrsets {
+		rrtype, ok := dns.StringToType[rrset.Type]
+		if !ok {
+			continue
+		}
+
+		pushed.address = rrset.Address
+		pushed.rrsets[rrset.Name+" "+rrset.Type] = []dns.RR{&dns.ANY{Hdr: dns.RR_Header{Name: rrset.Name, Rrtype: rrtype, Class: dns.ClassINET}}}
+	}
+
+	return pushed, nil
+}
+
+// pushRemove removes all the record sets sent to the primary DNS server of the zone.
+func (d *zone) pushRemove() error {
+	address := d.info.Config["push.address"]
+	if address == "" {
+		return nil
+	}
+
+	err := d.push(internalUtil.CanonicalNetworkAddress(address, 53), map[string][]dns.RR{})
+	if err != nil {
+		return err
+	}
+
+	pushReset(d.info.Name)
+
+	return nil
+}
+
+// pushSend sends an update message to the primary DNS server, signing it with the configured TSIG key.
+func (d *zone) pushSend(address string, m *dns.Msg) error {
+	client := &dns.Client{Net: "tcp", Timeout: 10 * time.Second}
+
+	keyName := d.info.Config["push.key.name"]
+	if keyName != "" {
+		keyName = dns.Fqdn(keyName)
+
+		algorithm := d.info.Config["push.key.algorithm"]
+		if algorithm == "" {
+			algorithm = "hmac-sha256"
+		}
+
+		client.TsigSecret = map[string]string{keyName: d.info.Config["push.key.secret"]}
+		m.SetTsig(keyName, dns.Fqdn(algorithm), 300, time.Now().Unix())
+	}
+
+	resp, _, err := client.Exchange(m, address)
+	if err != nil {
+		return fmt.Errorf("Failed sending update of zone %q to %q: %w", d.info.Name, address, err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("Update of zone %q refused by %q: %s", d.info.Name, address, dns.RcodeToString[resp.Rcode])
+	}
+
+	return nil
+}
+
+// pushReset forgets what was sent to the primary DNS server of the zone, so everything is sent on the next update.
+func pushReset(zoneName string) {
+	pushStatesMu.Lock()
+	defer pushStatesMu.Unlock()
+
+	delete(pushStates, zoneName)
+}
@@ -0,0 +1,387 @@
+package zone
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// testPrimary is a DNS server recording the dynamic updates it receives.
+type testPrimary struct {
+	mu      sync.Mutex
+	updates [][]string
+	rcode   int
+}
+
+// newTestPrimary starts a DNS server accepting updates signed with the given TSIG secrets.
+func newTestPrimary(t *testing.T, tsigSecret map[string]string) (*testPrimary, string) {
+	primary := &testPrimary{rcode: dns.RcodeSuccess}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		Listener:          listener,
+		Handler:           primary,
+		TsigSecret:        tsigSecret,
+		NotifyStartedFunc: func() { close(started) },
+
+		// Accept update messages.
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return primary, listener.Addr().String()
+}
+
+func (p *testPrimary) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m := &dns.Msg{}
+
+	// Reject updates with an invalid signature.
+	if r.IsTsig() != nil && w.TsigStatus() != nil {
+		m.SetRcode(r, dns.RcodeNotAuth)
+		_ = w.WriteMsg(m)
+		return
+	}
+
+	// Record the update section.
+	update := []string{}
+	for _, rr := range r.Ns {
+		if rr.Header().Class == dns.ClassANY {
+			update = append(update, fmt.Sprintf("delete %s %s", rr.Header().Name, dns.TypeToString[rr.Header().Rrtype]))
+			continue
+		}
+
+		update = append(update, "add "+strings.ReplaceAll(rr.String(), "\t", " "))
+	}
+
+	if r.IsTsig() != nil {
+		update = append(update, "tsig "+r.IsTsig().Hdr.Name)
+	}
+
+	p.updates = append(p.updates, update)
+
+	m.SetRcode(r, p.rcode)
+	if r.IsTsig() != nil {
+		m.SetTsig(r.IsTsig().Hdr.Name, r.IsTsig().Algorithm, 300, time.Now().Unix())
+	}
+
+	_ = w.WriteMsg(m)
+}
+
+// setRcode sets the response code of the next updates.
+func (p *testPrimary) setRcode(rcode int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rcode = rcode
+}
+
+// received returns the updates received since the last call.
+func (p *testPrimary) received() [][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	updates := p.updates
+	p.updates = nil
+
+	return updates
+}
+
+// testPushStore holds the record sets sent to the primary DNS servers in place of the database, by zone name.
+var testPushStore = map[string]map[string]db.NetworkZonePushedRRSet{}
+
+// newTestPushZone returns a zone pushing its records to the given address.
+func newTestPushZone(t *testing.T, name string, address string) *zone {
+	load, save, reset := pushStateLoad, pushStateSave, pushStateClear
+	t.Cleanup(func() {
+		pushStateLoad, pushStateSave, pushStateClear = load, save, reset
+		delete(testPushStore, name)
+		pushReset(name)
+	})
+
+	pushStateLoad = func(d *zone) ([]db.NetworkZonePushedRRSet, error) {
+		rrsets := []db.NetworkZonePushedRRSet{}
+		for _, rrset := range testPushStore[d.info.Name] {
+			rrsets = append(rrsets, rrset)
+		}
+
+		return rrsets, nil
+	}
+
+	pushStateSave = func(d *zone, sent []db.NetworkZonePushedRRSet, removed []db.NetworkZonePushedRRSet) error {
+		if testPushStore[d.info.Name] == nil {
+			testPushStore[d.info.Name] = map[string]db.NetworkZonePushedRRSet{}
+		}
+
+		for _, rrset := range sent {
+			testPushStore[d.info.Name][rrset.Name+" "+rrset.Type] = rrset
+		}
+
+		for _, rrset := range removed {
+			delete(testPushStore[d.info.Name], rrset.Name+" "+rrset.Type)
+		}
+
+		return nil
+	}
+
+	pushStateClear = func(d *zone) error {
+		delete(testPushStore, d.info.Name)
+		return nil
+	}
+
+	return &zone{info: &api.NetworkZone{Name: name, NetworkZonePut: api.NetworkZonePut{Config: map[string]string{"push.address": address}}}}
+}
+
+// testPushRecords returns the record sets to push for the given zone content.
+func testPushRecords(t *testing.T, zoneName string, content string) map[string][]dns.RR {
+	rrsets, err := pushRecords(zoneName, content)
+	require.NoError(t, err)
+
+	return rrsets
+}
+
+func TestPushRecords(t *testing.T) {
+	rrsets := testPushRecords(t, "incus.example.net", `incus.example.net. 300 IN SOA incus.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+incus.example.net. 300 IN NS ns1.incus.example.net.
+lab.incus.example.net. 300 IN NS ns1.lab.incus.example.net.
+c1.incus.example.net. 300 IN A 192.0.2.10
+c1.incus.example.net. 300 IN A 192.0.2.11
+C1.incus.example.net. 300 IN A 192.0.2.10
+c1.incus.example.net. 300 IN AAAA 2001:db8::10
+incus.example.net. 300 IN SOA incus.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+`)
+
+	// The SOA and apex NS records are left out and duplicate records are merged.
+	keys := []string{}
+	for key := range rrsets {
+		keys = append(keys, key)
+	}
+
+	assert.ElementsMatch(t, []string{"lab.incus.example.net. NS", "c1.incus.example.net. A", "c1.incus.example.net. AAAA"}, keys)
+	assert.Len(t, rrsets["c1.incus.example.net. A"], 2)
+
+	_, err := pushRecords("incus.example.net", "c1.incus.example.net. 300 IN A invalid")
+	assert.Error(t, err)
+}
+
+func TestPush(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	d := newTestPushZone(t, "push.example.net", address)
+
+	// All the records are sent initially.
+	err := d.push(address, testPushRecords(t, "push.example.net", `c1.push.example.net. 300 IN A 192.0.2.10
+c2.push.example.net. 300 IN A 192.0.2.20
+www.push.example.net. 300 IN CNAME c1.push.example.net.
+`))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		"delete c1.push.example.net. A",
+		"add c1.push.example.net. 300 IN A 192.0.2.10",
+		"delete c2.push.example.net. A",
+		"add c2.push.example.net. 300 IN A 192.0.2.20",
+		"delete www.push.example.net. CNAME",
+		"add www.push.example.net. 300 IN CNAME c1.push.example.net.",
+	}}, primary.received())
+
+	// Nothing is sent when the records didn't change.
+	err = d.push(address, testPushRecords(t, "push.example.net", `c1.push.example.net. 300 IN A 192.0.2.10
+c2.push.example.net. 300 IN A 192.0.2.20
+www.push.example.net. 300 IN CNAME c1.push.example.net.
+`))
+	require.NoError(t, err)
+	assert.Empty(t, primary.received())
+
+	// Only the changed record sets are sent.
+	err = d.push(address, testPushRecords(t, "push.example.net", `c1.push.example.net. 300 IN A 192.0.2.10
+c1.push.example.net. 300 IN A 192.0.2.11
+c2.push.example.net. 300 IN A 192.0.2.20
+c3.push.example.net. 300 IN A 192.0.2.30
+`))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		"delete c1.push.example.net. A",
+		"add c1.push.example.net. 300 IN A 192.0.2.10",
+		"add c1.push.example.net. 300 IN A 192.0.2.11",
+		"delete c3.push.example.net. A",
+		"add c3.push.example.net. 300 IN A 192.0.2.30",
+		"delete www.push.example.net. CNAME",
+	}}, primary.received())
+
+	// Everything is sent again after the full update interval.
+	pushStates["push.example.net"].lastFull = time.Now().Add(-pushFullInterval)
+
+	err = d.push(address, testPushRecords(t, "push.example.net", "c2.push.example.net. 300 IN A 192.0.2.20\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		"delete c1.push.example.net. A",
+		"delete c2.push.example.net. A",
+		"add c2.push.example.net. 300 IN A 192.0.2.20",
+		"delete c3.push.example.net. A",
+	}}, primary.received())
+}
+
+func TestPushAddressChange(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	otherPrimary, otherAddress := newTestPrimary(t, nil)
+	d := newTestPushZone(t, "move.example.net", address)
+
+	rrsets := testPushRecords(t, "move.example.net", "c1.move.example.net. 300 IN A 192.0.2.10\n")
+
+	require.NoError(t, d.push(address, rrsets))
+	assert.Len(t, primary.received(), 1)
+
+	// All the records are sent to a new primary.
+	require.NoError(t, d.push(otherAddress, rrsets))
+	assert.Empty(t, primary.received())
+	assert.Equal(t, [][]string{{
+		"delete c1.move.example.net. A",
+		"add c1.move.example.net. 300 IN A 192.0.2.10",
+	}}, otherPrimary.received())
+
+	// Disabling pushing forgets what was sent.
+	d.info.Config["push.address"] = ""
+	require.NoError(t, d.Push())
+	assert.NotContains(t, pushStates, "move.example.net")
+	assert.NotContains(t, testPushStore, "move.example.net")
+}
+
+func TestPushRestart(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	d := newTestPushZone(t, "restart.example.net", address)
+
+	err := d.push(address, testPushRecords(t, "restart.example.net", `c1.restart.example.net. 300 IN A 192.0.2.10
+c2.restart.example.net. 300 IN A 192.0.2.20
+`))
+	require.NoError(t, err)
+	assert.Len(t, primary.received(), 1)
+	assert.Len(t, testPushStore["restart.example.net"], 2)
+
+	// The record sets removed while restarting are removed from the primary.
+	pushReset("restart.example.net")
+
+	err = d.push(address, testPushRecords(t, "restart.example.net", "c1.restart.example.net. 300 IN A 192.0.2.10\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		"delete c1.restart.example.net. A",
+		"add c1.restart.example.net. 300 IN A 192.0.2.10",
+		"delete c2.restart.example.net. A",
+	}}, primary.received())
+	assert.Equal(t, map[string]db.NetworkZonePushedRRSet{
+		"c1.restart.example.net. A": {Address: address, Name: "c1.restart.example.net.", Type: "A"},
+	}, testPushStore["restart.example.net"])
+
+	// Unless the primary changed in the meantime.
+	otherPrimary, otherAddress := newTestPrimary(t, nil)
+	pushReset("restart.example.net")
+
+	err = d.push(otherAddress, testPushRecords(t, "restart.example.net", "c3.restart.example.net. 300 IN A 192.0.2.30\n"))
+	require.NoError(t, err)
+	assert.Empty(t, primary.received())
+	assert.Equal(t, [][]string{{
+		"delete c3.restart.example.net. A",
+		"add c3.restart.example.net. 300 IN A 192.0.2.30",
+	}}, otherPrimary.received())
+	assert.Len(t, testPushStore["restart.example.net"], 1)
+}
+
+func TestPushRemove(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	d := newTestPushZone(t, "remove.example.net", address)
+
+	err := d.push(address, testPushRecords(t, "remove.example.net", `c1.remove.example.net. 300 IN A 192.0.2.10
+c1.remove.example.net. 300 IN AAAA 2001:db8::10
+`))
+	require.NoError(t, err)
+	assert.Len(t, primary.received(), 1)
+
+	// All the record sets sent are removed, including after a restart.
+	pushReset("remove.example.net")
+
+	require.NoError(t, d.pushRemove())
+	assert.Equal(t, [][]string{{
+		"delete c1.remove.example.net. A",
+		"delete c1.remove.example.net. AAAA",
+	}}, primary.received())
+	assert.Empty(t, testPushStore["remove.example.net"])
+	assert.NotContains(t, pushStates, "remove.example.net")
+}
+
+func TestPushRefused(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	primary.setRcode(dns.RcodeRefused)
+	d := newTestPushZone(t, "refused.example.net", address)
+
+	rrsets := testPushRecords(t, "refused.example.net", "c1.refused.example.net. 300 IN A 192.0.2.10\n")
+
+	err := d.push(address, rrsets)
+	assert.ErrorContains(t, err, "REFUSED")
+	assert.Len(t, primary.received(), 1)
+
+	// The records are sent again on the next update.
+	primary.setRcode(dns.RcodeSuccess)
+
+	require.NoError(t, d.push(address, rrsets))
+	assert.Len(t, primary.received(), 1)
+}
+
+func TestPushTSIG(t *testing.T) {
+	secret := "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0"
+	primary, address := newTestPrimary(t, map[string]string{"push-key.": secret})
+	d := newTestPushZone(t, "tsig.example.net", address)
+	d.info.Config["push.key.name"] = "push-key"
+	d.info.Config["push.key.secret"] = secret
+
+	rrsets := testPushRecords(t, "tsig.example.net", "c1.tsig.example.net. 300 IN A 192.0.2.10\n")
+
+	// Updates are signed with the configured key.
+	require.NoError(t, d.push(address, rrsets))
+	assert.Equal(t, [][]string{{
+		"delete c1.tsig.example.net. A",
+		"add c1.tsig.example.net. 300 IN A 192.0.2.10",
+		"tsig push-key.",
+	}}, primary.received())
+
+	// Updates signed with the wrong secret are rejected.
+	pushReset("tsig.example.net")
+	d.info.Config["push.key.secret"] = "b3RoZXJvdGhlcm90aGVyb3RoZXJvdGhlcg=="
+
+	err := d.push(address, rrsets)
+	assert.Error(t, err)
+}
+
+func TestPushBatches(t *testing.T) {
+	primary, address := newTestPrimary(t, nil)
+	d := newTestPushZone(t, "batch.example.net", address)
+
+	content := &strings.Builder{}
+	for i := 0; i < 2*pushBatchSize+10; i++ {
+		fmt.Fprintf(content, "c%d.batch.example.net. 300 IN A 192.0.2.1\n", i)
+	}
+
+	// Large updates are split into several messages.
+	require.NoError(t, d.push(address, testPushRecords(t, "batch.example.net", content.String())))
+
+	updates := primary.received()
+	require.Len(t, updates, 3)
+	assert.Len(t, updates[0], 2*pushBatchSize)
+	assert.Len(t, updates[1], 2*pushBatchSize)
+	assert.Len(t, updates[2], 20)
+}
@@ -3,6 +3,7 @@ package zone
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"slices"
 	"strings"
@@ -174,6 +175,42 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  shortdesc: Comma-separated list of DNS server FQDNs (for NS records)
 	rules["dns.nameservers"] = validate.IsListOf(validate.IsAny)
 
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.enabled)
+	// See {ref}`network-zones-dnssec`.
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to sign the zone with DNSSEC
+	rules["dnssec.enabled"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.algorithm)
+	// Possible values are `ECDSAP256SHA256`, `ECDSAP384SHA384`, `ED25519` and `RSASHA256`.
+	// ---
+	//  type: string
+	//  required: no
+	//  defaultdesc: `ECDSAP256SHA256`
+	//  shortdesc: Algorithm of the DNSSEC keys
+	rules["dnssec.algorithm"] = validate.Optional(validate.IsOneOf(slices.Sorted(maps.Keys(dnssecAlgorithmBits))...))
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.ksk.lifetime)
+	// As the DS record of the key signing key must be updated in the parent zone when it's replaced, automatic replacement is disabled by default.
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `0`
+	//  shortdesc: Number of days after which the key signing key is replaced (`0` to disable)
+	rules["dnssec.ksk.lifetime"] = validate.Optional(validate.IsUint32)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.zsk.lifetime)
+	//
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `30`
+	//  shortdesc: Number of days after which the zone signing key is replaced (`0` to disable)
+	rules["dnssec.zsk.lifetime"] = validate.Optional(validate.IsUint32)
+
 	// gendoc:generate(entity=network_zone, group=common, key=network.nat)
 	//
 	// ---
@@ -183,6 +220,39 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  shortdesc: Whether to generate records for NAT-ed subnets
 	rules["network.nat"] = validate.Optional(validate.IsBool)
 
+	// gendoc:generate(entity=network_zone, group=common, key=push.address)
+	// See {ref}`network-zones-push`.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Address of the primary DNS server to send dynamic updates to
+	rules["push.address"] = validate.Optional(validate.IsListenAddress(true, false, false))
+
+	// gendoc:generate(entity=network_zone, group=common, key=push.key.algorithm)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  defaultdesc: `hmac-sha256`
+	//  shortdesc: Algorithm of the TSIG key used for dynamic updates
+	rules["push.key.algorithm"] = validate.Optional(validate.IsOneOf("hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512"))
+
+	// gendoc:generate(entity=network_zone, group=common, key=push.key.name)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Name of the TSIG key used for dynamic updates
+	rules["push.key.name"] = validate.Optional(validate.IsAny)
+
+	// gendoc:generate(entity=network_zone, group=common, key=push.key.secret)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Secret of the TSIG key used for dynamic updates
+	rules["push.key.secret"] = validate.Optional(validate.IsAny)
+
 	// Validate peer config.
 	for k := range info.Config {
 		if !strings.HasPrefix(k, "peers.") {
@@ -307,6 +377,14 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		}
 	}
 
+	// Generate or remove the DNSSEC keys.
+	if clientType == request.ClientTypeNormal {
+		err = d.RotateDNSSECKeys()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Trigger a refresh of the TSIG entries.
 	err = d.state.DNS.UpdateTSIG()
 	if err != nil {
@@ -328,6 +406,12 @@ func (d *zone) Delete() error {
 		return fmt.Errorf("Cannot delete a zone that is in use")
 	}
 
+	// Remove the records from the primary DNS server.
+	err = d.pushRemove()
+	if err != nil {
+		return fmt.Errorf("Failed removing the records of the zone from its primary DNS server: %w", err)
+	}
+
 	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Delete the database record.
 		err = tx.DeleteNetworkZone(ctx, d.id)
@@ -338,6 +422,9 @@ func (d *zone) Delete() error {
 		return err
 	}
 
+	pushReset(d.info.Name)
+	d.state.DNS.RemoveZone(d.info.Name)
+
 	// Trigger a refresh of the TSIG entries.
 	err = d.state.DNS.UpdateTSIG()
 	if err != nil {
@@ -4,12 +4,19 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/cancel"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 func registerDBOperation(op *Operation, opType operationtype.Type) error {
@@ -55,6 +62,192 @@ func removeDBOperation(op *Operation) error {
 	return err
 }
 
+func saveDBCheckpoint(op *Operation) error {
+	op.lock.Lock()
+
+	resources := make(map[string][]string, len(op.resources))
+	for key, urls := range op.resources {
+		for _, u := range urls {
+			resources[key] = append(resources[key], u.String())
+		}
+	}
+
+	checkpoint := db.OperationCheckpoint{
+		UUID:       op.id,
+		Project:    op.projectName,
+		Type:       op.dbOpType,
+		CreatedAt:  op.createdAt,
+		UpdatedAt:  op.updatedAt,
+		Checkpoint: "null",
+	}
+
+	if op.checkpoint != nil {
+		checkpoint.Checkpoint = string(op.checkpoint)
+	}
+
+	metadata, err := json.Marshal(op.metadata)
+	requestor, _ := json.Marshal(op.requestor)
+	op.lock.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("Failed encoding operation metadata: %w", err)
+	}
+
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return fmt.Errorf("Failed encoding operation resources: %w", err)
+	}
+
+	checkpoint.Metadata = string(metadata)
+	checkpoint.Resources = string(resourcesJSON)
+	checkpoint.Requestor = string(requestor)
+
+	return op.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertOperationCheckpoint(ctx, checkpoint)
+	})
+}
+
+func removeDBCheckpoint(op *Operation) error {
+	return op.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteOperationCheckpoint(ctx, op.id)
+	})
+}
+
+// RestoreInterrupted restores the durable operations of this member which were interrupted by the last shutdown.
+// Operations with a checkpoint and a resume function for their type are resumed under the same ID, the others
+// are marked as failed and kept around for a while so that clients can find out what happened to them.
+func RestoreInterrupted(s *state.State, resumers map[operationtype.Type]ResumeFunc) error {
+	var checkpoints []db.OperationCheckpoint
+
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		checkpoints, err = tx.GetOperationCheckpoints(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading interrupted operations: %w", err)
+	}
+
+	for _, checkpoint := range checkpoints {
+		op, err := restoreOperation(s, checkpoint)
+		if err != nil {
+			logger.Warn("Failed restoring interrupted operation", logger.Ctx{"operation": checkpoint.UUID, "err": err})
+
+			err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.DeleteOperationCheckpoint(ctx, checkpoint.UUID)
+			})
+			if err != nil {
+				logger.Warn("Failed to delete operation checkpoint", logger.Ctx{"operation": checkpoint.UUID, "err": err})
+			}
+
+			continue
+		}
+
+		resume, ok := resumers[op.dbOpType]
+		if ok && op.checkpoint != nil {
+			op.logger.Info("Resuming interrupted operation")
+
+			data := op.checkpoint
+			op.onRun = func(op *Operation) error {
+				return resume(op, data)
+			}
+
+			err = op.Start()
+			if err != nil {
+				op.logger.Warn("Failed resuming interrupted operation", logger.Ctx{"err": err})
+			}
+
+			continue
+		}
+
+		op.logger.Info("Marking interrupted operation as failed")
+
+		op.lock.Lock()
+		op.status = api.Failure
+		op.err = fmt.Errorf("Operation interrupted by a restart of the server")
+		op.retention = interruptedRetention
+		op.lock.Unlock()
+		op.done()
+
+		_, md, _ := op.Render()
+
+		op.lock.Lock()
+		op.sendEvent(md)
+		op.lock.Unlock()
+	}
+
+	return nil
+}
+
+// restoreOperation registers a pending operation from its recorded state.
+func restoreOperation(s *state.State, checkpoint db.OperationCheckpoint) (*Operation, error) {
+	op := &Operation{}
+	op.projectName = checkpoint.Project
+	op.id = checkpoint.UUID
+	op.description = checkpoint.Type.Description()
+	op.objectType, op.entitlement = checkpoint.Type.Permission()
+	op.dbOpType = checkpoint.Type
+	op.class = OperationClassTask
+	op.createdAt = checkpoint.CreatedAt
+	op.updatedAt = time.Now()
+	op.status = api.Pending
+	op.url = fmt.Sprintf("/%s/operations/%s", version.APIVersion, op.id)
+	op.finished = cancel.New(context.Background())
+	op.state = s
+	op.logger = logger.AddContext(logger.Ctx{"operation": op.id, "project": op.projectName, "class": op.class.String(), "description": op.description})
+	op.retention = finishedRetention
+	op.durable = true
+	op.SetEventServer(s.Events)
+
+	resources := map[string][]string{}
+	err := json.Unmarshal([]byte(checkpoint.Resources), &resources)
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding operation resources: %w", err)
+	}
+
+	op.resources = make(map[string][]api.URL, len(resources))
+	for key, urls := range resources {
+		for _, rawURL := range urls {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing operation resource %q: %w", rawURL, err)
+			}
+
+			op.resources[key] = append(op.resources[key], api.URL{URL: *u})
+		}
+	}
+
+	err = json.Unmarshal([]byte(checkpoint.Metadata), &op.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding operation metadata: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(checkpoint.Requestor), &op.requestor)
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding operation requestor: %w", err)
+	}
+
+	if checkpoint.Checkpoint != "" && checkpoint.Checkpoint != "null" {
+		op.checkpoint = json.RawMessage(checkpoint.Checkpoint)
+	}
+
+	operationsLock.Lock()
+	operations[op.id] = op
+	operationsLock.Unlock()
+
+	err = registerDBOperation(op, op.dbOpType)
+	if err != nil {
+		operationsLock.Lock()
+		delete(operations, op.id)
+		operationsLock.Unlock()
+
+		return nil, err
+	}
+
+	return op, nil
+}
+
 func (op *Operation) sendEvent(eventMessage any) {
 	if op.events == nil {
 		return
@@ -0,0 +1,133 @@
+//go:build linux && cgo && !agent
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// checkpointIDs returns the IDs of the operations with a recorded checkpoint.
+func checkpointIDs(t *testing.T, s *state.State) []string {
+	var checkpoints []db.OperationCheckpoint
+
+	err := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		checkpoints, err = tx.GetOperationCheckpoints(ctx)
+		return err
+	})
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, checkpoint := range checkpoints {
+		ids = append(ids, checkpoint.UUID)
+	}
+
+	return ids
+}
+
+// Test that durable operations interrupted by a shutdown are resumed or failed on the next start.
+func TestRestoreInterrupted(t *testing.T) {
+	cluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+
+	s := &state.State{ShutdownCtx: shutdownCtx, DB: &db.DB{Cluster: cluster}}
+
+	// Start durable operations which complete once released.
+	releases := map[*Operation]chan error{}
+
+	start := func(opType operationtype.Type) *Operation {
+		release := make(chan error)
+		run := func(op *Operation) error { return <-release }
+
+		op, err := OperationCreate(s, "default", OperationClassTask, opType, nil, map[string]any{"progress": "10%"}, run, nil, nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, op.Start())
+
+		releases[op] = release
+
+		return op
+	}
+
+	download := start(operationtype.ImageDownload)
+	require.NoError(t, download.Checkpoint(map[string]string{"fingerprint": "abcd"}))
+
+	completed := start(operationtype.InstanceCreate)
+	interrupted := start(operationtype.InstanceCreate)
+	succeeded := start(operationtype.VolumeCopy)
+	assert.ElementsMatch(t, []string{download.ID(), completed.ID(), interrupted.ID(), succeeded.ID()}, checkpointIDs(t, s))
+
+	// Operations completing before the shutdown don't get restored.
+	releases[completed] <- errors.New("Instance creation failed")
+	require.Error(t, completed.Wait(context.Background()))
+	assert.Eventually(t, func() bool { return len(checkpointIDs(t, s)) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.NotContains(t, checkpointIDs(t, s), completed.ID())
+
+	// Failures caused by the shutdown keep the checkpoint while successes remove it.
+	shutdown()
+
+	for _, op := range []*Operation{download, interrupted} {
+		releases[op] <- context.Canceled
+		require.Error(t, op.Wait(context.Background()))
+	}
+
+	releases[succeeded] <- nil
+	require.NoError(t, succeeded.Wait(context.Background()))
+	assert.Eventually(t, func() bool { return len(checkpointIDs(t, s)) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{download.ID(), interrupted.ID()}, checkpointIDs(t, s))
+
+	// Add a checkpoint which can't be restored.
+	err := cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertOperationCheckpoint(ctx, db.OperationCheckpoint{UUID: "broken", Type: operationtype.ImageDownload, CreatedAt: time.Now(), UpdatedAt: time.Now(), Resources: "{", Metadata: "{}", Requestor: "null", Checkpoint: "null"})
+	})
+	require.NoError(t, err)
+
+	// Restart.
+	s = &state.State{ShutdownCtx: context.Background(), DB: &db.DB{Cluster: cluster}}
+
+	resumed := make(chan json.RawMessage, 1)
+	err = RestoreInterrupted(s, map[operationtype.Type]ResumeFunc{
+		operationtype.ImageDownload: func(op *Operation, checkpoint json.RawMessage) error {
+			assert.Equal(t, download.ID(), op.ID())
+			resumed <- checkpoint
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	// Operations with a resume function are resumed under the same ID from their checkpoint.
+	op, err := OperationGetInternal(download.ID())
+	require.NoError(t, err)
+	require.NoError(t, op.Wait(context.Background()))
+	assert.JSONEq(t, `{"fingerprint": "abcd"}`, string(<-resumed))
+	assert.Equal(t, api.Success, op.Status())
+	assert.Equal(t, "10%", op.Metadata()["progress"])
+
+	// The others are failed and kept around for clients to find out about them.
+	op, err = OperationGetInternal(interrupted.ID())
+	require.NoError(t, err)
+	assert.ErrorContains(t, op.Wait(context.Background()), "interrupted by a restart")
+	assert.Equal(t, api.Failure, op.Status())
+	assert.Equal(t, interruptedRetention, op.retention)
+
+	_, err = OperationGetInternal("broken")
+	assert.Error(t, err)
+
+	// No checkpoint is left once the operations are restored.
+	assert.Eventually(t, func() bool { return len(checkpointIDs(t, s)) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 )
 
@@ -25,6 +26,19 @@ func removeDBOperation(op *Operation) error {
 	return nil
 }
 
+func saveDBCheckpoint(op *Operation) error {
+	return fmt.Errorf("saveDBCheckpoint not supported on this platform")
+}
+
+func removeDBCheckpoint(op *Operation) error {
+	return fmt.Errorf("removeDBCheckpoint not supported on this platform")
+}
+
+// RestoreInterrupted restores the durable operations of this member which were interrupted by the last shutdown.
+func RestoreInterrupted(s *state.State, resumers map[operationtype.Type]ResumeFunc) error {
+	return fmt.Errorf("RestoreInterrupted not supported on this platform")
+}
+
 func (op *Operation) sendEvent(eventMessage any) {
 	if op.events == nil {
 		return
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -29,6 +30,15 @@ var (
 	operations     = make(map[string]*Operation)
 )
 
+// finishedRetention is how long finished operations are kept around for clients to query their outcome.
+const finishedRetention = 5 * time.Second
+
+// interruptedRetention is how long durable operations which couldn't be resumed after a restart are kept around.
+const interruptedRetention = 24 * time.Hour
+
+// ResumeFunc resumes an interrupted durable operation from its last checkpoint.
+type ResumeFunc func(op *Operation, checkpoint json.RawMessage) error
+
 // OperationClass represents the OperationClass type.
 type OperationClass int
 
@@ -106,6 +116,11 @@ type Operation struct {
 	dbOpType    operationtype.Type
 	requestor   *api.EventLifecycleRequestor
 	logger      logger.Logger
+	retention   time.Duration
+
+	// Durable operations have their state recorded in the database so they can be restored after a restart.
+	durable    bool
+	checkpoint json.RawMessage
 
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
@@ -146,6 +161,8 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 	op.finished = cancel.New(context.Background())
 	op.state = s
 	op.logger = logger.AddContext(logger.Ctx{"operation": op.id, "project": op.projectName, "class": op.class.String(), "description": op.description})
+	op.retention = finishedRetention
+	op.durable = s != nil && opClass == OperationClassTask && opType.Durable()
 
 	if s != nil {
 		op.SetEventServer(s.Events)
@@ -194,6 +211,13 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 		return nil, err
 	}
 
+	if op.durable {
+		err = saveDBCheckpoint(&op)
+		if err != nil {
+			op.logger.Warn("Failed recording durable operation, it won't be restored after a restart", logger.Ctx{"err": err})
+		}
+	}
+
 	op.logger.Debug("New operation")
 	_, md, _ := op.Render()
 
@@ -235,8 +259,17 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	status := op.status
 	op.lock.Unlock()
 
+	// Keep the state of durable operations interrupted by a shutdown so they get restored on the next start.
+	if op.durable && (op.state.ShutdownCtx.Err() == nil || status == api.Success) {
+		err := removeDBCheckpoint(op)
+		if err != nil {
+			op.logger.Warn("Failed to delete operation checkpoint", logger.Ctx{"err": err})
+		}
+	}
+
 	go func() {
 		shutdownCtx := context.Background()
 		if op.state != nil {
@@ -246,7 +279,7 @@ func (op *Operation) done() {
 		select {
 		case <-shutdownCtx.Done():
 			return // Expect all operation records to be removed by waitForOperations in one query.
-		case <-time.After(time.Second * 5): // Wait 5s before removing from internal map and database.
+		case <-time.After(op.retention): // Wait before removing from internal map and database.
 		}
 
 		operationsLock.Lock()
@@ -638,6 +671,26 @@ func (op *Operation) ExtendMetadata(metadata any) error {
 	return nil
 }
 
+// Checkpoint records the progress of a durable operation along with its current metadata.
+// Should the daemon restart before the operation completes, the data is handed back to the resume function
+// registered for the operation type.
+func (op *Operation) Checkpoint(data any) error {
+	if !op.durable {
+		return fmt.Errorf("Only durable operations can be checkpointed")
+	}
+
+	checkpoint, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("Failed encoding operation checkpoint: %w", err)
+	}
+
+	op.lock.Lock()
+	op.checkpoint = checkpoint
+	op.lock.Unlock()
+
+	return saveDBCheckpoint(op)
+}
+
 // ID returns the operation ID.
 func (op *Operation) ID() string {
 	return op.id
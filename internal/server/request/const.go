@@ -28,6 +28,12 @@ const (
 
 	// CtxForwardedProtocol is the forwarded protocol field in request context.
 	CtxForwardedProtocol CtxKey = "forwarded_protocol"
+
+	// CtxOIDCGroups is the OIDC groups field in request context.
+	CtxOIDCGroups CtxKey = "oidc_groups"
+
+	// CtxForwardedOIDCGroups is the forwarded OIDC groups field in request context.
+	CtxForwardedOIDCGroups CtxKey = "forwarded_oidc_groups"
 )
 
 // Headers.
@@ -40,4 +46,7 @@ const (
 
 	// HeaderForwardedProtocol is the forwarded protocol field in request header.
 	HeaderForwardedProtocol = "X-Incus-forwarded-protocol"
+
+	// HeaderForwardedOIDCGroups is the forwarded OIDC groups field in request header.
+	HeaderForwardedOIDCGroups = "X-Incus-forwarded-oidc-groups"
 )
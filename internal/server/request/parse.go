@@ -1,6 +1,7 @@
 package request
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
 
@@ -38,3 +39,31 @@ func QueryParam(request *http.Request, key string) string {
 
 	return values.Get(key)
 }
+
+// EncodeForwardedOIDCGroups encodes the OIDC groups for the forwarded OIDC groups header.
+// The groups are encoded as a JSON list, as group names may contain any character.
+func EncodeForwardedOIDCGroups(groups []string) (string, error) {
+	value, err := json.Marshal(groups)
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+// ParseForwardedOIDCGroups decodes the value of the forwarded OIDC groups header.
+// Invalid values result in no groups.
+func ParseForwardedOIDCGroups(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	groups := []string{}
+	err := json.Unmarshal([]byte(value), &groups)
+	if err != nil {
+		logger.Warn("Failed to parse forwarded OIDC groups", logger.Ctx{"err": err})
+		return nil
+	}
+
+	return groups
+}
@@ -0,0 +1,20 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardedOIDCGroups(t *testing.T) {
+	groups := []string{"admins", "dev,ops", "a \"quoted\" group", "line\nbreak", "équipe"}
+
+	value, err := EncodeForwardedOIDCGroups(groups)
+	require.NoError(t, err)
+	assert.NotContains(t, value, "\n")
+	assert.Equal(t, groups, ParseForwardedOIDCGroups(value))
+
+	assert.Nil(t, ParseForwardedOIDCGroups(""))
+	assert.Nil(t, ParseForwardedOIDCGroups("admins,dev"))
+}
@@ -76,6 +76,10 @@ var instanceDiskVolumeEffectiveFields = []string{
 	"size.state",
 }
 
+// instanceQuiesceTimeout is how long the guest filesystems of a virtual machine may remain frozen while a snapshot
+// of it is taken.
+const instanceQuiesceTimeout = time.Minute
+
 type backend struct {
 	driver drivers.Driver
 	id     int64
@@ -1017,6 +1021,81 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 	return postHook, revertHook, nil
 }
 
+// RefreshInstanceFromBackup applies an incremental backup onto an existing instance.
+// The instance must already have the snapshot the backup is based on and the new snapshots contained in the
+// backup are added to it, while its main volume is replaced by the one from the backup.
+// The caller is responsible for creating the database records of the new snapshots beforehand.
+func (b *backend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "incrementalFrom": srcBackup.IncrementalFrom, "snapshots": srcBackup.Snapshots})
+	l.Debug("RefreshInstanceFromBackup started")
+	defer l.Debug("RefreshInstanceFromBackup finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if srcBackup.IncrementalFrom == "" {
+		return fmt.Errorf("Backup isn't an incremental backup")
+	}
+
+	if srcBackup.OptimizedStorage == nil || !*srcBackup.OptimizedStorage || srcBackup.Backend != b.driver.Info().Name {
+		return fmt.Errorf("Incremental backups can only be applied onto a storage pool using the %q driver", srcBackup.Backend)
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	// Load storage volume from database.
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	// Generate the effective root device volume for instance.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Unpack the incremental backup onto the existing storage volume(s).
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(vol, srcBackup, srcData, op)
+	if err != nil {
+		return err
+	}
+
+	if revertHook != nil {
+		reverter.Add(revertHook)
+	}
+
+	if len(srcBackup.Snapshots) > 0 {
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	// If the driver returned a post hook, run it now.
+	if volPostHook != nil {
+		err = volPostHook(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // CreateInstanceFromCopy copies an instance volume and optionally its snapshots to new volume(s).
 func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
@@ -2689,11 +2768,22 @@ func (b *backend) CleanupInstancePaths(inst instance.Instance, op *operations.Op
 }
 
 // BackupInstance creates an instance backup.
-func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+// If incrementalFrom is set, only the changes made since that snapshot are included in the backup.
+func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, incrementalFrom string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "incrementalFrom": incrementalFrom})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	if incrementalFrom != "" {
+		if !optimized || !b.driver.Info().OptimizedBackups {
+			return fmt.Errorf("Incremental backups require optimized storage")
+		}
+
+		if !snapshots {
+			return fmt.Errorf("Incremental backups require snapshots to be included")
+		}
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2715,6 +2805,12 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		return err
 	}
 
+	// Let the driver flush and freeze the guest filesystems of virtual machines while it copies the volume.
+	vm, ok := inst.(instance.VM)
+	if ok {
+		vol.SetQuiesceHook(vm.Quiesce)
+	}
+
 	// Ensure the backup file reflects current config.
 	err = b.UpdateInstanceBackupFile(inst, snapshots, op)
 	if err != nil {
@@ -2734,9 +2830,19 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 			_, snapName, _ := api.GetParentAndSnapshotName(instSnapshot.Name())
 			snapNames = append(snapNames, snapName)
 		}
+
+		if incrementalFrom != "" {
+			// Only include the snapshots taken after the incremental base snapshot.
+			idx := slices.Index(snapNames, incrementalFrom)
+			if idx < 0 {
+				return fmt.Errorf("Incremental base snapshot %q not found", incrementalFrom)
+			}
+
+			snapNames = snapNames[idx+1:]
+		}
 	}
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, incrementalFrom, op)
 	if err != nil {
 		return err
 	}
@@ -3104,11 +3210,28 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 
 	defer unlock()
 
+	// Flush and freeze the guest filesystems of virtual machines while the snapshot is taken.
+	thaw := func() error { return nil }
+	vm, ok := src.(instance.VM)
+	if ok && !inst.IsStateful() {
+		thaw, err = vm.Quiesce(instanceQuiesceTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = b.driver.CreateVolumeSnapshot(vol, op)
+	thawErr := thaw()
 	if err != nil {
 		return err
 	}
 
+	reverter.Add(func() { _ = b.driver.DeleteVolumeSnapshot(vol, op) })
+
+	if thawErr != nil {
+		return thawErr
+	}
+
 	err = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
 	if err != nil {
 		return err
@@ -5347,7 +5470,12 @@ func (b *backend) RenameCustomVolume(projectName string, volName string, newVolN
 		backupRow := br // Local var for revert.
 		_, backupName, _ := api.GetParentAndSnapshotName(backupRow.Name)
 		newVolBackupName := drivers.GetSnapshotVolumeName(newVolName, backupName)
-		volBackup := backup.NewVolumeBackup(b.state, projectName, b.name, volName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage)
+		target, err := instance.BackupTargetLoad(b.state, backupRow.Target)
+		if err != nil {
+			return err
+		}
+
+		volBackup := backup.NewVolumeBackup(b.state, projectName, b.name, volName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage, target)
 		err = volBackup.Rename(newVolBackupName)
 		if err != nil {
 			return fmt.Errorf("Failed renaming backup %q to %q: %w", backupRow.Name, newVolBackupName, err)
@@ -5748,6 +5876,30 @@ func (b *backend) DeleteCustomVolume(projectName string, volName string, op *ope
 		}
 	}
 
+	// Remove all backups, including those stored on a remote backup target.
+	var backups []db.StoragePoolVolumeBackup
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		backups, err = tx.GetStoragePoolVolumeBackups(ctx, projectName, volName, b.ID())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, backupRow := range backups {
+		target, err := instance.BackupTargetLoad(b.state, backupRow.Target)
+		if err != nil {
+			return err
+		}
+
+		volBackup := backup.NewVolumeBackup(b.state, projectName, b.name, volName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage, target)
+		err = volBackup.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup %q: %w", backupRow.Name, err)
+		}
+	}
+
 	// Remove backups directory for volume.
 	backupsPath := internalUtil.VarPath("backups", "custom", b.name, project.StorageVolume(projectName, volName))
 	if util.PathExists(backupsPath) {
@@ -6249,6 +6401,70 @@ func (b *backend) RestoreCustomVolume(projectName, volName string, snapshotName
 	return nil
 }
 
+// DiffVolumeSnapshots lists the paths that differ between two snapshots of a filesystem volume.
+// An empty toSnapshotName compares the snapshot against the current state of the volume.
+func (b *backend) DiffVolumeSnapshots(projectName string, volName string, volType drivers.VolumeType, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "volType": volType, "fromSnapshotName": fromSnapshotName, "toSnapshotName": toSnapshotName})
+	l.Debug("DiffVolumeSnapshots started")
+	defer l.Debug("DiffVolumeSnapshots finished")
+
+	// Quick checks.
+	if internalInstance.IsSnapshot(volName) {
+		return nil, fmt.Errorf("Volume cannot be snapshot")
+	}
+
+	if fromSnapshotName == "" || internalInstance.IsSnapshot(fromSnapshotName) || internalInstance.IsSnapshot(toSnapshotName) {
+		return nil, fmt.Errorf("Invalid snapshot name")
+	}
+
+	// Get current volume.
+	curVol, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the snapshots exist.
+	for _, snapshotName := range []string{fromSnapshotName, toSnapshotName} {
+		if snapshotName == "" {
+			continue
+		}
+
+		_, err = VolumeDBGet(b, projectName, drivers.GetSnapshotVolumeName(volName, snapshotName), volType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbContentType, err := VolumeContentTypeNameToContentType(curVol.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := VolumeDBContentTypeToContentType(dbContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != drivers.ContentTypeFS {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Snapshot diffs are only supported for filesystem volumes")
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(volType, contentType, volStorageName, curVol.Config)
+
+	diff, err := b.driver.DiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Snapshot diffs aren't supported by the %q storage driver", b.driver.Info().Name)
+		}
+
+		return nil, err
+	}
+
+	return diff, nil
+}
+
 func (b *backend) createStorageStructure(path string) error {
 	for _, volType := range b.driver.Info().VolumeTypes {
 		for _, name := range drivers.BaseDirectories[volType] {
@@ -7159,7 +7375,7 @@ func (b *backend) BackupCustomVolume(projectName string, volName string, tarWrit
 
 	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, "", op)
 	if err != nil {
 		return err
 	}
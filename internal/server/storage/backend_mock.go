@@ -117,6 +117,10 @@ func (b *mockBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io
 	return nil, nil, nil
 }
 
+func (b *mockBackend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	return nil
 }
@@ -182,7 +186,7 @@ func (b *mockBackend) RefreshInstance(inst instance.Instance, src instance.Insta
 	return nil
 }
 
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, incrementalFrom string, op *operations.Operation) error {
 	return nil
 }
 
@@ -351,6 +355,10 @@ func (b *mockBackend) RestoreCustomVolume(projectName string, volName string, sn
 	return nil
 }
 
+func (b *mockBackend) DiffVolumeSnapshots(projectName string, volName string, volType drivers.VolumeType, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	return nil, nil
+}
+
 func (b *mockBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
 	return nil
 }
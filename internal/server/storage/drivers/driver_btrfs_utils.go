@@ -658,3 +658,134 @@ func (d *btrfs) receiveSubVolume(r io.Reader, receivePath string, tracker *iopro
 
 	return subVolPath, nil
 }
+
+// btrfsParseDiffDump converts the output of `btrfs receive --dump` for an incremental send stream into
+// volume diff entries. New files are created under temporary names by the stream before being renamed
+// into place, so changes are tracked per path until the whole stream has been processed.
+func btrfsParseDiffDump(dump string) ([]api.StorageVolumeSnapshotDiff, error) {
+	changes := map[string]string{}
+	prefix := ""
+
+	// markAdded records a new path, replacing a previously removed path counts as a modification.
+	markAdded := func(path string) {
+		if changes[path] == VolumeDiffRemoved {
+			changes[path] = VolumeDiffModified
+		} else {
+			changes[path] = VolumeDiffAdded
+		}
+	}
+
+	// markRemoved records a removed path, removing a path added by the stream cancels it out.
+	markRemoved := func(path string) {
+		if changes[path] == VolumeDiffAdded {
+			delete(changes, path)
+		} else {
+			changes[path] = VolumeDiffRemoved
+		}
+	}
+
+	for _, line := range strings.Split(dump, "\n") {
+		fields := btrfsSplitDumpLine(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		command := fields[0]
+
+		// The paths of all other commands are below the received subvolume.
+		if command == "snapshot" || command == "subvol" {
+			prefix = fields[1]
+			continue
+		}
+
+		path := volumeDiffPath(strings.TrimPrefix(fields[1], prefix))
+
+		switch command {
+		case "mkfile", "mkdir", "mknod", "mkfifo", "mksock", "symlink", "link":
+			markAdded(path)
+		case "unlink", "rmdir":
+			markRemoved(path)
+		case "rename":
+			var dest string
+			for _, field := range fields[2:] {
+				value, found := strings.CutPrefix(field, "dest=")
+				if found {
+					dest = value
+					break
+				}
+			}
+
+			if dest == "" {
+				return nil, fmt.Errorf("Missing rename destination in BTRFS stream dump %q", line)
+			}
+
+			markRemoved(path)
+			markAdded(volumeDiffPath(strings.TrimPrefix(dest, prefix)))
+		default:
+			// Any other command changes the content or the metadata of an existing path.
+			_, found := changes[path]
+			if !found {
+				changes[path] = VolumeDiffModified
+			}
+		}
+	}
+
+	diff := make([]api.StorageVolumeSnapshotDiff, 0, len(changes))
+	for path, change := range changes {
+		diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: change})
+	}
+
+	return diff, nil
+}
+
+// btrfsSplitDumpLine splits a line of `btrfs receive --dump` output into its fields, unescaping the
+// whitespace, control and non-printable characters that are escaped with a backslash in paths.
+func btrfsSplitDumpLine(line string) []string {
+	escapes := map[byte]byte{'a': '\a', 'b': '\b', 'e': 0x1b, 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v'}
+
+	var fields []string
+	var field []byte
+	inField := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, string(field))
+				field = field[:0]
+				inField = false
+			}
+
+			continue
+		case c == '\\' && i+1 < len(line):
+			i++
+			next := line[i]
+
+			escaped, found := escapes[next]
+			if found {
+				c = escaped
+			} else if next >= '0' && next <= '7' && i+2 < len(line) {
+				value, err := strconv.ParseUint(line[i:i+3], 8, 8)
+				if err == nil {
+					c = byte(value)
+					i += 2
+				} else {
+					c = next
+				}
+			} else {
+				c = next
+			}
+		}
+
+		field = append(field, c)
+		inField = true
+	}
+
+	if inField {
+		fields = append(fields, string(field))
+	}
+
+	return fields
+}
@@ -0,0 +1,40 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Test btrfsParseDiffDump.
+func TestBtrfsParseDiffDump(t *testing.T) {
+	dump := `snapshot        ./snap1                         uuid=0b0a1e04-3d4f-3a4e-9c0b-5d1c2c3e4f50 transid=12 parent_uuid=1c1b2f15-4e5a-4b5f-8d1c-6e2d3d4f5a61 parent_transid=10
+utimes          ./snap1/                        atime=2024-01-01T00:00:00+0000 mtime=2024-01-01T00:00:00+0000 ctime=2024-01-01T00:00:00+0000
+mkfile          ./snap1/o257-12-0
+rename          ./snap1/o257-12-0               dest=./snap1/new\ file
+utimes          ./snap1/new\ file               atime=2024-01-01T00:00:00+0000 mtime=2024-01-01T00:00:00+0000 ctime=2024-01-01T00:00:00+0000
+chown           ./snap1/new\ file               gid=0 uid=0
+unlink          ./snap1/old
+rename          ./snap1/a                       dest=./snap1/b
+update_extent   ./snap1/changed                 offset=0 len=4096
+unlink          ./snap1/replaced
+mkfile          ./snap1/o258-12-0
+rename          ./snap1/o258-12-0               dest=./snap1/replaced
+`
+
+	diff, err := btrfsParseDiffDump(dump)
+	assert.NoError(t, err)
+
+	sortVolumeDiff(diff)
+	assert.Equal(t, []api.StorageVolumeSnapshotDiff{
+		{Path: "/", Type: VolumeDiffModified},
+		{Path: "/a", Type: VolumeDiffRemoved},
+		{Path: "/b", Type: VolumeDiffAdded},
+		{Path: "/changed", Type: VolumeDiffModified},
+		{Path: "/new file", Type: VolumeDiffAdded},
+		{Path: "/old", Type: VolumeDiffRemoved},
+		{Path: "/replaced", Type: VolumeDiffModified},
+	}, diff)
+}
@@ -164,7 +164,17 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are applied on top of the existing volume which must have the base snapshot.
+	if srcBackup.IncrementalFrom != "" {
+		if !volExists {
+			return nil, nil, fmt.Errorf("Cannot apply incremental backup, volume doesn't exist on target")
+		}
+
+		err = vol.SnapshotsExist([]string{srcBackup.IncrementalFrom}, op)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Cannot apply incremental backup: %w", err)
+		}
+	} else if volExists {
 		return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
 	}
 
@@ -180,8 +190,10 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume (unless it existed before).
+		if srcBackup.IncrementalFrom == "" {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 	// Only execute the revert function if we have had an error internally.
 	reverter.Add(revertHook)
@@ -374,6 +386,14 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 			return nil, nil, err
 		}
 
+		// When applying an incremental backup, replace the existing main volume subvolume.
+		if srcBackup.IncrementalFrom != "" && d.isSubvolume(copyOp.dest) {
+			err = d.deleteSubvolume(copyOp.dest, true)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
 		// Clear the target for the subvol to use.
 		_ = os.Remove(copyOp.dest)
 
@@ -1527,7 +1547,7 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1551,7 +1571,13 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	if incrementalFrom != "" {
+		// Check requested snapshots and their incremental base exist in storage.
+		err := vol.SnapshotsExist(append([]string{incrementalFrom}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -1701,6 +1727,12 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 
 	// Backup snapshots if populated.
 	lastVolPath := "" // Used as parent for differential exports.
+	if incrementalFrom != "" {
+		// Incremental backups only include the changes since the base snapshot.
+		baseSnapVol, _ := vol.NewSnapshot(incrementalFrom)
+		lastVolPath = baseSnapVol.MountPath()
+	}
+
 	for _, snapName := range snapshots {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
@@ -1741,15 +1773,25 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 		return fmt.Errorf("Failed to chmod %q: %w", tmpInstanceMntPoint, err)
 	}
 
-	// Create the read-only snapshot.
+	// Create the read-only snapshot, with the data of the running instance frozen only while it's taken.
+	thaw, err := vol.quiesce(quiesceSnapshotTimeout)
+	if err != nil {
+		return err
+	}
+
 	targetVolume := fmt.Sprintf("%s/.backup", tmpInstanceMntPoint)
 	_, err = d.snapshotSubvolume(sourceVolume, targetVolume, true)
+	thawErr := thaw()
 	if err != nil {
 		return err
 	}
 
 	defer func() { _ = d.deleteSubvolume(targetVolume, true) }()
 
+	if thawErr != nil {
+		return thawErr
+	}
+
 	err = d.setSubvolumeReadonlyProperty(targetVolume, true)
 	if err != nil {
 		return err
@@ -1941,6 +1983,70 @@ func (d *btrfs) volumeSnapshotsSorted(vol Volume, op *operations.Operation) ([]s
 	return snapshotNames, nil
 }
 
+// DiffVolume lists the paths that differ between two snapshots of a volume using the metadata of an
+// incremental send stream. As only read-only subvolumes can be sent, comparing a snapshot against the
+// volume itself falls back to comparing the file trees.
+func (d *btrfs) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	if vol.contentType != ContentTypeFS || toSnapshotName == "" {
+		return genericVFSDiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+	}
+
+	fromPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(vol.name, fromSnapshotName))
+	toPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(vol.name, toSnapshotName))
+
+	receiver := exec.Command("btrfs", "receive", "--dump")
+	sender := exec.Command("btrfs", "send", "--no-data", "-q", "-p", fromPath, toPath)
+
+	// Configure the pipes.
+	var err error
+	receiver.Stdin, err = sender.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var dump bytes.Buffer
+	receiver.Stdout = &dump
+
+	var recvStderr bytes.Buffer
+	receiver.Stderr = &recvStderr
+
+	var sendStderr bytes.Buffer
+	sender.Stderr = &sendStderr
+
+	err = receiver.Start()
+	if err != nil {
+		return nil, fmt.Errorf("Failed starting BTRFS receive: %w", err)
+	}
+
+	err = sender.Start()
+	if err != nil {
+		_ = receiver.Process.Kill()
+		_ = receiver.Wait()
+		return nil, fmt.Errorf("Failed starting BTRFS send: %w", err)
+	}
+
+	err = sender.Wait()
+	if err != nil {
+		_ = receiver.Process.Kill()
+		_ = receiver.Wait()
+		return nil, fmt.Errorf("Failed BTRFS send: %w (%s)", err, strings.TrimSpace(sendStderr.String()))
+	}
+
+	err = receiver.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("Failed BTRFS receive: %w (%s)", err, strings.TrimSpace(recvStderr.String()))
+	}
+
+	diff, err := btrfsParseDiffDump(dump.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sortVolumeDiff(diff)
+
+	return diff, nil
+}
+
 // RestoreVolume restores a volume from a snapshot.
 func (d *btrfs) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
 	reverter := revert.New()
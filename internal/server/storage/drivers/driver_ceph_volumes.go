@@ -1471,7 +1471,7 @@ func (d *ceph) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *lo
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
 }
 
@@ -1835,6 +1835,11 @@ func (d *ceph) RestoreVolume(vol Volume, snapshotName string, op *operations.Ope
 	return nil
 }
 
+// DiffVolume lists the paths that differ between two states of a volume.
+func (d *ceph) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	return genericVFSDiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+}
+
 // RenameVolumeSnapshot renames a volume snapshot.
 func (d *ceph) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
 	reverter := revert.New()
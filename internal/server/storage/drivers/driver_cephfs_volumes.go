@@ -482,7 +482,7 @@ func (d *cephfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *cephfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *cephfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
 }
 
@@ -589,6 +589,11 @@ func (d *cephfs) RestoreVolume(vol Volume, snapshotName string, op *operations.O
 	return nil
 }
 
+// DiffVolume lists the paths that differ between two states of a volume.
+func (d *cephfs) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	return genericVFSDiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+}
+
 // RenameVolumeSnapshot renames a snapshot.
 func (d *cephfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
 	parentName, snapName, _ := api.GetParentAndSnapshotName(snapVol.name)
@@ -17,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
@@ -432,7 +433,7 @@ func (d *common) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
@@ -466,6 +467,11 @@ func (d *common) RestoreVolume(vol Volume, snapshotName string, op *operations.O
 	return ErrNotSupported
 }
 
+// DiffVolume lists the paths that differ between two states of a volume.
+func (d *common) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	return nil, ErrNotSupported
+}
+
 // RenameVolumeSnapshot renames a snapshot.
 func (d *common) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
 	return ErrNotSupported
@@ -834,6 +834,11 @@ func (d *linstor) RestoreVolume(vol Volume, snapshotName string, op *operations.
 	return nil
 }
 
+// DiffVolume lists the paths that differ between two states of a volume.
+func (d *linstor) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	return genericVFSDiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+}
+
 // RenameVolumeSnapshot is a no-op.
 func (d *linstor) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
 	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
@@ -1225,7 +1230,7 @@ func (d *linstor) VolumeSnapshots(vol Volume, op *operations.Operation) ([]strin
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *linstor) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *linstor) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
 }
 
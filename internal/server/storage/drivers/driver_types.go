@@ -29,3 +29,10 @@ type VolumeFiller struct {
 
 	Fingerprint string // If the Filler will unpack an image, it should be this fingerprint.
 }
+
+// Types of changes reported when comparing two states of a volume.
+const (
+	VolumeDiffAdded    = "added"
+	VolumeDiffRemoved  = "removed"
+	VolumeDiffModified = "modified"
+)
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -15,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
@@ -208,6 +210,84 @@ func (d *zfs) setDatasetProperties(dataset string, options ...string) error {
 	return nil
 }
 
+// zfsCloneProperties returns the clone options needed to carry over the locally set properties of a dataset.
+// It takes the output of "zfs get -H -p -s local -o property,value all".
+func zfsCloneProperties(output string) []string {
+	// Properties which can only be set when creating a dataset and are inherited by clones.
+	createOnly := []string{"volsize", "volblocksize", "encryption", "keyformat", "keylocation", "pbkdf2iters"}
+
+	options := []string{}
+	for _, row := range strings.Split(output, "\n") {
+		prop := strings.SplitN(row, "\t", 2)
+		if len(prop) < 2 || slices.Contains(createOnly, prop[0]) {
+			continue
+		}
+
+		options = append(options, "-o", fmt.Sprintf("%s=%s", prop[0], prop[1]))
+	}
+
+	return options
+}
+
+// setAsideDataset renames a dataset to aside and replaces it with a promoted clone of one of its snapshots.
+// The clone takes over that snapshot, all older ones and the locally set properties, leaving only what was
+// written since the snapshot to the dataset which was moved aside.
+func (d *zfs) setAsideDataset(dataset string, aside string, snapshot string) error {
+	output, err := subprocess.RunCommand("zfs", "get", "-H", "-p", "-s", "local", "-o", "property,value", "all", dataset)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"clone"}, zfsCloneProperties(output)...)
+	args = append(args, fmt.Sprintf("%s@%s", aside, snapshot), dataset)
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	_, err = subprocess.RunCommand("zfs", "rename", dataset, aside)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _, _ = subprocess.RunCommand("zfs", "rename", aside, dataset) })
+
+	_, err = subprocess.RunCommand("zfs", args...)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _, _ = subprocess.RunCommand("zfs", "destroy", dataset) })
+
+	_, err = subprocess.RunCommand("zfs", "promote", dataset)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// restoreAsideDataset puts back a dataset moved aside by setAsideDataset, destroying its replacement.
+func (d *zfs) restoreAsideDataset(dataset string, aside string) error {
+	// Hand the older snapshots back to the original dataset.
+	_, err := subprocess.RunCommand("zfs", "promote", aside)
+	if err != nil {
+		return err
+	}
+
+	_, err = subprocess.TryRunCommand("zfs", "destroy", "-r", dataset)
+	if err != nil {
+		return err
+	}
+
+	_, err = subprocess.RunCommand("zfs", "rename", aside, dataset)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (d *zfs) setBlocksizeFromConfig(vol Volume) error {
 	size := vol.ExpandedConfig("zfs.blocksize")
 	if size == "" {
@@ -472,3 +552,68 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// zfsParseDiff converts the output of `zfs diff -H` into volume diff entries relative to mountPath.
+func zfsParseDiff(out string, mountPath string) ([]api.StorageVolumeSnapshotDiff, error) {
+	diff := []api.StorageVolumeSnapshotDiff{}
+
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Unexpected zfs diff output %q", line)
+		}
+
+		path := zfsDiffPath(fields[1], mountPath)
+
+		switch fields[0] {
+		case "+":
+			diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffAdded})
+		case "-":
+			diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffRemoved})
+		case "M":
+			diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffModified})
+		case "R":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("Unexpected zfs diff output %q", line)
+			}
+
+			// Report renames as the removal of the old path and the addition of the new one.
+			diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffRemoved})
+			diff = append(diff, api.StorageVolumeSnapshotDiff{Path: zfsDiffPath(fields[2], mountPath), Type: VolumeDiffAdded})
+		default:
+			return nil, fmt.Errorf("Unknown zfs diff change type %q", fields[0])
+		}
+	}
+
+	return diff, nil
+}
+
+// zfsDiffPath unescapes a path reported by zfs diff and makes it relative to mountPath.
+// zfs diff escapes spaces, backslashes and non-printable characters as a backslash followed by four octal digits.
+func zfsDiffPath(path string, mountPath string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 < len(path) {
+			value, err := strconv.ParseUint(path[i+1:i+5], 8, 8)
+			if err == nil {
+				sb.WriteByte(byte(value))
+				i += 4
+				continue
+			}
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	relPath, err := filepath.Rel(mountPath, sb.String())
+	if err != nil {
+		return volumeDiffPath(sb.String())
+	}
+
+	return volumeDiffPath(relPath)
+}
@@ -0,0 +1,48 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Test zfsParseDiff.
+func TestZfsParseDiff(t *testing.T) {
+	mountPath := "/var/lib/incus/storage-pools/default/containers/c1"
+
+	out := `M	/var/lib/incus/storage-pools/default/containers/c1/rootfs/etc
++	/var/lib/incus/storage-pools/default/containers/c1/rootfs/etc/new\0040file
+-	/var/lib/incus/storage-pools/default/containers/c1/rootfs/etc/old
+R	/var/lib/incus/storage-pools/default/containers/c1/rootfs/a	/var/lib/incus/storage-pools/default/containers/c1/rootfs/b
+`
+
+	diff, err := zfsParseDiff(out, mountPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []api.StorageVolumeSnapshotDiff{
+		{Path: "/rootfs/etc", Type: VolumeDiffModified},
+		{Path: "/rootfs/etc/new file", Type: VolumeDiffAdded},
+		{Path: "/rootfs/etc/old", Type: VolumeDiffRemoved},
+		{Path: "/rootfs/a", Type: VolumeDiffRemoved},
+		{Path: "/rootfs/b", Type: VolumeDiffAdded},
+	}, diff)
+
+	// Test unknown change types.
+	_, err = zfsParseDiff("X\t/foo\n", mountPath)
+	assert.Error(t, err)
+}
+
+// Test zfsCloneProperties.
+func TestZfsCloneProperties(t *testing.T) {
+	out := "mountpoint\tlegacy\nvolsize\t10737418240\nrefquota\t10737418240\nincus:content_type\tblock\nvolblocksize\t16384\ncanmount\tnoauto\n"
+
+	assert.Equal(t, []string{
+		"-o", "mountpoint=legacy",
+		"-o", "refquota=10737418240",
+		"-o", "incus:content_type=block",
+		"-o", "canmount=noauto",
+	}, zfsCloneProperties(out))
+
+	assert.Equal(t, []string{}, zfsCloneProperties(""))
+}
@@ -371,7 +371,17 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are applied on top of the existing volume which must have the base snapshot.
+	if srcBackup.IncrementalFrom != "" {
+		if !volExists {
+			return nil, nil, fmt.Errorf("Cannot apply incremental backup, volume doesn't exist on target")
+		}
+
+		err = vol.SnapshotsExist([]string{srcBackup.IncrementalFrom}, op)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Cannot apply incremental backup: %w", err)
+		}
+	} else if volExists {
 		return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
 	}
 
@@ -387,8 +397,10 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume (unless it existed before).
+		if srcBackup.IncrementalFrom == "" {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 
 	// Only execute the revert function if we have had an error internally.
@@ -446,7 +458,31 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 
 	vols = append(vols, vol)
 
+	// Datasets moved aside while applying an incremental backup, indexed by their original name.
+	asideDatasets := map[string]string{}
+
 	for _, v := range vols {
+		// Receiving an incremental stream with "-F" discards anything written since the base snapshot.
+		// So keep the existing dataset aside and receive into a clone of its base snapshot instead,
+		// allowing the volume to be put back as it was until the backup has been fully applied.
+		if srcBackup.IncrementalFrom != "" {
+			_, err = d.UnmountVolume(v, false, op)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			dataset := d.dataset(v, false)
+			aside := d.dataset(v, true)
+
+			err = d.setAsideDataset(dataset, aside, fmt.Sprintf("snapshot-%s", srcBackup.IncrementalFrom))
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed setting aside dataset %q: %w", dataset, err)
+			}
+
+			asideDatasets[dataset] = aside
+			reverter.Add(func() { _ = d.restoreAsideDataset(dataset, aside) })
+		}
+
 		// Find the compression algorithm used for backup source data.
 		_, err := srcData.Seek(0, io.SeekStart)
 		if err != nil {
@@ -563,6 +599,28 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 		}
 	}
 
+	// Only discard the datasets moved aside once the caller is done with the restored volume.
+	if len(asideDatasets) > 0 {
+		mountPostHook := postHook
+		postHook = func(postVol Volume) error {
+			if mountPostHook != nil {
+				err := mountPostHook(postVol)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, aside := range asideDatasets {
+				err := d.deleteDatasetRecursive(aside)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
 	cleanup := reverter.Clone().Fail // Clone before calling reverter.Success() so we can return the Fail func.
 	reverter.Success()
 	return postHook, cleanup, nil
@@ -2782,7 +2840,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, incrementalFrom string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -2806,7 +2864,13 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	if incrementalFrom != "" {
+		// Check requested snapshots and their incremental base exist in storage.
+		err := vol.SnapshotsExist(append([]string{incrementalFrom}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -2817,7 +2881,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, op)
+		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, incrementalFrom, op)
 		if err != nil {
 			return err
 		}
@@ -2878,18 +2942,17 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 
 	// Handle snapshots.
 	finalParent := ""
+	if incrementalFrom != "" {
+		// Incremental backups only include the changes since the base snapshot.
+		baseSnapshot, _ := vol.NewSnapshot(incrementalFrom)
+		finalParent = d.dataset(baseSnapshot, false)
+	}
+
 	if len(snapshots) > 0 {
-		for i, snapName := range snapshots {
+		for _, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
 
-			// Figure out parent and current subvolumes.
-			parent := ""
-			if i > 0 {
-				oldSnapshot, _ := vol.NewSnapshot(snapshots[i-1])
-				parent = d.dataset(oldSnapshot, false)
-			}
-
-			// Make a binary zfs backup.
+			// Make a binary zfs backup (differential from the previous snapshot if any).
 			prefix := "snapshots"
 			fileName := fmt.Sprintf("%s.bin", snapName)
 			if vol.volType == VolumeTypeVM {
@@ -2902,7 +2965,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 			}
 
 			target := fmt.Sprintf("backup/%s/%s", prefix, fileName)
-			err := sendToFile(d.dataset(snapshot, false), parent, target)
+			err := sendToFile(d.dataset(snapshot, false), finalParent, target)
 			if err != nil {
 				return err
 			}
@@ -2911,9 +2974,15 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		}
 	}
 
-	// Create a temporary read-only snapshot.
+	// Create a temporary read-only snapshot, with the data of the running instance frozen only while it's taken.
+	thaw, err := vol.quiesce(quiesceSnapshotTimeout)
+	if err != nil {
+		return err
+	}
+
 	srcSnapshot := fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), uuid.New().String())
-	_, err := subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
+	_, err = subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
+	thawErr := thaw()
 	if err != nil {
 		return err
 	}
@@ -2926,6 +2995,10 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		}
 	}()
 
+	if thawErr != nil {
+		return thawErr
+	}
+
 	// Dump the container to a file.
 	fileName := "container.bin"
 	if vol.volType == VolumeTypeVM {
@@ -3373,6 +3446,40 @@ func (d *zfs) RestoreVolume(vol Volume, snapshotName string, op *operations.Oper
 	return d.restoreVolume(vol, snapshotName, false, op)
 }
 
+// DiffVolume lists the paths that differ between two states of a volume using zfs diff.
+func (d *zfs) DiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	// Volumes using a block device need their filesystems compared instead.
+	if vol.contentType != ContentTypeFS || d.isBlockBacked(vol) {
+		return genericVFSDiffVolume(vol, fromSnapshotName, toSnapshotName, op)
+	}
+
+	fromDataset := fmt.Sprintf("%s@snapshot-%s", d.dataset(vol, false), fromSnapshotName)
+	toDataset := d.dataset(vol, false)
+	if toSnapshotName != "" {
+		toDataset = fmt.Sprintf("%s@snapshot-%s", toDataset, toSnapshotName)
+	}
+
+	var diff []api.StorageVolumeSnapshotDiff
+
+	// The dataset needs to be mounted as zfs diff reports paths below its mount path.
+	err := vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		out, err := subprocess.RunCommand("zfs", "diff", "-H", fromDataset, toDataset)
+		if err != nil {
+			return err
+		}
+
+		diff, err = zfsParseDiff(out, mountPath)
+		return err
+	}, op)
+	if err != nil {
+		return nil, err
+	}
+
+	sortVolumeDiff(diff)
+
+	return diff, nil
+}
+
 func (d *zfs) restoreVolume(vol Volume, snapshotName string, migration bool, op *operations.Operation) error {
 	// Get the list of snapshots.
 	entries, err := d.getDatasets(d.dataset(vol, false), "snapshot")
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/lxc/incus/v6/internal/instancewriter"
@@ -489,6 +490,141 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 	return nil
 }
 
+// genericVFSDiffVolume is a generic DiffVolume implementation comparing the mounted file trees.
+// Regular files are considered modified when their size, modification time, ownership or mode changed.
+func genericVFSDiffVolume(vol Volume, fromSnapshotName string, toSnapshotName string, op *operations.Operation) ([]api.StorageVolumeSnapshotDiff, error) {
+	if vol.contentType != ContentTypeFS {
+		return nil, ErrNotSupported
+	}
+
+	fromVol, err := vol.NewSnapshot(fromSnapshotName)
+	if err != nil {
+		return nil, err
+	}
+
+	toVol := vol
+	if toSnapshotName != "" {
+		toVol, err = vol.NewSnapshot(toSnapshotName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var diff []api.StorageVolumeSnapshotDiff
+
+	err = fromVol.MountTask(func(fromPath string, op *operations.Operation) error {
+		return toVol.MountTask(func(toPath string, op *operations.Operation) error {
+			fromFiles, err := genericVFSDiffScan(fromPath)
+			if err != nil {
+				return err
+			}
+
+			toFiles, err := genericVFSDiffScan(toPath)
+			if err != nil {
+				return err
+			}
+
+			for path, fromFile := range fromFiles {
+				toFile, found := toFiles[path]
+				if !found {
+					diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffRemoved})
+				} else if toFile != fromFile {
+					diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffModified})
+				}
+			}
+
+			for path := range toFiles {
+				_, found := fromFiles[path]
+				if !found {
+					diff = append(diff, api.StorageVolumeSnapshotDiff{Path: path, Type: VolumeDiffAdded})
+				}
+			}
+
+			return nil
+		}, op)
+	}, op)
+	if err != nil {
+		return nil, err
+	}
+
+	sortVolumeDiff(diff)
+
+	return diff, nil
+}
+
+// genericVFSDiffFile holds the attributes of a file used to detect changes.
+type genericVFSDiffFile struct {
+	mode  fs.FileMode
+	uid   uint32
+	gid   uint32
+	size  int64
+	mtime int64
+	link  string
+}
+
+// genericVFSDiffScan returns the attributes of all files below rootPath, keyed by their path relative to it.
+func genericVFSDiffScan(rootPath string) (map[string]genericVFSDiffFile, error) {
+	files := map[string]genericVFSDiffFile{}
+
+	err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		// Ignore files removed while walking a mounted volume.
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fi, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		file := genericVFSDiffFile{
+			mode:  fi.Mode(),
+			size:  fi.Size(),
+			mtime: fi.ModTime().UnixNano(),
+		}
+
+		stat, ok := fi.Sys().(*syscall.Stat_t)
+		if ok {
+			file.uid = stat.Uid
+			file.gid = stat.Gid
+		}
+
+		// Directory sizes depend on the filesystem rather than their content.
+		if fi.IsDir() {
+			file.size = 0
+		}
+
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			file.link, err = os.Readlink(path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+
+		files[volumeDiffPath(relPath)] = file
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed scanning %q: %w", rootPath, err)
+	}
+
+	return files, nil
+}
+
 // genericVFSHasVolume is a generic HasVolume implementation for VFS-only drivers.
 func genericVFSHasVolume(vol Volume) (bool, error) {
 	_, err := os.Lstat(vol.MountPath())
@@ -688,11 +824,26 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		prefix = "backup/volume"
 	}
 
+	// Unless it's read from a point-in-time copy, keep the data of the running instance frozen while it's read.
+	thaw := func() error { return nil }
+	if vol.mountCustomPath == "" {
+		var err error
+		thaw, err = vol.quiesce(quiesceCopyTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := backupVolume(vol, prefix)
+	thawErr := thaw()
 	if err != nil {
 		return err
 	}
 
+	if thawErr != nil {
+		return thawErr
+	}
+
 	return nil
 }
 
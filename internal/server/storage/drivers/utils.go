@@ -1011,3 +1011,20 @@ func roundAbove(above, val int64) int64 {
 
 	return rounded
 }
+
+// volumeDiffPath returns the path relative to the root of a volume in the form used in volume diffs.
+func volumeDiffPath(relPath string) string {
+	relPath = filepath.Clean(relPath)
+	if relPath == "." {
+		return "/"
+	}
+
+	return "/" + strings.TrimPrefix(relPath, "/")
+}
+
+// sortVolumeDiff sorts volume diff entries by path.
+func sortVolumeDiff(diff []api.StorageVolumeSnapshotDiff) {
+	slices.SortFunc(diff, func(a api.StorageVolumeSnapshotDiff, b api.StorageVolumeSnapshotDiff) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/locking"
@@ -37,6 +38,13 @@ const defaultFilesystemMountOptions = "discard"
 // volIDQuotaSkip is used to indicate to drivers that quotas should not be setup, used during backup import.
 const volIDQuotaSkip = int64(-1)
 
+// quiesceSnapshotTimeout is how long the data of a running instance may remain frozen while a point-in-time copy
+// of its volume is taken.
+const quiesceSnapshotTimeout = time.Minute
+
+// quiesceCopyTimeout is how long the data of a running instance may remain frozen while its volume is read directly.
+const quiesceCopyTimeout = time.Hour
+
 // VolumeType represents a storage volume type.
 type VolumeType string
 
@@ -107,6 +115,9 @@ type Volume struct {
 	mountFilesystemProbe bool   // Probe filesystem type when mounting volume (when needed).
 	hasSource            bool   // Whether the volume is created from a source volume.
 	isDeleted            bool   // Whether we're dealing with a hidden volume (kept until all references are gone).
+
+	// Flushes and freezes the data of the running instance using the volume, returns a function thawing it.
+	quiesceHook func(timeout time.Duration) (func() error, error)
 }
 
 // NewVolume instantiates a new Volume struct.
@@ -421,6 +432,27 @@ func (v Volume) SnapshotsMatch(snapNames []string, op *operations.Operation) err
 	return nil
 }
 
+// SnapshotsExist checks that the snapshots provided all exist according to the storage driver (other snapshots
+// may exist too).
+func (v Volume) SnapshotsExist(snapNames []string, op *operations.Operation) error {
+	if v.IsSnapshot() {
+		return fmt.Errorf("Volume is a snapshot")
+	}
+
+	snapshots, err := v.driver.VolumeSnapshots(v, op)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapNames {
+		if !slices.Contains(snapshots, snapName) {
+			return fmt.Errorf("Snapshot %q expected but not in storage", snapName)
+		}
+	}
+
+	return nil
+}
+
 // IsBlockBacked indicates whether storage device is block backed.
 func (v Volume) IsBlockBacked() bool {
 	return v.driver.isBlockBacked(v) || v.mountFilesystemProbe
@@ -613,6 +645,22 @@ func (v *Volume) SetHasSource(hasSource bool) {
 	v.hasSource = hasSource
 }
 
+// SetQuiesceHook sets the function used to flush and freeze the data of the running instance using the volume
+// while a consistent copy of it is taken. The function it returns thaws the data and fails if it was thawed early.
+func (v *Volume) SetQuiesceHook(hook func(timeout time.Duration) (func() error, error)) {
+	v.quiesceHook = hook
+}
+
+// quiesce flushes and freezes the data of the running instance using the volume, if a quiesce hook is set.
+// The returned function thaws it and must always be called.
+func (v Volume) quiesce(timeout time.Duration) (func() error, error) {
+	if v.quiesceHook == nil {
+		return func() error { return nil }, nil
+	}
+
+	return v.quiesceHook(timeout)
+}
+
 // Clone returns a copy of the volume.
 func (v Volume) Clone() Volume {
 	// Copy the config map to avoid internal modifications affecting external state.
@@ -0,0 +1,183 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/lxc/incus/v6/internal/server/backup"
+)
+
+// backupPartSize is the size of the parts used when streaming backups of unknown size.
+const backupPartSize = 64 * 1024 * 1024
+
+// BackupTarget stores backup tarballs in a bucket on an S3 compatible endpoint.
+type BackupTarget struct {
+	endpoint   string
+	bucketName string
+	accessKey  string
+	secretKey  string
+	prefix     string
+}
+
+// NewBackupTarget instantiates a new BackupTarget struct.
+// The configuration is only checked once the target is used so that existing backups can still be
+// loaded while the target is misconfigured.
+func NewBackupTarget(endpoint string, bucketName string, accessKey string, secretKey string, prefix string) *BackupTarget {
+	return &BackupTarget{
+		endpoint:   endpoint,
+		bucketName: bucketName,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		prefix:     strings.Trim(prefix, "/"),
+	}
+}
+
+// Validate checks that the target is fully configured.
+func (t *BackupTarget) Validate() error {
+	_, err := t.getMinioClient()
+	return err
+}
+
+// Name returns the name of the target.
+func (t *BackupTarget) Name() string {
+	return backup.TargetS3
+}
+
+// Create returns a writer streaming a new backup tarball to the bucket.
+// The object is only complete once the writer has been closed.
+func (t *BackupTarget) Create(backupPath string) (io.WriteCloser, error) {
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	w := &backupTargetWriter{
+		pipeWriter: pipeWriter,
+		uploadRes:  make(chan error, 1),
+	}
+
+	go func() {
+		_, err := minioClient.PutObject(context.TODO(), t.bucketName, t.key(backupPath), pipeReader, -1, minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+			PartSize:    backupPartSize,
+		})
+
+		// Unblock any pending writes if the upload failed.
+		_ = pipeReader.CloseWithError(err)
+		w.uploadRes <- err
+	}()
+
+	return w, nil
+}
+
+// Open returns a reader for an existing backup tarball along with its size.
+func (t *BackupTarget) Open(backupPath string) (io.ReadSeekCloser, int64, error) {
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return nil, -1, err
+	}
+
+	object, err := minioClient.GetObject(context.TODO(), t.bucketName, t.key(backupPath), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, -1, err
+	}
+
+	objectInfo, err := object.Stat()
+	if err != nil {
+		_ = object.Close()
+		return nil, -1, fmt.Errorf("Failed getting backup object %q: %w", t.key(backupPath), err)
+	}
+
+	return object, objectInfo.Size, nil
+}
+
+// Rename moves an existing backup tarball to a new path.
+func (t *BackupTarget) Rename(oldPath string, newPath string) error {
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return err
+	}
+
+	// S3 has no rename, so copy the object and remove the original.
+	src := minio.CopySrcOptions{Bucket: t.bucketName, Object: t.key(oldPath)}
+	dst := minio.CopyDestOptions{Bucket: t.bucketName, Object: t.key(newPath)}
+
+	_, err = minioClient.ComposeObject(context.TODO(), dst, src)
+	if err != nil {
+		return fmt.Errorf("Failed copying backup object %q to %q: %w", src.Object, dst.Object, err)
+	}
+
+	return t.Delete(oldPath)
+}
+
+// Delete removes a backup tarball from the bucket.
+func (t *BackupTarget) Delete(backupPath string) error {
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return err
+	}
+
+	// Removing a missing object isn't an error in S3.
+	err = minioClient.RemoveObject(context.TODO(), t.bucketName, t.key(backupPath), minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed deleting backup object %q: %w", t.key(backupPath), err)
+	}
+
+	return nil
+}
+
+// getMinioClient returns a client for the configured endpoint.
+func (t *BackupTarget) getMinioClient() (*minio.Client, error) {
+	if t.endpoint == "" || t.bucketName == "" {
+		return nil, errors.New("The S3 backup target requires both backups.s3.endpoint and backups.s3.bucket to be set")
+	}
+
+	s3URL, err := url.Parse(t.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing S3 endpoint %q: %w", t.endpoint, err)
+	}
+
+	return NewTransferManager(s3URL, t.accessKey, t.secretKey).getMinioClient()
+}
+
+// key returns the object key for the given backup path.
+func (t *BackupTarget) key(backupPath string) string {
+	if t.prefix == "" {
+		return backupPath
+	}
+
+	return path.Join(t.prefix, backupPath)
+}
+
+// backupTargetWriter feeds an in-progress upload and waits for it to complete on close.
+type backupTargetWriter struct {
+	pipeWriter *io.PipeWriter
+	uploadRes  chan error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Write writes data to the upload.
+func (w *backupTargetWriter) Write(p []byte) (int, error) {
+	return w.pipeWriter.Write(p)
+}
+
+// Close ends the upload and returns its result.
+func (w *backupTargetWriter) Close() error {
+	w.closeOnce.Do(func() {
+		_ = w.pipeWriter.Close()
+		w.closeErr = <-w.uploadRes
+	})
+
+	return w.closeErr
+}
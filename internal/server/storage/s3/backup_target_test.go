@@ -0,0 +1,253 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectStore implements the subset of the S3 API used by the backup target.
+type fakeObjectStore struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	uploads  map[string]map[int][]byte
+	uploadID int
+	failPart bool
+}
+
+func newFakeObjectStore(t *testing.T) (*fakeObjectStore, string) {
+	store := &fakeObjectStore{
+		objects: map[string][]byte{},
+		uploads: map[string]map[int][]byte{},
+	}
+
+	server := httptest.NewTLSServer(store)
+	t.Cleanup(server.Close)
+
+	return store, server.URL
+}
+
+func (s *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case query.Has("location"):
+		s.writeXML(w, struct {
+			XMLName xml.Name `xml:"LocationConstraint"`
+		}{})
+
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.uploadID++
+		uploadID := fmt.Sprintf("upload%d", s.uploadID)
+		s.uploads[uploadID] = map[int][]byte{}
+
+		s.writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			UploadID string   `xml:"UploadId"`
+		}{UploadID: uploadID})
+
+	case r.Method == http.MethodPut && query.Has("uploadId") && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+
+		data, ok := s.objects[strings.TrimPrefix(source, "/")]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		// Objects are copied as parts of a multipart upload.
+		var partNumber, start, end int
+		_, _ = fmt.Sscanf(query.Get("partNumber"), "%d", &partNumber)
+		_, _ = fmt.Sscanf(r.Header.Get("X-Amz-Copy-Source-Range"), "bytes=%d-%d", &start, &end)
+
+		s.uploads[query.Get("uploadId")][partNumber] = bytes.Clone(data[start : end+1])
+		s.writeXML(w, struct {
+			XMLName      xml.Name `xml:"CopyPartResult"`
+			ETag         string   `xml:"ETag"`
+			LastModified string   `xml:"LastModified"`
+		}{ETag: fmt.Sprintf(`"part%d"`, partNumber), LastModified: time.Now().UTC().Format(time.RFC3339)})
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		if s.failPart {
+			s.writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+
+		var partNumber int
+		_, _ = fmt.Sscanf(query.Get("partNumber"), "%d", &partNumber)
+
+		data, _ := io.ReadAll(r.Body)
+		s.uploads[query.Get("uploadId")][partNumber] = data
+		w.Header().Set("ETag", fmt.Sprintf(`"part%d"`, partNumber))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		bucket, objectKey, _ := strings.Cut(key, "/")
+		parts := s.uploads[query.Get("uploadId")]
+		delete(s.uploads, query.Get("uploadId"))
+
+		data := []byte{}
+		for i := 1; i <= len(parts); i++ {
+			data = append(data, parts[i]...)
+		}
+
+		s.objects[key] = data
+
+		s.writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string   `xml:"Bucket"`
+			Key     string   `xml:"Key"`
+			ETag    string   `xml:"ETag"`
+		}{Bucket: bucket, Key: objectKey, ETag: `"object"`})
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		w.Header().Set("ETag", `"object"`)
+		http.ServeContent(w, r, key, time.Now(), bytes.NewReader(data))
+
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		s.writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *fakeObjectStore) writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func (s *fakeObjectStore) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+	}{Code: code})
+}
+
+func (s *fakeObjectStore) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func writeBackup(t *testing.T, target *BackupTarget, backupPath string, content string) {
+	w, err := target.Create(backupPath)
+	require.NoError(t, err)
+
+	_, err = io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func readBackup(t *testing.T, target *BackupTarget, backupPath string) string {
+	r, size, err := target.Open(backupPath)
+	require.NoError(t, err)
+
+	defer func() { _ = r.Close() }()
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+
+	return string(data)
+}
+
+func TestBackupTarget(t *testing.T) {
+	store, endpoint := newFakeObjectStore(t)
+	target := NewBackupTarget(endpoint, "bucket", "access", "secret", "/incus/")
+
+	require.NoError(t, target.Validate())
+	assert.Equal(t, "s3", target.Name())
+
+	// Backups are stored under the prefix.
+	writeBackup(t, target, "custom/default/default_vol1/backup0", "volume data")
+	assert.Equal(t, []string{"bucket/incus/custom/default/default_vol1/backup0"}, store.keys())
+	assert.Equal(t, "volume data", readBackup(t, target, "custom/default/default_vol1/backup0"))
+
+	// Renaming copies the object and removes the original.
+	require.NoError(t, target.Rename("custom/default/default_vol1/backup0", "custom/default/default_vol2/backup0"))
+	assert.Equal(t, []string{"bucket/incus/custom/default/default_vol2/backup0"}, store.keys())
+	assert.Equal(t, "volume data", readBackup(t, target, "custom/default/default_vol2/backup0"))
+
+	_, _, err := target.Open("custom/default/default_vol1/backup0")
+	assert.Error(t, err)
+
+	require.NoError(t, target.Delete("custom/default/default_vol2/backup0"))
+	assert.Empty(t, store.keys())
+
+	// Deleting a missing backup isn't an error.
+	require.NoError(t, target.Delete("custom/default/default_vol2/backup0"))
+}
+
+func TestBackupTargetNoPrefix(t *testing.T) {
+	store, endpoint := newFakeObjectStore(t)
+	target := NewBackupTarget(endpoint, "bucket", "access", "secret", "")
+
+	writeBackup(t, target, "instances/c1/backup0", "instance data")
+	assert.Equal(t, []string{"bucket/instances/c1/backup0"}, store.keys())
+}
+
+func TestBackupTargetFailedUpload(t *testing.T) {
+	store, endpoint := newFakeObjectStore(t)
+	store.failPart = true
+	target := NewBackupTarget(endpoint, "bucket", "access", "secret", "")
+
+	w, err := target.Create("instances/c1/backup0")
+	require.NoError(t, err)
+
+	// The upload error is reported when closing the writer.
+	_, _ = io.WriteString(w, "instance data")
+	assert.Error(t, w.Close())
+	assert.Empty(t, store.keys())
+}
+
+func TestBackupTargetMissingConfig(t *testing.T) {
+	for _, target := range []*BackupTarget{
+		NewBackupTarget("", "bucket", "access", "secret", ""),
+		NewBackupTarget("https://s3.example.net", "", "access", "secret", ""),
+	} {
+		assert.ErrorContains(t, target.Validate(), "requires both backups.s3.endpoint and backups.s3.bucket")
+
+		_, err := target.Create("instances/c1/backup0")
+		assert.Error(t, err)
+
+		_, _, err = target.Open("instances/c1/backup0")
+		assert.Error(t, err)
+
+		assert.Error(t, target.Rename("instances/c1/backup0", "instances/c1/backup1"))
+		assert.Error(t, target.Delete("instances/c1/backup0"))
+	}
+}
@@ -173,6 +173,11 @@ func (t TransferManager) getEndpoint() string {
 		hostname = fmt.Sprintf("[%s]", hostname)
 	}
 
+	// Let the client pick the default port for the scheme.
+	if t.s3URL.Port() == "" {
+		return hostname
+	}
+
 	return fmt.Sprintf("%s:%s", hostname, t.s3URL.Port())
 }
 
@@ -489,6 +489,14 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		},
 		"snapshots.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 		"snapshots.pattern":  validate.IsAny,
+		"backups.expiry": func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		},
+		"backups.schedule":  validate.Optional(validate.IsCron(internalInstance.BackupScheduleAliases)),
+		"backups.retention": validate.Optional(validate.IsUint32),
+		"backups.target":    validate.Optional(validate.IsOneOf("s3")),
 	}
 
 	// Options relevant for custom filesystem volumes.
@@ -511,11 +519,12 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 // validatePoolCommonRules returns a map of pool config rules common to all drivers.
 func validatePoolCommonRules() map[string]func(string) error {
 	rules := map[string]func(string) error{
-		"source":                  validate.IsAny,
-		"source.wipe":             validate.Optional(validate.IsBool),
-		"volatile.initial_source": validate.IsAny,
-		"rsync.bwlimit":           validate.Optional(validate.IsSize),
-		"rsync.compression":       validate.Optional(validate.IsBool),
+		"source":                    validate.IsAny,
+		"source.wipe":               validate.Optional(validate.IsBool),
+		"volatile.initial_source":   validate.IsAny,
+		"migration.bandwidth_limit": validate.Optional(validate.IsBitrate),
+		"rsync.bwlimit":             validate.Optional(validate.IsSize),
+		"rsync.compression":         validate.Optional(validate.IsBool),
 	}
 
 	// Add to pool config rules (prefixed with volume.*) which are common for pool and volume.
@@ -480,6 +480,30 @@ var APIExtensions = []string{
 	"server_logging",
 	"network_forward_snat",
 	"memory_hotplug",
+	"backup_schedule",
+	"backup_incremental",
+	"backup_s3",
+	"storage_volume_snapshot_diff",
+	"metrics_push",
+	"logging_otlp_webhook",
+	"project_usage_accounting",
+	"network_wireguard",
+	"network_bridge_vxlan",
+	"images_download_limits",
+	"network_zones_dns_queries",
+	"network_zones_dnssec",
+	"network_zones_push",
+	"instance_snapshots_quiesce",
+	"simplestreams_signing",
+	"network_bgp_import",
+	"instance_placement_groups",
+	"instance_boot_dependencies",
+	"auth_builtin",
+	"audit_log",
+	"operations_durable",
+	"migration_stream_compression",
+	"instances_vm_crash_handling",
+	"backup_s3_volumes",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
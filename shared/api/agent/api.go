@@ -18,3 +18,12 @@ type API10Put struct {
 	// Example: true
 	DevIncus bool `json:"dev_incus" yaml:"dev_incus"`
 }
+
+// FreezePost contains the fields used to freeze the guest filesystems.
+//
+// API extension: instance_snapshots_quiesce.
+type FreezePost struct {
+	// Number of seconds after which the filesystems are automatically thawed
+	// Example: 60
+	Timeout int `json:"timeout" yaml:"timeout"`
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+)
+
+// AuditEntry represents an entry of the audit log.
+//
+// swagger:model
+//
+// API extension: audit_log.
+type AuditEntry struct {
+	// ID of the entry
+	// Example: 42
+	ID int64 `json:"id" yaml:"id"`
+
+	// When the request was handled
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	Date time.Time `json:"date" yaml:"date"`
+
+	// What cluster member handled the request
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// The identity which made the request
+	// Example: 2a8ab8ca6e2f8a5d84fbf8f3cc3ff3d3e7bf96d70a48e2d1c2e0f8dae4b0ef92
+	Username string `json:"username" yaml:"username"`
+
+	// The authentication protocol used by the identity
+	// Example: tls
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// The address the request came from
+	// Example: 10.0.0.1:34528
+	Address string `json:"address" yaml:"address"`
+
+	// The HTTP method of the request
+	// Example: POST
+	Method string `json:"method" yaml:"method"`
+
+	// The URL of the request
+	// Example: /1.0/instances?project=default
+	URL string `json:"url" yaml:"url"`
+
+	// The entity affected by the request
+	// Example: /1.0/instances/c1?project=default
+	Entity string `json:"entity" yaml:"entity"`
+
+	// The HTTP status code of the response
+	// Example: 202
+	StatusCode int `json:"status_code" yaml:"status_code"`
+
+	// The error returned by the request, if any
+	// Example: Instance not found
+	Error string `json:"error" yaml:"error"`
+
+	// The ID of the operation started by the request, if any
+	// Example: b0a2c1e4-8d0a-4a2b-9e8f-0ce2b7c3c3c5
+	OperationID string `json:"operation_id" yaml:"operation_id"`
+
+	// The final status of the operation started by the request, if any
+	// Example: Success
+	OperationStatus string `json:"operation_status" yaml:"operation_status"`
+}
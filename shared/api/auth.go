@@ -7,3 +7,137 @@ const (
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
 )
+
+// AuthGroupsPost represents the fields of a new authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupsPost struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPost represents the fields required to rename an authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupPost struct {
+	// The new name of the group
+	// Example: admins
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPut represents the modifiable fields of an authorization group
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroupPut struct {
+	// Description of the group
+	// Example: Operators of the default project
+	Description string `json:"description" yaml:"description"`
+
+	// Permissions granted to the members of the group
+	Permissions []AuthPermission `json:"permissions" yaml:"permissions"`
+
+	// Groups (as found in the OIDC groups claim) whose members are part of the group
+	// Example: ["incus-operators"]
+	OIDCGroups []string `json:"oidc_groups" yaml:"oidc_groups"`
+}
+
+// AuthGroup represents an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthGroup struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+
+	// List of identities that are members of the group
+	// Read only: true
+	// Example: ["/1.0/auth/identities/oidc/jane@example.com"]
+	Identities []string `json:"identities" yaml:"identities"`
+}
+
+// Writable converts a full AuthGroup struct into an AuthGroupPut struct (filters read-only fields).
+func (g *AuthGroup) Writable() AuthGroupPut {
+	return g.AuthGroupPut
+}
+
+// AuthPermission represents an entitlement granted on an authorization object.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type AuthPermission struct {
+	// Entitlement granted on the object
+	// Example: can_exec
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+
+	// Authorization object (type:identifier) the entitlement applies to
+	// Example: instance:default/c1
+	Object string `json:"object" yaml:"object"`
+}
+
+// IdentitiesPost represents the fields of a new identity
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type IdentitiesPost struct {
+	IdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC username)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// IdentityPut represents the modifiable fields of an identity
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type IdentityPut struct {
+	// Human friendly name of the identity
+	// Example: Jane Doe
+	Name string `json:"name" yaml:"name"`
+
+	// Groups the identity is a member of
+	// Example: ["operators"]
+	Groups []string `json:"groups" yaml:"groups"`
+}
+
+// Identity represents an identity known to the authorization driver.
+//
+// swagger:model
+//
+// API extension: auth_builtin.
+type Identity struct {
+	IdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC username)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// Writable converts a full Identity struct into an IdentityPut struct (filters read-only fields).
+func (i *Identity) Writable() IdentityPut {
+	return i.IdentityPut
+}
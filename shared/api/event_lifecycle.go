@@ -2,6 +2,10 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthGroupCreated                  = "auth-group-created"
+	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
+	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -21,6 +25,9 @@ const (
 	EventLifecycleClusterMemberUpdated              = "cluster-member-updated"
 	EventLifecycleClusterTokenCreated               = "cluster-token-created"
 	EventLifecycleConfigUpdated                     = "config-updated"
+	EventLifecycleIdentityCreated                   = "identity-created"
+	EventLifecycleIdentityDeleted                   = "identity-deleted"
+	EventLifecycleIdentityUpdated                   = "identity-updated"
 	EventLifecycleImageAliasCreated                 = "image-alias-created"
 	EventLifecycleImageAliasDeleted                 = "image-alias-deleted"
 	EventLifecycleImageAliasRenamed                 = "image-alias-renamed"
@@ -38,6 +45,7 @@ const (
 	EventLifecycleInstanceConsole                   = "instance-console"
 	EventLifecycleInstanceConsoleReset              = "instance-console-reset"
 	EventLifecycleInstanceConsoleRetrieved          = "instance-console-retrieved"
+	EventLifecycleInstanceCrashed                   = "instance-crashed"
 	EventLifecycleInstanceCreated                   = "instance-created"
 	EventLifecycleInstanceDeleted                   = "instance-deleted"
 	EventLifecycleInstanceExec                      = "instance-exec"
@@ -235,6 +235,12 @@ type ImageSource struct {
 	//
 	// API extension: image_types
 	ImageType string `json:"image_type" yaml:"image_type"`
+
+	// ASCII armored OpenPGP public keys trusted to sign the index of the source server (simplestreams only)
+	// Example: OpenPGP public key block
+	//
+	// API extension: simplestreams_signing
+	TrustedKeys string `json:"trusted_keys,omitempty" yaml:"trusted_keys,omitempty"`
 }
 
 // ImageAliasesPost represents a new image alias
@@ -346,7 +346,7 @@ type InstanceSource struct {
 	// Example: {"criu": "RANDOM-STRING", "rsync": "RANDOM-STRING"}
 	Websockets map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
 
-	// Existing instance name or snapshot (for copy)
+	// Existing instance name or snapshot (for copy) or instance backup (for backup)
 	// Example: foo/snap0
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
@@ -368,7 +368,7 @@ type InstanceSource struct {
 	// API extension: custom_volume_refresh_exclude_older_snapshots
 	RefreshExcludeOlder bool `json:"refresh_exclude_older,omitempty" yaml:"refresh_exclude_older,omitempty"`
 
-	// Source project name (for copy and local image)
+	// Source project name (for copy, backup and local image)
 	// Example: blah
 	Project string `json:"project,omitempty" yaml:"project,omitempty"`
 
@@ -377,4 +377,10 @@ type InstanceSource struct {
 	//
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool `json:"allow_inconsistent" yaml:"allow_inconsistent"`
+
+	// ASCII armored OpenPGP public keys trusted to sign the index of the image server (for remote simplestreams images)
+	// Example: OpenPGP public key block
+	//
+	// API extension: simplestreams_signing
+	TrustedKeys string `json:"trusted_keys,omitempty" yaml:"trusted_keys,omitempty"`
 }
@@ -31,6 +31,18 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_compression_algorithm
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Name of the snapshot to use as the base of an incremental backup
+	// Example: snap0
+	//
+	// API extension: backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
+
+	// Where to store the backup (empty for local storage or "s3")
+	// Example: s3
+	//
+	// API extension: backup_s3
+	Target string `json:"target" yaml:"target"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -58,6 +70,12 @@ type InstanceBackup struct {
 	// Whether to use a pool-optimized binary format (instead of plain tarball)
 	// Example: true
 	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// Where the backup is stored (empty for local storage or "s3")
+	// Example: s3
+	//
+	// API extension: backup_s3
+	Target string `json:"target" yaml:"target"`
 }
 
 // InstanceBackupPost represents the fields available for the renaming of a instance backup.
@@ -25,6 +25,12 @@ type InstanceStatePut struct {
 	// Whether to store the runtime state (for stop)
 	// Example: false
 	Stateful bool `json:"stateful" yaml:"stateful"`
+
+	// Whether to start the instances listed in boot.depends_on first (for start)
+	// Example: false
+	//
+	// API extension: instance_boot_dependencies.
+	WithDependencies bool `json:"with_dependencies" yaml:"with_dependencies"`
 }
 
 // InstanceState represents an instance's state.
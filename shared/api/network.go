@@ -188,6 +188,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Routes learned from BGP peers
+	//
+	// API extension: network_bgp_import
+	BGP *NetworkStateBGP `json:"bgp" yaml:"bgp"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +352,32 @@ type NetworkStateOVN struct {
 	// API extension: network_ovn_state_addresses
 	UplinkIPv6 string `json:"uplink_ipv6" yaml:"uplink_ipv6"`
 }
+
+// NetworkStateBGP represents the routes learned from BGP peers
+//
+// swagger:model
+//
+// API extension: network_bgp_import.
+type NetworkStateBGP struct {
+	// List of imported routes
+	Routes []NetworkStateBGPRoute `json:"routes" yaml:"routes"`
+}
+
+// NetworkStateBGPRoute represents a route learned from a BGP peer
+//
+// swagger:model
+//
+// API extension: network_bgp_import.
+type NetworkStateBGPRoute struct {
+	// Route prefix
+	// Example: 10.0.0.0/8
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Next-hop address
+	// Example: 192.0.2.1
+	Nexthop string `json:"nexthop" yaml:"nexthop"`
+
+	// Address of the peer the route was received from
+	// Example: 192.0.2.1
+	Peer string `json:"peer" yaml:"peer"`
+}
@@ -50,6 +50,13 @@ type NetworkZone struct {
 	//
 	// API extension: network_zones_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// DS records to publish in the parent zone when DNSSEC is enabled
+	// Read only: true
+	// Example: ["example.net. 3600 IN DS 2371 13 2 1F987CC6583E92DF0890718C42D8C2B3CF1E09F1F2C9B6A9C4A2B5C2B0F1E3D7"]
+	//
+	// API extension: network_zones_dnssec
+	DSRecords []string `json:"ds_records" yaml:"ds_records"`
 }
 
 // Writable converts a full NetworkZone struct into a NetworkZonePut struct (filters read-only fields).
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ProjectDefaultName is the name of the default project that can never be deleted.
 const ProjectDefaultName = "default"
 
@@ -99,3 +103,72 @@ type ProjectStateResource struct {
 	// Example: 4
 	Usage int64
 }
+
+// ProjectResourceUsage represents the resources consumed over a period of time
+//
+// swagger:model
+//
+// API extension: project_usage_accounting.
+type ProjectResourceUsage struct {
+	// CPU time used, in seconds
+	// Example: 3600.5
+	CPUSeconds float64 `json:"cpu_seconds" yaml:"cpu_seconds"`
+
+	// Memory used over time, in GiB-hours
+	// Example: 12.25
+	MemoryGiBHours float64 `json:"memory_gib_hours" yaml:"memory_gib_hours"`
+
+	// Data read from disks, in bytes
+	// Example: 1073741824
+	DiskBytesRead int64 `json:"disk_bytes_read" yaml:"disk_bytes_read"`
+
+	// Data written to disks, in bytes
+	// Example: 536870912
+	DiskBytesWritten int64 `json:"disk_bytes_written" yaml:"disk_bytes_written"`
+
+	// Data received on network interfaces, in bytes
+	// Example: 250000000
+	NetworkBytesReceived int64 `json:"network_bytes_received" yaml:"network_bytes_received"`
+
+	// Data sent on network interfaces, in bytes
+	// Example: 125000000
+	NetworkBytesSent int64 `json:"network_bytes_sent" yaml:"network_bytes_sent"`
+}
+
+// ProjectInstanceUsage represents the resources consumed by an instance over a period of time
+//
+// swagger:model
+//
+// API extension: project_usage_accounting.
+type ProjectInstanceUsage struct {
+	ProjectResourceUsage `yaml:",inline"`
+
+	// Name of the instance
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Type of the instance
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+}
+
+// ProjectUsage represents the resources consumed by the instances of a project over a period of time
+//
+// swagger:model
+//
+// API extension: project_usage_accounting.
+type ProjectUsage struct {
+	// Start of the accounted period
+	// Example: 2024-01-01T00:00:00Z
+	Since time.Time `json:"since" yaml:"since"`
+
+	// End of the accounted period
+	// Example: 2024-02-01T00:00:00Z
+	Until time.Time `json:"until" yaml:"until"`
+
+	// Resources consumed by all instances of the project
+	Total ProjectResourceUsage `json:"total" yaml:"total"`
+
+	// Resources consumed by each instance of the project
+	Instances []ProjectInstanceUsage `json:"instances" yaml:"instances"`
+}
@@ -183,11 +183,11 @@ type StorageVolumePut struct {
 //
 // API extension: storage_api_local_volume_handling.
 type StorageVolumeSource struct {
-	// Source volume name (for copy)
+	// Source volume name (for copy) or volume backup (for backup)
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
 
-	// Source type (copy or migration)
+	// Source type (copy, migration or backup)
 	// Example: copy
 	Type string `json:"type" yaml:"type"`
 
@@ -29,6 +29,12 @@ type StorageVolumeBackup struct {
 	// Whether to use a pool-optimized binary format (instead of plain tarball)
 	// Example: true
 	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// Where the backup is stored (empty for local storage or "s3")
+	// Example: s3
+	//
+	// API extension: backup_s3_volumes
+	Target string `json:"target" yaml:"target"`
 }
 
 // StorageVolumeBackupsPost represents the fields available for a new volume backup
@@ -56,6 +62,12 @@ type StorageVolumeBackupsPost struct {
 	// What compression algorithm to use
 	// Example: gzip
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Where to store the backup (empty for local storage or "s3")
+	// Example: s3
+	//
+	// API extension: backup_s3_volumes
+	Target string `json:"target" yaml:"target"`
 }
 
 // StorageVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -92,3 +92,18 @@ type StorageVolumeSnapshotPut struct {
 func (storageVolumeSnapshot *StorageVolumeSnapshot) Writable() StorageVolumeSnapshotPut {
 	return storageVolumeSnapshot.StorageVolumeSnapshotPut
 }
+
+// StorageVolumeSnapshotDiff represents a path that differs between two states of a storage volume.
+//
+// swagger:model
+//
+// API extension: storage_volume_snapshot_diff.
+type StorageVolumeSnapshotDiff struct {
+	// Path relative to the root of the volume
+	// Example: /rootfs/etc/hostname
+	Path string `json:"path" yaml:"path"`
+
+	// Type of change (added, removed or modified)
+	// Example: modified
+	Type string `json:"type" yaml:"type"`
+}
@@ -78,6 +78,15 @@ func (c *Config) ServerCertPath(remote string) string {
 	return c.ConfigPath("servercerts", fmt.Sprintf("%s.crt", remote))
 }
 
+// TrustedKeysPath returns the path for the remote's trusted OpenPGP keys.
+func (c *Config) TrustedKeysPath(remote string) string {
+	if c.Remotes[remote].Global {
+		return c.GlobalConfigPath("trustedkeys", fmt.Sprintf("%s.asc", remote))
+	}
+
+	return c.ConfigPath("trustedkeys", fmt.Sprintf("%s.asc", remote))
+}
+
 // OIDCTokenPath returns the path for the remote's OIDC tokens.
 func (c *Config) OIDCTokenPath(remote string) string {
 	return c.ConfigPath("oidctokens", fmt.Sprintf("%s.json", remote))
@@ -291,6 +291,16 @@ func (c *Config) getConnectionArgs(name string) (*incus.ConnectionArgs, error) {
 		args.TLSServerCert = string(content)
 	}
 
+	// Trusted signing keys
+	if util.PathExists(c.TrustedKeysPath(name)) {
+		content, err := os.ReadFile(c.TrustedKeysPath(name))
+		if err != nil {
+			return nil, err
+		}
+
+		args.TrustedKeys = string(content)
+	}
+
 	// Stop here if no client certificate involved
 	if remote.Protocol != "incus" || slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) {
 		return &args, nil
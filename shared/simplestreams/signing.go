@@ -0,0 +1,105 @@
+package simplestreams
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+)
+
+// SignedPath returns the path of the signed variant of a JSON index file.
+func SignedPath(path string) string {
+	return strings.TrimSuffix(path, ".json") + ".sjson"
+}
+
+// ParseKeyring parses a list of ASCII armored OpenPGP keys.
+func ParseKeyring(armored string) (openpgp.EntityList, error) {
+	var keyring openpgp.EntityList
+
+	// Keys may be exported separately, so read all the armored blocks. The decoder reads from a
+	// shared buffer so that nothing past the end of a block gets lost.
+	r := bufio.NewReader(strings.NewReader(armored))
+	for {
+		block, err := armor.Decode(r)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed parsing OpenPGP keys: %w", err)
+		}
+
+		if block.Type != openpgp.PublicKeyType && block.Type != openpgp.PrivateKeyType {
+			return nil, fmt.Errorf("Unexpected OpenPGP block type %q", block.Type)
+		}
+
+		entities, err := openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing OpenPGP keys: %w", err)
+		}
+
+		keyring = append(keyring, entities...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, errors.New("No OpenPGP key found")
+	}
+
+	return keyring, nil
+}
+
+// Sign returns the content as a clearsigned document, signed with the first private key of the keyring.
+func Sign(content []byte, keyring openpgp.EntityList) ([]byte, error) {
+	var key *openpgp.Entity
+	for _, entity := range keyring {
+		if entity.PrivateKey != nil {
+			key = entity
+			break
+		}
+	}
+
+	if key == nil {
+		return nil, errors.New("No OpenPGP private key found")
+	}
+
+	if key.PrivateKey.Encrypted {
+		return nil, errors.New("Passphrase protected OpenPGP keys aren't supported")
+	}
+
+	buf := bytes.Buffer{}
+	w, err := clearsign.Encode(&buf, key.PrivateKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing: %w", err)
+	}
+
+	_, err = w.Write(content)
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing: %w", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Verify checks that a clearsigned document was signed by one of the keys of the keyring and returns its content.
+func Verify(signed []byte, keyring openpgp.EntityList) ([]byte, error) {
+	block, _ := clearsign.Decode(signed)
+	if block == nil {
+		return nil, errors.New("No signed content found")
+	}
+
+	_, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %w", err)
+	}
+
+	return block.Plaintext, nil
+}
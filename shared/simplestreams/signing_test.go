@@ -0,0 +1,101 @@
+package simplestreams
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testArmoredKey returns a new OpenPGP key, ASCII armored with or without its private part.
+func testArmoredKey(t *testing.T, private bool) string {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	require.NoError(t, err)
+
+	blockType := openpgp.PublicKeyType
+	if private {
+		blockType = openpgp.PrivateKeyType
+	}
+
+	buf := bytes.Buffer{}
+	w, err := armor.Encode(&buf, blockType, nil)
+	require.NoError(t, err)
+
+	if private {
+		err = entity.SerializePrivate(w, nil)
+	} else {
+		err = entity.Serialize(w)
+	}
+
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.String()
+}
+
+func TestParseKeyring(t *testing.T) {
+	keyring, err := ParseKeyring(testArmoredKey(t, true) + "\n" + testArmoredKey(t, false))
+	require.NoError(t, err)
+	require.Len(t, keyring, 2)
+	assert.NotNil(t, keyring[0].PrivateKey)
+	assert.Nil(t, keyring[1].PrivateKey)
+
+	_, err = ParseKeyring("")
+	assert.Error(t, err)
+
+	_, err = ParseKeyring("not a key")
+	assert.Error(t, err)
+}
+
+func TestSignVerify(t *testing.T) {
+	keyring, err := ParseKeyring(testArmoredKey(t, true))
+	require.NoError(t, err)
+
+	content := []byte(`{"format": "index:1.0", "index": {}}`)
+
+	signed, err := Sign(content, keyring)
+	require.NoError(t, err)
+
+	verified, err := Verify(signed, keyring)
+	require.NoError(t, err)
+	assert.Equal(t, content, verified)
+
+	// Modified content is rejected.
+	tampered := bytes.Replace(signed, []byte("index:1.0"), []byte("index:2.0"), 1)
+	_, err = Verify(tampered, keyring)
+	assert.ErrorContains(t, err, "Invalid signature")
+
+	// Unsigned content is rejected.
+	_, err = Verify(content, keyring)
+	assert.ErrorContains(t, err, "No signed content found")
+}
+
+func TestVerifyUntrustedKey(t *testing.T) {
+	keyring, err := ParseKeyring(testArmoredKey(t, true))
+	require.NoError(t, err)
+
+	otherKeyring, err := ParseKeyring(testArmoredKey(t, false))
+	require.NoError(t, err)
+
+	signed, err := Sign([]byte("content"), keyring)
+	require.NoError(t, err)
+
+	// Signatures from keys outside of the keyring are rejected.
+	_, err = Verify(signed, otherKeyring)
+	assert.ErrorContains(t, err, "Invalid signature")
+}
+
+func TestSignWithoutPrivateKey(t *testing.T) {
+	keyring, err := ParseKeyring(testArmoredKey(t, false))
+	require.NoError(t, err)
+
+	_, err = Sign([]byte("content"), keyring)
+	assert.ErrorContains(t, err, "No OpenPGP private key found")
+}
+
+func TestSignedPath(t *testing.T) {
+	assert.Equal(t, "streams/v1/index.sjson", SignedPath("streams/v1/index.json"))
+}
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/util"
@@ -56,6 +58,8 @@ type SimpleStreams struct {
 
 	cachePath   string
 	cacheExpiry time.Duration
+
+	keyring openpgp.EntityList
 }
 
 // SetCache configures the on-disk cache.
@@ -92,6 +96,19 @@ func (s *SimpleStreams) readCache(path string) ([]byte, bool) {
 	return body, expired
 }
 
+// SetTrustedKeys configures the ASCII armored OpenPGP keys used to verify the index files.
+// Once set, only signed index files are accepted.
+func (s *SimpleStreams) SetTrustedKeys(armored string) error {
+	keyring, err := ParseKeyring(armored)
+	if err != nil {
+		return err
+	}
+
+	s.keyring = keyring
+
+	return nil
+}
+
 // InvalidateCache removes the on-disk cache for the SimpleStreams remote.
 func (s *SimpleStreams) InvalidateCache() {
 	_ = os.RemoveAll(s.cachePath)
@@ -176,13 +193,33 @@ func (s *SimpleStreams) cachedDownload(path string) ([]byte, error) {
 	return body, nil
 }
 
+// downloadIndex retrieves an index file, using its signed variant when trusted keys are configured.
+func (s *SimpleStreams) downloadIndex(path string) ([]byte, error) {
+	if s.keyring == nil {
+		return s.cachedDownload(path)
+	}
+
+	signedPath := SignedPath(path)
+	body, err := s.cachedDownload(signedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := Verify(body, s.keyring)
+	if err != nil {
+		return nil, fmt.Errorf("Failed verifying %q: %w", signedPath, err)
+	}
+
+	return content, nil
+}
+
 func (s *SimpleStreams) parseStream() (*Stream, error) {
 	if s.cachedStream != nil {
 		return s.cachedStream, nil
 	}
 
 	path := "streams/v1/index.json"
-	body, err := s.cachedDownload(path)
+	body, err := s.downloadIndex(path)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +243,7 @@ func (s *SimpleStreams) parseProducts(path string) (*Products, error) {
 		return s.cachedProducts[path], nil
 	}
 
-	body, err := s.cachedDownload(path)
+	body, err := s.downloadIndex(path)
 	if err != nil {
 		return nil, err
 	}
@@ -290,6 +327,46 @@ func (s *SimpleStreams) applyAliases(images []api.Image) ([]api.Image, []extende
 	return newImages, aliasesList, nil
 }
 
+// GetProducts returns the image products of all the indices of the stream.
+func (s *SimpleStreams) GetProducts() (*Products, error) {
+	// Load the stream data
+	stream, err := s.parseStream()
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing stream: %w", err)
+	}
+
+	result := Products{
+		ContentID: "images",
+		DataType:  "image-downloads",
+		Format:    "products:1.0",
+		Products:  map[string]Product{},
+	}
+
+	// Iterate through the various indices
+	for _, entry := range stream.Index {
+		// We only care about images
+		if entry.DataType != "image-downloads" {
+			continue
+		}
+
+		// No point downloading an empty image list
+		if len(entry.Products) == 0 {
+			continue
+		}
+
+		products, err := s.parseProducts(entry.Path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing products: %w", err)
+		}
+
+		for name, product := range products.Products {
+			result.Products[name] = product
+		}
+	}
+
+	return &result, nil
+}
+
 func (s *SimpleStreams) getImages() ([]api.Image, []extendedAlias, error) {
 	if s.cachedImages != nil && s.cachedAliases != nil {
 		return s.cachedImages, s.cachedAliases, nil
@@ -17,11 +17,54 @@ import (
 // can not be found (404 HTTP status code).
 var ErrNotFound = errors.New("resource not found")
 
+// ErrHashMismatch is returned when the hash of a downloaded file doesn't match the expected one.
+var ErrHashMismatch = errors.New("Hash mismatch")
+
 // DownloadFileHash downloads a file while validating its hash.
 func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent string, progress func(progress ioprogress.ProgressData), canceler *cancel.HTTPRequestCanceller, filename string, url string, fileHash string, hashFunc hash.Hash, target io.WriteSeeker) (int64, error) {
 	// Always seek to the beginning
 	_, _ = target.Seek(0, io.SeekStart)
 
+	return downloadFile(ctx, httpClient, useragent, progress, canceler, filename, url, fileHash, hashFunc, target, 0)
+}
+
+// DownloadFileHashResume downloads a file while validating its hash, resuming from the data already present in target.
+// The existing data is hashed and only the rest of the file is requested, using an HTTP range request. If the server
+// doesn't support range requests, the whole file is downloaded again.
+// The returned size may be smaller than the size of target, in which case the caller should truncate it.
+func DownloadFileHashResume(ctx context.Context, httpClient *http.Client, useragent string, progress func(progress ioprogress.ProgressData), canceler *cancel.HTTPRequestCanceller, filename string, url string, fileHash string, hashFunc hash.Hash, target io.ReadWriteSeeker) (int64, error) {
+	// Get the size of the existing data.
+	offset, err := target.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = target.Seek(0, io.SeekStart)
+	if err != nil {
+		return -1, err
+	}
+
+	if offset == 0 {
+		return downloadFile(ctx, httpClient, useragent, progress, canceler, filename, url, fileHash, hashFunc, target, 0)
+	}
+
+	// Hash the existing data (which also moves to the end of it).
+	if hashFunc != nil {
+		_, err = io.CopyN(hashFunc, target, offset)
+	} else {
+		_, err = target.Seek(offset, io.SeekStart)
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	return downloadFile(ctx, httpClient, useragent, progress, canceler, filename, url, fileHash, hashFunc, target, offset)
+}
+
+// downloadFile downloads a file into target, starting at the given offset.
+// The data before the offset must already have been written to hashFunc and target must be positioned at the offset.
+func downloadFile(ctx context.Context, httpClient *http.Client, useragent string, progress func(progress ioprogress.ProgressData), canceler *cancel.HTTPRequestCanceller, filename string, url string, fileHash string, hashFunc hash.Hash, target io.WriteSeeker, offset int64) (int64, error) {
 	var req *http.Request
 	var err error
 
@@ -40,6 +83,10 @@ func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent st
 		req.Header.Set("User-Agent", useragent)
 	}
 
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
 	// Perform the request
 	r, doneCh, err := cancel.CancelableDownload(canceler, httpClient.Do, req)
 	if err != nil {
@@ -49,7 +96,47 @@ func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent st
 	defer func() { _ = r.Body.Close() }()
 	defer close(doneCh)
 
-	if r.StatusCode != http.StatusOK {
+	// Restarts the download from the beginning.
+	restart := func() (int64, error) {
+		if hashFunc != nil {
+			hashFunc.Reset()
+		}
+
+		_, err := target.Seek(0, io.SeekStart)
+		if err != nil {
+			return -1, err
+		}
+
+		return downloadFile(ctx, httpClient, useragent, progress, canceler, filename, url, fileHash, hashFunc, target, 0)
+	}
+
+	if offset > 0 {
+		switch r.StatusCode {
+		case http.StatusPartialContent:
+			// Resume the download.
+		case http.StatusRequestedRangeNotSatisfiable:
+			// The existing data may already be the complete file.
+			if hashFunc != nil && fmt.Sprintf("%x", hashFunc.Sum(nil)) == fileHash {
+				return offset, nil
+			}
+
+			return restart()
+		case http.StatusOK:
+			// The server doesn't support range requests.
+			if hashFunc != nil {
+				hashFunc.Reset()
+			}
+
+			_, err = target.Seek(0, io.SeekStart)
+			if err != nil {
+				return -1, err
+			}
+
+			offset = 0
+		}
+	}
+
+	if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusPartialContent {
 		if r.StatusCode == http.StatusNotFound {
 			return -1, fmt.Errorf("Unable to fetch %s: %w", url, ErrNotFound)
 		}
@@ -85,7 +172,7 @@ func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent st
 
 		result := fmt.Sprintf("%x", hashFunc.Sum(nil))
 		if result != fileHash {
-			return -1, fmt.Errorf("Hash mismatch for %s: %s != %s", url, result, fileHash)
+			return -1, fmt.Errorf("%w for %s: %s != %s", ErrHashMismatch, url, result, fileHash)
 		}
 	} else {
 		size, err = io.Copy(target, body)
@@ -94,5 +181,5 @@ func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent st
 		}
 	}
 
-	return size, nil
+	return offset + size, nil
 }
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDownloadServer returns a server providing content, with or without support for range requests.
+// The range headers of the received requests are appended to ranges.
+func newDownloadServer(t *testing.T, content []byte, supportsRange bool, ranges *[]string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+
+		if !supportsRange {
+			_, _ = w.Write(content)
+			return
+		}
+
+		http.ServeContent(w, r, "image", time.Now(), bytes.NewReader(content))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+// newPartialFile returns a file holding the given data.
+func newPartialFile(t *testing.T, data []byte) *os.File {
+	f, err := os.Create(filepath.Join(t.TempDir(), "image"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = f.Close() })
+
+	_, err = f.Write(data)
+	require.NoError(t, err)
+
+	return f
+}
+
+func readFile(t *testing.T, f *os.File, size int64) []byte {
+	data := make([]byte, size)
+	_, err := f.ReadAt(data, 0)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestDownloadFileHashResume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	fileHash := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	tests := []struct {
+		name          string
+		existing      []byte
+		supportsRange bool
+		wantRanges    []string
+	}{
+		{
+			name:          "New download",
+			existing:      nil,
+			supportsRange: true,
+			wantRanges:    []string{""},
+		},
+		{
+			name:          "Partial download",
+			existing:      content[:4321],
+			supportsRange: true,
+			wantRanges:    []string{"bytes=4321-"},
+		},
+		{
+			name:          "Partial download without range support",
+			existing:      content[:4321],
+			supportsRange: false,
+			wantRanges:    []string{"bytes=4321-"},
+		},
+		{
+			name:          "Complete download",
+			existing:      content,
+			supportsRange: true,
+			wantRanges:    []string{fmt.Sprintf("bytes=%d-", len(content))},
+		},
+		{
+			name:          "Invalid existing data",
+			existing:      append(bytes.Repeat([]byte("x"), 500), content...),
+			supportsRange: true,
+			wantRanges:    []string{fmt.Sprintf("bytes=%d-", len(content)+500), ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := []string{}
+			url := newDownloadServer(t, content, tt.supportsRange, &ranges)
+			target := newPartialFile(t, tt.existing)
+
+			size, err := DownloadFileHashResume(context.Background(), http.DefaultClient, "", nil, nil, "", url, fileHash, sha256.New(), target)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), size)
+			assert.Equal(t, tt.wantRanges, ranges)
+
+			// Like the callers, drop the existing data past the end of the downloaded file.
+			err = target.Truncate(size)
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(target.Name())
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+		})
+	}
+}
+
+func TestDownloadFileHashResumeMismatch(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	fileHash := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	// The resumed data doesn't match the existing data.
+	ranges := []string{}
+	url := newDownloadServer(t, content, true, &ranges)
+	target := newPartialFile(t, bytes.Repeat([]byte("x"), 100))
+
+	_, err := DownloadFileHashResume(context.Background(), http.DefaultClient, "", nil, nil, "", url, fileHash, sha256.New(), target)
+	assert.ErrorIs(t, err, ErrHashMismatch)
+}
+
+func TestDownloadFileHash(t *testing.T) {
+	content := []byte("image content")
+	fileHash := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	ranges := []string{}
+	url := newDownloadServer(t, content, true, &ranges)
+
+	// Existing data is overwritten.
+	target := newPartialFile(t, []byte("existing"))
+
+	size, err := DownloadFileHash(context.Background(), http.DefaultClient, "", nil, nil, "", url, fileHash, sha256.New(), target)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), size)
+	assert.Equal(t, content, readFile(t, target, size))
+	assert.Equal(t, []string{""}, ranges)
+}
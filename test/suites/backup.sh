@@ -1022,3 +1022,91 @@ test_backup_export_import_instance_only() {
   rm "${INCUS_DIR}/c1.tar.gz"
   incus delete -f c1
 }
+
+test_backup_s3() {
+  # shellcheck disable=2039,3043
+  local incus_backend
+
+  incus_backend=$(storage_backend "$INCUS_DIR")
+
+  # Use a local MinIO backed storage bucket as the S3 backup target.
+  # This requires a dir pool on a real filesystem as MinIO doesn't support running on tmpfs.
+  if [ "$incus_backend" != "dir" ]; then
+    return
+  fi
+
+  if ! command -v minio ; then
+    export TEST_UNMET_REQUIREMENT="minio command not found"
+    return
+  fi
+
+  ensure_import_testimage
+
+  bucketPrefix="inc$$"
+  configure_loop_device loop_file_1 loop_device_1
+  # shellcheck disable=SC2154
+  mkfs.ext4 "${loop_device_1}"
+  mkdir "${TEST_DIR}/${bucketPrefix}"
+  mount "${loop_device_1}" "${TEST_DIR}/${bucketPrefix}"
+  losetup -d "${loop_device_1}"
+  mkdir "${TEST_DIR}/${bucketPrefix}/s3"
+  incus storage create s3 dir source="${TEST_DIR}/${bucketPrefix}/s3"
+
+  buckets_addr="127.0.0.1:$(local_tcp_port)"
+  incus config set core.storage_buckets_address "${buckets_addr}"
+  s3Endpoint="https://${buckets_addr}"
+
+  initCreds=$(incus storage bucket create s3 "${bucketPrefix}.backups")
+  accessKey=$(echo "${initCreds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')
+  secretKey=$(echo "${initCreds}" | awk '{ if ($2 == "secret" && $3 == "key:") {print $4}}')
+
+  incus init testimage c1
+
+  # Creating a backup on an unconfigured target fails.
+  ! incus query --wait -X POST -d '{"name": "b1", "target": "s3"}' /1.0/instances/c1/backups || false
+  ! incus query --wait -X POST -d '{"name": "b1", "target": "foo"}' /1.0/instances/c1/backups || false
+
+  incus config set backups.s3.endpoint="${s3Endpoint}" backups.s3.bucket="${bucketPrefix}.backups" backups.s3.prefix=incus
+  incus config set backups.s3.access_key="${accessKey}" backups.s3.secret_key="${secretKey}"
+
+  # Create a backup on the S3 target and check it's stored in the bucket rather than on disk.
+  incus query --wait -X POST -d '{"name": "b1", "target": "s3"}' /1.0/instances/c1/backups
+  [ "$(incus query /1.0/instances/c1/backups/b1 | jq -r .target)" = "s3" ]
+  [ ! -e "${INCUS_DIR}/backups/instances/c1/b1" ]
+  s3cmdrun "${incus_backend}" "${accessKey}" "${secretKey}" ls "s3://${bucketPrefix}.backups/incus/instances/c1/" | grep -F "incus/instances/c1/b1"
+
+  # Export the backup from the S3 target.
+  incus query /1.0/instances/c1/backups/b1/export > "${INCUS_DIR}/c1.tar.gz"
+  tar -tzf "${INCUS_DIR}/c1.tar.gz" | grep -F "backup/index.yaml"
+  rm "${INCUS_DIR}/c1.tar.gz"
+
+  # Rename the backup.
+  incus query --wait -X POST -d '{"name": "b2"}' /1.0/instances/c1/backups/b1
+  s3cmdrun "${incus_backend}" "${accessKey}" "${secretKey}" ls "s3://${bucketPrefix}.backups/incus/instances/c1/" | grep -F "incus/instances/c1/b2"
+  ! s3cmdrun "${incus_backend}" "${accessKey}" "${secretKey}" ls "s3://${bucketPrefix}.backups/incus/instances/c1/" | grep -F "incus/instances/c1/b1" || false
+
+  # Restore the backup as a new instance.
+  incus query --wait -X POST -d '{"name": "c2", "source": {"type": "backup", "source": "c1/b2"}}' /1.0/instances
+  incus info c2
+
+  # Delete the backup.
+  incus query --wait -X DELETE /1.0/instances/c1/backups/b2
+  ! s3cmdrun "${incus_backend}" "${accessKey}" "${secretKey}" ls "s3://${bucketPrefix}.backups/incus/instances/c1/" | grep -F "incus/instances/c1/b2" || false
+
+  # Clean up.
+  incus delete -f c1 c2
+  incus config unset backups.s3.endpoint
+  incus config unset backups.s3.bucket
+  incus config unset backups.s3.prefix
+  incus config unset backups.s3.access_key
+  incus config unset backups.s3.secret_key
+  incus storage bucket delete s3 "${bucketPrefix}.backups"
+  incus storage delete s3
+  incus config unset core.storage_buckets_address
+
+  umount "${TEST_DIR}/${bucketPrefix}"
+  rmdir "${TEST_DIR}/${bucketPrefix}"
+
+  # shellcheck disable=SC2154
+  deconfigure_loop_device "${loop_file_1}" "${loop_device_1}"
+}
@@ -1089,6 +1089,14 @@ test_projects_usage() {
   incus project info test-usage --format csv | grep -q "PROCESSES,40,20"
   incus project info test-usage --format csv | grep -q "VIRTUAL-MACHINES,UNLIMITED,0"
 
+  # Check the recorded resource usage (nothing was running yet)
+  incus project usage test-usage
+  [ "$(incus project usage test-usage --format csv)" = "" ]
+  incus project usage test-usage --since 2024-01-01 --until 2024-02-01 --format json | jq -e '.instances == []'
+  ! incus project usage test-usage --since 2024-02-01 --until 2024-01-01 || false
+  ! incus project usage test-usage --since yesterday || false
+  ! incus project usage missing-project || false
+
   incus delete c1 --project test-usage
   incus image delete testimage --project test-usage
   incus project delete test-usage
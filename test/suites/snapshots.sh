@@ -367,6 +367,39 @@ test_snap_schedule() {
   incus rm -f c1 c2 c3 c4 c5
 }
 
+test_snap_diff() {
+  ensure_import_testimage
+  ensure_has_localhost_remote "${INCUS_ADDR}"
+
+  incus init testimage c1
+  echo foo > "${TEST_DIR}/foo"
+  incus file push "${TEST_DIR}/foo" c1/root/old
+  incus file push "${TEST_DIR}/foo" c1/root/changed
+  incus snapshot create c1 snap0
+
+  # Changes between two snapshots.
+  echo bar > "${TEST_DIR}/foo"
+  incus file push "${TEST_DIR}/foo" c1/root/changed
+  incus file push "${TEST_DIR}/foo" c1/root/new
+  incus file delete c1/root/old
+  incus snapshot create c1 snap1
+
+  incus snapshot diff c1 snap1 --from snap0 --format csv | grep -xF "ADDED,/rootfs/root/new"
+  incus snapshot diff c1 snap1 --from snap0 --format csv | grep -xF "REMOVED,/rootfs/root/old"
+  incus snapshot diff c1 snap1 --from snap0 --format csv | grep -xF "MODIFIED,/rootfs/root/changed"
+
+  # Changes made since a snapshot.
+  [ -z "$(incus snapshot diff c1 snap1 --format csv | grep /rootfs/root/ || true)" ]
+  incus file delete c1/root/new
+  incus snapshot diff c1 snap1 --format csv | grep -xF "REMOVED,/rootfs/root/new"
+
+  # Missing snapshots are rejected.
+  ! incus snapshot diff c1 snap1 --from snap2 || false
+
+  incus delete -f c1
+  rm -f "${TEST_DIR}/foo"
+}
+
 test_snap_volume_db_recovery() {
   # shellcheck disable=2039,3043
   local incus_backend